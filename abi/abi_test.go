@@ -82,5 +82,39 @@ func TestABI(t *testing.T) {
 
 		assert.Equal(t, value, d)
 
+		key := thor.BytesToBytes32([]byte("k"))
+		values, err := event.DecodeValues([]thor.Bytes32{event.ID(), key}, data)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"key", "value"}, event.InputNames())
+		assert.Equal(t, 2, len(values))
+		assert.Equal(t, value, values[1])
+	}
+
+	// decode input values
+	{
+		method, _ := abi.MethodByName("set")
+		key := thor.BytesToBytes32([]byte("k"))
+		input, _ := method.EncodeInput(key, big.NewInt(1))
+
+		values, err := method.DecodeInputValues(input)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(values))
+		assert.Equal(t, big.NewInt(1), values[1])
+		assert.Equal(t, []string{"_key", "_value"}, method.InputNames())
+	}
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	data := common.Hex2Bytes("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000001a" +
+		"4e6f7420656e6f7567682045746865722070726f76696465642e000000000000")
+	reason, ok := abi.DecodeRevertReason(data)
+	assert.True(t, ok)
+	assert.Equal(t, "Not enough Ether provided.", reason)
+
+	for _, bad := range [][]byte{nil, data[:4], data[:len(data)-32], common.Hex2Bytes("4e487b71")} {
+		_, ok := abi.DecodeRevertReason(bad)
+		assert.False(t, ok)
 	}
 }
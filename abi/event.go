@@ -6,6 +6,8 @@
 package abi
 
 import (
+	"errors"
+
 	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/vechain/thor/thor"
 )
@@ -50,3 +52,52 @@ func (e *Event) Encode(args ...interface{}) ([]byte, error) {
 func (e *Event) Decode(data []byte, v interface{}) error {
 	return e.argsWithoutIndexed.Unpack(v, data)
 }
+
+// InputNames returns names of event inputs, including indexed ones.
+func (e *Event) InputNames() []string {
+	names := make([]string, 0, len(e.event.Inputs))
+	for _, arg := range e.event.Inputs {
+		names = append(names, arg.Name)
+	}
+	return names
+}
+
+// DecodeValues decodes all event inputs from topics and data, in the order of event inputs.
+// Indexed inputs of dynamic types are stored as hashes, and returned as thor.Bytes32.
+func (e *Event) DecodeValues(topics []thor.Bytes32, data []byte) ([]interface{}, error) {
+	if !e.event.Anonymous {
+		if len(topics) == 0 || topics[0] != e.id {
+			return nil, errors.New("event id mismatch")
+		}
+		topics = topics[1:]
+	}
+	nonIndexed, err := e.argsWithoutIndexed.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0, len(e.event.Inputs))
+	for _, arg := range e.event.Inputs {
+		if !arg.Indexed {
+			values = append(values, nonIndexed[0])
+			nonIndexed = nonIndexed[1:]
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, errors.New("insufficient topics")
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		switch arg.Type.T {
+		case ethabi.StringTy, ethabi.BytesTy, ethabi.SliceTy, ethabi.ArrayTy:
+			values = append(values, topic)
+		default:
+			v, err := ethabi.Arguments{{Type: arg.Type}}.UnpackValues(topic[:])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v[0])
+		}
+	}
+	return values, nil
+}
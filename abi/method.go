@@ -50,6 +50,23 @@ func (m *Method) DecodeInput(input []byte, v interface{}) error {
 	return m.method.Inputs.Unpack(v, input[4:])
 }
 
+// InputNames returns names of method inputs.
+func (m *Method) InputNames() []string {
+	names := make([]string, 0, len(m.method.Inputs))
+	for _, arg := range m.method.Inputs {
+		names = append(names, arg.Name)
+	}
+	return names
+}
+
+// DecodeInputValues decode input data into values, in the order of method inputs.
+func (m *Method) DecodeInputValues(input []byte) ([]interface{}, error) {
+	if !bytes.HasPrefix(input, m.id[:]) {
+		return nil, errors.New("input has incorrect prefix")
+	}
+	return m.method.Inputs.UnpackValues(input[4:])
+}
+
 // EncodeOutput encode output args to data.
 func (m *Method) EncodeOutput(args ...interface{}) ([]byte, error) {
 	return m.method.Outputs.Pack(args...)
@@ -0,0 +1,26 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package abi
+
+// errorMethod is the pseudo method Error(string), whose input encoding is used by
+// solidity to carry the reason of revert and require.
+var errorMethod = func() *Method {
+	abi, err := New([]byte(`[{"type":"function","name":"Error","inputs":[{"name":"reason","type":"string"}]}]`))
+	if err != nil {
+		panic(err)
+	}
+	m, _ := abi.MethodByName("Error")
+	return m
+}()
+
+// DecodeRevertReason decodes the reason string from data returned by a reverted execution.
+// ok is false if data is not encoded as Error(string).
+func DecodeRevertReason(data []byte) (reason string, ok bool) {
+	if err := errorMethod.DecodeInput(data, &reason); err != nil {
+		return "", false
+	}
+	return reason, true
+}
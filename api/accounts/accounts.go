@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -26,30 +27,40 @@ import (
 	"github.com/vechain/thor/xenv"
 )
 
+// max storage keys to prove in a request
+const maxProofKeys = 100
+
 type Accounts struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
 	callGasLimit uint64
+	execLimiter  *utils.ExecLimiter
 }
 
-func New(chain *chain.Chain, stateCreator *state.Creator, callGasLimit uint64) *Accounts {
+func New(chain *chain.Chain, stateCreator *state.Creator, callGasLimit uint64, execLimiter *utils.ExecLimiter) *Accounts {
 	return &Accounts{
 		chain,
 		stateCreator,
 		callGasLimit,
+		execLimiter,
 	}
 }
 
 func (a *Accounts) getCode(addr thor.Address, stateRoot thor.Bytes32) ([]byte, error) {
-	state, err := a.stateCreator.NewState(stateRoot)
+	state, err := a.stateCreator.NewReadOnly(stateRoot)
 	if err != nil {
 		return nil, err
 	}
-	code := state.GetCode(addr)
-	if err := state.Err(); err != nil {
-		return nil, err
+	return state.GetCode(addr)
+}
+
+// getCodeHash returns keccak256 hash of code, or zero hash if no code.
+func (a *Accounts) getCodeHash(addr thor.Address, stateRoot thor.Bytes32) (thor.Bytes32, error) {
+	state, err := a.stateCreator.NewReadOnly(stateRoot)
+	if err != nil {
+		return thor.Bytes32{}, err
 	}
-	return code, nil
+	return state.GetCodeHash(addr)
 }
 
 func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error {
@@ -58,10 +69,21 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "address"))
 	}
+	hashOnly := req.URL.Query().Get("hashOnly")
+	if hashOnly != "" && hashOnly != "false" && hashOnly != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "hashOnly"))
+	}
 	h, err := a.handleRevision(req.URL.Query().Get("revision"))
 	if err != nil {
 		return err
 	}
+	if hashOnly == "true" {
+		codeHash, err := a.getCodeHash(addr, h.StateRoot())
+		if err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, map[string]string{"codeHash": codeHash.String()})
+	}
 	code, err := a.getCode(addr, h.StateRoot())
 	if err != nil {
 		return err
@@ -70,33 +92,35 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 }
 
 func (a *Accounts) getAccount(addr thor.Address, header *block.Header) (*Account, error) {
-	state, err := a.stateCreator.NewState(header.StateRoot())
+	state, err := a.stateCreator.NewReadOnly(header.StateRoot())
 	if err != nil {
 		return nil, err
 	}
-	b := state.GetBalance(addr)
-	code := state.GetCode(addr)
-	energy := state.GetEnergy(addr, header.Timestamp())
-	if err := state.Err(); err != nil {
+	acc, err := state.GetAccount(addr)
+	if err != nil {
 		return nil, err
 	}
 	return &Account{
-		Balance: math.HexOrDecimal256(*b),
-		Energy:  math.HexOrDecimal256(*energy),
-		HasCode: len(code) != 0,
+		Balance: math.HexOrDecimal256(*acc.Balance),
+		Energy:  math.HexOrDecimal256(*acc.CalcEnergy(header.Timestamp())),
+		HasCode: len(acc.CodeHash) != 0,
 	}, nil
 }
 
 func (a *Accounts) getStorage(addr thor.Address, key thor.Bytes32, stateRoot thor.Bytes32) (thor.Bytes32, error) {
-	state, err := a.stateCreator.NewState(stateRoot)
+	state, err := a.stateCreator.NewReadOnly(stateRoot)
 	if err != nil {
 		return thor.Bytes32{}, err
 	}
-	storage := state.GetStorage(addr, key)
-	if err := state.Err(); err != nil {
-		return thor.Bytes32{}, err
+	return state.GetStorage(addr, key)
+}
+
+// parseStorageKey parses storage key, which can be given in short form (e.g. slot number '0x1'), left padded with zeros.
+func parseStorageKey(str string) (thor.Bytes32, error) {
+	if len(str) > 2 && len(str) < 66 && strings.ToLower(str[:2]) == "0x" {
+		str = "0x" + strings.Repeat("0", 66-len(str)) + str[2:]
 	}
-	return storage, nil
+	return thor.ParseBytes32(str)
 }
 
 func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) error {
@@ -112,7 +136,7 @@ func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) er
 	if err != nil {
 		return err
 	}
-	return utils.WriteJSON(w, acc)
+	return utils.WriteJSONFields(w, acc, req.URL.Query().Get("fields"))
 }
 
 func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) error {
@@ -120,7 +144,7 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "address"))
 	}
-	key, err := thor.ParseBytes32(mux.Vars(req)["key"])
+	key, err := parseStorageKey(mux.Vars(req)["key"])
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "key"))
 	}
@@ -135,6 +159,40 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 	return utils.WriteJSON(w, map[string]string{"value": storage.String()})
 }
 
+func (a *Accounts) handleGetProof(w http.ResponseWriter, req *http.Request) error {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	var keys []thor.Bytes32
+	if str := req.URL.Query().Get("keys"); str != "" {
+		items := strings.Split(str, ",")
+		if len(items) > maxProofKeys {
+			return utils.BadRequest(errors.WithMessage(fmt.Errorf("exceeds limit %v", maxProofKeys), "keys"))
+		}
+		for _, item := range items {
+			key, err := parseStorageKey(strings.TrimSpace(item))
+			if err != nil {
+				return utils.BadRequest(errors.WithMessage(err, "keys"))
+			}
+			keys = append(keys, key)
+		}
+	}
+	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	state, err := a.stateCreator.NewReadOnly(h.StateRoot())
+	if err != nil {
+		return err
+	}
+	proof, err := state.Prove(addr, keys)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, convertAccountProof(proof, h))
+}
+
 func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request) error {
 	callData := &CallData{}
 	if err := utils.ParseJSON(req.Body, &callData); err != nil {
@@ -237,6 +295,168 @@ func (a *Accounts) batchCall(ctx context.Context, batchCallData *BatchCallData,
 	return results, nil
 }
 
+func (a *Accounts) handleEstimateGas(w http.ResponseWriter, req *http.Request) error {
+	batchCallData := &BatchCallData{}
+	if err := utils.ParseJSON(req.Body, &batchCallData); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if len(batchCallData.Clauses) == 0 {
+		return utils.BadRequest(errors.New("clauses: empty"))
+	}
+	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	result, err := a.estimateGas(req.Context(), batchCallData, h)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// estimateGas finds the least gas for all clauses to succeed, by binary searching between
+// the gas used with the full allowance and the allowance itself. More than used gas may be
+// required, since refunds are paid after execution and calls reserve part of the remaining gas.
+func (a *Accounts) estimateGas(ctx context.Context, batchCallData *BatchCallData, header *block.Header) (*GasEstimation, error) {
+	_, _, _, clauses, err := a.handleBatchCallData(batchCallData)
+	if err != nil {
+		return nil, err
+	}
+	intrinsicGas, err := tx.IntrinsicGas(clauses...)
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "clauses"))
+	}
+
+	// tries with given gas, and returns total gas used if succeeded
+	try := func(gas uint64) (uint64, *CallResult, error) {
+		data := *batchCallData
+		data.Gas = gas
+		results, err := a.batchCall(ctx, &data, header)
+		if err != nil {
+			return 0, nil, err
+		}
+		var used uint64
+		for _, r := range results {
+			used += r.GasUsed
+		}
+		last := results[len(results)-1]
+		if len(results) < len(clauses) || last.Reverted {
+			return used, last, nil
+		}
+		return used, nil, nil
+	}
+
+	hi := batchCallData.Gas
+	if hi == 0 {
+		hi = a.callGasLimit
+	}
+	used, failure, err := try(hi)
+	if err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return &GasEstimation{
+			IntrinsicGas: intrinsicGas,
+			ExecutionGas: used,
+			Reverted:     true,
+			VMError:      failure.VMError,
+		}, nil
+	}
+	if used == 0 {
+		// e.g. plain VET transfers
+		hi = 0
+	} else {
+		// the least succeeded allowance is in (lo, hi]
+		lo := used - 1
+		for lo+1 < hi {
+			mid := lo + (hi-lo)/2
+			_, failure, err := try(mid)
+			if err != nil {
+				return nil, err
+			}
+			if failure != nil {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+	}
+	return &GasEstimation{
+		Gas:          intrinsicGas + hi,
+		IntrinsicGas: intrinsicGas,
+		ExecutionGas: hi,
+	}, nil
+}
+
+func (a *Accounts) handleSimulateDeploy(w http.ResponseWriter, req *http.Request) error {
+	callData := &CallData{}
+	if err := utils.ParseJSON(req.Body, &callData); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if callData.Data == "" {
+		return utils.BadRequest(errors.New("data: empty creation code"))
+	}
+	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	result, err := a.simulateDeploy(req.Context(), callData, h)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// simulateDeploy executes a creation clause speculatively upon the state of given header.
+// The state is discarded after execution.
+func (a *Accounts) simulateDeploy(ctx context.Context, callData *CallData, header *block.Header) (*DeployResult, error) {
+	gas, gasPrice, caller, clauses, err := a.handleBatchCallData(&BatchCallData{
+		Clauses:  Clauses{Clause{Value: callData.Value, Data: callData.Data}},
+		Gas:      callData.Gas,
+		GasPrice: callData.GasPrice,
+		Caller:   callData.Caller,
+	})
+	if err != nil {
+		return nil, err
+	}
+	state, err := a.stateCreator.NewState(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	signer, _ := header.Signer()
+	rt := runtime.New(a.chain.NewSeeker(header.ParentID()), state,
+		&xenv.BlockContext{
+			Beneficiary: header.Beneficiary(),
+			Signer:      signer,
+			Number:      header.Number(),
+			Time:        header.Timestamp(),
+			GasLimit:    header.GasLimit(),
+			TotalScore:  header.TotalScore()})
+
+	exec, interrupt := rt.PrepareClause(clauses[0], 0, gas, &xenv.TransactionContext{
+		Origin:     *caller,
+		GasPrice:   gasPrice,
+		ProvedWork: &big.Int{}})
+	vmout := make(chan *runtime.Output, 1)
+	go func() {
+		out, _ := exec()
+		vmout <- out
+	}()
+	select {
+	case <-ctx.Done():
+		interrupt()
+		return nil, ctx.Err()
+	case out := <-vmout:
+		if err := rt.Seeker().Err(); err != nil {
+			return nil, err
+		}
+		if err := state.Err(); err != nil {
+			return nil, err
+		}
+		return convertDeployResult(out, gas), nil
+	}
+}
+
 func (a *Accounts) handleBatchCallData(batchCallData *BatchCallData) (gas uint64, gasPrice *big.Int, caller *thor.Address, clauses []*tx.Clause, err error) {
 	if batchCallData.Gas > a.callGasLimit {
 		return 0, nil, nil, nil, utils.Forbidden(errors.New("gas: exceeds limit"))
@@ -277,6 +497,18 @@ func (a *Accounts) handleBatchCallData(batchCallData *BatchCallData) (gas uint64
 }
 
 func (a *Accounts) handleRevision(revision string) (*block.Header, error) {
+	h, err := a.parseRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+	// fail fast with a clear error, rather than partial state read from shared nodes
+	if err := a.stateCreator.CheckPruned(h.Number()); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (a *Accounts) parseRevision(revision string) (*block.Header, error) {
 	if revision == "" || revision == "best" {
 		return a.chain.BestBlock().Header(), nil
 	}
@@ -314,11 +546,14 @@ func (a *Accounts) handleRevision(revision string) (*block.Header, error) {
 func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
-	sub.Path("/*").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallBatchCode))
+	sub.Path("/*").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.execLimiter.Wrap(a.handleCallBatchCode)))
 	sub.Path("/{address}").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetAccount))
 	sub.Path("/{address}/code").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetCode))
+	sub.Path("/{address}/proof").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetProof))
 	sub.Path("/{address}/storage/{key}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetStorage))
-	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallContract))
-	sub.Path("/{address}").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallContract))
+	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.execLimiter.Wrap(a.handleCallContract)))
+	sub.Path("/deploy").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.execLimiter.Wrap(a.handleSimulateDeploy)))
+	sub.Path("/estimate").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.execLimiter.Wrap(a.handleEstimateGas)))
+	sub.Path("/{address}").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.execLimiter.Wrap(a.handleCallContract)))
 
 }
@@ -88,6 +88,7 @@ var storageKey = thor.Bytes32{}
 var storageValue = byte(1)
 
 var contractAddr thor.Address
+var genesisID thor.Bytes32
 
 var bytecode = common.Hex2Bytes("608060405234801561001057600080fd5b50610125806100206000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806324b8ba5f14604e578063bb4e3f4d14607b575b600080fd5b348015605957600080fd5b506079600480360381019080803560ff16906020019092919050505060cf565b005b348015608657600080fd5b5060b3600480360381019080803560ff169060200190929190803560ff16906020019092919050505060ec565b604051808260ff1660ff16815260200191505060405180910390f35b806000806101000a81548160ff021916908360ff16021790555050565b60008183019050929150505600a165627a7a723058201584add23e31d36c569b468097fe01033525686b59bbb263fb3ab82e9553dae50029")
 
@@ -105,9 +106,12 @@ func TestAccount(t *testing.T) {
 	getAccount(t)
 	getCode(t)
 	getStorage(t)
+	getProof(t)
 	deployContractWithCall(t)
+	simulateDeploy(t)
 	callContract(t)
 	batchCall(t)
+	estimateGas(t)
 }
 
 func getAccount(t *testing.T) {
@@ -124,8 +128,32 @@ func getAccount(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, math.HexOrDecimal256(*value), acc.Balance, "balance should be equal")
+	assert.False(t, acc.HasCode)
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
 
+	// energy grows with time, and is computed at the timestamp of queried revision
+	dev := genesis.DevAccounts()[0].Address
+	var accAtGenesis, accAtBest accounts.Account
+	res, _ = httpGet(t, ts.URL+"/accounts/"+dev.String()+"?revision=0")
+	if err := json.Unmarshal(res, &accAtGenesis); err != nil {
+		t.Fatal(err)
+	}
+	res, _ = httpGet(t, ts.URL+"/accounts/"+dev.String())
+	if err := json.Unmarshal(res, &accAtBest); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, (*big.Int)(&accAtBest.Energy).Cmp((*big.Int)(&accAtGenesis.Energy)) > 0)
+
+	// balance before the transfer, queried by block number and ID
+	for _, revision := range []string{"0", genesisID.String()} {
+		res, statusCode = httpGet(t, ts.URL+"/accounts/"+addr.String()+"?revision="+revision)
+		var acc accounts.Account
+		if err := json.Unmarshal(res, &acc); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, 0, (*big.Int)(&acc.Balance).Sign(), "no balance before transfer")
+	}
 }
 
 func getCode(t *testing.T) {
@@ -147,6 +175,31 @@ func getCode(t *testing.T) {
 	}
 	assert.Equal(t, runtimeBytecode, c, "code should be equal")
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
+
+	// not deployed yet at genesis
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/code?revision=0")
+	if err := json.Unmarshal(res, &code); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "0x", code["code"])
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/code?hashOnly=true")
+	if err := json.Unmarshal(res, &code); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, thor.Bytes32(crypto.Keccak256Hash(runtimeBytecode)).String(), code["codeHash"])
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/code?hashOnly=true&revision=0")
+	if err := json.Unmarshal(res, &code); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, thor.Bytes32{}.String(), code["codeHash"], "no code at genesis")
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/code?hashOnly=1")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad hashOnly")
 }
 
 func getStorage(t *testing.T) {
@@ -171,6 +224,71 @@ func getStorage(t *testing.T) {
 	}
 	assert.Equal(t, thor.BytesToBytes32([]byte{storageValue}), h, "storage should be equal")
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
+
+	// deployed in block 1, but value set in block 2
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/"+storageKey.String()+"?revision=1")
+	if err := json.Unmarshal(res, &value); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, thor.Bytes32{}.String(), value["value"])
+
+	// short form of slot 0
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/0x0")
+	if err := json.Unmarshal(res, &value); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, thor.BytesToBytes32([]byte{storageValue}).String(), value["value"])
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/0xg")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad short storage key")
+}
+
+func getProof(t *testing.T) {
+	res, statusCode := httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/proof?keys=0x0,"+thor.BytesToBytes32([]byte("absent")).String())
+	assert.Equal(t, http.StatusOK, statusCode)
+	var proof accounts.AccountProof
+	if err := json.Unmarshal(res, &proof); err != nil {
+		t.Fatal(err)
+	}
+	toBytes := func(nodes []hexutil.Bytes) [][]byte {
+		var proof [][]byte
+		for _, node := range nodes {
+			proof = append(proof, node)
+		}
+		return proof
+	}
+	acc, err := state.VerifyAccountProof(proof.StateRoot, contractAddr, toBytes(proof.AccountProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []byte(proof.CodeHash), acc.CodeHash)
+	assert.Equal(t, crypto.Keccak256(runtimeBytecode), acc.CodeHash)
+
+	assert.Equal(t, 2, len(proof.StorageProof))
+	raw, err := state.VerifyStorageProof(thor.BytesToBytes32(acc.StorageRoot), proof.StorageProof[0].Key, toBytes(proof.StorageProof[0].Proof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, raw)
+	assert.Equal(t, thor.BytesToBytes32([]byte{storageValue}), proof.StorageProof[0].Value)
+	raw, err = state.VerifyStorageProof(thor.BytesToBytes32(acc.StorageRoot), proof.StorageProof[1].Key, toBytes(proof.StorageProof[1].Proof))
+	assert.Nil(t, err)
+	assert.Empty(t, raw, "absent key")
+
+	// at genesis, contract not deployed
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/proof?revision="+genesisID.String())
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &proof); err != nil {
+		t.Fatal(err)
+	}
+	acc, err = state.VerifyAccountProof(proof.StateRoot, contractAddr, toBytes(proof.AccountProof))
+	assert.Nil(t, err)
+	assert.True(t, acc.IsEmpty())
+
+	_, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/proof?keys=0xg")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad key")
 }
 
 func initAccountServer(t *testing.T) {
@@ -183,6 +301,7 @@ func initAccountServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	chain, _ := chain.New(db, b)
+	genesisID = b.Header().ID()
 	claTransfer := tx.NewClause(&addr).WithValue(value)
 	claDeploy := tx.NewClause(nil).WithData(bytecode)
 	transaction := buildTxWithClauses(t, chain.Tag(), claTransfer, claDeploy)
@@ -201,7 +320,7 @@ func initAccountServer(t *testing.T) {
 	packTx(chain, stateC, transactionCall, t)
 
 	router := mux.NewRouter()
-	accounts.New(chain, stateC, math.MaxUint64).Mount(router, "/accounts")
+	accounts.New(chain, stateC, math.MaxUint64, nil).Mount(router, "/accounts")
 	ts = httptest.NewServer(router)
 }
 
@@ -268,6 +387,25 @@ func deployContractWithCall(t *testing.T) {
 
 }
 
+func simulateDeploy(t *testing.T) {
+	_, statusCode := httpPost(t, ts.URL+"/accounts/deploy", &accounts.CallData{Gas: 10000000})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "empty data")
+
+	res, statusCode := httpPost(t, ts.URL+"/accounts/deploy", &accounts.CallData{
+		Gas:  10000000,
+		Data: hexutil.Encode(bytecode),
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	var result *accounts.DeployResult
+	if err := json.Unmarshal(res, &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, result.Reverted)
+	assert.NotNil(t, result.ContractAddress)
+	assert.Equal(t, hexutil.Encode(runtimeBytecode), result.Code)
+	assert.True(t, result.GasUsed > 0)
+}
+
 func callContract(t *testing.T) {
 	res, statusCode := httpPost(t, ts.URL+"/accounts/"+invalidAddr, nil)
 	assert.Equal(t, http.StatusBadRequest, statusCode, "invalid address")
@@ -400,3 +538,71 @@ func httpGet(t *testing.T, url string) ([]byte, int) {
 	}
 	return r, res.StatusCode
 }
+
+func estimateGas(t *testing.T) {
+	res, statusCode := httpPost(t, ts.URL+"/accounts/estimate", &accounts.BatchCallData{})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "empty clauses")
+
+	// plain transfer costs intrinsic gas only
+	caller := genesis.DevAccounts()[0].Address
+	transfer := &accounts.BatchCallData{
+		Clauses: accounts.Clauses{accounts.Clause{To: &addr, Value: (*math.HexOrDecimal256)(big.NewInt(1))}},
+		Caller:  &caller,
+	}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/estimate", transfer)
+	var est accounts.GasEstimation
+	if err := json.Unmarshal(res, &est); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, est.Reverted)
+	assert.Equal(t, uint64(21000), est.Gas)
+	assert.Equal(t, uint64(0), est.ExecutionGas)
+
+	abi, err := ABI.New([]byte(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := abi.MethodByName("set")
+	input, err := m.EncodeInput(uint8(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := &accounts.BatchCallData{
+		Clauses: accounts.Clauses{accounts.Clause{To: &contractAddr, Data: hexutil.Encode(input)}},
+	}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/estimate", set)
+	if err := json.Unmarshal(res, &est); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, est.Reverted)
+	assert.Equal(t, est.IntrinsicGas+est.ExecutionGas, est.Gas)
+
+	// the estimation is exactly the least gas to succeed
+	var results accounts.BatchCallResults
+	set.Gas = est.ExecutionGas
+	res, _ = httpPost(t, ts.URL+"/accounts/*", set)
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, results[0].Reverted)
+	set.Gas = est.ExecutionGas - 1
+	res, _ = httpPost(t, ts.URL+"/accounts/*", set)
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, results[0].Reverted)
+
+	// always reverted
+	bad := &accounts.BatchCallData{
+		Clauses: accounts.Clauses{accounts.Clause{To: &contractAddr, Data: "0x12345678"}},
+	}
+	res, statusCode = httpPost(t, ts.URL+"/accounts/estimate", bad)
+	if err := json.Unmarshal(res, &est); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, est.Reverted)
+	assert.Equal(t, uint64(0), est.Gas)
+}
@@ -8,8 +8,11 @@ package accounts
 import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/abi"
 	"github.com/vechain/thor/api/transactions"
+	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/runtime"
+	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 )
 
@@ -36,18 +39,22 @@ type CallResult struct {
 	GasUsed   uint64                   `json:"gasUsed"`
 	Reverted  bool                     `json:"reverted"`
 	VMError   string                   `json:"vmError"`
+	// decoded from returned data if reverted with a reason
+	RevertReason string `json:"revertReason,omitempty"`
 }
 
 func convertCallResultWithInputGas(vo *runtime.Output, inputGas uint64) *CallResult {
 	gasUsed := inputGas - vo.LeftOverGas
 	var (
-		vmError  string
-		reverted bool
+		vmError      string
+		reverted     bool
+		revertReason string
 	)
 
 	if vo.VMErr != nil {
 		reverted = true
 		vmError = vo.VMErr.Error()
+		revertReason, _ = abi.DecodeRevertReason(vo.Data)
 	}
 
 	events := make([]*transactions.Event, len(vo.Events))
@@ -74,15 +81,47 @@ func convertCallResultWithInputGas(vo *runtime.Output, inputGas uint64) *CallRes
 	}
 
 	return &CallResult{
-		Data:      hexutil.Encode(vo.Data),
-		Events:    events,
-		Transfers: transfers,
-		GasUsed:   gasUsed,
-		Reverted:  reverted,
-		VMError:   vmError,
+		Data:         hexutil.Encode(vo.Data),
+		Events:       events,
+		Transfers:    transfers,
+		GasUsed:      gasUsed,
+		Reverted:     reverted,
+		VMError:      vmError,
+		RevertReason: revertReason,
 	}
 }
 
+// DeployResult result of simulated contract deployment.
+type DeployResult struct {
+	ContractAddress *thor.Address `json:"contractAddress"`
+	Code            string        `json:"code"`
+	CallResult
+}
+
+func convertDeployResult(vo *runtime.Output, inputGas uint64) *DeployResult {
+	result := &DeployResult{
+		CallResult: *convertCallResultWithInputGas(vo, inputGas),
+	}
+	// the returned data of creation is the deployed runtime code
+	result.Code = result.Data
+	if vo.VMErr == nil {
+		result.ContractAddress = vo.ContractAddress
+	} else {
+		result.Code = "0x"
+	}
+	return result
+}
+
+// GasEstimation result of gas estimation. If clauses revert even with the full allowance,
+// Reverted is set and Gas is zero.
+type GasEstimation struct {
+	Gas          uint64 `json:"gas"` // recommended tx gas, intrinsic gas included
+	IntrinsicGas uint64 `json:"intrinsicGas"`
+	ExecutionGas uint64 `json:"executionGas"`
+	Reverted     bool   `json:"reverted"`
+	VMError      string `json:"vmError"`
+}
+
 type Clause struct {
 	To    *thor.Address         `json:"to"`
 	Value *math.HexOrDecimal256 `json:"value"`
@@ -101,3 +140,56 @@ type BatchCallData struct {
 }
 
 type BatchCallResults []*CallResult
+
+// AccountProof merkle proofs of an account and its storage against state root of the block.
+// Account fields are raw as stored in the trie, e.g. energy is as of blockTime.
+type AccountProof struct {
+	BlockID      thor.Bytes32          `json:"blockID"`
+	StateRoot    thor.Bytes32          `json:"stateRoot"`
+	Balance      *math.HexOrDecimal256 `json:"balance"`
+	Energy       *math.HexOrDecimal256 `json:"energy"`
+	BlockTime    uint64                `json:"blockTime"`
+	Master       hexutil.Bytes         `json:"master"`
+	CodeHash     hexutil.Bytes         `json:"codeHash"`
+	StorageRoot  hexutil.Bytes         `json:"storageRoot"`
+	AccountProof []hexutil.Bytes       `json:"accountProof"`
+	StorageProof []*StorageProof       `json:"storageProof"`
+}
+
+// StorageProof merkle proof of a storage value against storage root of the account.
+type StorageProof struct {
+	Key   thor.Bytes32    `json:"key"`
+	Value thor.Bytes32    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+func convertProof(proof [][]byte) []hexutil.Bytes {
+	nodes := make([]hexutil.Bytes, 0, len(proof))
+	for _, node := range proof {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func convertAccountProof(p *state.AccountProof, header *block.Header) *AccountProof {
+	storageProofs := make([]*StorageProof, 0, len(p.StorageProofs))
+	for _, sp := range p.StorageProofs {
+		storageProofs = append(storageProofs, &StorageProof{
+			Key:   sp.Key,
+			Value: sp.Value,
+			Proof: convertProof(sp.Proof),
+		})
+	}
+	return &AccountProof{
+		BlockID:      header.ID(),
+		StateRoot:    header.StateRoot(),
+		Balance:      (*math.HexOrDecimal256)(p.Account.Balance),
+		Energy:       (*math.HexOrDecimal256)(p.Account.Energy),
+		BlockTime:    p.Account.BlockTime,
+		Master:       p.Account.Master,
+		CodeHash:     p.Account.CodeHash,
+		StorageRoot:  p.Account.StorageRoot,
+		AccountProof: convertProof(p.Proof),
+		StorageProof: storageProofs,
+	}
+}
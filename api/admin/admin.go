@@ -0,0 +1,220 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/jobs"
+	"github.com/vechain/thor/loglevel"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
+)
+
+// Network manages P2P peers.
+type Network interface {
+	PeersStats() []*comm.PeerStats
+	AddStatic(node *discover.Node)
+	RemoveStatic(node *discover.Node)
+}
+
+// Admin serves node operations that change runtime behavior.
+// All requests must carry the admin token as 'Authorization: Bearer <token>'.
+type Admin struct {
+	chain      *chain.Chain
+	pool       *txpool.TxPool
+	nw         Network
+	logHandler *loglevel.Handler
+	reload     func() error
+	jobs       *jobs.Scheduler
+	token      string
+}
+
+// New creates admin APIs. nw can be nil if P2P networking is not enabled,
+// and reload reloads node config, which can be nil if not supported.
+func New(chain *chain.Chain, pool *txpool.TxPool, nw Network, logHandler *loglevel.Handler, reload func() error, jobs *jobs.Scheduler, token string) *Admin {
+	return &Admin{
+		chain,
+		pool,
+		nw,
+		logHandler,
+		reload,
+		jobs,
+		token,
+	}
+}
+
+// auth rejects requests without valid token.
+func (a *Admin) auth(f utils.HandlerFunc) utils.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) error {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if a.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			return utils.HTTPError(errors.New("unauthorized"), http.StatusUnauthorized)
+		}
+		return f(w, req)
+	}
+}
+
+func (a *Admin) handleGetOriginPolicy(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, a.pool.OriginPolicy())
+}
+
+func (a *Admin) handleSetOriginPolicy(w http.ResponseWriter, req *http.Request) error {
+	var policy txpool.OriginPolicy
+	if err := utils.ParseJSON(req.Body, &policy); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	a.pool.SetOriginPolicy(policy)
+	return utils.WriteJSON(w, a.pool.OriginPolicy())
+}
+
+func (a *Admin) handleCancelTx(w http.ResponseWriter, req *http.Request) error {
+	txID, err := thor.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	pending, err := a.pool.RemoveLocal(txID)
+	if err != nil {
+		return utils.Forbidden(err)
+	}
+	return utils.WriteJSON(w, map[string]bool{
+		"pending": pending,
+	})
+}
+
+func (a *Admin) handleFlushTxPool(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, map[string]int{
+		"dropped": a.pool.Flush(),
+	})
+}
+
+func (a *Admin) handleGetPeers(w http.ResponseWriter, req *http.Request) error {
+	if a.nw == nil {
+		return utils.WriteJSON(w, []*node.PeerStats{})
+	}
+	return utils.WriteJSON(w, node.ConvertPeersStats(a.nw.PeersStats()))
+}
+
+// parsePeer parses the node of peer management requests.
+func (a *Admin) parsePeer(req *http.Request) (*discover.Node, error) {
+	if a.nw == nil {
+		return nil, utils.Forbidden(errors.New("P2P networking not enabled"))
+	}
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	node, err := discover.ParseNode(body.URL)
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "url"))
+	}
+	return node, nil
+}
+
+func (a *Admin) handleAddPeer(w http.ResponseWriter, req *http.Request) error {
+	node, err := a.parsePeer(req)
+	if err != nil {
+		return err
+	}
+	a.nw.AddStatic(node)
+	return utils.WriteJSON(w, map[string]string{
+		"id": node.ID.String(),
+	})
+}
+
+func (a *Admin) handleRemovePeer(w http.ResponseWriter, req *http.Request) error {
+	node, err := a.parsePeer(req)
+	if err != nil {
+		return err
+	}
+	a.nw.RemoveStatic(node)
+	return utils.WriteJSON(w, map[string]string{
+		"id": node.ID.String(),
+	})
+}
+
+func (a *Admin) handleGetHead(w http.ResponseWriter, req *http.Request) error {
+	header := a.chain.BestBlock().Header()
+	peers := 0
+	if a.nw != nil {
+		peers = len(a.nw.PeersStats())
+	}
+	return utils.WriteJSON(w, &HeadInfo{
+		ID:         header.ID(),
+		Number:     header.Number(),
+		Timestamp:  header.Timestamp(),
+		TotalScore: header.TotalScore(),
+		TxsInPool:  len(a.pool.Dump()),
+		Peers:      peers,
+	})
+}
+
+func (a *Admin) handleGetLogLevels(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, a.logHandler.Levels())
+}
+
+func (a *Admin) handleSetLogLevels(w http.ResponseWriter, req *http.Request) error {
+	var levels loglevel.Levels
+	if err := utils.ParseJSON(req.Body, &levels); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if err := a.logHandler.Apply(levels); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return utils.WriteJSON(w, a.logHandler.Levels())
+}
+
+func (a *Admin) handleReload(w http.ResponseWriter, req *http.Request) error {
+	if a.reload == nil {
+		return utils.Forbidden(errors.New("config reloading not enabled"))
+	}
+	if err := a.reload(); err != nil {
+		return utils.HTTPError(errors.WithMessage(err, "reload"), http.StatusInternalServerError)
+	}
+	return utils.WriteJSON(w, map[string]bool{
+		"reloaded": true,
+	})
+}
+
+func (a *Admin) handleGetJobs(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, a.jobs.Status())
+}
+
+func (a *Admin) handleRunJob(w http.ResponseWriter, req *http.Request) error {
+	if err := a.jobs.Trigger(mux.Vars(req)["name"]); err != nil {
+		return utils.HTTPError(err, http.StatusNotFound)
+	}
+	return utils.WriteJSON(w, a.jobs.Status())
+}
+
+func (a *Admin) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/txpool/origin-policy").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleGetOriginPolicy)))
+	sub.Path("/txpool/origin-policy").Methods(http.MethodPut).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleSetOriginPolicy)))
+	sub.Path("/log-levels").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleGetLogLevels)))
+	sub.Path("/log-levels").Methods(http.MethodPut).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleSetLogLevels)))
+	sub.Path("/reload").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleReload)))
+	sub.Path("/jobs").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleGetJobs)))
+	sub.Path("/jobs/{name}/run").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleRunJob)))
+	sub.Path("/txpool/txs/{id}").Methods(http.MethodDelete).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleCancelTx)))
+	sub.Path("/txpool/flush").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleFlushTxPool)))
+	sub.Path("/peers").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleGetPeers)))
+	sub.Path("/peers").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleAddPeer)))
+	sub.Path("/peers").Methods(http.MethodDelete).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleRemovePeer)))
+	sub.Path("/chain/head").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.auth(a.handleGetHead)))
+}
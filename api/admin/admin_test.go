@@ -0,0 +1,262 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/admin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/jobs"
+	"github.com/vechain/thor/loglevel"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txpool"
+)
+
+const token = "secret"
+
+var (
+	ts        *httptest.Server
+	c         *chain.Chain
+	pool      *txpool.TxPool
+	reloaded  int
+	scheduler *jobs.Scheduler
+	network   = &fakeNetwork{static: make(map[discover.NodeID]bool)}
+)
+
+type fakeNetwork struct {
+	static map[discover.NodeID]bool
+}
+
+func (n *fakeNetwork) PeersStats() []*comm.PeerStats {
+	return []*comm.PeerStats{{Name: "peer", PeerID: "id"}}
+}
+
+func (n *fakeNetwork) AddStatic(node *discover.Node)    { n.static[node.ID] = true }
+func (n *fakeNetwork) RemoveStatic(node *discover.Node) { delete(n.static, node.ID) }
+
+func TestAdmin(t *testing.T) {
+	initAdminServer(t)
+	defer ts.Close()
+
+	unauthorized(t)
+	originPolicy(t)
+	cancelTx(t)
+	logLevels(t)
+	reload(t)
+	listJobs(t)
+	flushTxPool(t)
+	peers(t)
+	head(t)
+}
+
+func unauthorized(t *testing.T) {
+	res, _ := httpDo(t, http.MethodGet, "/admin/txpool/origin-policy", "wrong", nil)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func originPolicy(t *testing.T) {
+	policy := txpool.OriginPolicy{
+		Allowlist: []thor.Address{},
+		Blocklist: []thor.Address{thor.BytesToAddress([]byte("spam"))},
+	}
+	res, _ := httpDo(t, http.MethodPut, "/admin/txpool/origin-policy", token, policy)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, body := httpDo(t, http.MethodGet, "/admin/txpool/origin-policy", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var got txpool.OriginPolicy
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, policy, got)
+}
+
+func cancelTx(t *testing.T) {
+	acc := genesis.DevAccounts()[0]
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(100).
+		Gas(21000).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), acc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+	assert.Nil(t, pool.AddLocal(trx))
+
+	var result map[string]bool
+	for _, pending := range []bool{true, false} {
+		res, body := httpDo(t, http.MethodDelete, "/admin/txpool/txs/"+trx.ID().String(), token, nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		if err := json.Unmarshal(body, &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, pending, result["pending"])
+	}
+}
+
+func logLevels(t *testing.T) {
+	levels := loglevel.Levels{Global: "warn", Modules: map[string]string{"txpool": "dbug"}}
+	res, _ := httpDo(t, http.MethodPut, "/admin/log-levels", token, levels)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, body := httpDo(t, http.MethodGet, "/admin/log-levels", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var got loglevel.Levels
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, levels, got)
+
+	res, _ = httpDo(t, http.MethodPut, "/admin/log-levels", token, loglevel.Levels{Global: "loud"})
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func reload(t *testing.T) {
+	res, _ := httpDo(t, http.MethodPost, "/admin/reload", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 1, reloaded)
+}
+
+func listJobs(t *testing.T) {
+	res, body := httpDo(t, http.MethodGet, "/admin/jobs", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var status []jobs.Status
+	if err := json.Unmarshal(body, &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(status))
+	assert.Equal(t, "noop", status[0].Name)
+
+	res, _ = httpDo(t, http.MethodPost, "/admin/jobs/none/run", token, nil)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	res, _ = httpDo(t, http.MethodPost, "/admin/jobs/noop/run", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func flushTxPool(t *testing.T) {
+	acc := genesis.DevAccounts()[1]
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(100).
+		Gas(21000).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), acc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, pool.Add(trx.WithSignature(sig)))
+
+	res, body := httpDo(t, http.MethodPost, "/admin/txpool/flush", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var result map[string]int
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, result["dropped"])
+	assert.Equal(t, 0, len(pool.Dump()))
+}
+
+func peers(t *testing.T) {
+	res, body := httpDo(t, http.MethodGet, "/admin/peers", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var stats []map[string]interface{}
+	if err := json.Unmarshal(body, &stats); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(stats))
+
+	key, _ := crypto.GenerateKey()
+	node := discover.NewNode(discover.PubkeyID(&key.PublicKey), []byte{127, 0, 0, 1}, 11235, 11235)
+	peer := map[string]string{"url": node.String()}
+	res, _ = httpDo(t, http.MethodPost, "/admin/peers", token, peer)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.True(t, network.static[node.ID])
+
+	res, _ = httpDo(t, http.MethodDelete, "/admin/peers", token, peer)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.False(t, network.static[node.ID])
+
+	res, _ = httpDo(t, http.MethodPost, "/admin/peers", token, map[string]string{"url": "invalid"})
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func head(t *testing.T) {
+	res, body := httpDo(t, http.MethodGet, "/admin/chain/head", token, nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var info admin.HeadInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, c.BestBlock().Header().ID(), info.ID)
+	assert.Equal(t, uint32(0), info.Number)
+	assert.Equal(t, 1, info.Peers)
+}
+
+func initAdminServer(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ = chain.New(db, b)
+	pool = txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+
+	scheduler = jobs.New(func() bool { return true })
+	scheduler.Add(jobs.Job{Name: "noop", Interval: time.Hour, Run: func(context.Context) error { return nil }})
+
+	router := mux.NewRouter()
+	admin.New(c, pool, network, loglevel.NewHandler(log15.DiscardHandler(), log15.LvlInfo), func() error {
+		reloaded++
+		return nil
+	}, scheduler, token).Mount(router, "/admin")
+	ts = httptest.NewServer(router)
+}
+
+func httpDo(t *testing.T, method, path, token string, obj interface{}) (*http.Response, []byte) {
+	var data []byte
+	if obj != nil {
+		var err error
+		if data, err = json.Marshal(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, body
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import "github.com/vechain/thor/thor"
+
+// HeadInfo summarizes the chain head and the node around it.
+type HeadInfo struct {
+	ID         thor.Bytes32 `json:"id"`
+	Number     uint32       `json:"number"`
+	Timestamp  uint64       `json:"timestamp"`
+	TotalScore uint64       `json:"totalScore"`
+	TxsInPool  int          `json:"txsInPool"`
+	Peers      int          `json:"peers"`
+}
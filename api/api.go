@@ -8,6 +8,7 @@ package api
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	"github.com/gorilla/handlers"
@@ -18,19 +19,25 @@ import (
 	"github.com/vechain/thor/api/doc"
 	"github.com/vechain/thor/api/events"
 	"github.com/vechain/thor/api/eventslegacy"
+	"github.com/vechain/thor/api/graphql"
+	"github.com/vechain/thor/api/health"
 	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/api/stats"
 	"github.com/vechain/thor/api/subscriptions"
 	"github.com/vechain/thor/api/transactions"
 	"github.com/vechain/thor/api/transfers"
 	"github.com/vechain/thor/api/transferslegacy"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/txpool"
 )
 
-//New return api router
-func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool, logDB *logdb.LogDB, nw node.Network, allowedOrigins string, backtraceLimit uint32, callGasLimit uint64) (http.HandlerFunc, func()) {
+//New return api router.
+//APIs are served under version prefix, and unversioned paths are kept as deprecated aliases until legacySunset (never if zero).
+func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool, logDB *logdb.LogDB, nw node.Network, allowedOrigins string, backtraceLimit uint32, callGasLimit uint64, maxBlockRange uint32, legacySunset time.Time, filterLimits utils.FilterLimits, gc node.GCInfo, execLimiter *utils.ExecLimiter, db kv.Getter, readiness health.Options, version string) (http.HandlerFunc, func()) {
 	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
 	for i, o := range origins {
 		origins[i] = strings.ToLower(strings.TrimSpace(o))
@@ -38,6 +45,8 @@ func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool,
 
 	router := mux.NewRouter()
 
+	// openapi doc generated from routes
+	router.Path("/doc/openapi.json").Methods(http.MethodGet).HandlerFunc(doc.Handler(router, currentVersionPrefix))
 	// to serve api doc and swagger-ui
 	router.PathPrefix("/doc").Handler(
 		http.StripPrefix("/doc/", http.FileServer(
@@ -52,33 +61,46 @@ func New(chain *chain.Chain, stateCreator *state.Creator, txPool *txpool.TxPool,
 			http.Redirect(w, req, "doc/swagger-ui/", http.StatusTemporaryRedirect)
 		})
 
-	accounts.New(chain, stateCreator, callGasLimit).
-		Mount(router, "/accounts")
-	eventslegacy.New(logDB).
-		Mount(router, "/events")
-	transferslegacy.New(logDB).
-		Mount(router, "/transfers")
-	eventslegacy.New(logDB).
-		Mount(router, "/logs/events")
-	events.New(logDB).
-		Mount(router, "/logs/event")
-	transferslegacy.New(logDB).
-		Mount(router, "/logs/transfers")
-	transfers.New(logDB).
-		Mount(router, "/logs/transfer")
-	blocks.New(chain).
-		Mount(router, "/blocks")
-	transactions.New(chain, txPool).
-		Mount(router, "/transactions")
-	debug.New(chain, stateCreator).
-		Mount(router, "/debug")
-	node.New(nw).
-		Mount(router, "/node")
-	subs := subscriptions.New(chain, origins, backtraceLimit)
-	subs.Mount(router, "/subscriptions")
+	v1 := router.PathPrefix(currentVersionPrefix).Subrouter()
+
+	accounts.New(chain, stateCreator, callGasLimit, execLimiter).
+		Mount(v1, "/accounts")
+	eventslegacy.New(logDB, filterLimits).
+		Mount(v1, "/events")
+	transferslegacy.New(logDB, filterLimits).
+		Mount(v1, "/transfers")
+	eventslegacy.New(logDB, filterLimits).
+		Mount(v1, "/logs/events")
+	events.New(logDB, filterLimits).
+		Mount(v1, "/logs/event")
+	transferslegacy.New(logDB, filterLimits).
+		Mount(v1, "/logs/transfers")
+	transfers.New(logDB, filterLimits).
+		Mount(v1, "/logs/transfer")
+	blocks.New(chain, maxBlockRange).
+		Mount(v1, "/blocks")
+	transactions.New(chain, stateCreator, txPool, execLimiter).
+		Mount(v1, "/transactions")
+	debug.New(chain, stateCreator, execLimiter).
+		Mount(v1, "/debug")
+	node.New(nw, chain, stateCreator, txPool, filterLimits, gc, version, db).
+		Mount(v1, "/node")
+	stats.New(chain, stateCreator, db).
+		Mount(v1, "/stats")
+	graphql.New(chain, stateCreator, logDB, filterLimits, execLimiter).
+		Mount(v1, "/graphql")
+	subs := subscriptions.New(chain, txPool, origins, backtraceLimit)
+	subs.Mount(v1, "/subscriptions")
+
+	// probes are unversioned, as they are for infrastructure rather than clients
+	health.New(chain, db, nw, readiness).
+		Mount(router)
+
+	// compatibility layer for unversioned paths
+	router.PathPrefix("/").Handler(newCompatHandler(router, legacySunset))
 
 	return handlers.CORS(
 			handlers.AllowedOrigins(origins),
-			handlers.AllowedHeaders([]string{"content-type"}))(router).ServeHTTP,
+			handlers.AllowedHeaders([]string{"content-type", "x-api-key"}))(router).ServeHTTP,
 		subs.Close // subscriptions handles hijacked conns, which need to be closed
 }
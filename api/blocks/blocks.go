@@ -6,6 +6,8 @@
 package blocks
 
 import (
+	"encoding/hex"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -19,12 +21,15 @@ import (
 )
 
 type Blocks struct {
-	chain *chain.Chain
+	chain    *chain.Chain
+	maxRange uint32
 }
 
-func New(chain *chain.Chain) *Blocks {
+// New creates blocks API. maxRange limits the number of blocks returned by a range query.
+func New(chain *chain.Chain, maxRange uint32) *Blocks {
 	return &Blocks{
 		chain,
+		maxRange,
 	}
 }
 
@@ -33,28 +38,227 @@ func (b *Blocks) handleGetBlock(w http.ResponseWriter, req *http.Request) error
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "revision"))
 	}
-	block, err := b.getBlock(revision)
+	if req.URL.Query().Get("raw") == "true" {
+		return b.writeRawBlock(w, revision)
+	}
+	summary, err := b.getBlockSummary(revision)
 	if err != nil {
 		if b.chain.IsNotFound(err) {
 			return utils.WriteJSON(w, nil)
 		}
 		return err
 	}
-	isTrunk, err := b.isTrunk(block.Header().ID(), block.Header().Number())
+	isTrunk, err := b.isTrunk(summary.Header.ID(), summary.Header.Number())
 	if err != nil {
 		return err
 	}
-	blk, err := convertBlock(block, isTrunk)
+	blk, err := convertBlock(summary, isTrunk)
 	if err != nil {
 		return err
 	}
-	return utils.WriteJSON(w, blk)
+	return utils.WriteJSONFields(w, blk, req.URL.Query().Get("fields"))
+}
+
+func (b *Blocks) handleGetBlockReceipts(w http.ResponseWriter, req *http.Request) error {
+	revision, err := b.parseRevision(mux.Vars(req)["revision"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	blk, err := b.getBlock(revision)
+	if err != nil {
+		if b.chain.IsNotFound(err) {
+			return utils.WriteJSON(w, nil)
+		}
+		return err
+	}
+	txs := blk.Transactions()
+	receipts, err := b.chain.GetBlockReceipts(blk.Header().ID())
+	if err != nil {
+		if !b.chain.IsNotFound(err) {
+			return err
+		}
+		// genesis block has no receipts
+		receipts = nil
+	}
+	results := make([]*Receipt, 0, len(receipts))
+	for i, r := range receipts {
+		receipt, err := convertReceipt(r, blk.Header(), txs[i], uint64(i))
+		if err != nil {
+			return err
+		}
+		results = append(results, receipt)
+	}
+	return utils.WriteJSONFields(w, results, req.URL.Query().Get("fields"))
+}
+
+// handleGetBlockRange returns trunk blocks numbered from 'from' to 'to' inclusively, truncated to
+// maxRange blocks and to the best block. Txs are included if expanded.
+func (b *Blocks) handleGetBlockRange(w http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	if query.Get("from") == "" {
+		return utils.BadRequest(errors.New("from: required"))
+	}
+	from, err := parseUint(query.Get("from"), 0)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "from"))
+	}
+	to, err := parseUint(query.Get("to"), math.MaxUint32)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "to"))
+	}
+	if to < from {
+		return utils.BadRequest(errors.New("to: less than from"))
+	}
+	expanded := query.Get("expanded")
+	if expanded != "" && expanded != "false" && expanded != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "expanded"))
+	}
+
+	// resolve all blocks against the same best block, to be consistent with concurrent reorgs
+	best := b.chain.BestBlock().Header()
+	if to > uint64(best.Number()) {
+		to = uint64(best.Number())
+	}
+	if b.maxRange > 0 && to-from >= uint64(b.maxRange) {
+		to = from + uint64(b.maxRange) - 1
+	}
+	results := make([]interface{}, 0)
+	for num := from; num <= to; num++ {
+		id, err := b.chain.GetAncestorBlockID(best.ID(), uint32(num))
+		if err != nil {
+			return err
+		}
+		summary, err := b.chain.GetBlockSummary(id)
+		if err != nil {
+			return err
+		}
+		blk, err := convertBlock(summary, true)
+		if err != nil {
+			return err
+		}
+		if expanded != "true" {
+			results = append(results, blk)
+			continue
+		}
+		body, err := b.chain.GetBlockBody(id)
+		if err != nil {
+			return err
+		}
+		expandedBlk := &ExpandedBlock{
+			Block:        *blk,
+			Transactions: make([]*Transaction, 0, len(body.Txs)),
+		}
+		for i, tx := range body.Txs {
+			t, err := convertTransaction(tx, uint64(i))
+			if err != nil {
+				return err
+			}
+			expandedBlk.Transactions = append(expandedBlk.Transactions, t)
+		}
+		results = append(results, expandedBlk)
+	}
+	return utils.WriteJSON(w, results)
+}
+
+// max number of txs listed per page
+const maxTxPageSize = 100
+
+func parseUint(str string, def uint64) (uint64, error) {
+	if str == "" {
+		return def, nil
+	}
+	return strconv.ParseUint(str, 0, 64)
+}
+
+func (b *Blocks) handleGetBlockTransactions(w http.ResponseWriter, req *http.Request) error {
+	revision, err := b.parseRevision(mux.Vars(req)["revision"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	query := req.URL.Query()
+	offset, err := parseUint(query.Get("offset"), 0)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "offset"))
+	}
+	limit, err := parseUint(query.Get("limit"), maxTxPageSize)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "limit"))
+	}
+	if limit > maxTxPageSize {
+		return utils.BadRequest(errors.Errorf("limit: exceeds %v", maxTxPageSize))
+	}
+	expanded := query.Get("expanded")
+	if expanded != "" && expanded != "false" && expanded != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "expanded"))
+	}
+
+	summary, err := b.getBlockSummary(revision)
+	if err != nil {
+		if b.chain.IsNotFound(err) {
+			return utils.WriteJSON(w, nil)
+		}
+		return err
+	}
+	total := uint64(len(summary.TxIDs))
+	from, to := total, total
+	if offset < total {
+		from = offset
+		if limit < total-offset {
+			to = offset + limit
+		}
+	}
+	page := &TxPage{
+		Total:        len(summary.TxIDs),
+		Transactions: make([]interface{}, 0, to-from),
+	}
+	if expanded != "true" {
+		for _, id := range summary.TxIDs[from:to] {
+			page.Transactions = append(page.Transactions, id)
+		}
+		return utils.WriteJSON(w, page)
+	}
+	for i := from; i < to; i++ {
+		tx, err := b.chain.GetTransaction(summary.Header.ID(), i)
+		if err != nil {
+			return err
+		}
+		t, err := convertTransaction(tx, i)
+		if err != nil {
+			return err
+		}
+		page.Transactions = append(page.Transactions, t)
+	}
+	return utils.WriteJSON(w, page)
+}
+
+// writeRawBlock streams the rlp encoded block as hex, without buffering the whole block.
+func (b *Blocks) writeRawBlock(w http.ResponseWriter, revision interface{}) error {
+	blk, err := b.getBlock(revision)
+	if err != nil {
+		if b.chain.IsNotFound(err) {
+			return utils.WriteJSON(w, nil)
+		}
+		return err
+	}
+
+	w.Header().Set("Content-Type", utils.JSONContentType)
+	if _, err := io.WriteString(w, `{"raw":"0x`); err != nil {
+		return err
+	}
+	if _, err := blk.WriteTo(hex.NewEncoder(w)); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\"}\n")
+	return err
 }
 
 func (b *Blocks) parseRevision(revision string) (interface{}, error) {
 	if revision == "" || revision == "best" {
 		return nil, nil
 	}
+	if revision == "finalized" {
+		return b.chain.Finalized().ID(), nil
+	}
 	if len(revision) == 66 || len(revision) == 64 {
 		blockID, err := thor.ParseBytes32(revision)
 		if err != nil {
@@ -83,6 +287,17 @@ func (b *Blocks) getBlock(revision interface{}) (*block.Block, error) {
 	}
 }
 
+func (b *Blocks) getBlockSummary(revision interface{}) (*block.Summary, error) {
+	switch revision.(type) {
+	case thor.Bytes32:
+		return b.chain.GetBlockSummary(revision.(thor.Bytes32))
+	case uint32:
+		return b.chain.GetTrunkBlockSummary(revision.(uint32))
+	default:
+		return b.chain.GetBlockSummary(b.chain.BestBlock().Header().ID())
+	}
+}
+
 func (b *Blocks) isTrunk(blkID thor.Bytes32, blkNum uint32) (bool, error) {
 	best := b.chain.BestBlock()
 	ancestorID, err := b.chain.GetAncestorBlockID(best.Header().ID(), blkNum)
@@ -94,6 +309,9 @@ func (b *Blocks) isTrunk(blkID thor.Bytes32, blkNum uint32) (bool, error) {
 
 func (b *Blocks) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
+	sub.Path("").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(b.handleGetBlockRange))
 	sub.Path("/{revision}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(b.handleGetBlock))
+	sub.Path("/{revision}/receipts").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(b.handleGetBlockReceipts))
+	sub.Path("/{revision}/transactions").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(b.handleGetBlockTransactions))
 
 }
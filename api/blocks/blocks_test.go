@@ -71,6 +71,98 @@ func TestBlock(t *testing.T) {
 	checkBlock(t, blk, rb)
 	assert.Equal(t, http.StatusOK, statusCode)
 
+	res, statusCode = httpGet(t, ts.URL+"/blocks/finalized")
+	var finalized map[string]interface{}
+	if err := json.Unmarshal(res, &finalized); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, float64(0), finalized["number"], "nothing finalized yet")
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks/1?fields=number,id")
+	var partial map[string]interface{}
+	if err := json.Unmarshal(res, &partial); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, len(partial))
+	assert.Equal(t, blk.Header().ID().String(), partial["id"])
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks/1/receipts")
+	var receipts []*blocks.Receipt
+	if err := json.Unmarshal(res, &receipts); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, len(blk.Transactions()), len(receipts))
+	for i, r := range receipts {
+		assert.Equal(t, blk.Transactions()[i].ID(), r.Meta.TxID)
+		assert.Equal(t, uint64(i), r.TxIndex)
+	}
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks/1/transactions")
+	var ids struct {
+		Total        int            `json:"total"`
+		Transactions []thor.Bytes32 `json:"transactions"`
+	}
+	if err := json.Unmarshal(res, &ids); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, len(blk.Transactions()), ids.Total)
+	assert.Equal(t, []thor.Bytes32{blk.Transactions()[0].ID()}, ids.Transactions)
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks/1/transactions?expanded=true&offset=0&limit=1")
+	var expanded struct {
+		Total        int                   `json:"total"`
+		Transactions []*blocks.Transaction `json:"transactions"`
+	}
+	if err := json.Unmarshal(res, &expanded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1, len(expanded.Transactions))
+	assert.Equal(t, blk.Transactions()[0].ID(), expanded.Transactions[0].ID)
+	assert.Equal(t, uint64(0), expanded.Transactions[0].Index)
+	assert.Equal(t, genesis.DevAccounts()[0].Address, expanded.Transactions[0].Origin)
+
+	// offset beyond the end
+	res, statusCode = httpGet(t, ts.URL+"/blocks/1/transactions?offset=10")
+	if err := json.Unmarshal(res, &ids); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 0, len(ids.Transactions))
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks?from=0&to=100")
+	var rng []*blocks.Block
+	if err := json.Unmarshal(res, &rng); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, len(rng), "truncated to best block")
+	assert.Equal(t, uint32(0), rng[0].Number)
+	checkBlock(t, blk, rng[1])
+
+	res, statusCode = httpGet(t, ts.URL+"/blocks?from=1&expanded=true")
+	var expandedRng []*blocks.ExpandedBlock
+	if err := json.Unmarshal(res, &expandedRng); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1, len(expandedRng))
+	assert.Equal(t, blk.Header().ID(), expandedRng[0].ID)
+	assert.Equal(t, blk.Transactions()[0].ID(), expandedRng[0].Transactions[0].ID)
+
+	_, statusCode = httpGet(t, ts.URL+"/blocks")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "from required")
+	_, statusCode = httpGet(t, ts.URL+"/blocks?from=2&to=1")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad range")
+
+	_, statusCode = httpGet(t, ts.URL+"/blocks/1/transactions?limit=1000")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "limit too large")
+	_, statusCode = httpGet(t, ts.URL+"/blocks/1/transactions?expanded=yes")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad expanded")
 }
 
 func initBlockServer(t *testing.T) {
@@ -120,7 +212,7 @@ func initBlockServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	router := mux.NewRouter()
-	blocks.New(chain).Mount(router, "/blocks")
+	blocks.New(chain, 0).Mount(router, "/blocks")
 	ts = httptest.NewServer(router)
 	blk = block
 }
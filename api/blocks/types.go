@@ -6,8 +6,12 @@
 package blocks
 
 import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/api/transactions"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
 )
 
 //Block block
@@ -29,21 +33,16 @@ type Block struct {
 	Transactions []thor.Bytes32 `json:"transactions"`
 }
 
-func convertBlock(b *block.Block, isTrunk bool) (*Block, error) {
-	if b == nil {
+func convertBlock(summary *block.Summary, isTrunk bool) (*Block, error) {
+	if summary == nil {
 		return nil, nil
 	}
-	signer, err := b.Header().Signer()
+	header := summary.Header
+	signer, err := header.Signer()
 	if err != nil {
 		return nil, err
 	}
-	txs := b.Transactions()
-	txIds := make([]thor.Bytes32, len(txs))
-	for i, tx := range txs {
-		txIds[i] = tx.ID()
-	}
 
-	header := b.Header()
 	return &Block{
 		Number:       header.Number(),
 		ID:           header.ID(),
@@ -54,11 +53,90 @@ func convertBlock(b *block.Block, isTrunk bool) (*Block, error) {
 		GasUsed:      header.GasUsed(),
 		Beneficiary:  header.Beneficiary(),
 		Signer:       signer,
-		Size:         uint32(b.Size()),
+		Size:         uint32(summary.Size),
 		StateRoot:    header.StateRoot(),
 		ReceiptsRoot: header.ReceiptsRoot(),
 		TxsRoot:      header.TxsRoot(),
 		IsTrunk:      isTrunk,
-		Transactions: txIds,
+		Transactions: summary.TxIDs,
 	}, nil
 }
+
+// Clause clause of tx.
+type Clause struct {
+	To    *thor.Address         `json:"to"`
+	Value *math.HexOrDecimal256 `json:"value"`
+	Data  string                `json:"data"`
+}
+
+// Transaction tx in block, along with its index.
+type Transaction struct {
+	ID           thor.Bytes32        `json:"id"`
+	Index        uint64              `json:"index"`
+	ChainTag     byte                `json:"chainTag"`
+	BlockRef     string              `json:"blockRef"`
+	Expiration   uint32              `json:"expiration"`
+	Clauses      []*Clause           `json:"clauses"`
+	GasPriceCoef uint8               `json:"gasPriceCoef"`
+	Gas          uint64              `json:"gas"`
+	Origin       thor.Address        `json:"origin"`
+	Nonce        math.HexOrDecimal64 `json:"nonce"`
+	DependsOn    *thor.Bytes32       `json:"dependsOn"`
+	Size         uint32              `json:"size"`
+}
+
+// ExpandedBlock block with full txs instead of tx IDs.
+type ExpandedBlock struct {
+	Block
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// TxPage a page of txs in block. Items of Transactions are IDs, or Transaction objects if expanded.
+type TxPage struct {
+	Total        int           `json:"total"`
+	Transactions []interface{} `json:"transactions"`
+}
+
+func convertTransaction(tx *tx.Transaction, index uint64) (*Transaction, error) {
+	origin, err := tx.Signer()
+	if err != nil {
+		return nil, err
+	}
+	clauses := make([]*Clause, 0, len(tx.Clauses()))
+	for _, c := range tx.Clauses() {
+		clauses = append(clauses, &Clause{
+			To:    c.To(),
+			Value: (*math.HexOrDecimal256)(c.Value()),
+			Data:  hexutil.Encode(c.Data()),
+		})
+	}
+	br := tx.BlockRef()
+	return &Transaction{
+		ID:           tx.ID(),
+		Index:        index,
+		ChainTag:     tx.ChainTag(),
+		BlockRef:     hexutil.Encode(br[:]),
+		Expiration:   tx.Expiration(),
+		Clauses:      clauses,
+		GasPriceCoef: tx.GasPriceCoef(),
+		Gas:          tx.Gas(),
+		Origin:       origin,
+		Nonce:        math.HexOrDecimal64(tx.Nonce()),
+		DependsOn:    tx.DependsOn(),
+		Size:         uint32(tx.Size()),
+	}, nil
+}
+
+// Receipt receipt of a tx in block, along with the tx index.
+type Receipt struct {
+	*transactions.Receipt
+	TxIndex uint64 `json:"txIndex"`
+}
+
+func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transaction, index uint64) (*Receipt, error) {
+	receipt, err := transactions.ConvertReceipt(txReceipt, header, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &Receipt{receipt, index}, nil
+}
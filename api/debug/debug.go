@@ -13,10 +13,12 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/consensus"
 	"github.com/vechain/thor/runtime"
@@ -28,14 +30,16 @@ import (
 )
 
 type Debug struct {
-	chain  *chain.Chain
-	stateC *state.Creator
+	chain       *chain.Chain
+	stateC      *state.Creator
+	execLimiter *utils.ExecLimiter
 }
 
-func New(chain *chain.Chain, stateC *state.Creator) *Debug {
+func New(chain *chain.Chain, stateC *state.Creator, execLimiter *utils.ExecLimiter) *Debug {
 	return &Debug{
 		chain,
 		stateC,
+		execLimiter,
 	}
 }
 
@@ -54,6 +58,10 @@ func (d *Debug) handleTxEnv(ctx context.Context, blockID thor.Bytes32, txIndex u
 	if clauseIndex >= uint64(len(txs[txIndex].Clauses())) {
 		return nil, nil, utils.Forbidden(errors.New("clause index out of range"))
 	}
+	// txs are replayed upon the parent state
+	if err := d.stateC.CheckPruned(block.Header().Number() - 1); err != nil {
+		return nil, nil, err
+	}
 	rt, err := consensus.New(d.chain, d.stateC).NewRuntimeForReplay(block.Header())
 	if err != nil {
 		return nil, nil, err
@@ -99,6 +107,10 @@ func (d *Debug) traceTransaction(ctx context.Context, tracer vm.Tracer, blockID
 	if err != nil {
 		return nil, err
 	}
+	return tracerResult(tracer, gasUsed, output)
+}
+
+func tracerResult(tracer vm.Tracer, gasUsed uint64, output *runtime.Output) (interface{}, error) {
 	switch tr := tracer.(type) {
 	case *vm.StructLogger:
 		return &ExecutionResult{
@@ -114,6 +126,54 @@ func (d *Debug) traceTransaction(ctx context.Context, tracer vm.Tracer, blockID
 	}
 }
 
+func newTracer(name string) (vm.Tracer, error) {
+	if name == "" {
+		return vm.NewStructLogger(nil), nil
+	}
+	if !strings.HasSuffix(name, "Tracer") {
+		name += "Tracer"
+	}
+	code, ok := tracers.CodeByName(name)
+	if !ok {
+		return nil, utils.BadRequest(errors.New("name: unsupported tracer"))
+	}
+	return tracers.New(code)
+}
+
+//trace all clauses of an existed transaction, each with a new tracer
+func (d *Debug) traceWholeTransaction(ctx context.Context, tracerName string, blockID thor.Bytes32, txIndex uint64) (*TxTraceResult, error) {
+	rt, txExec, err := d.handleTxEnv(ctx, blockID, txIndex, 0)
+	if err != nil {
+		return nil, err
+	}
+	block, err := d.chain.GetBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	result := &TxTraceResult{
+		TxID:    block.Transactions()[txIndex].ID(),
+		Clauses: []interface{}{},
+	}
+	// clauses after a reverted one are not executed
+	for txExec.HasNextClause() {
+		tracer, err := newTracer(tracerName)
+		if err != nil {
+			return nil, err
+		}
+		rt.SetVMConfig(vm.Config{Debug: true, Tracer: tracer})
+		gasUsed, output, err := txExec.NextClause()
+		if err != nil {
+			return nil, err
+		}
+		res, err := tracerResult(tracer, gasUsed, output)
+		if err != nil {
+			return nil, err
+		}
+		result.Clauses = append(result.Clauses, res)
+	}
+	return result, nil
+}
+
 func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request) error {
 	var opt *TracerOption
 	if err := utils.ParseJSON(req.Body, &opt); err != nil {
@@ -122,23 +182,21 @@ func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request)
 	if opt == nil {
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
-	var tracer vm.Tracer
-	if opt.Name == "" {
-		tracer = vm.NewStructLogger(nil)
-	} else {
-		name := opt.Name
-		if !strings.HasSuffix(name, "Tracer") {
-			name += "Tracer"
-		}
-		code, ok := tracers.CodeByName(name)
-		if !ok {
-			return utils.BadRequest(errors.New("name: unsupported tracer"))
+	// target without clause index stands for the whole tx
+	if strings.Count(opt.Target, "/") < 2 {
+		blockID, txIndex, err := d.parseTxTarget(opt.Target)
+		if err != nil {
+			return err
 		}
-		tr, err := tracers.New(code)
+		res, err := d.traceWholeTransaction(req.Context(), opt.Name, blockID, txIndex)
 		if err != nil {
 			return err
 		}
-		tracer = tr
+		return utils.WriteJSON(w, res)
+	}
+	tracer, err := newTracer(opt.Name)
+	if err != nil {
+		return err
 	}
 	blockID, txIndex, clauseIndex, err := d.parseTarget(opt.Target)
 	if err != nil {
@@ -213,6 +271,111 @@ func (d *Debug) handleDebugStorage(w http.ResponseWriter, req *http.Request) err
 	return utils.WriteJSON(w, res)
 }
 
+func (d *Debug) stateDiff(from, to *block.Header) ([]*AccountDiff, error) {
+	var fromRoot thor.Bytes32
+	var fromTime uint64
+	if from != nil {
+		if err := d.stateC.CheckPruned(from.Number()); err != nil {
+			return nil, err
+		}
+		fromRoot, fromTime = from.StateRoot(), from.Timestamp()
+	}
+	if err := d.stateC.CheckPruned(to.Number()); err != nil {
+		return nil, err
+	}
+	diffs, err := d.stateC.Diff(fromRoot, to.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*AccountDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		ad := &AccountDiff{
+			AddressHash: diff.AddressHash,
+			Address:     diff.Address,
+			From:        convertAccountState(diff.From, fromTime),
+			To:          convertAccountState(diff.To, to.Timestamp()),
+			Storage:     make([]*StorageDiff, 0, len(diff.Storage)),
+		}
+		for _, sd := range diff.Storage {
+			fromValue, err := decodeStorageValue(sd.From)
+			if err != nil {
+				return nil, err
+			}
+			toValue, err := decodeStorageValue(sd.To)
+			if err != nil {
+				return nil, err
+			}
+			ad.Storage = append(ad.Storage, &StorageDiff{KeyHash: sd.KeyHash, Key: sd.Key, From: fromValue, To: toValue})
+		}
+		result = append(result, ad)
+	}
+	return result, nil
+}
+
+func convertAccountState(a *state.Account, blockTime uint64) *AccountState {
+	if a == nil {
+		return nil
+	}
+	return &AccountState{
+		Balance:     math.HexOrDecimal256(*a.Balance),
+		Energy:      math.HexOrDecimal256(*a.CalcEnergy(blockTime)),
+		Master:      thor.BytesToAddress(a.Master),
+		CodeHash:    thor.BytesToBytes32(a.CodeHash),
+		StorageRoot: thor.BytesToBytes32(a.StorageRoot),
+	}
+}
+
+func decodeStorageValue(data []byte) (*thor.Bytes32, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	_, content, _, err := rlp.Split(data)
+	if err != nil {
+		return nil, err
+	}
+	v := thor.BytesToBytes32(content)
+	return &v, nil
+}
+
+func (d *Debug) handleStateDiff(w http.ResponseWriter, req *http.Request) error {
+	var opt *StateDiffOption
+	if err := utils.ParseJSON(req.Body, &opt); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if opt == nil {
+		return utils.BadRequest(errors.New("body: empty body"))
+	}
+	getHeader := func(id thor.Bytes32) (*block.Header, error) {
+		header, err := d.chain.GetBlockHeader(id)
+		if err != nil {
+			if d.chain.IsNotFound(err) {
+				return nil, utils.Forbidden(errors.New("block not found"))
+			}
+			return nil, err
+		}
+		return header, nil
+	}
+	to, err := getHeader(opt.To)
+	if err != nil {
+		return err
+	}
+	var from *block.Header
+	if opt.From != nil {
+		if from, err = getHeader(*opt.From); err != nil {
+			return err
+		}
+	} else if to.Number() > 0 {
+		if from, err = getHeader(to.ParentID()); err != nil {
+			return err
+		}
+	}
+	res, err := d.stateDiff(from, to)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, res)
+}
+
 func (d *Debug) parseTarget(target string) (blockID thor.Bytes32, txIndex uint64, clauseIndex uint64, err error) {
 	parts := strings.Split(target, "/")
 	if len(parts) != 3 {
@@ -222,37 +385,72 @@ func (d *Debug) parseTarget(target string) (blockID thor.Bytes32, txIndex uint64
 	if err != nil {
 		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
 	}
-	if len(parts[1]) == 64 || len(parts[1]) == 66 {
-		txID, err := thor.ParseBytes32(parts[1])
+	if txIndex, err = d.parseTxIndex(blockID, parts[1]); err != nil {
+		return thor.Bytes32{}, 0, 0, err
+	}
+	clauseIndex, err = strconv.ParseUint(parts[2], 0, 0)
+	if err != nil {
+		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[2]"))
+	}
+	return
+}
+
+// parseTxTarget parses target of a whole tx, in format 'blockID/(txIndex|txId)' or 'txId'.
+// Tx of the latter is looked up on the trunk.
+func (d *Debug) parseTxTarget(target string) (blockID thor.Bytes32, txIndex uint64, err error) {
+	parts := strings.Split(target, "/")
+	if len(parts) == 1 {
+		txID, err := thor.ParseBytes32(parts[0])
 		if err != nil {
-			return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
+			return thor.Bytes32{}, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
 		}
-		txMeta, err := d.chain.GetTransactionMeta(txID, blockID)
+		txMeta, err := d.chain.GetTrunkTransactionMeta(txID)
 		if err != nil {
 			if d.chain.IsNotFound(err) {
-				return thor.Bytes32{}, 0, 0, utils.Forbidden(errors.New("transaction not found"))
+				return thor.Bytes32{}, 0, utils.Forbidden(errors.New("transaction not found"))
 			}
-			return thor.Bytes32{}, 0, 0, err
+			return thor.Bytes32{}, 0, err
+		}
+		return txMeta.BlockID, txMeta.Index, nil
+	}
+	blockID, err = thor.ParseBytes32(parts[0])
+	if err != nil {
+		return thor.Bytes32{}, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
+	}
+	if txIndex, err = d.parseTxIndex(blockID, parts[1]); err != nil {
+		return thor.Bytes32{}, 0, err
+	}
+	return
+}
+
+// parseTxIndex parses tx part of target, which is either index or ID of the tx in the block.
+func (d *Debug) parseTxIndex(blockID thor.Bytes32, str string) (uint64, error) {
+	if len(str) == 64 || len(str) == 66 {
+		txID, err := thor.ParseBytes32(str)
+		if err != nil {
+			return 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
 		}
-		txIndex = txMeta.Index
-	} else {
-		i, err := strconv.ParseUint(parts[1], 0, 0)
+		txMeta, err := d.chain.GetTransactionMeta(txID, blockID)
 		if err != nil {
-			return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
+			if d.chain.IsNotFound(err) {
+				return 0, utils.Forbidden(errors.New("transaction not found"))
+			}
+			return 0, err
 		}
-		txIndex = i
+		return txMeta.Index, nil
 	}
-	clauseIndex, err = strconv.ParseUint(parts[2], 0, 0)
+	i, err := strconv.ParseUint(str, 0, 0)
 	if err != nil {
-		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[2]"))
+		return 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
 	}
-	return
+	return i, nil
 }
 
 func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
-	sub.Path("/tracers").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleTraceTransaction))
-	sub.Path("/storage-range").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleDebugStorage))
+	sub.Path("/tracers").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.execLimiter.Wrap(d.handleTraceTransaction)))
+	sub.Path("/storage-range").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.execLimiter.Wrap(d.handleDebugStorage)))
+	sub.Path("/state-diff").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.execLimiter.Wrap(d.handleStateDiff)))
 
 }
@@ -14,6 +14,12 @@ type TracerOption struct {
 	Target string `json:"target"`
 }
 
+// TxTraceResult results of tracing all clauses of a tx, in clause order.
+type TxTraceResult struct {
+	TxID    thor.Bytes32  `json:"txID"`
+	Clauses []interface{} `json:"clauses"`
+}
+
 type ExecutionResult struct {
 	Gas         uint64         `json:"gas"`
 	Failed      bool           `json:"failed"`
@@ -88,3 +94,34 @@ type StorageEntry struct {
 	Key   *thor.Bytes32 `json:"key"`
 	Value *thor.Bytes32 `json:"value"`
 }
+
+type StateDiffOption struct {
+	From *thor.Bytes32 `json:"from"` // the parent of 'to' if omitted
+	To   thor.Bytes32  `json:"to"`
+}
+
+// AccountDiff an account changed between two blocks.
+type AccountDiff struct {
+	AddressHash thor.Bytes32   `json:"addressHash"`
+	Address     *thor.Address  `json:"address"` // nil if the preimage not recorded
+	From        *AccountState  `json:"from"`    // nil if not existed
+	To          *AccountState  `json:"to"`      // nil if deleted
+	Storage     []*StorageDiff `json:"storage"`
+}
+
+// AccountState account state with energy calculated at the block time.
+type AccountState struct {
+	Balance     math.HexOrDecimal256 `json:"balance"`
+	Energy      math.HexOrDecimal256 `json:"energy"`
+	Master      thor.Address         `json:"master"`
+	CodeHash    thor.Bytes32         `json:"codeHash"`
+	StorageRoot thor.Bytes32         `json:"storageRoot"`
+}
+
+// StorageDiff a storage slot changed between two blocks.
+type StorageDiff struct {
+	KeyHash thor.Bytes32  `json:"keyHash"`
+	Key     *thor.Bytes32 `json:"key"`  // nil if the preimage not recorded
+	From    *thor.Bytes32 `json:"from"` // nil if not set
+	To      *thor.Bytes32 `json:"to"`   // nil if cleared
+}
@@ -116,7 +116,7 @@ func swaggerUiFavicon32x32Png() (*asset, error) {
 	return a, nil
 }
 
-var _swaggerUiIndexHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x54\x4f\x4f\xdc\x3e\x10\xbd\xef\xa7\x18\xcc\x01\xf8\x09\x27\x3f\x40\x42\x55\x9a\xe4\x40\x69\x55\x24\xaa\x22\xc1\x1e\xaa\xaa\xaa\x9c\x78\x92\x9d\xe2\xd8\x2b\xdb\xd9\x3f\xa0\x7e\xf7\x2a\x4e\x76\x03\x5d\x04\x52\xb5\x87\x8c\xe7\xbd\x79\x1e\x3f\x8f\x37\xdd\xe3\x1c\x3e\xdf\x7d\xb9\x86\xca\x58\x70\x5e\x78\x2a\x41\x92\xf3\x96\x8a\xd6\x93\xd1\x50\xb4\x5a\x2a\x84\xa2\x25\x25\x81\xf3\x7c\x92\xee\x5d\x7e\xfd\x70\xf7\xed\xe6\x23\xcc\x7c\xa3\xf2\x49\xda\x7d\x40\x09\x5d\x67\x0c\x35\xcb\x27\x00\xe9\x0c\x85\xec\x02\x80\xb4\x41\x2f\xa0\x9c\x09\xeb\xd0\x67\x6c\x7a\xf7\x89\xbf\x63\x03\xe4\xc9\x2b\xcc\x6f\x97\xa2\xae\xd1\xc2\xf4\x2a\x8d\xfb\x4c\x8f\x2a\xd2\xf7\x60\x51\x65\xcc\xf9\xb5\x42\x37\x43\xf4\x0c\xfc\x7a\x8e\x19\xf3\xb8\xf2\x71\xe9\x1c\x83\x99\xc5\x2a\x63\x51\xec\x7a\x15\xde\x52\x14\xf2\x3b\x22\x54\x1a\xbd\x29\xa7\x46\xd4\x18\xcf\x75\x3d\xd6\x57\x62\xd1\x31\xf8\xd9\xe9\xea\xec\x34\x0a\x90\xa3\x07\x74\x19\x0b\x19\x06\xf1\xbf\x2a\x9e\x9c\xaf\x4e\xce\x9f\x29\x86\xcc\xa8\x18\x8e\xd7\xc7\x10\x3c\x1d\xc2\xc7\xe1\x0b\x50\x98\x15\x77\xf4\x40\xba\x4e\xa0\x30\x56\xa2\xe5\x85\x59\xbd\xdf\xe2\x66\x81\xb6\x52\x66\x99\x00\x6f\xcc\x03\x77\xa5\x35\x4a\x15\xc2\x3a\xbe\x40\xeb\xa9\x14\x6a\x97\xcb\xd7\x09\xf4\xc4\x0d\xf6\x7b\x32\x04\xff\x1d\x6f\x82\xa4\xc0\xca\x58\x1c\xd7\xa2\xf2\x68\x5f\xed\x8f\xf4\x0c\x2d\xf9\x1d\xd1\xc2\xc8\xf5\x4e\x61\x23\x6c\x4d\x3a\xf9\x7f\x6c\xaf\x10\xe5\x7d\x6d\x4d\xab\x65\x02\xfb\x95\xe8\x7e\xa3\x54\xb0\x2b\xde\xfa\x95\xc6\xfd\x9c\x75\x61\x27\x3f\xf8\x29\x69\x01\x24\x33\x36\x8e\x04\xcb\xd3\x58\xd2\x22\x9f\x0c\x86\x97\x96\xe6\x1e\x9c\x2d\x9f\x4f\x0e\xef\x67\x3d\xfa\xe5\x58\xde\xed\x13\x68\xf9\x1b\x35\xce\x0b\x2d\x85\x32\x1a\xf9\xdc\xa2\x43\xff\x4a\x79\xbf\x58\x92\x96\x66\x19\x19\xad\x8c\x90\x90\x41\xd5\xea\xb2\x7b\x6a\x87\x47\xf0\xb8\x71\x2b\x8e\xe1\x22\xbc\x38\x01\x6e\xed\x3c\x36\x43\xbe\x34\xda\x79\x68\x09\x32\x18\xde\xcd\xf4\xea\x22\x74\x7d\x38\xba\xda\x5a\x95\x00\x8b\xa2\xd8\xcf\x8c\x8d\xd6\xa2\x51\xec\x78\x0b\x4a\xd3\xfc\x24\x99\xc0\xc1\xfe\x78\x88\x83\x27\x30\xe2\xfc\x9a\xf4\x7d\xb8\x4b\x6f\x5b\x1c\xa1\xfe\x78\x2e\x81\xef\xdb\x14\xfc\xdd\x45\x34\x90\x22\x31\x27\x77\xfc\x12\xef\x76\xeb\xd7\x4d\xa0\x6e\x39\x3f\x9e\xec\xa4\xda\x9a\xf4\x5b\x3b\xf5\xa4\xe8\xd2\x2c\x83\x93\x53\xab\x5e\xd2\x52\x62\x6d\x5a\x9f\x00\x1b\x37\xbe\x0e\x29\xb6\x19\xab\xa3\x8d\xe9\xc3\xc5\x04\x77\x5b\x9a\x6c\x66\xee\xe9\x55\xa6\x71\x3f\x68\x69\xdc\xff\xf9\xfd\x09\x00\x00\xff\xff\xfc\xa6\x9c\x83\x40\x05\x00\x00")
+var _swaggerUiIndexHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x9d\x54\x4d\x4f\xdc\x30\x10\xbd\xef\xaf\x18\xcc\x01\xa8\x70\x52\x40\x42\x55\x9a\xe4\x40\x69\x55\xa4\xad\x8a\x04\x7b\xa8\xaa\xaa\x72\x92\x49\xd6\xc5\xb1\x23\xdb\x61\x77\xa9\xfa\xdf\xeb\x38\xd9\x04\xba\x08\xa4\x2a\x07\x8f\xe7\x7b\x9e\xdf\x24\xde\xa3\x14\x3e\xdf\x7e\x99\x43\xa9\x34\x18\xcb\x2c\xcf\xa1\xe0\xc6\x6a\x9e\xb5\x96\x2b\x09\x59\x2b\x0b\x81\xee\xe0\xa2\x00\x4a\xd3\x59\xbc\x77\xf9\xf5\xc3\xed\xb7\xeb\x8f\xb0\xb4\xb5\x70\xf7\xee\x00\xc1\x64\x95\x10\x94\x24\x9d\x01\xc4\x4b\x64\x45\x27\x38\xb1\x46\xcb\x20\x5f\x32\x6d\xd0\x26\x64\x71\xfb\x89\xbe\x23\x83\xc9\x72\x2b\x30\xbd\x59\xb1\xaa\x42\x0d\x8b\xab\x38\xec\x35\xbd\x55\x70\x79\x07\x1a\x45\x42\x8c\xdd\x08\x34\x4b\x44\x4b\xc0\x6e\x1a\x4c\x88\xc5\xb5\x0d\x73\x63\x08\x2c\x35\x96\x09\x09\x42\xd3\x67\xa1\x2d\x0f\xbc\x7e\x27\x09\xcf\x95\xdc\x86\xf3\x9a\x55\x18\x36\xb2\x9a\xe2\x4b\x76\xdf\x79\xd0\xb3\xd3\xf5\xd9\x69\xe0\x4d\x86\x3f\xa0\x49\x88\xd7\x10\x08\xff\x37\xe3\xc9\xf9\xfa\xe4\xfc\x49\x46\xaf\x99\x32\xfa\xf1\x7a\x19\x3c\xa6\x83\xf8\x7b\x38\x01\x32\xb5\xa6\x2e\x96\xcb\x2a\x72\xb2\x2e\xdc\x9c\x4e\xf5\x7e\xb4\xab\x7b\xd4\xa5\x50\xab\x08\x68\xad\x1e\xa8\xc9\xb5\x12\x22\x73\x90\x53\x67\x70\x2f\xca\xc4\xae\x2f\xdd\x44\xd0\x3b\x6e\x6d\x7f\x66\x83\xf0\xe6\x78\x2b\x44\x19\x3a\x5e\xe0\x74\x67\xa5\x45\xfd\x62\x7f\x5c\x2e\x51\x73\xbb\x93\x34\x53\xc5\x66\x27\xb0\x66\xba\xe2\x32\x7a\x3b\xb5\x97\xb1\xfc\xae\xd2\xca\x91\x2e\x82\xfd\x92\x75\xdf\x94\xca\xc3\x15\x8e\x78\xc5\x61\xcf\xb3\x4e\xec\xd2\x0f\x78\x16\xfc\x1e\x78\xe1\x68\x33\x52\x82\xa4\x71\xe8\xb4\xe9\x6c\x00\x3c\xd7\xbc\xb1\x60\x74\xfe\x94\x39\xb4\xe7\x7a\xf0\xcb\x90\xb4\xab\xe3\xdd\xd2\x57\x62\xdc\xce\xc8\x82\x09\x25\x91\x36\x1a\x1d\xc9\x5f\x08\xef\x2f\x2b\x2e\x0b\xb5\x0a\x94\x14\x8a\x15\x90\x40\xd9\xca\xbc\x5b\xb5\xc3\x23\x87\xcc\x30\x6b\x18\xc2\x85\xdf\x38\x06\x66\x63\x2c\xd6\x83\xde\x11\xca\x58\x68\xb9\x0b\x1b\xf6\x66\x71\x75\xe1\xbb\x3e\x9c\x50\x6d\xb5\x88\x80\x04\x41\xa8\x1a\x94\xac\xe1\xae\x23\xc7\xd4\xe3\xd1\x5e\xa8\xfa\x27\x77\xf8\x1e\xec\x4f\x73\x1c\x3c\x32\x23\x36\x73\xc7\x72\xff\x9c\x56\xb7\x38\x99\xfa\x09\x4d\x04\xdf\x47\x15\xfc\xdb\x48\x30\x38\x05\xae\xb2\x39\x7e\xce\xef\x66\x84\xec\xda\xbb\x8e\x3e\x3f\x1e\x55\x12\xad\x63\xc6\x6b\x95\x7a\xa7\xe0\x52\xad\x3c\x98\x0b\x2d\x9e\xcb\x25\xd8\x46\xb5\xd6\x41\x32\x15\x9e\x7b\x15\xd9\x32\xeb\x68\x8b\xfb\xf0\x36\x1e\xe0\x96\xcf\xb6\xb4\x7b\xfc\x9a\x71\xd8\x73\xcd\xb1\xcf\xff\xff\xfe\x02\x57\x4a\x51\x76\x43\x05\x00\x00")
 
 func swaggerUiIndexHtmlBytes() ([]byte, error) {
 	return bindataRead(
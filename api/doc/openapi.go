@@ -0,0 +1,181 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// methods probed on routes, as mux exposes no accessor of method matchers
+var probedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch}
+
+var pathVarPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Generate builds the OpenAPI document of routes registered on router under prefix.
+// Paths and methods come from route definitions, so the document always matches what is served.
+// Operations described in thor.yaml take those descriptions, and others get stubs derived from routes.
+func Generate(router *mux.Router, prefix string) ([]byte, error) {
+	var base map[interface{}]interface{}
+	if err := yaml.Unmarshal(MustAsset("thor.yaml"), &base); err != nil {
+		return nil, err
+	}
+	spec := toJSONValue(base).(map[string]interface{})
+	described, _ := spec["paths"].(map[string]interface{})
+
+	paths := make(map[string]interface{})
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		re, err := route.GetPathRegexp()
+		if err != nil || !strings.HasSuffix(re, "$") {
+			// prefix routes are mount points rather than endpoints
+			return nil
+		}
+		if !strings.HasPrefix(tpl, prefix+"/") {
+			return nil
+		}
+		path := pathVarPattern.ReplaceAllString(strings.TrimPrefix(tpl, prefix), "{$1}")
+
+		methods := routeMethods(route, pathVarPattern.ReplaceAllString(tpl, "0"))
+		if len(methods) == 0 {
+			return nil
+		}
+
+		desc, _ := described[path].(map[string]interface{})
+		item, _ := paths[path].(map[string]interface{})
+		if item == nil {
+			item = make(map[string]interface{})
+			// path level fields like parameters
+			for k, v := range desc {
+				if !isMethod(k) {
+					item[k] = v
+				}
+			}
+			paths[path] = item
+		}
+		for _, m := range methods {
+			if _, ok := item[m]; ok {
+				continue
+			}
+			if op, ok := desc[m]; ok {
+				item[m] = op
+			} else {
+				item[m] = stubOperation(path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spec["paths"] = paths
+	spec["servers"] = []interface{}{
+		map[string]interface{}{"url": prefix, "description": "local thor node"},
+	}
+	return json.Marshal(spec)
+}
+
+// Handler serves the OpenAPI document generated by Generate.
+// The document is generated at the first request, when all routes are registered.
+func Handler(router *mux.Router, prefix string) http.HandlerFunc {
+	var (
+		once sync.Once
+		data []byte
+		err  error
+	)
+	return func(w http.ResponseWriter, req *http.Request) {
+		once.Do(func() {
+			data, err = Generate(router, prefix)
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(data)
+	}
+}
+
+// routeMethods returns lower-cased methods the route accepts. A route accepting any method is
+// taken as GET only.
+func routeMethods(route *mux.Route, samplePath string) []string {
+	var methods []string
+	for _, m := range probedMethods {
+		req, err := http.NewRequest(m, samplePath, nil)
+		if err != nil {
+			return nil
+		}
+		if route.Match(req, &mux.RouteMatch{}) {
+			methods = append(methods, strings.ToLower(m))
+		}
+	}
+	if len(methods) == len(probedMethods) {
+		return []string{"get"}
+	}
+	return methods
+}
+
+func stubOperation(path string) map[string]interface{} {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	op := map[string]interface{}{
+		"tags": []interface{}{strings.Title(segs[0])},
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{"description": "OK"},
+		},
+	}
+	var params []interface{}
+	for _, m := range pathVarPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	return op
+}
+
+func isMethod(key string) bool {
+	for _, m := range probedMethods {
+		if strings.ToLower(m) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// toJSONValue converts value decoded from yaml to be json marshalable.
+func toJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = toJSONValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = toJSONValue(val)
+		}
+		return s
+	}
+	return v
+}
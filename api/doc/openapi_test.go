@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package doc_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/doc"
+)
+
+func TestGenerate(t *testing.T) {
+	noop := func(http.ResponseWriter, *http.Request) {}
+
+	router := mux.NewRouter()
+	router.PathPrefix("/doc").HandlerFunc(noop)
+	v1 := router.PathPrefix("/v1").Subrouter()
+	accounts := v1.PathPrefix("/accounts").Subrouter()
+	accounts.Path("/{address}").Methods(http.MethodGet).HandlerFunc(noop)
+	accounts.Path("/{address}").Methods(http.MethodPost).HandlerFunc(noop)
+	v1.PathPrefix("/widgets").Subrouter().
+		Path("/{id:[0-9]+}/parts").Methods(http.MethodDelete).HandlerFunc(noop)
+
+	data, err := doc.Generate(router, "/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]struct {
+			Tags       []string `json:"tags"`
+			Summary    string   `json:"summary"`
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEmpty(t, spec.OpenAPI)
+	assert.Equal(t, "/v1", spec.Servers[0].URL)
+	assert.Equal(t, 2, len(spec.Paths), "only registered endpoints")
+
+	account := spec.Paths["/accounts/{address}"]
+	assert.Equal(t, 2, len(account))
+	assert.NotEmpty(t, account["get"].Summary, "described in thor.yaml")
+	assert.NotEmpty(t, account["post"].Summary, "described in thor.yaml")
+
+	parts := spec.Paths["/widgets/{id}/parts"]
+	assert.Equal(t, 1, len(parts))
+	assert.Equal(t, []string{"Widgets"}, parts["delete"].Tags)
+	assert.Equal(t, "id", parts["delete"].Parameters[0].Name)
+	assert.Equal(t, "path", parts["delete"].Parameters[0].In)
+}
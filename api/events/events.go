@@ -16,21 +16,32 @@ import (
 )
 
 type Events struct {
-	db *logdb.LogDB
+	db     *logdb.LogDB
+	limits utils.FilterLimits
 }
 
-func New(db *logdb.LogDB) *Events {
+func New(db *logdb.LogDB, limits utils.FilterLimits) *Events {
 	return &Events{
 		db,
+		limits,
 	}
 }
 
 //Filter query events with option
 func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*FilteredEvent, error) {
-	events, err := e.db.FilterEvents(ctx, convertEventFilter(ef))
+	filter := convertEventFilter(ef)
+	opts, err := e.limits.Narrow(filter.Range, filter.Options)
 	if err != nil {
 		return nil, err
 	}
+	filter.Options = opts
+	events, err := e.db.FilterEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.limits.CheckResults(len(events)); err != nil {
+		return nil, err
+	}
 	fes := make([]*FilteredEvent, len(events))
 	for i, e := range events {
 		fes[i] = convertEvent(e)
@@ -43,6 +54,11 @@ func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
 	if err := utils.ParseJSON(req.Body, &filter); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
+	switch filter.Order {
+	case "", logdb.ASC, logdb.DESC:
+	default:
+		return utils.BadRequest(errors.New("order: should be one of [asc, desc]"))
+	}
 	fes, err := e.filter(req.Context(), &filter)
 	if err != nil {
 		return err
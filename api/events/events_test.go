@@ -16,6 +16,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/api/events"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/thor"
@@ -29,6 +30,8 @@ func TestEvents(t *testing.T) {
 	initEventServer(t)
 	defer ts.Close()
 	getEvents(t)
+	getEventsPaged(t)
+	getEventsBadOrder(t)
 }
 
 func getEvents(t *testing.T) {
@@ -68,6 +71,47 @@ func getEvents(t *testing.T) {
 	}
 	assert.Equal(t, limit, len(logs), "should be `limit` logs")
 }
+
+func getEventsPaged(t *testing.T) {
+	filter := &events.EventFilter{
+		Range: &logdb.Range{
+			Unit: logdb.Block,
+			From: 10,
+			To:   19,
+		},
+		Options: &logdb.Options{
+			Offset: 2,
+			Limit:  3,
+		},
+		Order: logdb.DESC,
+		CriteriaSet: []*events.EventCriteria{
+			&events.EventCriteria{
+				Address: &contractAddr,
+			},
+		},
+	}
+	res := httpPost(t, ts.URL+"/logs/event", filter)
+	var logs []*events.FilteredEvent
+	if err := json.Unmarshal(res, &logs); err != nil {
+		t.Fatal(err)
+	}
+	var nums []uint32
+	for _, log := range logs {
+		nums = append(nums, log.Meta.BlockNumber)
+	}
+	assert.Equal(t, []uint32{17, 16, 15}, nums)
+}
+
+func getEventsBadOrder(t *testing.T) {
+	data, _ := json.Marshal(&events.EventFilter{Order: "random"})
+	res, err := http.Post(ts.URL+"/logs/event", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
 func initEventServer(t *testing.T) {
 	db, err := logdb.NewMem()
 	if err != nil {
@@ -91,7 +135,7 @@ func initEventServer(t *testing.T) {
 	}
 
 	router := mux.NewRouter()
-	events.New(db).Mount(router, "/logs/event")
+	events.New(db, utils.FilterLimits{}).Mount(router, "/logs/event")
 	ts = httptest.NewServer(router)
 }
 
@@ -17,22 +17,32 @@ import (
 )
 
 type EventsLegacy struct {
-	db *logdb.LogDB
+	db     *logdb.LogDB
+	limits utils.FilterLimits
 }
 
-func New(db *logdb.LogDB) *EventsLegacy {
+func New(db *logdb.LogDB, limits utils.FilterLimits) *EventsLegacy {
 	return &EventsLegacy{
 		db,
+		limits,
 	}
 }
 
 //Filter query events with option
 func (e *EventsLegacy) filter(ctx context.Context, filter *FilterLegacy) ([]*FilteredEvent, error) {
 	f := convertFilter(filter)
+	opts, err := e.limits.Narrow(f.Range, f.Options)
+	if err != nil {
+		return nil, err
+	}
+	f.Options = opts
 	events, err := e.db.FilterEvents(ctx, f)
 	if err != nil {
 		return nil, err
 	}
+	if err := e.limits.CheckResults(len(events)); err != nil {
+		return nil, err
+	}
 	fes := make([]*FilteredEvent, len(events))
 	for i, e := range events {
 		fes[i] = convertEvent(e)
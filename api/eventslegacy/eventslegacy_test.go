@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/api/events"
 	"github.com/vechain/thor/api/eventslegacy"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/thor"
@@ -88,7 +89,7 @@ func initEventServer(t *testing.T) {
 	}
 
 	router := mux.NewRouter()
-	eventslegacy.New(db).Mount(router, "/logs/events")
+	eventslegacy.New(db, utils.FilterLimits{}).Mount(router, "/logs/events")
 	ts = httptest.NewServer(router)
 }
 
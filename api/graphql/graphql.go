@@ -0,0 +1,69 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package graphql serves blocks, transactions, receipts, accounts and logs through GraphQL,
+// so that nested data can be fetched in one round trip.
+package graphql
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
+)
+
+const (
+	maxQueryDepth  = 10
+	maxParallelism = 10
+)
+
+type GraphQL struct {
+	schema      *gql.Schema
+	execLimiter *utils.ExecLimiter
+}
+
+type queryParams struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func New(chain *chain.Chain, stateCreator *state.Creator, logDB *logdb.LogDB, limits utils.FilterLimits, execLimiter *utils.ExecLimiter) *GraphQL {
+	root := &resolver{
+		chain:        chain,
+		stateCreator: stateCreator,
+		logDB:        logDB,
+		limits:       limits,
+	}
+	return &GraphQL{
+		gql.MustParseSchema(schema, root,
+			gql.MaxDepth(maxQueryDepth),
+			gql.MaxParallelism(maxParallelism)),
+		execLimiter,
+	}
+}
+
+func (g *GraphQL) handleQuery(w http.ResponseWriter, req *http.Request) error {
+	var params queryParams
+	if err := utils.ParseJSON(req.Body, &params); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if params.Query == "" {
+		return utils.BadRequest(errors.New("query: required"))
+	}
+	// errors of resolvers are carried in the response
+	return utils.WriteJSON(w, g.schema.Exec(req.Context(), params.Query, params.OperationName, params.Variables))
+}
+
+func (g *GraphQL) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(g.execLimiter.Wrap(g.handleQuery)))
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/graphql"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+var (
+	recipient = thor.BytesToAddress([]byte("to"))
+	blk       *block.Block
+	ts        *httptest.Server
+)
+
+type result struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func TestGraphQL(t *testing.T) {
+	initServer(t)
+	defer ts.Close()
+
+	var nested struct {
+		Block struct {
+			Number       uint32 `json:"number"`
+			ID           string `json:"id"`
+			IsTrunk      bool   `json:"isTrunk"`
+			Transactions []struct {
+				ID      string `json:"id"`
+				Clauses []struct {
+					To    string `json:"to"`
+					Value string `json:"value"`
+				} `json:"clauses"`
+				Receipt struct {
+					GasUsed  uint64 `json:"gasUsed"`
+					Reverted bool   `json:"reverted"`
+				} `json:"receipt"`
+			} `json:"transactions"`
+		} `json:"block"`
+	}
+	query(t, `{ block(revision: "1") { number id isTrunk transactions { id clauses { to value } receipt { gasUsed reverted } } } }`, &nested)
+	assert.Equal(t, uint32(1), nested.Block.Number)
+	assert.Equal(t, blk.Header().ID().String(), nested.Block.ID)
+	assert.True(t, nested.Block.IsTrunk)
+	assert.Equal(t, 1, len(nested.Block.Transactions))
+	assert.Equal(t, blk.Transactions()[0].ID().String(), nested.Block.Transactions[0].ID)
+	assert.Equal(t, recipient.String(), nested.Block.Transactions[0].Clauses[0].To)
+	assert.Equal(t, "0x2710", nested.Block.Transactions[0].Clauses[0].Value)
+	assert.Equal(t, uint64(21000), nested.Block.Transactions[0].Receipt.GasUsed)
+	assert.False(t, nested.Block.Transactions[0].Receipt.Reverted)
+
+	var acc struct {
+		Before struct {
+			Balance string `json:"balance"`
+		} `json:"before"`
+		After struct {
+			Balance string `json:"balance"`
+			HasCode bool   `json:"hasCode"`
+		} `json:"after"`
+	}
+	query(t, `{ before: account(address: "`+recipient.String()+`", revision: "0") { balance }
+		after: account(address: "`+recipient.String()+`") { balance hasCode } }`, &acc)
+	assert.Equal(t, "0x0", acc.Before.Balance)
+	assert.Equal(t, "0x2710", acc.After.Balance)
+	assert.False(t, acc.After.HasCode)
+
+	var missing struct {
+		Block *struct{} `json:"block"`
+	}
+	query(t, `{ block(revision: "100") { id } }`, &missing)
+	assert.Nil(t, missing.Block)
+
+	res := post(t, map[string]interface{}{"query": `{ account(address: "invalid") { balance } }`})
+	assert.NotEmpty(t, res.Errors, "invalid address should be reported")
+
+	data, _ := json.Marshal(map[string]interface{}{})
+	resp, err := http.Post(ts.URL+"/graphql", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "query is required")
+}
+
+func query(t *testing.T, q string, v interface{}) {
+	res := post(t, map[string]interface{}{"query": q})
+	if len(res.Errors) > 0 {
+		t.Fatal(res.Errors)
+	}
+	if err := json.Unmarshal(res.Data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func post(t *testing.T, body interface{}) *result {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(ts.URL+"/graphql", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	r, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var res result
+	if err := json.Unmarshal(r, &res); err != nil {
+		t.Fatal(err)
+	}
+	return &res
+}
+
+func initServer(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	gene := genesis.NewDevnet()
+
+	b, _, err := gene.Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b)
+	cla := tx.NewClause(&recipient).WithValue(big.NewInt(10000))
+	tx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		GasPriceCoef(1).
+		Expiration(10).
+		Gas(21000).
+		Nonce(1).
+		Clause(cla).
+		BlockRef(tx.NewBlockRef(0)).
+		Build()
+
+	sig, err := crypto.Sign(tx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx = tx.WithSignature(sig)
+	packer := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address)
+	flow, err := packer.Schedule(b.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := flow.Adopt(tx); err != nil {
+		t.Fatal(err)
+	}
+	block, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(block, receipts); err != nil {
+		t.Fatal(err)
+	}
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := mux.NewRouter()
+	graphql.New(chain, stateC, logDB, utils.FilterLimits{}, nil).Mount(router, "/graphql")
+	ts = httptest.NewServer(router)
+	blk = block
+}
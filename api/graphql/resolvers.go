@@ -0,0 +1,527 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package graphql
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// Long is the scalar of unsigned 64-bit integer, serialized as JSON number.
+type Long uint64
+
+// ImplementsGraphQLType maps the type to the scalar in schema.
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL accepts number or numeric string.
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case int32:
+		if v < 0 {
+			return errors.New("negative value")
+		}
+		*l = Long(v)
+	case float64:
+		if v < 0 || v > math.MaxUint64 || v != math.Trunc(v) {
+			return errors.New("not an unsigned integer")
+		}
+		*l = Long(v)
+	case string:
+		n, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return err
+		}
+		*l = Long(n)
+	default:
+		return errors.New("wrong type")
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l Long) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, uint64(l), 10), nil
+}
+
+func encodeBig(v *big.Int) string {
+	return (*hexutil.Big)(v).String()
+}
+
+// resolver is the root resolver.
+type resolver struct {
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	logDB        *logdb.LogDB
+	limits       utils.FilterLimits
+}
+
+func (r *resolver) getHeader(revision *string) (*block.Header, error) {
+	if revision == nil || *revision == "" || *revision == "best" {
+		return r.chain.BestBlock().Header(), nil
+	}
+	if len(*revision) == 66 || len(*revision) == 64 {
+		id, err := thor.ParseBytes32(*revision)
+		if err != nil {
+			return nil, errors.WithMessage(err, "revision")
+		}
+		return r.chain.GetBlockHeader(id)
+	}
+	n, err := strconv.ParseUint(*revision, 0, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "revision")
+	}
+	if n > math.MaxUint32 {
+		return nil, errors.WithMessage(errors.New("block number out of max uint32"), "revision")
+	}
+	return r.chain.GetTrunkBlockHeader(uint32(n))
+}
+
+func (r *resolver) Block(args struct{ Revision *string }) (*blockResolver, error) {
+	header, err := r.getHeader(args.Revision)
+	if err != nil {
+		if r.chain.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &blockResolver{r, header}, nil
+}
+
+func (r *resolver) Transaction(args struct{ ID string }) (*txResolver, error) {
+	txID, err := thor.ParseBytes32(args.ID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "id")
+	}
+	meta, err := r.chain.GetTransactionMeta(txID, r.chain.BestBlock().Header().ID())
+	if err != nil {
+		if r.chain.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.newTxResolver(meta.BlockID, meta.Index)
+}
+
+func (r *resolver) newTxResolver(blockID thor.Bytes32, index uint64) (*txResolver, error) {
+	header, err := r.chain.GetBlockHeader(blockID)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.chain.GetTransaction(blockID, index)
+	if err != nil {
+		return nil, err
+	}
+	return &txResolver{r, tx, header, index}, nil
+}
+
+func (r *resolver) Account(args struct {
+	Address  string
+	Revision *string
+}) (*accountResolver, error) {
+	addr, err := thor.ParseAddress(args.Address)
+	if err != nil {
+		return nil, errors.WithMessage(err, "address")
+	}
+	header, err := r.getHeader(args.Revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.stateCreator.CheckPruned(header.Number()); err != nil {
+		return nil, err
+	}
+	st, err := r.stateCreator.NewReadOnly(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	return &accountResolver{addr, header, st}, nil
+}
+
+type eventFilterInput struct {
+	Address *string
+	Topic0  *string
+	Topic1  *string
+	Topic2  *string
+	Topic3  *string
+	Topic4  *string
+	From    *Long
+	To      *Long
+	Offset  *Long
+	Limit   *Long
+	Order   *string
+}
+
+func (in *eventFilterInput) convert() (*logdb.EventFilter, error) {
+	criteria := &logdb.EventCriteria{}
+	if in.Address != nil {
+		addr, err := thor.ParseAddress(*in.Address)
+		if err != nil {
+			return nil, errors.WithMessage(err, "address")
+		}
+		criteria.Address = &addr
+	}
+	for i, t := range []*string{in.Topic0, in.Topic1, in.Topic2, in.Topic3, in.Topic4} {
+		if t == nil {
+			continue
+		}
+		topic, err := thor.ParseBytes32(*t)
+		if err != nil {
+			return nil, errors.WithMessage(err, "topic"+strconv.Itoa(i))
+		}
+		criteria.Topics[i] = &topic
+	}
+
+	filter := &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{criteria},
+		Range:       &logdb.Range{Unit: logdb.Block, To: math.MaxUint32},
+		Options:     &logdb.Options{Limit: math.MaxUint32},
+	}
+	if in.From != nil {
+		filter.Range.From = uint64(*in.From)
+	}
+	if in.To != nil {
+		filter.Range.To = uint64(*in.To)
+	}
+	if in.Offset != nil {
+		filter.Options.Offset = uint64(*in.Offset)
+	}
+	if in.Limit != nil {
+		filter.Options.Limit = uint64(*in.Limit)
+	}
+	if in.Order != nil {
+		switch order := logdb.Order(*in.Order); order {
+		case logdb.ASC, logdb.DESC:
+			filter.Order = order
+		default:
+			return nil, errors.New("order: should be one of [asc, desc]")
+		}
+	}
+	return filter, nil
+}
+
+func (r *resolver) Events(ctx context.Context, args struct{ Filter eventFilterInput }) ([]*eventResolver, error) {
+	filter, err := args.Filter.convert()
+	if err != nil {
+		return nil, err
+	}
+	if filter.Options, err = r.limits.Narrow(filter.Range, filter.Options); err != nil {
+		return nil, err
+	}
+	events, err := r.logDB.FilterEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.limits.CheckResults(len(events)); err != nil {
+		return nil, err
+	}
+	resolvers := make([]*eventResolver, 0, len(events))
+	for _, ev := range events {
+		resolvers = append(resolvers, &eventResolver{r, ev})
+	}
+	return resolvers, nil
+}
+
+type blockResolver struct {
+	root   *resolver
+	header *block.Header
+}
+
+func (b *blockResolver) Number() Long         { return Long(b.header.Number()) }
+func (b *blockResolver) ID() string           { return b.header.ID().String() }
+func (b *blockResolver) ParentID() string     { return b.header.ParentID().String() }
+func (b *blockResolver) Timestamp() Long      { return Long(b.header.Timestamp()) }
+func (b *blockResolver) GasLimit() Long       { return Long(b.header.GasLimit()) }
+func (b *blockResolver) Beneficiary() string  { return b.header.Beneficiary().String() }
+func (b *blockResolver) GasUsed() Long        { return Long(b.header.GasUsed()) }
+func (b *blockResolver) TotalScore() Long     { return Long(b.header.TotalScore()) }
+func (b *blockResolver) TxsRoot() string      { return b.header.TxsRoot().String() }
+func (b *blockResolver) StateRoot() string    { return b.header.StateRoot().String() }
+func (b *blockResolver) ReceiptsRoot() string { return b.header.ReceiptsRoot().String() }
+
+func (b *blockResolver) Signer() (string, error) {
+	signer, err := b.header.Signer()
+	if err != nil {
+		return "", err
+	}
+	return signer.String(), nil
+}
+
+func (b *blockResolver) Size() (int32, error) {
+	summary, err := b.root.chain.GetBlockSummary(b.header.ID())
+	if err != nil {
+		return 0, err
+	}
+	return int32(summary.Size), nil
+}
+
+func (b *blockResolver) IsTrunk() (bool, error) {
+	id, err := b.root.chain.GetAncestorBlockID(b.root.chain.BestBlock().Header().ID(), b.header.Number())
+	if err != nil {
+		return false, err
+	}
+	return id == b.header.ID(), nil
+}
+
+func (b *blockResolver) Transactions() ([]*txResolver, error) {
+	body, err := b.root.chain.GetBlockBody(b.header.ID())
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*txResolver, 0, len(body.Txs))
+	for i, tx := range body.Txs {
+		resolvers = append(resolvers, &txResolver{b.root, tx, b.header, uint64(i)})
+	}
+	return resolvers, nil
+}
+
+type txResolver struct {
+	root   *resolver
+	tx     *tx.Transaction
+	header *block.Header
+	index  uint64
+}
+
+func (t *txResolver) ID() string          { return t.tx.ID().String() }
+func (t *txResolver) ChainTag() int32     { return int32(t.tx.ChainTag()) }
+func (t *txResolver) Expiration() int32   { return int32(t.tx.Expiration()) }
+func (t *txResolver) GasPriceCoef() int32 { return int32(t.tx.GasPriceCoef()) }
+func (t *txResolver) Gas() Long           { return Long(t.tx.Gas()) }
+func (t *txResolver) Nonce() string       { return hexutil.EncodeUint64(t.tx.Nonce()) }
+func (t *txResolver) Size() int32         { return int32(t.tx.Size()) }
+
+func (t *txResolver) BlockRef() string {
+	br := t.tx.BlockRef()
+	return hexutil.Encode(br[:])
+}
+
+func (t *txResolver) Origin() (string, error) {
+	origin, err := t.tx.Signer()
+	if err != nil {
+		return "", err
+	}
+	return origin.String(), nil
+}
+
+func (t *txResolver) DependsOn() *string {
+	if dep := t.tx.DependsOn(); dep != nil {
+		str := dep.String()
+		return &str
+	}
+	return nil
+}
+
+func (t *txResolver) Clauses() []*clauseResolver {
+	resolvers := make([]*clauseResolver, 0, len(t.tx.Clauses()))
+	for _, c := range t.tx.Clauses() {
+		resolvers = append(resolvers, &clauseResolver{c})
+	}
+	return resolvers
+}
+
+func (t *txResolver) Block() *blockResolver {
+	return &blockResolver{t.root, t.header}
+}
+
+func (t *txResolver) Receipt() (*receiptResolver, error) {
+	receipt, err := t.root.chain.GetTransactionReceipt(t.header.ID(), t.index)
+	if err != nil {
+		if t.root.chain.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &receiptResolver{t.tx, receipt}, nil
+}
+
+type clauseResolver struct {
+	clause *tx.Clause
+}
+
+func (c *clauseResolver) To() *string {
+	if to := c.clause.To(); to != nil {
+		str := to.String()
+		return &str
+	}
+	return nil
+}
+
+func (c *clauseResolver) Value() string { return encodeBig(c.clause.Value()) }
+func (c *clauseResolver) Data() string  { return hexutil.Encode(c.clause.Data()) }
+
+type receiptResolver struct {
+	tx      *tx.Transaction
+	receipt *tx.Receipt
+}
+
+func (r *receiptResolver) GasUsed() Long    { return Long(r.receipt.GasUsed) }
+func (r *receiptResolver) GasPayer() string { return r.receipt.GasPayer.String() }
+func (r *receiptResolver) Paid() string     { return encodeBig(r.receipt.Paid) }
+func (r *receiptResolver) Reward() string   { return encodeBig(r.receipt.Reward) }
+func (r *receiptResolver) Reverted() bool   { return r.receipt.Reverted }
+
+func (r *receiptResolver) Outputs() []*outputResolver {
+	resolvers := make([]*outputResolver, 0, len(r.receipt.Outputs))
+	for i, output := range r.receipt.Outputs {
+		var contractAddr *thor.Address
+		if r.tx.Clauses()[i].To() == nil {
+			addr := thor.CreateContractAddress(r.tx.ID(), uint32(i), 0)
+			contractAddr = &addr
+		}
+		resolvers = append(resolvers, &outputResolver{contractAddr, output})
+	}
+	return resolvers
+}
+
+type outputResolver struct {
+	contractAddr *thor.Address
+	output       *tx.Output
+}
+
+func (o *outputResolver) ContractAddress() *string {
+	if o.contractAddr != nil {
+		str := o.contractAddr.String()
+		return &str
+	}
+	return nil
+}
+
+func (o *outputResolver) Events() []*receiptEventResolver {
+	resolvers := make([]*receiptEventResolver, 0, len(o.output.Events))
+	for _, ev := range o.output.Events {
+		resolvers = append(resolvers, &receiptEventResolver{ev})
+	}
+	return resolvers
+}
+
+func (o *outputResolver) Transfers() []*transferResolver {
+	resolvers := make([]*transferResolver, 0, len(o.output.Transfers))
+	for _, tr := range o.output.Transfers {
+		resolvers = append(resolvers, &transferResolver{tr})
+	}
+	return resolvers
+}
+
+type receiptEventResolver struct {
+	event *tx.Event
+}
+
+func (e *receiptEventResolver) Address() string { return e.event.Address.String() }
+func (e *receiptEventResolver) Data() string    { return hexutil.Encode(e.event.Data) }
+
+func (e *receiptEventResolver) Topics() []string {
+	topics := make([]string, 0, len(e.event.Topics))
+	for _, t := range e.event.Topics {
+		topics = append(topics, t.String())
+	}
+	return topics
+}
+
+type transferResolver struct {
+	transfer *tx.Transfer
+}
+
+func (t *transferResolver) Sender() string    { return t.transfer.Sender.String() }
+func (t *transferResolver) Recipient() string { return t.transfer.Recipient.String() }
+func (t *transferResolver) Amount() string    { return encodeBig(t.transfer.Amount) }
+
+type accountResolver struct {
+	addr   thor.Address
+	header *block.Header
+	state  *state.ReadOnly
+}
+
+func (a *accountResolver) Address() string { return a.addr.String() }
+
+func (a *accountResolver) Balance() (string, error) {
+	acc, err := a.state.GetAccount(a.addr)
+	if err != nil {
+		return "", err
+	}
+	return encodeBig(acc.Balance), nil
+}
+
+func (a *accountResolver) Energy() (string, error) {
+	acc, err := a.state.GetAccount(a.addr)
+	if err != nil {
+		return "", err
+	}
+	return encodeBig(acc.CalcEnergy(a.header.Timestamp())), nil
+}
+
+func (a *accountResolver) HasCode() (bool, error) {
+	acc, err := a.state.GetAccount(a.addr)
+	if err != nil {
+		return false, err
+	}
+	return len(acc.CodeHash) != 0, nil
+}
+
+func (a *accountResolver) Code() (string, error) {
+	code, err := a.state.GetCode(a.addr)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(code), nil
+}
+
+func (a *accountResolver) Storage(args struct{ Key string }) (string, error) {
+	key, err := thor.ParseBytes32(args.Key)
+	if err != nil {
+		return "", errors.WithMessage(err, "key")
+	}
+	value, err := a.state.GetStorage(a.addr, key)
+	if err != nil {
+		return "", err
+	}
+	return value.String(), nil
+}
+
+type eventResolver struct {
+	root  *resolver
+	event *logdb.Event
+}
+
+func (e *eventResolver) Address() string { return e.event.Address.String() }
+func (e *eventResolver) Data() string    { return hexutil.Encode(e.event.Data) }
+
+func (e *eventResolver) Topics() []string {
+	topics := make([]string, 0, len(e.event.Topics))
+	for _, t := range e.event.Topics {
+		if t != nil {
+			topics = append(topics, t.String())
+		}
+	}
+	return topics
+}
+
+func (e *eventResolver) Block() (*blockResolver, error) {
+	header, err := e.root.chain.GetBlockHeader(e.event.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	return &blockResolver{e.root, header}, nil
+}
+
+func (e *eventResolver) Transaction() (*txResolver, error) {
+	meta, err := e.root.chain.GetTransactionMeta(e.event.TxID, e.event.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	return e.root.newTxResolver(meta.BlockID, meta.Index)
+}
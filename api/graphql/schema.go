@@ -0,0 +1,124 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package graphql
+
+// schema describes the queryable data. Hashes, addresses and byte arrays are 0x-prefixed hex strings,
+// and token amounts are hex strings as in REST APIs.
+const schema = `
+scalar Long
+
+schema {
+	query: Query
+}
+
+type Query {
+	# revision can be 'best', block number or block ID, default to 'best'
+	block(revision: String): Block
+	transaction(id: String!): Transaction
+	account(address: String!, revision: String): Account!
+	events(filter: EventFilter!): [Event!]!
+}
+
+type Block {
+	number: Long!
+	id: String!
+	size: Int!
+	parentID: String!
+	timestamp: Long!
+	gasLimit: Long!
+	beneficiary: String!
+	gasUsed: Long!
+	totalScore: Long!
+	txsRoot: String!
+	stateRoot: String!
+	receiptsRoot: String!
+	signer: String!
+	isTrunk: Boolean!
+	transactions: [Transaction!]!
+}
+
+type Transaction {
+	id: String!
+	chainTag: Int!
+	blockRef: String!
+	expiration: Int!
+	clauses: [Clause!]!
+	gasPriceCoef: Int!
+	gas: Long!
+	origin: String!
+	nonce: String!
+	dependsOn: String
+	size: Int!
+	block: Block!
+	receipt: Receipt
+}
+
+type Clause {
+	to: String
+	value: String!
+	data: String!
+}
+
+type Receipt {
+	gasUsed: Long!
+	gasPayer: String!
+	paid: String!
+	reward: String!
+	reverted: Boolean!
+	outputs: [Output!]!
+}
+
+type Output {
+	contractAddress: String
+	events: [ReceiptEvent!]!
+	transfers: [Transfer!]!
+}
+
+type ReceiptEvent {
+	address: String!
+	topics: [String!]!
+	data: String!
+}
+
+type Transfer {
+	sender: String!
+	recipient: String!
+	amount: String!
+}
+
+type Account {
+	address: String!
+	balance: String!
+	energy: String!
+	hasCode: Boolean!
+	code: String!
+	storage(key: String!): String!
+}
+
+# block range is inclusive, and results are paginated by offset and limit
+input EventFilter {
+	address: String
+	topic0: String
+	topic1: String
+	topic2: String
+	topic3: String
+	topic4: String
+	from: Long
+	to: Long
+	offset: Long
+	limit: Long
+	# 'asc' or 'desc', default to 'asc'
+	order: String
+}
+
+type Event {
+	address: String!
+	topics: [String!]!
+	data: String!
+	block: Block!
+	transaction: Transaction!
+}
+`
@@ -0,0 +1,96 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
+)
+
+// key probed to check DB availability, absent is fine
+var probeKey = []byte("health-probe")
+
+// Options conditions of readiness. Zero values disable the checks.
+type Options struct {
+	MaxLag   time.Duration // max time elapsed since best block
+	MinPeers int
+}
+
+// Health serves liveness and readiness probes.
+type Health struct {
+	chain *chain.Chain
+	db    kv.Getter
+	nw    node.Network
+	opts  Options
+}
+
+func New(chain *chain.Chain, db kv.Getter, nw node.Network, opts Options) *Health {
+	return &Health{
+		chain,
+		db,
+		nw,
+		opts,
+	}
+}
+
+func (h *Health) status() *Status {
+	best := h.chain.BestBlock().Header()
+	lag := time.Since(time.Unix(int64(best.Timestamp()), 0))
+	if lag < 0 {
+		lag = 0
+	}
+	s := &Status{
+		DB:            "ok",
+		Peers:         len(h.nw.PeersStats()),
+		BestNumber:    best.Number(),
+		BestTimestamp: best.Timestamp(),
+		SyncLag:       uint64(lag / time.Second),
+	}
+	if _, err := h.db.Has(probeKey); err != nil {
+		s.DB = err.Error()
+	}
+	return s
+}
+
+// writeStatus writes the status, with 503 if not ok.
+func writeStatus(w http.ResponseWriter, s *Status, ok bool) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return utils.HTTPError(err, http.StatusInternalServerError)
+	}
+	w.Header().Set("Content-Type", utils.JSONContentType)
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(data)
+	return nil
+}
+
+func (h *Health) handleHealthz(w http.ResponseWriter, req *http.Request) error {
+	s := h.status()
+	s.Healthy = s.DB == "ok"
+	return writeStatus(w, s, s.Healthy)
+}
+
+func (h *Health) handleReadyz(w http.ResponseWriter, req *http.Request) error {
+	s := h.status()
+	s.Healthy = s.DB == "ok" &&
+		s.Peers >= h.opts.MinPeers &&
+		(h.opts.MaxLag <= 0 || time.Duration(s.SyncLag)*time.Second <= h.opts.MaxLag)
+	return writeStatus(w, s, s.Healthy)
+}
+
+func (h *Health) Mount(root *mux.Router) {
+	root.Path("/healthz").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(h.handleHealthz))
+	root.Path("/readyz").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(h.handleReadyz))
+}
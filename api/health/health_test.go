@@ -0,0 +1,90 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/health"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+)
+
+type network int
+
+func (n network) PeersStats() []*comm.PeerStats {
+	return make([]*comm.PeerStats, int(n))
+}
+
+func TestProbes(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	b, _, err := genesis.NewDevnet().Build(state.NewCreator(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := chain.New(db, b)
+
+	probe := func(opts health.Options, peers int, path string) (int, *health.Status) {
+		router := mux.NewRouter()
+		health.New(c, db, network(peers), opts).Mount(router)
+		ts := httptest.NewServer(router)
+		defer ts.Close()
+
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		data, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s health.Status
+		if err := json.Unmarshal(data, &s); err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, &s
+	}
+
+	code, s := probe(health.Options{}, 0, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, s.Healthy)
+	assert.Equal(t, "ok", s.DB)
+	assert.Equal(t, b.Header().Timestamp(), s.BestTimestamp)
+	assert.True(t, s.SyncLag > 0)
+
+	code, _ = probe(health.Options{}, 0, "/readyz")
+	assert.Equal(t, http.StatusOK, code, "no condition")
+
+	code, s = probe(health.Options{MinPeers: 1}, 0, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code, "too few peers")
+	assert.False(t, s.Healthy)
+
+	code, _ = probe(health.Options{MinPeers: 1, MaxLag: time.Minute}, 2, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code, "genesis is far behind")
+
+	code, _ = probe(health.Options{MinPeers: 1, MaxLag: time.Since(time.Unix(int64(b.Header().Timestamp()), 0)) + time.Hour}, 2, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, s = probe(health.Options{MinPeers: 1}, 0, "/healthz")
+	assert.Equal(t, http.StatusOK, code, "liveness is not affected by peers")
+	assert.Equal(t, 0, s.Peers)
+
+	db.Close()
+	code, s = probe(health.Options{}, 0, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.NotEqual(t, "ok", s.DB)
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package health
+
+// Status reports conditions of the node checked by probes.
+type Status struct {
+	Healthy       bool   `json:"healthy"`
+	DB            string `json:"db"` // 'ok' or the error encountered
+	Peers         int    `json:"peers"`
+	BestNumber    uint32 `json:"bestNumber"`
+	BestTimestamp uint64 `json:"bestTimestamp"`
+	SyncLag       uint64 `json:"syncLag"` // seconds elapsed since best block
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/thor"
+)
+
+// Authority lists proposer candidates at the best block.
+type Authority struct {
+	BlockID     thor.Bytes32          `json:"blockID"`
+	Endorsement math.HexOrDecimal256  `json:"endorsement"`
+	Candidates  []*AuthorityCandidate `json:"candidates"`
+}
+
+// AuthorityCandidate a proposer candidate annotated with its status.
+// A candidate that is not endorsed is skipped when scheduling proposers.
+type AuthorityCandidate struct {
+	NodeMaster      thor.Address         `json:"nodeMaster"`
+	Endorsor        thor.Address         `json:"endorsor"`
+	Identity        thor.Bytes32         `json:"identity"`
+	Active          bool                 `json:"active"`
+	Endorsed        bool                 `json:"endorsed"`
+	EndorsorBalance math.HexOrDecimal256 `json:"endorsorBalance"`
+}
+
+func (n *Node) getAuthority() (*Authority, error) {
+	best := n.chain.BestBlock().Header()
+	st, err := n.stateCreator.NewState(best.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+
+	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
+	candidates := builtin.Authority.Native(st).AllCandidates()
+
+	result := &Authority{
+		BlockID:     best.ID(),
+		Endorsement: math.HexOrDecimal256(*endorsement),
+		Candidates:  make([]*AuthorityCandidate, 0, len(candidates)),
+	}
+	for _, c := range candidates {
+		bal := st.GetBalance(c.Endorsor)
+		result.Candidates = append(result.Candidates, &AuthorityCandidate{
+			NodeMaster:      c.NodeMaster,
+			Endorsor:        c.Endorsor,
+			Identity:        c.Identity,
+			Active:          c.Active,
+			Endorsed:        bal.Cmp(endorsement) >= 0,
+			EndorsorBalance: math.HexOrDecimal256(*bal),
+		})
+	}
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (n *Node) handleAuthority(w http.ResponseWriter, req *http.Request) error {
+	authority, err := n.getAuthority()
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, authority)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/doublesign"
+	"github.com/vechain/thor/thor"
+)
+
+// DoubleSign proves that an authority node signed two different blocks with the same parent and timestamp.
+type DoubleSign struct {
+	Signer thor.Address `json:"signer"`
+	First  SignedHeader `json:"first"`
+	Second SignedHeader `json:"second"`
+}
+
+// SignedHeader a block header along with its rlp encoding, to have the signature verified independently.
+type SignedHeader struct {
+	ID        thor.Bytes32  `json:"id"`
+	Number    uint32        `json:"number"`
+	ParentID  thor.Bytes32  `json:"parentID"`
+	Timestamp uint64        `json:"timestamp"`
+	Raw       hexutil.Bytes `json:"raw"`
+}
+
+func convertSignedHeader(header *block.Header) (SignedHeader, error) {
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return SignedHeader{}, err
+	}
+	return SignedHeader{
+		ID:        header.ID(),
+		Number:    header.Number(),
+		ParentID:  header.ParentID(),
+		Timestamp: header.Timestamp(),
+		Raw:       raw,
+	}, nil
+}
+
+func (n *Node) handleDoubleSigns(w http.ResponseWriter, req *http.Request) error {
+	evs, err := doublesign.Load(n.db)
+	if err != nil {
+		return err
+	}
+	result := make([]*DoubleSign, 0, len(evs))
+	for _, ev := range evs {
+		first, err := convertSignedHeader(ev.First)
+		if err != nil {
+			return err
+		}
+		second, err := convertSignedHeader(ev.Second)
+		if err != nil {
+			return err
+		}
+		result = append(result, &DoubleSign{
+			Signer: ev.Signer,
+			First:  first,
+			Second: second,
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
@@ -10,15 +10,34 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
 )
 
 type Node struct {
-	nw Network
+	nw           Network
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	pool         *txpool.TxPool
+	filterLimits utils.FilterLimits
+	gc           GCInfo
+	version      string
+	db           kv.Getter
 }
 
-func New(nw Network) *Node {
+func New(nw Network, chain *chain.Chain, stateCreator *state.Creator, pool *txpool.TxPool, filterLimits utils.FilterLimits, gc GCInfo, version string, db kv.Getter) *Node {
 	return &Node{
 		nw,
+		chain,
+		stateCreator,
+		pool,
+		filterLimits,
+		gc,
+		version,
+		db,
 	}
 }
 
@@ -30,8 +49,43 @@ func (n *Node) handleNetwork(w http.ResponseWriter, req *http.Request) error {
 	return utils.WriteJSON(w, n.PeersStats())
 }
 
+func (n *Node) handleInfo(w http.ResponseWriter, req *http.Request) error {
+	genesisID := n.chain.GenesisBlock().Header().ID()
+	fc := thor.GetForkConfig(genesisID)
+	best := n.chain.BestBlock().Header()
+	finalized := n.chain.Finalized()
+	return utils.WriteJSON(w, &Info{
+		Version:   n.version,
+		ChainTag:  n.chain.Tag(),
+		GenesisID: genesisID,
+		ForkConfig: ForkConfig{
+			FixTransferLog:  fc.FixTransferLog,
+			HeaderExtension: fc.HeaderExtension,
+			BlockInterval:   fc.BlockInterval,
+			VIP193:          fc.VIP193,
+		},
+		BestBlock: BlockSummary{
+			ID:        best.ID(),
+			Number:    best.Number(),
+			Timestamp: best.Timestamp(),
+		},
+		Finalized: BlockSummary{
+			ID:        finalized.ID(),
+			Number:    finalized.Number(),
+			Timestamp: finalized.Timestamp(),
+		},
+		PeerCount:    len(n.nw.PeersStats()),
+		FilterLimits: n.filterLimits,
+		GC:           n.gc,
+	})
+}
+
 func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("/network/peers").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleNetwork))
+	sub.Path("/info").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleInfo))
+	sub.Path("/authority").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleAuthority))
+	sub.Path("/txpool").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleTxPool))
+	sub.Path("/double-signs").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleDoubleSigns))
 }
@@ -12,18 +12,29 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/doublesign"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/txpool"
 )
 
-var ts *httptest.Server
+var (
+	ts   *httptest.Server
+	db   *lvldb.LevelDB
+	c    *chain.Chain
+	pool *txpool.TxPool
+)
 
 func TestNode(t *testing.T) {
 	initCommServer(t)
@@ -33,10 +44,110 @@ func TestNode(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, 0, len(peersStats), "count should be zero")
+
+	res = httpGet(t, ts.URL+"/node/info")
+	var info node.Info
+	if err := json.Unmarshal(res, &info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(100), info.FilterLimits.MaxBlockRange)
+	assert.Equal(t, node.GCInfo{Mode: "full", StateRetention: 128}, info.GC)
+	assert.Equal(t, "1.0.0-test", info.Version)
+	assert.Equal(t, c.Tag(), info.ChainTag)
+	assert.Equal(t, c.GenesisBlock().Header().ID(), info.GenesisID)
+	assert.Equal(t, c.BestBlock().Header().ID(), info.BestBlock.ID)
+	assert.Equal(t, c.Finalized().ID(), info.Finalized.ID)
+	assert.Equal(t, thor.GetForkConfig(info.GenesisID).FixTransferLog, info.ForkConfig.FixTransferLog)
+	assert.Equal(t, 0, info.PeerCount)
+
+	res = httpGet(t, ts.URL+"/node/authority")
+	var authority node.Authority
+	if err := json.Unmarshal(res, &authority); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(authority.Candidates), "devnet has the solo block signer only")
+	assert.Equal(t, genesis.DevAccounts()[0].Address, authority.Candidates[0].NodeMaster)
+	assert.True(t, authority.Candidates[0].Active)
+	assert.True(t, authority.Candidates[0].Endorsed)
+
+	txPool(t)
+	doubleSigns(t)
+}
+
+func txPool(t *testing.T) {
+	acc := genesis.DevAccounts()[0]
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(100).
+		Gas(21000).
+		Nonce(7).
+		Clause(tx.NewClause(&acc.Address)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), acc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+	assert.Nil(t, pool.AddLocal(trx))
+
+	var status node.TxPoolStatus
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/txpool"), &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, status.Total)
+	assert.Equal(t, 1, len(status.Origins))
+	assert.Equal(t, acc.Address, status.Origins[0].Origin)
+	assert.Equal(t, trx.ID(), status.Origins[0].Txs[0].ID)
+	assert.Equal(t, uint64(7), uint64(status.Origins[0].Txs[0].Nonce))
+	assert.True(t, status.Origins[0].Txs[0].Local)
+	assert.Nil(t, status.Origins[0].Txs[0].Clauses, "not expanded")
+
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/txpool?expanded=true"), &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(status.Origins[0].Txs[0].Clauses))
+	assert.Equal(t, uint64(21000), status.Origins[0].Txs[0].Gas)
+
+	res, err := http.Get(ts.URL + "/node/txpool?expanded=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func doubleSigns(t *testing.T) {
+	var evs []*node.DoubleSign
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/double-signs"), &evs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(evs))
+
+	acc := genesis.DevAccounts()[0]
+	d := doublesign.New(db)
+	for _, gasLimit := range []uint64{1, 2} {
+		b := new(block.Builder).ParentID(c.GenesisBlock().Header().ID()).Timestamp(10).GasLimit(gasLimit).Build()
+		sig, err := crypto.Sign(b.Header().SigningHash().Bytes(), acc.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Observe(b.WithSignature(sig).Header()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/double-signs"), &evs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(evs))
+	assert.Equal(t, acc.Address, evs[0].Signer)
+	assert.NotEqual(t, evs[0].First.ID, evs[0].Second.ID)
+	assert.Equal(t, evs[0].First.ParentID, evs[0].Second.ParentID)
+	assert.NotEmpty(t, evs[0].First.Raw)
 }
 
 func initCommServer(t *testing.T) {
-	db, _ := lvldb.NewMem()
+	db, _ = lvldb.NewMem()
 	stateC := state.NewCreator(db)
 	gene := genesis.NewDevnet()
 
@@ -44,14 +155,15 @@ func initCommServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	chain, _ := chain.New(db, b)
-	comm := comm.New(chain, txpool.New(chain, stateC, txpool.Options{
+	c, _ = chain.New(db, b)
+	pool = txpool.New(c, stateC, txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     10 * time.Minute,
-	}))
+	})
+	comm := comm.New(c, pool, false)
 	router := mux.NewRouter()
-	node.New(comm).Mount(router, "/node")
+	node.New(comm, c, stateC, pool, utils.FilterLimits{MaxBlockRange: 100}, node.GCInfo{Mode: "full", StateRetention: 128}, "1.0.0-test", db).Mount(router, "/node")
 	ts = httptest.NewServer(router)
 }
 
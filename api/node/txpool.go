@@ -0,0 +1,91 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
+)
+
+func convertPooledTx(ptx *txpool.PooledTx, expanded bool) *PooledTx {
+	res := &PooledTx{
+		ID:         ptx.ID(),
+		Nonce:      math.HexOrDecimal64(ptx.Nonce()),
+		Executable: ptx.Executable,
+		Local:      ptx.Local,
+	}
+	if !expanded {
+		return res
+	}
+	clauses := make([]PooledTxClause, 0, len(ptx.Clauses()))
+	for _, c := range ptx.Clauses() {
+		clauses = append(clauses, PooledTxClause{
+			To:    c.To(),
+			Value: (*math.HexOrDecimal256)(c.Value()),
+			Data:  hexutil.Encode(c.Data()),
+		})
+	}
+	br := ptx.BlockRef()
+	res.ChainTag = ptx.ChainTag()
+	res.BlockRef = hexutil.Encode(br[:])
+	res.Expiration = ptx.Expiration()
+	res.Clauses = clauses
+	res.GasPriceCoef = ptx.GasPriceCoef()
+	res.Gas = ptx.Gas()
+	res.DependsOn = ptx.DependsOn()
+	res.Size = uint32(ptx.Size())
+	return res
+}
+
+func (n *Node) handleTxPool(w http.ResponseWriter, req *http.Request) error {
+	expanded := req.URL.Query().Get("expanded")
+	if expanded != "" && expanded != "false" && expanded != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "expanded"))
+	}
+	pooled, orphans := n.pool.Inspect()
+
+	status := &TxPoolStatus{
+		Total:   len(pooled),
+		Orphans: orphans,
+		Origins: []*OriginQueue{},
+	}
+	queues := make(map[thor.Address]*OriginQueue)
+	for _, ptx := range pooled {
+		queue, ok := queues[ptx.Origin]
+		if !ok {
+			queue = &OriginQueue{Origin: ptx.Origin}
+			queues[ptx.Origin] = queue
+			status.Origins = append(status.Origins, queue)
+		}
+		if ptx.Executable {
+			status.Executable++
+			queue.Executable++
+		}
+		queue.Txs = append(queue.Txs, convertPooledTx(ptx, expanded == "true"))
+	}
+	// the busiest origins first
+	sort.Slice(status.Origins, func(i, j int) bool {
+		a, b := status.Origins[i], status.Origins[j]
+		if len(a.Txs) != len(b.Txs) {
+			return len(a.Txs) > len(b.Txs)
+		}
+		return bytes.Compare(a.Origin[:], b.Origin[:]) < 0
+	})
+	for _, queue := range status.Origins {
+		sort.Slice(queue.Txs, func(i, j int) bool {
+			return queue.Txs[i].Nonce < queue.Txs[j].Nonce
+		})
+	}
+	return utils.WriteJSON(w, status)
+}
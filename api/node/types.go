@@ -6,6 +6,8 @@
 package node
 
 import (
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/comm"
 	"github.com/vechain/thor/thor"
 )
@@ -14,6 +16,41 @@ type Network interface {
 	PeersStats() []*comm.PeerStats
 }
 
+// Info describes the node and the chain it runs, including limits applied to API queries.
+type Info struct {
+	Version      string             `json:"version"`
+	ChainTag     byte               `json:"chainTag"`
+	GenesisID    thor.Bytes32       `json:"genesisID"`
+	ForkConfig   ForkConfig         `json:"forkConfig"`
+	BestBlock    BlockSummary       `json:"bestBlock"`
+	Finalized    BlockSummary       `json:"finalized"`
+	PeerCount    int                `json:"peerCount"`
+	FilterLimits utils.FilterLimits `json:"filterLimits"`
+	GC           GCInfo             `json:"gc"`
+}
+
+// ForkConfig block numbers where forks take effect, max uint32 if never.
+type ForkConfig struct {
+	FixTransferLog  uint32 `json:"fixTransferLog"`
+	HeaderExtension uint32 `json:"headerExtension"`
+	BlockInterval   uint32 `json:"blockInterval"`
+	VIP193          uint32 `json:"vip193"`
+}
+
+// BlockSummary identifies a block.
+type BlockSummary struct {
+	ID        thor.Bytes32 `json:"id"`
+	Number    uint32       `json:"number"`
+	Timestamp uint64       `json:"timestamp"`
+}
+
+// GCInfo describes how much history the node keeps.
+type GCInfo struct {
+	Mode string `json:"mode"` // archive or full
+	// count of latest blocks whose states are available, zero means all
+	StateRetention uint32 `json:"stateRetention"`
+}
+
 type PeerStats struct {
 	Name        string       `json:"name"`
 	BestBlockID thor.Bytes32 `json:"bestBlockID"`
@@ -42,3 +79,41 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 	}
 	return peersStats
 }
+
+// TxPoolStatus summarizes txs in the pool, grouped by origin.
+type TxPoolStatus struct {
+	Total      int            `json:"total"`
+	Executable int            `json:"executable"`
+	Orphans    int            `json:"orphans"` // txs waiting for unknown dependencies, not listed in origins
+	Origins    []*OriginQueue `json:"origins"`
+}
+
+// OriginQueue txs of an origin in the pool, ordered by nonce.
+type OriginQueue struct {
+	Origin     thor.Address `json:"origin"`
+	Executable int          `json:"executable"`
+	Txs        []*PooledTx  `json:"txs"`
+}
+
+// PooledTx tx in the pool. Fields of tx body other than nonce are filled only if expanded.
+type PooledTx struct {
+	ID           thor.Bytes32        `json:"id"`
+	Nonce        math.HexOrDecimal64 `json:"nonce"`
+	Executable   bool                `json:"executable"`
+	Local        bool                `json:"local"`
+	ChainTag     byte                `json:"chainTag,omitempty"`
+	BlockRef     string              `json:"blockRef,omitempty"`
+	Expiration   uint32              `json:"expiration,omitempty"`
+	Clauses      []PooledTxClause    `json:"clauses,omitempty"`
+	GasPriceCoef uint8               `json:"gasPriceCoef,omitempty"`
+	Gas          uint64              `json:"gas,omitempty"`
+	DependsOn    *thor.Bytes32       `json:"dependsOn,omitempty"`
+	Size         uint32              `json:"size,omitempty"`
+}
+
+// PooledTxClause clause of the expanded pooled tx.
+type PooledTxClause struct {
+	To    *thor.Address         `json:"to"`
+	Value *math.HexOrDecimal256 `json:"value"`
+	Data  string                `json:"data"`
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package stats
+
+import (
+	"bytes"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/liveness"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+const (
+	defaultRange = 100
+	maxRange     = 8640 // about one day
+)
+
+type Stats struct {
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	forkConfig   thor.ForkConfig
+	db           kv.Getter  // where liveness records saved
+	summaries    *lru.Cache // block id -> *blockSummary
+	missed       *lru.Cache // block id -> []thor.Address
+	results      *lru.Cache // resultKey -> *ChainStats, proposersResultKey -> []*ProposerStats
+}
+
+type resultKey struct {
+	bestID thor.Bytes32
+	n      uint32
+}
+
+type proposersResultKey resultKey
+
+func New(chain *chain.Chain, stateCreator *state.Creator, db kv.Getter) *Stats {
+	summaries, _ := lru.New(maxRange)
+	missed, _ := lru.New(maxRange)
+	results, _ := lru.New(64)
+	return &Stats{
+		chain,
+		stateCreator,
+		thor.GetForkConfig(chain.GenesisBlock().Header().ID()),
+		db,
+		summaries,
+		missed,
+		results,
+	}
+}
+
+func (s *Stats) getSummary(id thor.Bytes32) (*blockSummary, error) {
+	if cached, ok := s.summaries.Get(id); ok {
+		return cached.(*blockSummary), nil
+	}
+	header, err := s.chain.GetBlockHeader(id)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.chain.GetBlockReceipts(id)
+	if err != nil {
+		if !s.chain.IsNotFound(err) {
+			return nil, err
+		}
+		// genesis block has no receipts
+		receipts = nil
+	}
+	summary := &blockSummary{
+		timestamp: header.Timestamp(),
+		gasLimit:  header.GasLimit(),
+		gasUsed:   header.GasUsed(),
+		txs:       uint64(len(receipts)),
+	}
+	for _, r := range receipts {
+		if r.Reverted {
+			summary.reverted++
+		}
+	}
+	if header.Number() > 0 {
+		if err := s.fillProposerSummary(summary, header); err != nil {
+			return nil, err
+		}
+	}
+	s.summaries.Add(id, summary)
+	return summary, nil
+}
+
+// fillProposerSummary fills signer and score of a non-genesis block, which are read from headers only.
+func (s *Stats) fillProposerSummary(summary *blockSummary, header *block.Header) error {
+	signer, err := header.Signer()
+	if err != nil {
+		return err
+	}
+	parent, err := s.chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		return err
+	}
+	summary.signer = signer
+	summary.score = header.TotalScore() - parent.TotalScore()
+	return nil
+}
+
+// getMissed returns proposers who missed their slots before the non-genesis block, by replaying
+// the schedule on the parent state, which should be checked not pruned.
+func (s *Stats) getMissed(id thor.Bytes32) ([]thor.Address, error) {
+	if cached, ok := s.missed.Get(id); ok {
+		return cached.([]thor.Address), nil
+	}
+	header, err := s.chain.GetBlockHeader(id)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := s.chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.stateCreator.NewState(parent.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	_, missed, err := poa.ReplaySchedule(s.forkConfig, st, parent, header)
+	if err != nil {
+		return nil, err
+	}
+	s.missed.Add(id, missed)
+	return missed, nil
+}
+
+// timingOf returns timings of blocks up to best, given numbers of their parents. The governed
+// interval is read once from the best state, as states of older blocks may be pruned, so that
+// a change of it within the range is not reflected.
+func (s *Stats) timingOf(best *block.Header) (func(parentNum uint32) poa.Timing, error) {
+	st, err := s.stateCreator.NewState(best.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	interval := builtin.Params.Native(st).Get(thor.KeyBlockInterval)
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	getParam := func(thor.Bytes32) *big.Int { return interval }
+	return func(parentNum uint32) poa.Timing {
+		return poa.TimingAt(s.forkConfig, parentNum, getParam)
+	}, nil
+}
+
+func (s *Stats) computeStats(best *block.Header, n uint32) (*ChainStats, error) {
+	key := resultKey{best.ID(), n}
+	if cached, ok := s.results.Get(key); ok {
+		return cached.(*ChainStats), nil
+	}
+
+	if n > best.Number() {
+		n = best.Number()
+	}
+	stats := &ChainStats{
+		From: best.Number() - n,
+		To:   best.Number(),
+	}
+	timing, err := s.timingOf(best)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		gasUsed, gasLimit uint64
+		txs, reverted     uint64
+		onTime            uint32
+		first, prev       *blockSummary
+		seeker            = s.chain.NewSeeker(best.ID())
+	)
+	for num := stats.From; num <= stats.To; num++ {
+		id := seeker.GetID(num)
+		if err := seeker.Err(); err != nil {
+			return nil, err
+		}
+		summary, err := s.getSummary(id)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil {
+			// the first block of the window only serves as the time base
+			gasUsed += summary.gasUsed
+			gasLimit += summary.gasLimit
+			txs += summary.txs
+			reverted += summary.reverted
+			if summary.timestamp-prev.timestamp == timing(num-1).Interval {
+				onTime++
+			}
+		} else {
+			first = summary
+		}
+		prev = summary
+	}
+
+	stats.Blocks = n
+	stats.TxCount = txs
+	stats.RevertedTxCount = reverted
+	if n > 0 {
+		elapsed := prev.timestamp - first.timestamp
+		stats.AvgBlockInterval = float64(elapsed) / float64(n)
+		stats.IntervalAdherence = float64(onTime) / float64(n)
+		if elapsed > 0 {
+			stats.TxPerSecond = float64(txs) / float64(elapsed)
+		}
+	}
+	if gasLimit > 0 {
+		stats.GasUtilization = float64(gasUsed) / float64(gasLimit)
+	}
+	if txs > 0 {
+		stats.RevertedRatio = float64(reverted) / float64(txs)
+	}
+
+	s.results.Add(key, stats)
+	return stats, nil
+}
+
+func (s *Stats) computeProposerStats(best *block.Header, n uint32) ([]*ProposerStats, error) {
+	key := proposersResultKey{best.ID(), n}
+	if cached, ok := s.results.Get(key); ok {
+		return cached.([]*ProposerStats), nil
+	}
+
+	if n > best.Number() {
+		n = best.Number()
+	}
+	var (
+		all    = make(map[thor.Address]*ProposerStats)
+		scores = make(map[thor.Address]uint64)
+		seeker = s.chain.NewSeeker(best.ID())
+	)
+	// missed slots are found out on parent states
+	from := best.Number() - n + 1
+	if n > 0 {
+		if err := s.stateCreator.CheckPruned(from - 1); err != nil {
+			return nil, err
+		}
+	}
+	get := func(addr thor.Address) *ProposerStats {
+		if ps, ok := all[addr]; ok {
+			return ps
+		}
+		ps := &ProposerStats{Address: addr}
+		all[addr] = ps
+		return ps
+	}
+	// same window as chain stats, which never includes genesis
+	for num := from; num <= best.Number(); num++ {
+		id := seeker.GetID(num)
+		if err := seeker.Err(); err != nil {
+			return nil, err
+		}
+		summary, err := s.getSummary(id)
+		if err != nil {
+			return nil, err
+		}
+		ps := get(summary.signer)
+		ps.SignedBlocks++
+		scores[summary.signer] += summary.score
+		missed, err := s.getMissed(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range missed {
+			get(addr).MissedSlots++
+		}
+	}
+
+	result := make([]*ProposerStats, 0, len(all))
+	for addr, ps := range all {
+		if ps.SignedBlocks > 0 {
+			ps.AvgScore = float64(scores[addr]) / float64(ps.SignedBlocks)
+		}
+		result = append(result, ps)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Address[:], result[j].Address[:]) < 0
+	})
+
+	s.results.Add(key, result)
+	return result, nil
+}
+
+func (s *Stats) handleProposerStats(w http.ResponseWriter, req *http.Request) error {
+	n, err := parseRange(req.URL.Query().Get("range"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "range"))
+	}
+	stats, err := s.computeProposerStats(s.chain.BestBlock().Header(), n)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, stats)
+}
+
+func (s *Stats) handleChainStats(w http.ResponseWriter, req *http.Request) error {
+	n, err := parseRange(req.URL.Query().Get("range"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "range"))
+	}
+	stats, err := s.computeStats(s.chain.BestBlock().Header(), n)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, stats)
+}
+
+// handleLiveness serves cumulative liveness of authority nodes, tracked since the node started tracking.
+func (s *Stats) handleLiveness(w http.ResponseWriter, req *http.Request) error {
+	records, err := liveness.Load(s.db)
+	if err != nil {
+		return err
+	}
+	result := make([]*Liveness, 0, len(records))
+	for addr, r := range records {
+		result = append(result, &Liveness{
+			Address:      addr,
+			SignedBlocks: r.Signed,
+			MissedSlots:  r.Missed,
+			Uptime:       r.Uptime(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Address[:], result[j].Address[:]) < 0
+	})
+	return utils.WriteJSON(w, result)
+}
+
+func parseRange(r string) (uint32, error) {
+	if r == "" {
+		return defaultRange, nil
+	}
+	n, err := strconv.ParseUint(r, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 || n > maxRange {
+		return 0, errors.Errorf("should be in range [1, %v]", maxRange)
+	}
+	return uint32(n), nil
+}
+
+func (s *Stats) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/chain").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(s.handleChainStats))
+	sub.Path("/proposers").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(s.handleProposerStats))
+	sub.Path("/liveness").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(s.handleLiveness))
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+package stats_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/stats"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/liveness"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+var (
+	ts      *httptest.Server
+	db      *lvldb.LevelDB
+	c       *chain.Chain
+	stateC  *state.Creator
+	tracker *liveness.Tracker
+)
+
+func TestStats(t *testing.T) {
+	initStatsServer(t)
+
+	// the first block takes the earliest slot, and the second one the latest slot
+	b1 := packBlock(t, c.GenesisBlock(), true)
+	b2 := packBlock(t, b1, false)
+	signer1, _ := b1.Header().Signer()
+	signer2, _ := b2.Header().Signer()
+
+	var chainStats stats.ChainStats
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/stats/chain?range=2", http.StatusOK), &chainStats); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint32(0), chainStats.From)
+	assert.Equal(t, uint32(2), chainStats.To)
+	assert.Equal(t, uint32(2), chainStats.Blocks)
+	assert.Equal(t, float64(b2.Header().Timestamp()-c.GenesisBlock().Header().Timestamp())/2, chainStats.AvgBlockInterval)
+	assert.Equal(t, 0.5, chainStats.IntervalAdherence, "only the first block on time")
+	assert.Equal(t, uint64(0), chainStats.TxCount)
+
+	var proposers []*stats.ProposerStats
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/stats/proposers?range=2", http.StatusOK), &proposers); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		signed = make(map[thor.Address]uint32)
+		missed uint32
+	)
+	for _, ps := range proposers {
+		signed[ps.Address] = ps.SignedBlocks
+		missed += ps.MissedSlots
+	}
+	if signer1 == signer2 {
+		assert.Equal(t, uint32(2), signed[signer1])
+	} else {
+		assert.Equal(t, uint32(1), signed[signer1])
+		assert.Equal(t, uint32(1), signed[signer2])
+	}
+	assert.True(t, missed > 0, "slots before the second block missed")
+
+	var records []*stats.Liveness
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/stats/liveness", http.StatusOK), &records); err != nil {
+		t.Fatal(err)
+	}
+	var signedTotal, missedTotal uint64
+	for _, r := range records {
+		signedTotal += r.SignedBlocks
+		missedTotal += r.MissedSlots
+	}
+	assert.Equal(t, uint64(2), signedTotal)
+	assert.Equal(t, uint64(missed), missedTotal, "liveness tracked along the same blocks")
+
+	httpGet(t, ts.URL+"/stats/chain?range=0", http.StatusBadRequest)
+	httpGet(t, ts.URL+"/stats/proposers?range=x", http.StatusBadRequest)
+}
+
+func TestStatsPruned(t *testing.T) {
+	initStatsServer(t)
+	if _, err := state.EnablePruning(db, 0, 128); err != nil {
+		t.Fatal(err)
+	}
+	packBlock(t, c.GenesisBlock(), true)
+
+	// chain stats are built from headers only
+	httpGet(t, ts.URL+"/stats/chain?range=1", http.StatusOK)
+	// while missed slots need the pruned genesis state
+	httpGet(t, ts.URL+"/stats/proposers?range=1", http.StatusGone)
+}
+
+// packBlock packs a block upon the parent by the authority taking the earliest or the latest slot,
+// and adds it into the chain.
+func packBlock(t *testing.T, parent *block.Block, earliest bool) *block.Block {
+	var (
+		picked    *packer.Flow
+		pickedAcc genesis.DevAccount
+	)
+	for _, acc := range genesis.DevAccounts() {
+		flow, err := packer.New(c, stateC, acc.Address, &acc.Address).Schedule(parent.Header(), parent.Header().Timestamp())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if picked == nil ||
+			(earliest && flow.When() < picked.When()) ||
+			(!earliest && flow.When() > picked.When()) {
+			picked, pickedAcc = flow, acc
+		}
+	}
+	blk, stage, receipts, err := picked.Pack(pickedAcc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	fork, err := c.AddBlock(blk, receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Apply(fork); err != nil {
+		t.Fatal(err)
+	}
+	return blk
+}
+
+func initStatsServer(t *testing.T) {
+	db, _ = lvldb.NewMem()
+	stateC = state.NewCreator(db)
+	gene := new(genesis.Builder).
+		GasLimit(thor.InitialGasLimit).
+		Timestamp(1526400000).
+		State(func(state *state.State) error {
+			state.SetCode(builtin.Authority.Address, builtin.Authority.RuntimeBytecodes())
+			for _, acc := range genesis.DevAccounts() {
+				builtin.Authority.Native(state).Add(acc.Address, acc.Address, thor.Bytes32{})
+			}
+			return nil
+		})
+
+	b, _, err := gene.Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ = chain.New(db, b)
+	tracker = liveness.New(c, stateC, db)
+
+	router := mux.NewRouter()
+	stats.New(c, stateC, db).Mount(router, "/stats")
+	ts = httptest.NewServer(router)
+}
+
+func httpGet(t *testing.T, url string, statusCode int) []byte {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, statusCode, res.StatusCode, url)
+	return r
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package stats
+
+import "github.com/vechain/thor/thor"
+
+// ChainStats aggregated statistics over a window of recent trunk blocks.
+type ChainStats struct {
+	From              uint32  `json:"from"`
+	To                uint32  `json:"to"`
+	Blocks            uint32  `json:"blocks"`
+	AvgBlockInterval  float64 `json:"avgBlockInterval"`
+	IntervalAdherence float64 `json:"intervalAdherence"`
+	GasUtilization    float64 `json:"gasUtilization"`
+	TxCount           uint64  `json:"txCount"`
+	TxPerSecond       float64 `json:"txPerSecond"`
+	RevertedTxCount   uint64  `json:"revertedTxCount"`
+	RevertedRatio     float64 `json:"revertedRatio"`
+}
+
+// ProposerStats block production statistics of a proposer over a window of recent trunk blocks.
+type ProposerStats struct {
+	Address      thor.Address `json:"address"`
+	SignedBlocks uint32       `json:"signedBlocks"`
+	MissedSlots  uint32       `json:"missedSlots"`
+	// average total score gained by blocks signed by the proposer
+	AvgScore float64 `json:"avgScore"`
+}
+
+// Liveness cumulative liveness of an authority node along the trunk.
+type Liveness struct {
+	Address      thor.Address `json:"address"`
+	SignedBlocks uint64       `json:"signedBlocks"`
+	MissedSlots  uint64       `json:"missedSlots"`
+	// ratio of slots taken to all slots scheduled
+	Uptime float64 `json:"uptime"`
+}
+
+// blockSummary is the per-block data needed to compute stats.
+type blockSummary struct {
+	timestamp uint64
+	gasLimit  uint64
+	gasUsed   uint64
+	txs       uint64
+	reverted  uint64
+	signer    thor.Address
+	score     uint64 // total score gained over the parent
+}
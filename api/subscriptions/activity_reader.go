@@ -0,0 +1,112 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/thor"
+)
+
+const maxWatchedAddresses = 256
+
+// activity roles of a watched address in a tx
+const (
+	roleOrigin            = "origin"
+	roleClauseRecipient   = "clauseRecipient"
+	roleEventAddress      = "eventAddress"
+	roleTransferSender    = "transferSender"
+	roleTransferRecipient = "transferRecipient"
+)
+
+type activityReader struct {
+	chain       *chain.Chain
+	watched     map[thor.Address]bool
+	blockReader chain.BlockReader
+}
+
+func newActivityReader(chain *chain.Chain, position thor.Bytes32, addresses []thor.Address) *activityReader {
+	watched := make(map[thor.Address]bool, len(addresses))
+	for _, addr := range addresses {
+		watched[addr] = true
+	}
+	return &activityReader{
+		chain:       chain,
+		watched:     watched,
+		blockReader: chain.NewBlockReader(position),
+	}
+}
+
+func (ar *activityReader) Read() ([]interface{}, bool, error) {
+	blocks, err := ar.blockReader.Read()
+	if err != nil {
+		return nil, false, err
+	}
+	var msgs []interface{}
+	for _, block := range blocks {
+		receipts, err := ar.chain.GetBlockReceipts(block.Header().ID())
+		if err != nil {
+			return nil, false, err
+		}
+		for i, tx := range block.Transactions() {
+			origin, err := tx.Signer()
+			if err != nil {
+				return nil, false, err
+			}
+
+			// roles of each watched address in this tx, in order of first appearance
+			var (
+				order []thor.Address
+				roles = make(map[thor.Address][]string)
+			)
+			mark := func(addr thor.Address, role string) {
+				if !ar.watched[addr] {
+					return
+				}
+				if _, ok := roles[addr]; !ok {
+					order = append(order, addr)
+				}
+				for _, r := range roles[addr] {
+					if r == role {
+						return
+					}
+				}
+				roles[addr] = append(roles[addr], role)
+			}
+
+			mark(origin, roleOrigin)
+			for _, clause := range tx.Clauses() {
+				if to := clause.To(); to != nil {
+					mark(*to, roleClauseRecipient)
+				}
+			}
+			for _, output := range receipts[i].Outputs {
+				for _, event := range output.Events {
+					mark(event.Address, roleEventAddress)
+				}
+				for _, transfer := range output.Transfers {
+					mark(transfer.Sender, roleTransferSender)
+					mark(transfer.Recipient, roleTransferRecipient)
+				}
+			}
+
+			for _, addr := range order {
+				msgs = append(msgs, &ActivityMessage{
+					Address: addr,
+					Roles:   roles[addr],
+					Meta: LogMeta{
+						BlockID:        block.Header().ID(),
+						BlockNumber:    block.Header().Number(),
+						BlockTimestamp: block.Header().Timestamp(),
+						TxID:           tx.ID(),
+						TxOrigin:       origin,
+					},
+					Obsolete: block.Obsolete,
+				})
+			}
+		}
+	}
+	return msgs, len(blocks) > 0, nil
+}
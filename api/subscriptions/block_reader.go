@@ -6,6 +6,7 @@
 package subscriptions
 
 import (
+	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/thor"
 )
@@ -28,8 +29,8 @@ func (br *blockReader) Read() ([]interface{}, bool, error) {
 		return nil, false, err
 	}
 	var msgs []interface{}
-	for _, block := range blocks {
-		msg, err := convertBlock(block)
+	for _, blk := range blocks {
+		msg, err := convertBlock(block.NewSummary(blk.Block), blk.Obsolete)
 		if err != nil {
 			return nil, false, err
 		}
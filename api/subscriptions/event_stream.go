@@ -0,0 +1,85 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+)
+
+// acceptsEventStream returns whether the client asks for server-sent events.
+func acceptsEventStream(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		if strings.HasPrefix(strings.TrimSpace(accept), "text/event-stream") {
+			return true
+		}
+	}
+	return false
+}
+
+// serveEventStream streams messages of reader as server-sent events, which is an alternative to
+// websocket when it's blocked by proxies. Messages are the same as those sent via websocket.
+func (s *Subscriptions) serveEventStream(w http.ResponseWriter, req *http.Request, reader msgReader, policy slowClientPolicy) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return utils.HTTPError(errors.New("streaming not supported"), http.StatusInternalServerError)
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	// disable response buffering of nginx
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	// since the header is sent, no error should be returned in lines below
+
+	err := s.pump(reader, policy, req.Context().Done(), func(queue <-chan interface{}) error {
+		return writeEventLoop(w, flusher, queue, pingPeriod)
+	})
+	if err != nil {
+		data, _ := json.Marshal(err.Error())
+		if _, err := fmt.Fprintf(w, "event: error\ndata: %s\n\n", data); err != nil {
+			log.Debug("write error event", "err", err)
+		}
+		flusher.Flush()
+	}
+	return nil
+}
+
+// writeEventLoop writes queued messages as events and keep-alive comments every pingInterval, until queue closed or error occurred.
+func writeEventLoop(w io.Writer, flusher http.Flusher, queue <-chan interface{}, pingInterval time.Duration) error {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-queue:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			// comment line, to keep idle connection from being closed by proxies
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+}
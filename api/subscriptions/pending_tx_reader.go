@@ -0,0 +1,121 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/vechain/thor/co"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txpool"
+)
+
+// pendingTxHub fans out txs entering the pool to subscribed readers.
+type pendingTxHub struct {
+	lock    sync.Mutex
+	readers map[*pendingTxReader]struct{}
+	// the pool reports a tx again when its status changes, so dispatched ones are remembered
+	seen *lru.Cache
+}
+
+func newPendingTxHub() *pendingTxHub {
+	seen, _ := lru.New(4096)
+	return &pendingTxHub{
+		readers: make(map[*pendingTxReader]struct{}),
+		seen:    seen,
+	}
+}
+
+// run dispatches tx events of the pool until done.
+func (h *pendingTxHub) run(pool *txpool.TxPool, done <-chan struct{}) {
+	ch := make(chan *txpool.TxEvent, sendQueueSize)
+	sub := pool.SubscribeTxEvent(ch)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-sub.Err():
+			return
+		case ev := <-ch:
+			h.dispatch(ev.Tx)
+		}
+	}
+}
+
+func (h *pendingTxHub) dispatch(tx *tx.Transaction) {
+	if seen, _ := h.seen.ContainsOrAdd(tx.ID(), struct{}{}); seen {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for r := range h.readers {
+		r.push(tx)
+	}
+}
+
+func (h *pendingTxHub) subscribe(expanded bool) *pendingTxReader {
+	r := &pendingTxReader{expanded: expanded}
+	h.lock.Lock()
+	h.readers[r] = struct{}{}
+	h.lock.Unlock()
+	return r
+}
+
+func (h *pendingTxHub) unsubscribe(r *pendingTxReader) {
+	h.lock.Lock()
+	delete(h.readers, r)
+	h.lock.Unlock()
+}
+
+// pendingTxReader reads txs dispatched by the hub. It's woken up by new txs instead of new blocks.
+type pendingTxReader struct {
+	expanded bool
+	lock     sync.Mutex
+	txs      []*tx.Transaction
+	signal   co.Signal
+}
+
+func (r *pendingTxReader) push(tx *tx.Transaction) {
+	r.lock.Lock()
+	// the oldest is dropped if not consumed in time, as the send queue overflows earlier anyway
+	if len(r.txs) >= sendQueueSize {
+		r.txs = r.txs[1:]
+	}
+	r.txs = append(r.txs, tx)
+	r.lock.Unlock()
+
+	r.signal.Signal()
+}
+
+// NewWaiter returns the waiter signaled when new txs arrive.
+func (r *pendingTxReader) NewWaiter() co.Waiter {
+	return r.signal.NewWaiter()
+}
+
+func (r *pendingTxReader) Read() ([]interface{}, bool, error) {
+	r.lock.Lock()
+	txs := r.txs
+	r.txs = nil
+	r.lock.Unlock()
+
+	msgs := make([]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		if !r.expanded {
+			msgs = append(msgs, &PendingTxMessage{ID: tx.ID()})
+			continue
+		}
+		msg, err := convertPendingTx(tx)
+		if err != nil {
+			// unlikely, as txs in pool are all validated
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, false, nil
+}
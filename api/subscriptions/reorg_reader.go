@@ -0,0 +1,73 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/co"
+)
+
+// reorgReader reads reorg events of the chain. It's woken up by reorgs instead of new blocks.
+// Events are buffered as soon as emitted, so that the chain is never blocked by slow clients.
+type reorgReader struct {
+	sub    event.Subscription
+	lock   sync.Mutex
+	events []*chain.ReorgEvent
+	signal co.Signal
+}
+
+func newReorgReader(c *chain.Chain) *reorgReader {
+	ch := make(chan *chain.ReorgEvent, sendQueueSize)
+	r := &reorgReader{sub: c.SubscribeReorg(ch)}
+	go func() {
+		for {
+			select {
+			case ev := <-ch:
+				r.push(ev)
+			case <-r.sub.Err():
+				return
+			}
+		}
+	}()
+	return r
+}
+
+func (r *reorgReader) push(ev *chain.ReorgEvent) {
+	r.lock.Lock()
+	if len(r.events) >= sendQueueSize {
+		r.events = r.events[1:]
+	}
+	r.events = append(r.events, ev)
+	r.lock.Unlock()
+
+	r.signal.Signal()
+}
+
+// Close stops receiving events.
+func (r *reorgReader) Close() {
+	r.sub.Unsubscribe()
+}
+
+// NewWaiter returns the waiter signaled when reorgs happen.
+func (r *reorgReader) NewWaiter() co.Waiter {
+	return r.signal.NewWaiter()
+}
+
+func (r *reorgReader) Read() ([]interface{}, bool, error) {
+	r.lock.Lock()
+	events := r.events
+	r.events = nil
+	r.lock.Unlock()
+
+	msgs := make([]interface{}, 0, len(events))
+	for _, ev := range events {
+		msgs = append(msgs, convertReorg(ev))
+	}
+	return msgs, false, nil
+}
@@ -7,7 +7,9 @@ package subscriptions
 
 import (
 	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -16,12 +18,15 @@ import (
 	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
 )
 
 type Subscriptions struct {
 	backtraceLimit uint32
 	chain          *chain.Chain
+	pendingTxs     *pendingTxHub
 	upgrader       *websocket.Upgrader
 	done           chan struct{}
 	wg             sync.WaitGroup
@@ -31,14 +36,41 @@ type msgReader interface {
 	Read() (msgs []interface{}, hasMore bool, err error)
 }
 
+// waker is implemented by readers which are woken up by their own source, rather than new blocks.
+type waker interface {
+	NewWaiter() co.Waiter
+}
+
+const (
+	// time allowed to write a message to the peer
+	writeWait = 10 * time.Second
+	// time allowed to read the next pong message from the peer
+	pongWait = 60 * time.Second
+	// send pings to peer with this period, must be less than pongWait
+	pingPeriod = (pongWait * 7) / 10
+	// max number of messages queued per connection
+	sendQueueSize = 256
+)
+
+// slowClientPolicy decides what to do when the send queue of a connection is full.
+type slowClientPolicy int
+
+const (
+	disconnectSlowClient slowClientPolicy = iota
+	dropOldestMessage
+)
+
 var (
 	log = log15.New("pkg", "subscriptions")
+
+	errSlowClient = errors.New("client too slow to consume messages")
 )
 
-func New(chain *chain.Chain, allowedOrigins []string, backtraceLimit uint32) *Subscriptions {
-	return &Subscriptions{
+func New(chain *chain.Chain, txPool *txpool.TxPool, allowedOrigins []string, backtraceLimit uint32) *Subscriptions {
+	s := &Subscriptions{
 		backtraceLimit: backtraceLimit,
 		chain:          chain,
+		pendingTxs:     newPendingTxHub(),
 		upgrader: &websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				origin := r.Header.Get("Origin")
@@ -55,6 +87,13 @@ func New(chain *chain.Chain, allowedOrigins []string, backtraceLimit uint32) *Su
 		},
 		done: make(chan struct{}),
 	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.pendingTxs.run(txPool, s.done)
+	}()
+	return s
 }
 
 func (s *Subscriptions) handleBlockReader(w http.ResponseWriter, req *http.Request) (*blockReader, error) {
@@ -130,6 +169,29 @@ func (s *Subscriptions) handleTransferReader(w http.ResponseWriter, req *http.Re
 	return newTransferReader(s.chain, position, transferFilter), nil
 }
 
+func (s *Subscriptions) handleActivityReader(w http.ResponseWriter, req *http.Request) (*activityReader, error) {
+	position, err := s.parsePosition(req.URL.Query().Get("pos"))
+	if err != nil {
+		return nil, err
+	}
+	addrs := req.URL.Query().Get("addrs")
+	if addrs == "" {
+		return nil, utils.BadRequest(errors.New("addrs: required"))
+	}
+	var addresses []thor.Address
+	for _, str := range strings.Split(addrs, ",") {
+		addr, err := thor.ParseAddress(strings.TrimSpace(str))
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "addrs"))
+		}
+		addresses = append(addresses, addr)
+	}
+	if len(addresses) > maxWatchedAddresses {
+		return nil, utils.Forbidden(errors.Errorf("addrs: exceeds limit %v", maxWatchedAddresses))
+	}
+	return newActivityReader(s.chain, position, addresses), nil
+}
+
 func (s *Subscriptions) handleBeatReader(w http.ResponseWriter, req *http.Request) (*beatReader, error) {
 	position, err := s.parsePosition(req.URL.Query().Get("pos"))
 	if err != nil {
@@ -138,33 +200,68 @@ func (s *Subscriptions) handleBeatReader(w http.ResponseWriter, req *http.Reques
 	return newBeatReader(s.chain, position), nil
 }
 
-func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request) error {
-	s.wg.Add(1)
-	defer s.wg.Done()
+func (s *Subscriptions) handlePendingTxReader(w http.ResponseWriter, req *http.Request) (*pendingTxReader, error) {
+	expanded := req.URL.Query().Get("expanded")
+	if expanded != "" && expanded != "false" && expanded != "true" {
+		return nil, utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "expanded"))
+	}
+	return s.pendingTxs.subscribe(expanded == "true"), nil
+}
 
-	var (
-		reader msgReader
-		err    error
-	)
+// newReader creates the reader of subject requested. release should be called once the reader is no longer used.
+func (s *Subscriptions) newReader(w http.ResponseWriter, req *http.Request) (reader msgReader, release func(), err error) {
+	release = func() {}
 	switch mux.Vars(req)["subject"] {
 	case "block":
-		if reader, err = s.handleBlockReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleBlockReader(w, req)
 	case "event":
-		if reader, err = s.handleEventReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleEventReader(w, req)
 	case "transfer":
-		if reader, err = s.handleTransferReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleTransferReader(w, req)
 	case "beat":
-		if reader, err = s.handleBeatReader(w, req); err != nil {
-			return err
+		reader, err = s.handleBeatReader(w, req)
+	case "activity":
+		reader, err = s.handleActivityReader(w, req)
+	case "txpool":
+		pendingTxReader, err := s.handlePendingTxReader(w, req)
+		if err != nil {
+			return nil, nil, err
 		}
+		return pendingTxReader, func() { s.pendingTxs.unsubscribe(pendingTxReader) }, nil
+	case "reorg":
+		reorgReader := newReorgReader(s.chain)
+		return reorgReader, reorgReader.Close, nil
+	default:
+		return nil, nil, utils.HTTPError(errors.New("not found"), http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, release, nil
+}
+
+func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request) error {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	var policy slowClientPolicy
+	switch req.URL.Query().Get("slowPolicy") {
+	case "", "disconnect":
+		policy = disconnectSlowClient
+	case "drop-oldest":
+		policy = dropOldestMessage
 	default:
-		return utils.HTTPError(errors.New("not found"), http.StatusNotFound)
+		return utils.BadRequest(errors.New("slowPolicy: should be one of [disconnect, drop-oldest]"))
+	}
+
+	reader, release, err := s.newReader(w, req)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if !websocket.IsWebSocketUpgrade(req) && acceptsEventStream(req) {
+		return s.serveEventStream(w, req, reader, policy)
 	}
 
 	conn, err := s.upgrader.Upgrade(w, req, nil)
@@ -181,20 +278,25 @@ func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request)
 	}()
 
 	var closeMsg []byte
-	if err := s.pipe(conn, reader); err != nil {
+	if err := s.pipe(conn, reader, policy); err != nil {
 		closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())
 	} else {
 		closeMsg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
 	}
 
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
 	if err := conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
 		log.Debug("write close message", "err", err)
 	}
 	return nil
 }
 
-func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader) error {
+func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader, policy slowClientPolicy) error {
 	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
 	// start read loop to handle close event
 	s.wg.Add(1)
 	go func() {
@@ -207,14 +309,45 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader) error {
 			}
 		}
 	}()
-	ticker := s.chain.NewTicker()
+
+	return s.pump(reader, policy, closed, func(queue <-chan interface{}) error {
+		return writeLoop(conn, queue, pingPeriod)
+	})
+}
+
+// pump feeds messages of reader to the write loop, until the client closed, the subscriptions closed,
+// or error occurred. The write loop is the only writer to the client until pump returns.
+func (s *Subscriptions) pump(reader msgReader, policy slowClientPolicy, closed <-chan struct{}, writeLoop func(queue <-chan interface{}) error) error {
+	var (
+		queue      = make(chan interface{}, sendQueueSize)
+		writerDone = make(chan struct{})
+		writeErr   error
+	)
+	go func() {
+		defer close(writerDone)
+		writeErr = writeLoop(queue)
+	}()
+	defer func() {
+		close(queue)
+		<-writerDone
+	}()
+
+	var ticker co.Waiter
+	if w, ok := reader.(waker); ok {
+		ticker = w.NewWaiter()
+	} else {
+		ticker = s.chain.NewTicker()
+	}
 	for {
 		msgs, hasMore, err := reader.Read()
 		if err != nil {
 			return err
 		}
 		for _, msg := range msgs {
-			if err := conn.WriteJSON(msg); err != nil {
+			if err := enqueue(queue, msg, policy, writerDone); err != nil {
+				if err == errWriterDone {
+					return writeErr
+				}
 				return err
 			}
 		}
@@ -224,6 +357,8 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader) error {
 				return nil
 			case <-closed:
 				return nil
+			case <-writerDone:
+				return writeErr
 			case <-ticker.C():
 			}
 		} else {
@@ -232,10 +367,73 @@ func (s *Subscriptions) pipe(conn *websocket.Conn, reader msgReader) error {
 				return nil
 			case <-closed:
 				return nil
+			case <-writerDone:
+				return writeErr
+			default:
+			}
+		}
+	}
+}
+
+var errWriterDone = errors.New("writer done")
+
+// enqueue puts msg into the send queue. If the queue is full, the oldest message is dropped
+// without waiting for the drop-oldest policy, otherwise the client is considered slow once the
+// queue stays full for writeWait.
+func enqueue(queue chan interface{}, msg interface{}, policy slowClientPolicy, writerDone <-chan struct{}) error {
+	select {
+	case queue <- msg:
+		return nil
+	default:
+	}
+
+	if policy == dropOldestMessage {
+		for {
+			// drop the oldest message to make room
+			select {
+			case <-queue:
+			default:
+			}
+			select {
+			case queue <- msg:
+				return nil
 			default:
 			}
 		}
 	}
+
+	timer := time.NewTimer(writeWait)
+	defer timer.Stop()
+	select {
+	case queue <- msg:
+		return nil
+	case <-writerDone:
+		return errWriterDone
+	case <-timer.C:
+		return errSlowClient
+	}
+}
+
+// writeLoop writes queued messages and pings every pingInterval to conn, until queue closed or error occurred.
+func writeLoop(conn *websocket.Conn, queue <-chan interface{}, pingInterval time.Duration) error {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-queue:
+			if !ok {
+				return nil
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return err
+			}
+		}
+	}
 }
 
 func (s *Subscriptions) parsePosition(posStr string) (thor.Bytes32, error) {
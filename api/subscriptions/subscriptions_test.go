@@ -0,0 +1,131 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func fullQueue() chan interface{} {
+	queue := make(chan interface{}, sendQueueSize)
+	for i := 0; i < sendQueueSize; i++ {
+		queue <- i
+	}
+	return queue
+}
+
+func TestEnqueueDropOldest(t *testing.T) {
+	queue := fullQueue()
+
+	start := time.Now()
+	for i := sendQueueSize; i < sendQueueSize+10; i++ {
+		assert.Nil(t, enqueue(queue, i, dropOldestMessage, nil))
+	}
+	assert.True(t, time.Since(start) < writeWait, "should drop without waiting")
+	assert.Equal(t, sendQueueSize, len(queue), "queue bounded")
+
+	// the oldest ones dropped
+	assert.Equal(t, 10, <-queue)
+	for len(queue) > 1 {
+		<-queue
+	}
+	assert.Equal(t, sendQueueSize+9, <-queue)
+}
+
+func TestEnqueueDisconnect(t *testing.T) {
+	queue := make(chan interface{}, sendQueueSize)
+	assert.Nil(t, enqueue(queue, 0, disconnectSlowClient, nil))
+	assert.Equal(t, 1, len(queue))
+
+	queue = fullQueue()
+	writerDone := make(chan struct{})
+	close(writerDone)
+	assert.Equal(t, errWriterDone, enqueue(queue, sendQueueSize, disconnectSlowClient, writerDone))
+	assert.Equal(t, sendQueueSize, len(queue), "queue bounded")
+	assert.Equal(t, 0, <-queue, "nothing dropped")
+}
+
+func TestWriteEventLoopKeepAlive(t *testing.T) {
+	rec := httptest.NewRecorder()
+	queue := make(chan interface{}, sendQueueSize)
+	done := make(chan error, 1)
+	go func() {
+		done <- writeEventLoop(rec, rec, queue, 10*time.Millisecond)
+	}()
+
+	queue <- "hello"
+	time.Sleep(50 * time.Millisecond)
+	close(queue)
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("loop should return once queue closed")
+	}
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "data: \"hello\"\n\n"), body)
+	assert.Contains(t, body, ": ping\n\n")
+	assert.True(t, rec.Flushed)
+}
+
+func TestWriteLoopKeepAlive(t *testing.T) {
+	queue := make(chan interface{}, sendQueueSize)
+	done := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, req, nil)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- writeLoop(conn, queue, 10*time.Millisecond)
+	}))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	queue <- "hello"
+	var msg string
+	assert.Nil(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "hello", msg)
+
+	// control frames are handled while reading
+	go conn.ReadMessage()
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		t.Fatal("ping expected")
+	}
+
+	close(queue)
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("loop should return once queue closed")
+	}
+}
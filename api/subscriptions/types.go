@@ -33,18 +33,13 @@ type BlockMessage struct {
 	Obsolete     bool           `json:"obsolete"`
 }
 
-func convertBlock(b *chain.Block) (*BlockMessage, error) {
-	header := b.Header()
+func convertBlock(summary *block.Summary, obsolete bool) (*BlockMessage, error) {
+	header := summary.Header
 	signer, err := header.Signer()
 	if err != nil {
 		return nil, err
 	}
 
-	txs := b.Transactions()
-	txIds := make([]thor.Bytes32, len(txs))
-	for i, tx := range txs {
-		txIds[i] = tx.ID()
-	}
 	return &BlockMessage{
 		Number:       header.Number(),
 		ID:           header.ID(),
@@ -55,12 +50,12 @@ func convertBlock(b *chain.Block) (*BlockMessage, error) {
 		GasUsed:      header.GasUsed(),
 		Beneficiary:  header.Beneficiary(),
 		Signer:       signer,
-		Size:         uint32(b.Size()),
+		Size:         uint32(summary.Size),
 		StateRoot:    header.StateRoot(),
 		ReceiptsRoot: header.ReceiptsRoot(),
 		TxsRoot:      header.TxsRoot(),
-		Transactions: txIds,
-		Obsolete:     b.Obsolete,
+		Transactions: summary.TxIDs,
+		Obsolete:     obsolete,
 	}, nil
 }
 
@@ -102,6 +97,15 @@ func convertTransfer(header *block.Header, tx *tx.Transaction, transfer *tx.Tran
 	}, nil
 }
 
+//ActivityMessage activity of a watched address piped by websocket.
+//Roles lists how the address is involved in the tx.
+type ActivityMessage struct {
+	Address  thor.Address `json:"address"`
+	Roles    []string     `json:"roles"`
+	Meta     LogMeta      `json:"meta"`
+	Obsolete bool         `json:"obsolete"`
+}
+
 //EventMessage event piped by websocket
 type EventMessage struct {
 	Address  thor.Address   `json:"address"`
@@ -199,3 +203,73 @@ type BeatMessage struct {
 	K         uint32       `json:"k"`
 	Obsolete  bool         `json:"obsolete"`
 }
+
+// PendingTxMessage tx entering the pool piped by websocket. Fields other than ID are filled only if expanded.
+type PendingTxMessage struct {
+	ID           thor.Bytes32         `json:"id"`
+	Origin       *thor.Address        `json:"origin,omitempty"`
+	ChainTag     byte                 `json:"chainTag,omitempty"`
+	BlockRef     string               `json:"blockRef,omitempty"`
+	Expiration   uint32               `json:"expiration,omitempty"`
+	Clauses      []PendingTxClause    `json:"clauses,omitempty"`
+	GasPriceCoef uint8                `json:"gasPriceCoef,omitempty"`
+	Gas          uint64               `json:"gas,omitempty"`
+	Nonce        *math.HexOrDecimal64 `json:"nonce,omitempty"`
+	DependsOn    *thor.Bytes32        `json:"dependsOn,omitempty"`
+	Size         uint32               `json:"size,omitempty"`
+}
+
+// PendingTxClause clause of the expanded pending tx.
+type PendingTxClause struct {
+	To    *thor.Address         `json:"to"`
+	Value *math.HexOrDecimal256 `json:"value"`
+	Data  string                `json:"data"`
+}
+
+func convertPendingTx(tx *tx.Transaction) (*PendingTxMessage, error) {
+	origin, err := tx.Signer()
+	if err != nil {
+		return nil, err
+	}
+	clauses := make([]PendingTxClause, 0, len(tx.Clauses()))
+	for _, c := range tx.Clauses() {
+		clauses = append(clauses, PendingTxClause{
+			To:    c.To(),
+			Value: (*math.HexOrDecimal256)(c.Value()),
+			Data:  hexutil.Encode(c.Data()),
+		})
+	}
+	br := tx.BlockRef()
+	nonce := math.HexOrDecimal64(tx.Nonce())
+	return &PendingTxMessage{
+		ID:           tx.ID(),
+		Origin:       &origin,
+		ChainTag:     tx.ChainTag(),
+		BlockRef:     hexutil.Encode(br[:]),
+		Expiration:   tx.Expiration(),
+		Clauses:      clauses,
+		GasPriceCoef: tx.GasPriceCoef(),
+		Gas:          tx.Gas(),
+		Nonce:        &nonce,
+		DependsOn:    tx.DependsOn(),
+		Size:         uint32(tx.Size()),
+	}, nil
+}
+
+// ReorgMessage describes switching of the best chain, for clients to roll back blocks dropped.
+// Dropped and Adopted are IDs of blocks after the common ancestor, in ascending order.
+type ReorgMessage struct {
+	AncestorID     thor.Bytes32   `json:"ancestorID"`
+	AncestorNumber uint32         `json:"ancestorNumber"`
+	Dropped        []thor.Bytes32 `json:"dropped"`
+	Adopted        []thor.Bytes32 `json:"adopted"`
+}
+
+func convertReorg(ev *chain.ReorgEvent) *ReorgMessage {
+	return &ReorgMessage{
+		AncestorID:     ev.Ancestor.ID(),
+		AncestorNumber: ev.Ancestor.Number(),
+		Dropped:        append([]thor.Bytes32{}, ev.Dropped...),
+		Adopted:        append([]thor.Bytes32{}, ev.Adopted...),
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package transactions
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/runtime"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/xenv"
+)
+
+// CheckRequest request to check a tx, either a raw one or one pending in the pool.
+type CheckRequest struct {
+	Raw  *string       `json:"raw"`
+	TxID *thor.Bytes32 `json:"txID"`
+}
+
+// CheckResult result of speculative execution of a tx upon the head state.
+type CheckResult struct {
+	// Executable is false if the tx can't be packed into the next block.
+	Executable bool `json:"executable"`
+	// Error tells why the tx is not executable.
+	Error    string `json:"error,omitempty"`
+	Reverted bool   `json:"reverted"`
+	// VMError and RevertedClause describe the clause that caused the revert.
+	VMError        string  `json:"vmError,omitempty"`
+	RevertedClause *uint32 `json:"revertedClause,omitempty"`
+	GasUsed        uint64  `json:"gasUsed"`
+}
+
+func (t *Transactions) handleCheck(w http.ResponseWriter, req *http.Request) error {
+	var checkReq CheckRequest
+	if err := utils.ParseJSON(req.Body, &checkReq); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+
+	var tx *tx.Transaction
+	switch {
+	case checkReq.Raw != nil && checkReq.TxID != nil:
+		return utils.BadRequest(errors.New("body: raw and txID are mutually exclusive"))
+	case checkReq.Raw != nil:
+		decoded, err := (&RawTx{*checkReq.Raw}).decode()
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "raw"))
+		}
+		tx = decoded
+	case checkReq.TxID != nil:
+		if tx = t.pool.Get(*checkReq.TxID); tx == nil {
+			return utils.WriteJSON(w, nil)
+		}
+	default:
+		return utils.BadRequest(errors.New("body: either raw or txID required"))
+	}
+
+	result, err := t.checkTransaction(req.Context(), tx, t.chain.BestBlock().Header())
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// checkTransaction executes tx as if it's packed into the block next to head.
+// The state is discarded after execution.
+func (t *Transactions) checkTransaction(ctx context.Context, tx *tx.Transaction, head *block.Header) (*CheckResult, error) {
+	number := head.Number() + 1
+	notExecutable := func(msg string) (*CheckResult, error) {
+		return &CheckResult{Error: msg}, nil
+	}
+	switch {
+	case tx.ChainTag() != t.chain.Tag():
+		return notExecutable("chain tag mismatch")
+	case tx.HasReservedFields():
+		return notExecutable("reserved fields not empty")
+	case tx.BlockRef().Number() > number:
+		return notExecutable("block ref in the future")
+	case tx.IsExpired(number):
+		return notExecutable("expired")
+	case tx.Gas() > head.GasLimit():
+		return notExecutable("gas too large")
+	}
+
+	if _, err := t.chain.GetTransactionMeta(tx.ID(), head.ID()); err == nil {
+		return notExecutable("known tx")
+	} else if !t.chain.IsNotFound(err) {
+		return nil, err
+	}
+	if dep := tx.DependsOn(); dep != nil {
+		meta, err := t.chain.GetTransactionMeta(*dep, head.ID())
+		if err != nil {
+			if t.chain.IsNotFound(err) {
+				return notExecutable("dep not found")
+			}
+			return nil, err
+		}
+		if meta.Reverted {
+			return notExecutable("dep reverted")
+		}
+	}
+
+	state, err := t.stateCreator.NewState(head.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	rt := runtime.New(t.chain.NewSeeker(head.ID()), state,
+		&xenv.BlockContext{
+			Number:     number,
+			Time:       head.Timestamp() + thor.BlockInterval,
+			GasLimit:   head.GasLimit(),
+			TotalScore: head.TotalScore()})
+
+	executor, err := rt.PrepareTransaction(tx)
+	if err != nil {
+		if err := state.Err(); err != nil {
+			return nil, err
+		}
+		return notExecutable(err.Error())
+	}
+	result := &CheckResult{Executable: true}
+	for i := uint32(0); executor.HasNextClause(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		_, output, err := executor.NextClause()
+		if err != nil {
+			return nil, err
+		}
+		if output.VMErr != nil {
+			index := i
+			result.Reverted = true
+			result.VMError = output.VMErr.Error()
+			result.RevertedClause = &index
+		}
+	}
+	receipt, err := executor.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	if err := rt.Seeker().Err(); err != nil {
+		return nil, err
+	}
+	if err := state.Err(); err != nil {
+		return nil, err
+	}
+	result.GasUsed = receipt.GasUsed
+	return result, nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package transactions
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/abi"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// DecodeRequest request to decode clause data, either of a settled tx or raw data.
+type DecodeRequest struct {
+	ABI  json.RawMessage `json:"abi"`
+	TxID *thor.Bytes32   `json:"txID"`
+	Data *string         `json:"data"`
+}
+
+// DecodedArg a decoded argument.
+type DecodedArg struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// DecodedCall decoded clause data.
+type DecodedCall struct {
+	Method string        `json:"method"`
+	Args   []*DecodedArg `json:"args"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// DecodedEvent decoded event emitted by a clause.
+type DecodedEvent struct {
+	ClauseIndex uint32        `json:"clauseIndex"`
+	Address     thor.Address  `json:"address"`
+	Event       string        `json:"event"`
+	Args        []*DecodedArg `json:"args"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// DecodeResult result of decoding.
+type DecodeResult struct {
+	Clauses []*DecodedCall  `json:"clauses"`
+	Events  []*DecodedEvent `json:"events"`
+}
+
+func (t *Transactions) handleDecode(w http.ResponseWriter, req *http.Request) error {
+	var decodeReq DecodeRequest
+	if err := utils.ParseJSON(req.Body, &decodeReq); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	contractABI, err := abi.New(decodeReq.ABI)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "abi"))
+	}
+
+	switch {
+	case decodeReq.TxID != nil && decodeReq.Data != nil:
+		return utils.BadRequest(errors.New("body: txID and data are mutually exclusive"))
+	case decodeReq.Data != nil:
+		data, err := hexutil.Decode(*decodeReq.Data)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "data"))
+		}
+		return utils.WriteJSON(w, &DecodeResult{
+			Clauses: []*DecodedCall{decodeCall(contractABI, data)},
+		})
+	case decodeReq.TxID != nil:
+		result, err := t.decodeTransaction(contractABI, *decodeReq.TxID)
+		if err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, result)
+	default:
+		return utils.BadRequest(errors.New("body: either txID or data required"))
+	}
+}
+
+func (t *Transactions) decodeTransaction(contractABI *abi.ABI, txID thor.Bytes32) (*DecodeResult, error) {
+	tx, meta, err := t.chain.GetTrunkTransaction(txID)
+	if err != nil {
+		if t.chain.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	receipt, err := t.chain.GetTransactionReceipt(meta.BlockID, meta.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DecodeResult{
+		Clauses: make([]*DecodedCall, 0, len(tx.Clauses())),
+		Events:  []*DecodedEvent{},
+	}
+	for _, clause := range tx.Clauses() {
+		result.Clauses = append(result.Clauses, decodeCall(contractABI, clause.Data()))
+	}
+	for i, output := range receipt.Outputs {
+		for _, ev := range output.Events {
+			result.Events = append(result.Events, decodeEvent(contractABI, uint32(i), ev))
+		}
+	}
+	return result, nil
+}
+
+func decodeCall(contractABI *abi.ABI, data []byte) *DecodedCall {
+	method, err := contractABI.MethodByInput(data)
+	if err != nil {
+		return &DecodedCall{Error: err.Error()}
+	}
+	values, err := method.DecodeInputValues(data)
+	if err != nil {
+		return &DecodedCall{Method: method.Name(), Error: err.Error()}
+	}
+	return &DecodedCall{
+		Method: method.Name(),
+		Args:   namedArgs(method.InputNames(), values),
+	}
+}
+
+func decodeEvent(contractABI *abi.ABI, clauseIndex uint32, ev *tx.Event) *DecodedEvent {
+	decoded := &DecodedEvent{
+		ClauseIndex: clauseIndex,
+		Address:     ev.Address,
+	}
+	if len(ev.Topics) == 0 {
+		decoded.Error = "anonymous event"
+		return decoded
+	}
+	event, found := contractABI.EventByID(ev.Topics[0])
+	if !found {
+		decoded.Error = "event not found"
+		return decoded
+	}
+	decoded.Event = event.Name()
+	values, err := event.DecodeValues(ev.Topics, ev.Data)
+	if err != nil {
+		decoded.Error = err.Error()
+		return decoded
+	}
+	decoded.Args = namedArgs(event.InputNames(), values)
+	return decoded
+}
+
+func namedArgs(names []string, values []interface{}) []*DecodedArg {
+	args := make([]*DecodedArg, 0, len(values))
+	for i, v := range values {
+		args = append(args, &DecodedArg{names[i], formatValue(v)})
+	}
+	return args
+}
+
+// formatValue converts decoded abi values into json friendly forms.
+func formatValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *big.Int:
+		return (*math.HexOrDecimal256)(v)
+	case common.Address:
+		return thor.Address(v)
+	case common.Hash:
+		return thor.Bytes32(v)
+	case []byte:
+		return hexutil.Bytes(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(data), rv)
+			return hexutil.Bytes(data)
+		}
+		list := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list = append(list, formatValue(rv.Index(i).Interface()))
+		}
+		return list
+	}
+	return v
+}
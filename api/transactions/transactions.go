@@ -7,6 +7,7 @@ package transactions
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -15,27 +16,34 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/abi"
 	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/consensus"
+	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/txpool"
 )
 
 type Transactions struct {
-	chain *chain.Chain
-	pool  *txpool.TxPool
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	pool         *txpool.TxPool
+	execLimiter  *utils.ExecLimiter
 }
 
-func New(chain *chain.Chain, pool *txpool.TxPool) *Transactions {
+func New(chain *chain.Chain, stateCreator *state.Creator, pool *txpool.TxPool, execLimiter *utils.ExecLimiter) *Transactions {
 	return &Transactions{
 		chain,
+		stateCreator,
 		pool,
+		execLimiter,
 	}
 }
 
 func (t *Transactions) getRawTransaction(txID thor.Bytes32, blockID thor.Bytes32) (*rawTransaction, error) {
-	txMeta, err := t.chain.GetTransactionMeta(txID, blockID)
+	txMeta, err := t.chain.LookupTransaction(txID, blockID)
 	if err != nil {
 		if t.chain.IsNotFound(err) {
 			return nil, nil
@@ -60,12 +68,14 @@ func (t *Transactions) getRawTransaction(txID thor.Bytes32, blockID thor.Bytes32
 			BlockID:        block.Header().ID(),
 			BlockNumber:    block.Header().Number(),
 			BlockTimestamp: block.Header().Timestamp(),
+			Confirmations:  txMeta.Confirmations,
+			IsTrunk:        txMeta.IsTrunk,
 		},
 	}, nil
 }
 
 func (t *Transactions) getTransactionByID(txID thor.Bytes32, blockID thor.Bytes32) (*Transaction, error) {
-	txMeta, err := t.chain.GetTransactionMeta(txID, blockID)
+	txMeta, err := t.chain.LookupTransaction(txID, blockID)
 	if err != nil {
 		if t.chain.IsNotFound(err) {
 			return nil, nil
@@ -80,32 +90,81 @@ func (t *Transactions) getTransactionByID(txID thor.Bytes32, blockID thor.Bytes3
 	if err != nil {
 		return nil, err
 	}
-	return convertTransaction(tx, h, txMeta.Index)
+	trx, err := convertTransaction(tx, h, txMeta.Index)
+	if err != nil {
+		return nil, err
+	}
+	trx.Meta.Confirmations = txMeta.Confirmations
+	trx.Meta.IsTrunk = txMeta.IsTrunk
+	return trx, nil
 }
 
 //GetTransactionReceiptByID get tx's receipt
-func (t *Transactions) getTransactionReceiptByID(txID thor.Bytes32, blockID thor.Bytes32) (*Receipt, error) {
-	txMeta, err := t.chain.GetTransactionMeta(txID, blockID)
+func (t *Transactions) getTransactionReceiptByID(txID thor.Bytes32, blockID thor.Bytes32) (*Receipt, *chain.TxMeta, error) {
+	receipt, txMeta, err := t.chain.GetTransactionReceiptByID(txID, blockID)
 	if err != nil {
 		if t.chain.IsNotFound(err) {
-			return nil, nil
+			return nil, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
 	}
+	// the tx is still required for its origin and clauses
 	tx, err := t.chain.GetTransaction(txMeta.BlockID, txMeta.Index)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	h, err := t.chain.GetBlockHeader(txMeta.BlockID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	receipt, err := t.chain.GetTransactionReceipt(txMeta.BlockID, txMeta.Index)
+	converted, err := ConvertReceipt(receipt, h, tx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	return converted, txMeta, nil
+}
+
+// replayRevertReason re-executes the block until the reverted clause of the tx, and decodes the revert reason
+// from the returned data. Reverted txs keep no returned data in receipts.
+func (t *Transactions) replayRevertReason(ctx context.Context, blockID thor.Bytes32, txIndex uint64) (string, error) {
+	blk, err := t.chain.GetBlock(blockID)
+	if err != nil {
+		return "", err
+	}
+	rt, err := consensus.New(t.chain, t.stateCreator).NewRuntimeForReplay(blk.Header())
+	if err != nil {
+		return "", err
 	}
-	return convertReceipt(receipt, h, tx)
+	for i, tx := range blk.Transactions() {
+		txExec, err := rt.PrepareTransaction(tx)
+		if err != nil {
+			return "", err
+		}
+		for txExec.HasNextClause() {
+			_, output, err := txExec.NextClause()
+			if err != nil {
+				return "", err
+			}
+			if uint64(i) == txIndex && output.VMErr != nil {
+				reason, _ := abi.DecodeRevertReason(output.Data)
+				return reason, nil
+			}
+		}
+		if uint64(i) == txIndex {
+			return "", nil
+		}
+		if _, err := txExec.Finalize(); err != nil {
+			return "", err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		default:
+		}
+	}
+	return "", nil
 }
+
 func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Request) error {
 	data, err := ioutil.ReadAll(req.Body)
 	if err != nil {
@@ -119,7 +178,7 @@ func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Re
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
 	var sendTx = func(tx *tx.Transaction) error {
-		if err := t.pool.Add(tx); err != nil {
+		if err := t.pool.AddLocal(tx); err != nil {
 			if txpool.IsBadTx(err) {
 				return utils.BadRequest(err)
 			}
@@ -194,13 +253,13 @@ func (t *Transactions) handleGetTransactionByID(w http.ResponseWriter, req *http
 		if err != nil {
 			return err
 		}
-		return utils.WriteJSON(w, tx)
+		return utils.WriteJSONFields(w, tx, req.URL.Query().Get("fields"))
 	}
 	tx, err := t.getTransactionByID(txID, h.ID())
 	if err != nil {
 		return err
 	}
-	return utils.WriteJSON(w, tx)
+	return utils.WriteJSONFields(w, tx, req.URL.Query().Get("fields"))
 
 }
 
@@ -221,11 +280,20 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 		}
 		return err
 	}
-	receipt, err := t.getTransactionReceiptByID(txID, h.ID())
+	revertReason := req.URL.Query().Get("revertReason")
+	if revertReason != "" && revertReason != "false" && revertReason != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "revertReason"))
+	}
+	receipt, meta, err := t.getTransactionReceiptByID(txID, h.ID())
 	if err != nil {
 		return err
 	}
-	return utils.WriteJSON(w, receipt)
+	if receipt != nil && receipt.Reverted && revertReason == "true" {
+		if receipt.RevertReason, err = t.replayRevertReason(req.Context(), meta.BlockID, meta.Index); err != nil {
+			return err
+		}
+	}
+	return utils.WriteJSONFields(w, receipt, req.URL.Query().Get("fields"))
 }
 
 func (t *Transactions) parseHead(head string) (thor.Bytes32, error) {
@@ -243,6 +311,10 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransaction))
+	sub.Path("/decode").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleDecode))
+	sub.Path("/check").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.execLimiter.Wrap(t.handleCheck)))
 	sub.Path("/{id}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionByID))
+	// decoding revert reason replays the block, so it's limited as other executions
+	sub.Path("/{id}/receipt").Queries("revertReason", "true").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.execLimiter.Wrap(t.handleGetTransactionReceiptByID)))
 	sub.Path("/{id}/receipt").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionReceiptByID))
 }
@@ -20,8 +20,10 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/abi"
 	"github.com/vechain/thor/api/transactions"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/logdb"
@@ -43,6 +45,8 @@ func TestTransaction(t *testing.T) {
 	getTx(t)
 	getTxReceipt(t)
 	senTx(t)
+	decodeData(t)
+	checkTransaction(t)
 }
 
 func getTx(t *testing.T) {
@@ -52,6 +56,8 @@ func getTx(t *testing.T) {
 		t.Fatal(err)
 	}
 	checkTx(t, transaction, rtx)
+	assert.Equal(t, uint32(1), rtx.Meta.Confirmations, "in the best block")
+	assert.True(t, rtx.Meta.IsTrunk)
 
 	res = httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"?raw=true")
 	var rawTx map[string]interface{}
@@ -72,6 +78,30 @@ func getTxReceipt(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, uint64(receipt.GasUsed), transaction.Gas(), "gas should be equal")
+	assert.False(t, receipt.Reverted)
+
+	origin, err := transaction.Signer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	best := c.BestBlock().Header()
+	assert.Equal(t, origin, receipt.GasPayer)
+	assert.Equal(t, best.ID(), receipt.Meta.BlockID)
+	assert.Equal(t, best.Number(), receipt.Meta.BlockNumber)
+	assert.Equal(t, transaction.ID(), receipt.Meta.TxID)
+	assert.Equal(t, origin, receipt.Meta.TxOrigin)
+
+	assert.Equal(t, 1, len(receipt.Outputs))
+	assert.Nil(t, receipt.Outputs[0].ContractAddress)
+	assert.Equal(t, 1, len(receipt.Outputs[0].Transfers))
+	transfer := receipt.Outputs[0].Transfers[0]
+	assert.Equal(t, origin, transfer.Sender)
+	assert.Equal(t, *transaction.Clauses()[0].To(), transfer.Recipient)
+	assert.Equal(t, transaction.Clauses()[0].Value(), (*big.Int)(transfer.Amount))
+
+	// unknown tx
+	r = httpGet(t, ts.URL+"/transactions/"+thor.Bytes32{}.String()+"/receipt")
+	assert.Equal(t, "null", string(bytes.TrimSpace(r)))
 }
 
 func senTx(t *testing.T) {
@@ -103,6 +133,17 @@ func senTx(t *testing.T) {
 	}
 	assert.Equal(t, tx.ID().String(), txObj["id"], "should be the same transaction id")
 
+	// malformed raw txs
+	for _, raw := range []string{"0xzz", hexutil.Encode(rlpTx[:len(rlpTx)-1]), hexutil.Encode(append(rlpTx, 0x80))} {
+		data, _ := json.Marshal(transactions.RawTx{Raw: raw})
+		resp, err := http.Post(ts.URL+"/transactions", "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, raw)
+	}
+
 	unsignedTx := transactions.UnSignedTx{
 		ChainTag:   chainTag,
 		BlockRef:   hexutil.Encode(blockRef[:]),
@@ -126,6 +167,93 @@ func senTx(t *testing.T) {
 	assert.Equal(t, tx.ID().String(), txObj["id"], "should be the same transaction id")
 }
 
+func decodeData(t *testing.T) {
+	abiJSON := json.RawMessage(`[{"type":"function","name":"set","inputs":[{"name":"key","type":"bytes32"},{"name":"value","type":"uint256"}]}]`)
+	contractABI, err := abi.New(abiJSON)
+	assert.Nil(t, err)
+	method, _ := contractABI.MethodByName("set")
+	input, err := method.EncodeInput(thor.Bytes32{1}, big.NewInt(10))
+	assert.Nil(t, err)
+
+	data := hexutil.Encode(input)
+	res := httpPost(t, ts.URL+"/transactions/decode", transactions.DecodeRequest{ABI: abiJSON, Data: &data})
+	var result transactions.DecodeResult
+	if err := json.Unmarshal(res, &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(result.Clauses))
+	assert.Equal(t, "set", result.Clauses[0].Method)
+	assert.Equal(t, 2, len(result.Clauses[0].Args))
+	assert.Equal(t, "key", result.Clauses[0].Args[0].Name)
+	assert.Equal(t, thor.Bytes32{1}.String(), result.Clauses[0].Args[0].Value)
+	assert.Equal(t, "0xa", result.Clauses[0].Args[1].Value)
+}
+
+func checkTransaction(t *testing.T) {
+	encode := func(tx *tx.Transaction) *string {
+		data, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := hexutil.Encode(data)
+		return &raw
+	}
+	sign := func(tx *tx.Transaction) *tx.Transaction {
+		sig, err := crypto.Sign(tx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.WithSignature(sig)
+	}
+	check := func(req transactions.CheckRequest) *transactions.CheckResult {
+		var result *transactions.CheckResult
+		if err := json.Unmarshal(httpPost(t, ts.URL+"/transactions/check", req), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := check(transactions.CheckRequest{Raw: encode(transaction)})
+	assert.False(t, result.Executable)
+	assert.Equal(t, "known tx", result.Error)
+
+	to := thor.BytesToAddress([]byte("to"))
+	ok := sign(new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(10).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		BlockRef(tx.NewBlockRef(0)).
+		Build())
+	result = check(transactions.CheckRequest{Raw: encode(ok)})
+	assert.True(t, result.Executable)
+	assert.False(t, result.Reverted)
+	assert.Equal(t, uint64(21000), result.GasUsed)
+
+	// only executor can set params
+	method, _ := builtin.Params.ABI.MethodByName("set")
+	input, err := method.EncodeInput(thor.Bytes32{1}, big.NewInt(1))
+	assert.Nil(t, err)
+	reverted := sign(new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(10).
+		Gas(100000).
+		Clause(tx.NewClause(&to)).
+		Clause(tx.NewClause(&builtin.Params.Address).WithData(input)).
+		BlockRef(tx.NewBlockRef(0)).
+		Build())
+	result = check(transactions.CheckRequest{Raw: encode(reverted)})
+	assert.True(t, result.Executable)
+	assert.True(t, result.Reverted)
+	assert.Equal(t, uint32(1), *result.RevertedClause)
+	assert.NotEmpty(t, result.VMError)
+
+	// not in pool
+	id := ok.ID()
+	res := httpPost(t, ts.URL+"/transactions/check", transactions.CheckRequest{TxID: &id})
+	assert.Equal(t, "null", string(bytes.TrimSpace(res)))
+}
+
 func httpPost(t *testing.T, url string, obj interface{}) []byte {
 	data, err := json.Marshal(obj)
 	if err != nil {
@@ -208,7 +336,7 @@ func initTransactionServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	router := mux.NewRouter()
-	transactions.New(c, txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})).Mount(router, "/transactions")
+	transactions.New(c, stateC, txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute}), nil).Mount(router, "/transactions")
 	ts = httptest.NewServer(router)
 
 }
@@ -185,6 +185,10 @@ type TxMeta struct {
 	BlockID        thor.Bytes32 `json:"blockID"`
 	BlockNumber    uint32       `json:"blockNumber"`
 	BlockTimestamp uint64       `json:"blockTimestamp"`
+	// count of blocks from the containing one to the head, both included
+	Confirmations uint32 `json:"confirmations"`
+	// whether the containing block is on the best chain
+	IsTrunk bool `json:"isTrunk"`
 }
 
 type LogMeta struct {
@@ -204,6 +208,8 @@ type Receipt struct {
 	Reverted bool                  `json:"reverted"`
 	Meta     LogMeta               `json:"meta"`
 	Outputs  []*Output             `json:"outputs"`
+	// present only if requested, and the tx reverted with a reason
+	RevertReason string `json:"revertReason,omitempty"`
 }
 
 // Output output of clause execution.
@@ -227,8 +233,8 @@ type Transfer struct {
 	Amount    *math.HexOrDecimal256 `json:"amount"`
 }
 
-//ConvertReceipt convert a raw clause into a jason format clause
-func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transaction) (*Receipt, error) {
+// ConvertReceipt converts the tx receipt into the json format, with meta of the containing block.
+func ConvertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transaction) (*Receipt, error) {
 	reward := math.HexOrDecimal256(*txReceipt.Reward)
 	paid := math.HexOrDecimal256(*txReceipt.Paid)
 	signer, err := tx.Signer()
@@ -17,6 +17,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/api/transfers"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/thor"
@@ -86,7 +87,7 @@ func initLogServer(t *testing.T) {
 	}
 
 	router := mux.NewRouter()
-	transfers.New(db).Mount(router, "/logs/transfer")
+	transfers.New(db, utils.FilterLimits{}).Mount(router, "/logs/transfer")
 	ts = httptest.NewServer(router)
 }
 
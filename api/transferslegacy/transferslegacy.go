@@ -16,21 +16,31 @@ import (
 )
 
 type TransfersLegacy struct {
-	db *logdb.LogDB
+	db     *logdb.LogDB
+	limits utils.FilterLimits
 }
 
-func New(db *logdb.LogDB) *TransfersLegacy {
+func New(db *logdb.LogDB, limits utils.FilterLimits) *TransfersLegacy {
 	return &TransfersLegacy{
 		db,
+		limits,
 	}
 }
 
 //Filter query logs with option
 func (t *TransfersLegacy) filter(ctx context.Context, filter *logdb.TransferFilter) ([]*FilteredTransfer, error) {
+	opts, err := t.limits.Narrow(filter.Range, filter.Options)
+	if err != nil {
+		return nil, err
+	}
+	filter.Options = opts
 	transfers, err := t.db.FilterTransfers(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
+	if err := t.limits.CheckResults(len(transfers)); err != nil {
+		return nil, err
+	}
 	tLogs := make([]*FilteredTransfer, len(transfers))
 	for i, trans := range transfers {
 		tLogs[i] = convertTransfer(trans)
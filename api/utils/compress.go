@@ -0,0 +1,155 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// responses smaller than this are not worth compressing
+const minCompressSize = 1024
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+	zlibWriters = sync.Pool{New: func() interface{} { return zlib.NewWriter(nil) }}
+)
+
+// acceptedEncoding returns the preferred encoding among gzip and deflate accepted by the request, or empty if none.
+func acceptedEncoding(req *http.Request) string {
+	var deflate bool
+	for _, item := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(item, ";")
+		if len(parts) > 1 && strings.Replace(strings.TrimSpace(parts[1]), " ", "", -1) == "q=0" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case "gzip":
+			return "gzip"
+		case "deflate":
+			deflate = true
+		}
+	}
+	if deflate {
+		return "deflate"
+	}
+	return ""
+}
+
+// compressWriter buffers the beginning of response to decide whether to compress.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+	buf      []byte
+	started  bool
+	enc      io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.started {
+		return
+	}
+	w.status = status
+}
+
+// start writes header, with compression if compress is true and the response is compressible.
+func (w *compressWriter) start(compress bool) {
+	w.started = true
+	header := w.Header()
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if header.Get("Content-Encoding") != "" || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		compress = false
+	}
+	if compress {
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.encoding)
+		if w.encoding == "gzip" {
+			gw := gzipWriters.Get().(*gzip.Writer)
+			gw.Reset(w.ResponseWriter)
+			w.enc = gw
+		} else {
+			zw := zlibWriters.Get().(*zlib.Writer)
+			zw.Reset(w.ResponseWriter)
+			w.enc = zw
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) > 0 {
+		w.write(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *compressWriter) write(data []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.started {
+		return w.write(data)
+	}
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= minCompressSize {
+		w.start(true)
+	}
+	return len(data), nil
+}
+
+// Flush sends buffered data at once, so streamed responses are not compressed unless already being compressed.
+func (w *compressWriter) Flush() {
+	if !w.started {
+		w.start(false)
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressWriter) close() {
+	if !w.started {
+		w.start(false)
+	}
+	switch enc := w.enc.(type) {
+	case *gzip.Writer:
+		enc.Close()
+		gzipWriters.Put(enc)
+	case *zlib.Writer:
+		enc.Close()
+		zlibWriters.Put(enc)
+	}
+}
+
+// Compress compresses responses with gzip or deflate as accepted by the client.
+// Small responses and protocol upgrades (e.g. websocket) are left as is.
+func Compress(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		encoding := acceptedEncoding(req)
+		if encoding == "" || req.Header.Get("Upgrade") != "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.close()
+		h.ServeHTTP(cw, req)
+	})
+}
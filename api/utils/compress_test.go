@@ -0,0 +1,71 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/utils"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"key":"value"}`, 1000)
+	h := utils.Compress(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if req.URL.Path == "/large" {
+			w.Write([]byte(large))
+		} else {
+			w.Write([]byte("small"))
+		}
+	}))
+	do := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("/large", "gzip, deflate")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	assert.True(t, rec.Body.Len() < len(large))
+	gr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gr)
+	assert.Nil(t, err)
+	assert.Equal(t, large, string(data))
+
+	rec = do("/large", "deflate, gzip;q=0")
+	assert.Equal(t, "deflate", rec.Header().Get("Content-Encoding"))
+	zr, err := zlib.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = ioutil.ReadAll(zr)
+	assert.Nil(t, err)
+	assert.Equal(t, large, string(data))
+
+	rec = do("/large", "")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"), "not accepted")
+	assert.Equal(t, large, rec.Body.String())
+
+	rec = do("/small", "gzip")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"), "too small")
+	assert.Equal(t, "small", rec.Body.String())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExecLimiter bounds concurrent executions (calls, traces, etc.) triggered by API requests.
+// Requests over the limit are queued for a while, then answered with 503.
+// A nil ExecLimiter imposes no limit.
+type ExecLimiter struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+// NewExecLimiter creates a limiter allowing max concurrent executions.
+// It returns nil if max is not positive.
+func NewExecLimiter(max int, wait time.Duration) *ExecLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &ExecLimiter{
+		slots: make(chan struct{}, max),
+		wait:  wait,
+	}
+}
+
+// Wrap limits the handler.
+func (l *ExecLimiter) Wrap(f HandlerFunc) HandlerFunc {
+	if l == nil {
+		return f
+	}
+	return func(w http.ResponseWriter, req *http.Request) error {
+		timer := time.NewTimer(l.wait)
+		defer timer.Stop()
+
+		select {
+		case l.slots <- struct{}{}:
+		case <-timer.C:
+			w.Header().Set("Retry-After", "1")
+			return HTTPError(errors.New("too many executions"), http.StatusServiceUnavailable)
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+		defer func() { <-l.slots }()
+		return f(w, req)
+	}
+}
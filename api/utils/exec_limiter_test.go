@@ -0,0 +1,52 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/utils"
+)
+
+func TestExecLimiter(t *testing.T) {
+	var nilLimiter *utils.ExecLimiter
+	assert.Nil(t, utils.NewExecLimiter(0, time.Second))
+
+	release := make(chan struct{})
+	entered := make(chan struct{})
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		entered <- struct{}{}
+		<-release
+		return nil
+	}
+	// nil limiter doesn't limit
+	nilHandler := utils.WrapHandlerFunc(nilLimiter.Wrap(handler))
+	go nilHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	go nilHandler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-entered
+	<-entered
+	release <- struct{}{}
+	release <- struct{}{}
+
+	limited := utils.WrapHandlerFunc(utils.NewExecLimiter(1, 10*time.Millisecond).Wrap(handler))
+	done := make(chan struct{})
+	go func() {
+		limited(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		close(done)
+	}()
+	<-entered
+
+	rec := httptest.NewRecorder()
+	limited(rec, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	release <- struct{}{}
+	<-done
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WriteJSONFields like WriteJSON, but trims the object to the given comma separated fields.
+// Nested fields are addressed with dot, e.g. 'meta.blockID'. Arrays are trimmed element-wise.
+// Empty fields means all fields.
+func WriteJSONFields(w http.ResponseWriter, obj interface{}, fields string) error {
+	if fields == "" {
+		return WriteJSON(w, obj)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return HTTPError(err, 500)
+	}
+	tree := make(fieldTree)
+	for _, path := range strings.Split(fields, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			return BadRequest(errors.New("fields: empty field"))
+		}
+		tree.add(strings.Split(path, "."))
+	}
+	trimmed, err := tree.selectFrom(data)
+	if err != nil {
+		return BadRequest(errors.WithMessage(err, "fields"))
+	}
+	w.Header().Set("Content-Type", JSONContentType)
+	w.Write(trimmed)
+	return nil
+}
+
+// fieldTree tree of selected fields, nil subtree means the whole field selected.
+type fieldTree map[string]fieldTree
+
+func (t fieldTree) add(path []string) {
+	sub, ok := t[path[0]]
+	if len(path) == 1 {
+		t[path[0]] = nil
+		return
+	}
+	if ok && sub == nil {
+		// whole field already selected
+		return
+	}
+	if sub == nil {
+		sub = make(fieldTree)
+		t[path[0]] = sub
+	}
+	sub.add(path[1:])
+}
+
+func (t fieldTree) selectFrom(data json.RawMessage) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return data, nil
+	}
+	switch data[0] {
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		trimmed := make(map[string]json.RawMessage, len(t))
+		for key, sub := range t {
+			value, ok := obj[key]
+			if !ok {
+				continue
+			}
+			if sub != nil {
+				var err error
+				if value, err = sub.selectFrom(value); err != nil {
+					return nil, err
+				}
+			}
+			trimmed[key] = value
+		}
+		return json.Marshal(trimmed)
+	case '[':
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		for i, elem := range list {
+			var err error
+			if list[i], err = t.selectFrom(elem); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(list)
+	case 'n':
+		// null
+		return data, nil
+	default:
+		return nil, errors.New("can't select fields of a non-object value")
+	}
+}
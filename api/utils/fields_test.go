@@ -0,0 +1,47 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/utils"
+)
+
+func TestWriteJSONFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"number": 1,
+		"id":     "0x01",
+		"meta":   map[string]interface{}{"blockID": "0x02", "txID": "0x03"},
+		"list":   []map[string]int{{"a": 1, "b": 2}},
+	}
+
+	tests := []struct {
+		fields   string
+		status   int
+		expected string
+	}{
+		{"number", http.StatusOK, `{"number":1}`},
+		{"number,meta.blockID", http.StatusOK, `{"meta":{"blockID":"0x02"},"number":1}`},
+		{"meta.txID,meta", http.StatusOK, `{"meta":{"blockID":"0x02","txID":"0x03"}}`},
+		{"list.b,unknown", http.StatusOK, `{"list":[{"b":2}]}`},
+		{"number.x", http.StatusBadRequest, ""},
+		{"number,,id", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		utils.WrapHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return utils.WriteJSONFields(w, obj, tt.fields)
+		})(rec, nil)
+		assert.Equal(t, tt.status, rec.Code, tt.fields)
+		if tt.status == http.StatusOK {
+			assert.Equal(t, tt.expected, rec.Body.String(), tt.fields)
+		}
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/state"
 )
 
 type httpError struct {
@@ -46,6 +49,7 @@ func Forbidden(cause error) error {
 
 // HandlerFunc like http.HandlerFunc, bu it returns an error.
 // If the returned error is httpError type, httpError.status will be responded,
+// http.StatusGone for errors of reading pruned state,
 // otherwise http.StatusInternalServerError responded.
 type HandlerFunc func(http.ResponseWriter, *http.Request) error
 
@@ -60,6 +64,8 @@ func WrapHandlerFunc(f HandlerFunc) http.HandlerFunc {
 				} else {
 					w.WriteHeader(he.status)
 				}
+			} else if state.IsPruned(errors.Cause(err)) {
+				http.Error(w, "state not available, it's pruned by the node: "+err.Error(), http.StatusGone)
 			} else {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 			}
@@ -0,0 +1,63 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/thor"
+)
+
+// FilterLimits server side limits of log filter queries.
+// Zero value means no limit.
+type FilterLimits struct {
+	MaxBlockRange uint64 `json:"maxBlockRange"`
+	MaxResults    uint64 `json:"maxResults"`
+}
+
+// QueryTooWide convenience method to create the error asking client to narrow the query.
+func QueryTooWide(cause error) error {
+	return &httpError{
+		cause:  errors.WithMessage(cause, "query too wide, narrow your query"),
+		status: http.StatusRequestEntityTooLarge,
+	}
+}
+
+// Narrow checks range of filter query against limits, and returns options to query with.
+// If results are limited, the returned options query one more item than limit, to detect overflow by CheckResults.
+func (l FilterLimits) Narrow(rng *logdb.Range, opts *logdb.Options) (*logdb.Options, error) {
+	if l.MaxBlockRange > 0 {
+		if rng == nil || rng.To < rng.From {
+			return nil, QueryTooWide(errors.New("range: should be bounded"))
+		}
+		blocks := rng.To - rng.From
+		if rng.Unit == logdb.Time {
+			blocks /= thor.BlockInterval
+		}
+		if blocks > l.MaxBlockRange {
+			return nil, QueryTooWide(errors.Errorf("range: exceeds %v blocks", l.MaxBlockRange))
+		}
+	}
+	if l.MaxResults > 0 {
+		if opts == nil {
+			return &logdb.Options{Offset: 0, Limit: l.MaxResults + 1}, nil
+		}
+		if opts.Limit > l.MaxResults {
+			return nil, QueryTooWide(errors.Errorf("options.limit: exceeds %v", l.MaxResults))
+		}
+	}
+	return opts, nil
+}
+
+// CheckResults checks count of results queried with options returned by Narrow.
+func (l FilterLimits) CheckResults(n int) error {
+	if l.MaxResults > 0 && uint64(n) > l.MaxResults {
+		return QueryTooWide(errors.Errorf("results: exceeds %v", l.MaxResults))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// buckets are swept when their count exceeds this
+	maxRateBuckets = 10000
+	// min interval between sweeps, so that the cost of sweeping is amortised
+	rateSweepInterval = time.Minute
+)
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// full returns whether the bucket is refilled to full at now, then equivalent to an absent one.
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+// RateLimiter limits request rate per client IP with token buckets.
+// Requests are grouped by the first path segment after version prefix (e.g. 'transactions'),
+// and each group of each IP has its own bucket. A nil RateLimiter imposes no limit.
+type RateLimiter struct {
+	rate       float64            // default requests per second
+	routeRates map[string]float64 // rates by route group, override the default
+	burst      float64
+	now        func() time.Time
+
+	lock      sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rate requests per second per IP, and up to burst
+// requests at once. routeRates overrides rate of route groups. Non-positive rate means no limit.
+// It returns nil if there is no limit at all.
+func NewRateLimiter(rate float64, burst int, routeRates map[string]float64) *RateLimiter {
+	limited := rate > 0
+	for _, r := range routeRates {
+		limited = limited || r > 0
+	}
+	if !limited {
+		return nil
+	}
+	return &RateLimiter{
+		rate:       rate,
+		routeRates: routeRates,
+		burst:      float64(burst),
+		now:        time.Now,
+		buckets:    make(map[string]*tokenBucket),
+	}
+}
+
+func routeGroup(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	if strings.HasPrefix(path, "v1/") {
+		path = path[len("v1/"):]
+	}
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// take takes a token from the bucket of key. If none, it returns how long to wait for one.
+func (l *RateLimiter) take(key string, rate float64) (bool, time.Duration) {
+	burst := math.Max(l.burst, math.Max(rate, 1))
+	now := l.now()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.buckets) > maxRateBuckets && now.Sub(l.lastSweep) >= rateSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// sweep drops full buckets. The caller must hold the lock.
+func (l *RateLimiter) sweep(now time.Time) {
+	for k, b := range l.buckets {
+		if b.full(now) {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Handler limits the handler. Requests over the limit are answered with 429.
+func (l *RateLimiter) Handler(h http.Handler) http.Handler {
+	if l == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		group := routeGroup(req.URL.Path)
+		rate, ok := l.routeRates[group]
+		if !ok {
+			rate = l.rate
+		}
+		if rate > 0 {
+			ip, _, err := net.SplitHostPort(req.RemoteAddr)
+			if err != nil {
+				ip = req.RemoteAddr
+			}
+			if ok, wait := l.take(ip+" "+group, rate); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+		}
+		h.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterSweep(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewRateLimiter(10, 1, map[string]float64{"transactions": 0.01})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < maxRateBuckets; i++ {
+		l.take(strconv.Itoa(i)+" blocks", 10)
+	}
+	l.take("1.1.1.1 transactions", 0.01)
+
+	// full by own rate of the group, not the rate of the sweeping request
+	now = now.Add(time.Second)
+	assert.True(t, l.buckets["0 blocks"].full(now))
+	assert.False(t, l.buckets["1.1.1.1 transactions"].full(now))
+
+	// not swept until the interval elapsed
+	l.lastSweep = now
+	l.take("2.2.2.2 transactions", 0.01)
+	assert.Equal(t, maxRateBuckets+2, len(l.buckets))
+
+	now = now.Add(rateSweepInterval)
+	l.take("3.3.3.3 transactions", 0.01)
+	assert.Equal(t, 3, len(l.buckets))
+	assert.Contains(t, l.buckets, "1.1.1.1 transactions")
+	assert.Contains(t, l.buckets, "2.2.2.2 transactions")
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/utils"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var nilLimiter *utils.RateLimiter
+	assert.Nil(t, utils.NewRateLimiter(0, 10, map[string]float64{"blocks": 0}))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	do := func(h http.Handler, ip, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = ip + ":12345"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// nil limiter doesn't limit
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, do(nilLimiter.Handler(ok), "1.1.1.1", "/blocks/best").Code)
+	}
+
+	h := utils.NewRateLimiter(0, 2, map[string]float64{"transactions": 0.1}).Handler(ok)
+	assert.Equal(t, http.StatusOK, do(h, "1.1.1.1", "/v1/transactions").Code)
+	assert.Equal(t, http.StatusOK, do(h, "1.1.1.1", "/transactions/0x00").Code)
+	rec := do(h, "1.1.1.1", "/transactions")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Retry-After"))
+
+	// other IPs are not affected
+	assert.Equal(t, http.StatusOK, do(h, "2.2.2.2", "/transactions").Code)
+	// routes not configured are not limited
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, do(h, "1.1.1.1", "/blocks/best").Code)
+	}
+
+	h = utils.NewRateLimiter(0.1, 1, nil).Handler(ok)
+	assert.Equal(t, http.StatusOK, do(h, "1.1.1.1", "/blocks/best").Code)
+	assert.Equal(t, http.StatusOK, do(h, "1.1.1.1", "/accounts/0x00").Code, "groups have separate buckets")
+	assert.Equal(t, http.StatusTooManyRequests, do(h, "1.1.1.1", "/blocks/0").Code)
+}
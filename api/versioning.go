@@ -0,0 +1,66 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+)
+
+const currentVersionPrefix = "/v1"
+
+// legacyRoutes maps paths of early API releases to their successors.
+var legacyRoutes = []struct {
+	pattern *regexp.Regexp
+	repl    string
+}{
+	{regexp.MustCompile(`^/transaction/hash/([^/]+)$`), "/transactions/$1"},
+	{regexp.MustCompile(`^/transaction/hash/([^/]+)/receipt$`), "/transactions/$1/receipt"},
+}
+
+// newCompatHandler serves unversioned paths by forwarding them to routes of current version.
+// Deprecation is signaled via response headers, and after sunset (if not zero),
+// unversioned paths are no longer served.
+func newCompatHandler(router *mux.Router, sunset time.Time) http.HandlerFunc {
+	gone := utils.WrapHandlerFunc(func(w http.ResponseWriter, req *http.Request) error {
+		return utils.HTTPError(errors.Errorf("unversioned path removed, use %v%v instead", currentVersionPrefix, req.URL.Path), http.StatusGone)
+	})
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		path := req.URL.Path
+		if path == currentVersionPrefix || strings.HasPrefix(path, currentVersionPrefix+"/") {
+			// unmatched versioned path
+			http.NotFound(w, req)
+			return
+		}
+		for _, r := range legacyRoutes {
+			if r.pattern.MatchString(path) {
+				path = r.pattern.ReplaceAllString(path, r.repl)
+				break
+			}
+		}
+		successor := currentVersionPrefix + path
+
+		if !sunset.IsZero() {
+			if !time.Now().Before(sunset) {
+				gone(w, req)
+				return
+			}
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+
+		req.URL.Path = successor
+		router.ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompatHandler(t *testing.T) {
+	newRouter := func(sunset time.Time) *mux.Router {
+		router := mux.NewRouter()
+		v1 := router.PathPrefix(currentVersionPrefix).Subrouter()
+		v1.Path("/transactions/{id}").HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(mux.Vars(req)["id"]))
+		})
+		router.PathPrefix("/").Handler(newCompatHandler(router, sunset))
+		return router
+	}
+	get := func(router *mux.Router, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	router := newRouter(time.Time{})
+	rec := get(router, "/v1/transactions/0x01")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0x01", rec.Body.String())
+	assert.Empty(t, rec.Header().Get("Deprecation"), "versioned path is not deprecated")
+
+	rec = get(router, "/transactions/0x01")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0x01", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Equal(t, `</v1/transactions/0x01>; rel="successor-version"`, rec.Header().Get("Link"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+
+	rec = get(router, "/transaction/hash/0x02")
+	assert.Equal(t, http.StatusOK, rec.Code, "legacy route")
+	assert.Equal(t, "0x02", rec.Body.String())
+
+	rec = get(router, "/v1/unknown")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	sunset := time.Now().Add(time.Hour)
+	rec = get(newRouter(sunset), "/transactions/0x01")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, sunset.UTC().Format(http.TimeFormat), rec.Header().Get("Sunset"))
+
+	rec = get(newRouter(time.Now().Add(-time.Hour)), "/transactions/0x01")
+	assert.Equal(t, http.StatusGone, rec.Code, "removed after sunset")
+}
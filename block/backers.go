@@ -0,0 +1,82 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package block
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/thor"
+)
+
+// Since the VIP193 fork, the header extension carries signatures of backers, which are members of a
+// committee of authority nodes co-signing the proposed block. Backers sign the proposal hash, which
+// excludes the total score, since the score counts backers.
+
+// ProposalHash computes hash of header fields signed by backers, i.e. fields excluding total score,
+// extension and signature.
+func (h *Header) ProposalHash() (hash thor.Bytes32) {
+	hw := thor.NewBlake2b()
+	rlp.Encode(hw, []interface{}{
+		h.body.ParentID,
+		h.body.Timestamp,
+		h.body.GasLimit,
+		h.body.Beneficiary,
+
+		h.body.GasUsed,
+
+		h.body.TxsRoot,
+		h.body.StateRoot,
+		h.body.ReceiptsRoot,
+	})
+	hw.Sum(hash[:0])
+	return
+}
+
+// BackerSignatures decodes backer signatures from the extension. No extension means no backers.
+func (h *Header) BackerSignatures() ([][]byte, error) {
+	ext := h.Extension()
+	if len(ext) == 0 {
+		return nil, nil
+	}
+	var sigs [][]byte
+	if err := rlp.DecodeBytes(ext, &sigs); err != nil {
+		return nil, errors.WithMessage(err, "decode backer signatures")
+	}
+	return sigs, nil
+}
+
+// Backers recovers backers from backer signatures. The result is memoized.
+func (h *Header) Backers() ([]thor.Address, error) {
+	h.cache.backers.once.Do(func() {
+		sigs, err := h.BackerSignatures()
+		if err != nil {
+			h.cache.backers.err = err
+			return
+		}
+		hash := h.ProposalHash()
+		addrs := make([]thor.Address, 0, len(sigs))
+		for _, sig := range sigs {
+			pub, err := crypto.SigToPub(hash.Bytes(), sig)
+			if err != nil {
+				h.cache.backers.err = errors.WithMessage(err, "recover backer")
+				return
+			}
+			addrs = append(addrs, thor.Address(crypto.PubkeyToAddress(*pub)))
+		}
+		h.cache.backers.addrs = addrs
+	})
+	return append([]thor.Address(nil), h.cache.backers.addrs...), h.cache.backers.err
+}
+
+// BackerSignatures set backer signatures into the extension. No signatures means no extension.
+func (b *Builder) BackerSignatures(sigs [][]byte) *Builder {
+	if len(sigs) == 0 {
+		return b.Extension(nil)
+	}
+	data, _ := rlp.EncodeToBytes(sigs)
+	return b.Extension(data)
+}
@@ -6,6 +6,7 @@
 package block_test
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 	"time"
@@ -85,3 +86,41 @@ func TestBlock(t *testing.T) {
 	fmt.Println(b.Header().ID())
 	fmt.Println(&b)
 }
+
+func TestBuilderFinalize(t *testing.T) {
+	tx1 := new(tx.Builder).Gas(21000).Clause(tx.NewClause(&thor.Address{})).Build()
+	tx2 := new(tx.Builder).Gas(53000).Clause(tx.NewClause(nil)).Build()
+	receipts := tx.Receipts{{GasUsed: 21000}, {GasUsed: 50000}}
+
+	builder := new(Builder).GasLimit(100000).Transaction(tx1).Transaction(tx2)
+	assert.NotNil(t, builder.Receipts(receipts[:1]).Finalize(), "count mismatch")
+
+	assert.Nil(t, builder.Receipts(receipts).Finalize())
+	h := builder.Build().Header()
+	assert.Equal(t, uint64(71000), h.GasUsed())
+	assert.Equal(t, receipts.RootHash(), h.ReceiptsRoot())
+	assert.Equal(t, tx.Transactions{tx1, tx2}.RootHash(), h.TxsRoot())
+
+	builder.GasLimit(70000)
+	assert.NotNil(t, builder.Finalize(), "exceeds gas limit")
+
+	builder.GasLimit(100000)
+	assert.NotNil(t, builder.Receipts(tx.Receipts{{GasUsed: 21000}, {GasUsed: 60000}}).Finalize(), "exceeds tx gas")
+}
+
+func TestBlockWriteTo(t *testing.T) {
+	builder := new(Builder).ParentID(thor.BytesToBytes32([]byte("parent")))
+	for _, count := range []int{0, 1, 100} {
+		for i := 0; i < count; i++ {
+			builder.Transaction(new(tx.Builder).Nonce(uint64(i)).Clause(tx.NewClause(&thor.Address{})).Build())
+		}
+		b := builder.Build()
+
+		expected, _ := rlp.EncodeToBytes(b)
+		var buf bytes.Buffer
+		n, err := b.WriteTo(&buf)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(len(expected)), n)
+		assert.Equal(t, expected, buf.Bytes())
+	}
+}
@@ -6,6 +6,7 @@
 package block
 
 import (
+	"github.com/pkg/errors"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 )
@@ -14,6 +15,7 @@ import (
 type Builder struct {
 	headerBody headerBody
 	txs        tx.Transactions
+	receipts   tx.Receipts
 }
 
 // ParentID set parent id.
@@ -64,12 +66,52 @@ func (b *Builder) ReceiptsRoot(hash thor.Bytes32) *Builder {
 	return b
 }
 
+// Extension set the opaque header extension. Empty data means no extension.
+func (b *Builder) Extension(data []byte) *Builder {
+	if len(data) == 0 {
+		b.headerBody.Extension = nil
+	} else {
+		b.headerBody.Extension = [][]byte{append([]byte(nil), data...)}
+	}
+	return b
+}
+
 // Transaction add a transaction.
 func (b *Builder) Transaction(tx *tx.Transaction) *Builder {
 	b.txs = append(b.txs, tx)
 	return b
 }
 
+// Receipts set receipts of transactions, which are consumed by Finalize.
+func (b *Builder) Receipts(receipts tx.Receipts) *Builder {
+	b.receipts = receipts
+	return b
+}
+
+// Finalize computes txs root, receipts root and gas used from the transactions and receipts,
+// and checks that they are consistent with each other. It should be called before Build
+// when receipts are available.
+func (b *Builder) Finalize() error {
+	if len(b.receipts) != len(b.txs) {
+		return errors.Errorf("receipts count mismatch: want %v, got %v", len(b.txs), len(b.receipts))
+	}
+	var gasUsed uint64
+	for i, r := range b.receipts {
+		if r.GasUsed > b.txs[i].Gas() {
+			return errors.Errorf("receipt %v: gas used exceeds tx gas", i)
+		}
+		gasUsed += r.GasUsed
+	}
+	if gasUsed > b.headerBody.GasLimit {
+		return errors.Errorf("gas used exceeds gas limit: limit %v, used %v", b.headerBody.GasLimit, gasUsed)
+	}
+
+	b.headerBody.TxsRoot = b.txs.RootHash()
+	b.headerBody.ReceiptsRoot = b.receipts.RootHash()
+	b.headerBody.GasUsed = gasUsed
+	return nil
+}
+
 // Build build a block object.
 func (b *Builder) Build() *Block {
 	header := Header{body: b.headerBody}
@@ -7,6 +7,7 @@ package block
 
 import (
 	"math"
+	"math/big"
 
 	"github.com/vechain/thor/thor"
 )
@@ -14,10 +15,29 @@ import (
 // GasLimit to support block gas limit validation and adjustment.
 type GasLimit uint64
 
+// MinGasLimitAt returns the gas limit floor for the child block of the parent with given number.
+// Since the fork (the same one making the block interval governed), the floor is governed by the
+// Params key thor.KeyMinGasLimit, read from the parent state. Zero or out of range values fall back to the default.
+func MinGasLimitAt(forkConfig thor.ForkConfig, parentNumber uint32, getParam func(key thor.Bytes32) *big.Int) uint64 {
+	if parentNumber+1 < forkConfig.BlockInterval {
+		return thor.MinGasLimit
+	}
+	v := getParam(thor.KeyMinGasLimit)
+	if v.Sign() <= 0 || !v.IsUint64() || v.Uint64() < thor.TxGas {
+		return thor.MinGasLimit
+	}
+	return v.Uint64()
+}
+
 // IsValid returns if the receiver is valid according to parent gas limit.
 func (gl GasLimit) IsValid(parentGasLimit uint64) bool {
+	return gl.IsValidAbove(parentGasLimit, thor.MinGasLimit)
+}
+
+// IsValidAbove returns if the receiver is valid according to parent gas limit, with the given floor.
+func (gl GasLimit) IsValidAbove(parentGasLimit uint64, minGasLimit uint64) bool {
 	gasLimit := uint64(gl)
-	if gasLimit < thor.MinGasLimit {
+	if gasLimit < minGasLimit {
 		return false
 	}
 	var diff uint64
@@ -33,19 +53,29 @@ func (gl GasLimit) IsValid(parentGasLimit uint64) bool {
 // Qualify qualify the receiver according to parent gas limit, and returns
 // the qualified gas limit value.
 func (gl GasLimit) Qualify(parentGasLimit uint64) uint64 {
+	return gl.QualifyAbove(parentGasLimit, thor.MinGasLimit)
+}
+
+// QualifyAbove is like Qualify, with the given floor.
+func (gl GasLimit) QualifyAbove(parentGasLimit uint64, minGasLimit uint64) uint64 {
 	gasLimit := uint64(gl)
 	maxDiff := parentGasLimit / thor.GasLimitBoundDivisor
 	if gasLimit > parentGasLimit {
 		diff := min64(gasLimit-parentGasLimit, maxDiff)
-		return GasLimit(parentGasLimit).Adjust(int64(diff))
+		return GasLimit(parentGasLimit).AdjustAbove(int64(diff), minGasLimit)
 	}
 	diff := min64(parentGasLimit-gasLimit, maxDiff)
-	return GasLimit(parentGasLimit).Adjust(-int64(diff))
+	return GasLimit(parentGasLimit).AdjustAbove(-int64(diff), minGasLimit)
 }
 
 // Adjust suppose the receiver is parent gas limit, and calculate a valid
 // gas limit value by apply `delta`.
 func (gl GasLimit) Adjust(delta int64) uint64 {
+	return gl.AdjustAbove(delta, thor.MinGasLimit)
+}
+
+// AdjustAbove is like Adjust, with the given floor.
+func (gl GasLimit) AdjustAbove(delta int64, minGasLimit uint64) uint64 {
 	gasLimit := uint64(gl)
 	maxDiff := gasLimit / thor.GasLimitBoundDivisor
 
@@ -61,9 +91,9 @@ func (gl GasLimit) Adjust(delta int64) uint64 {
 
 	// reduce
 	diff := min64(uint64(-delta), maxDiff)
-	if thor.MinGasLimit+diff > gasLimit {
+	if minGasLimit+diff > gasLimit {
 		// reach floor
-		return thor.MinGasLimit
+		return minGasLimit
 	}
 	return gasLimit - diff
 }
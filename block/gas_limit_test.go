@@ -7,6 +7,7 @@ package block_test
 
 import (
 	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -67,3 +68,27 @@ func TestGasLimit_Qualify(t *testing.T) {
 		assert.Equal(t, tt.want, block.GasLimit(tt.gl).Qualify(tt.parentGL))
 	}
 }
+
+func TestGasLimit_Above(t *testing.T) {
+	min := thor.MinGasLimit / 10
+
+	assert.True(t, block.GasLimit(min).IsValidAbove(min, min))
+	assert.False(t, block.GasLimit(min-1).IsValidAbove(min, min))
+	assert.Equal(t, min, block.GasLimit(min).AdjustAbove(-1, min))
+	assert.Equal(t, min, block.GasLimit(0).QualifyAbove(min, min))
+}
+
+func TestMinGasLimitAt(t *testing.T) {
+	fc := thor.ForkConfig{BlockInterval: 10}
+	getParam := func(v uint64) func(thor.Bytes32) *big.Int {
+		return func(key thor.Bytes32) *big.Int {
+			assert.Equal(t, thor.KeyMinGasLimit, key)
+			return new(big.Int).SetUint64(v)
+		}
+	}
+
+	assert.Equal(t, thor.MinGasLimit, block.MinGasLimitAt(fc, 8, getParam(100000)), "before fork")
+	assert.Equal(t, uint64(100000), block.MinGasLimitAt(fc, 9, getParam(100000)))
+	assert.Equal(t, thor.MinGasLimit, block.MinGasLimitAt(fc, 9, getParam(0)), "unset")
+	assert.Equal(t, thor.MinGasLimit, block.MinGasLimitAt(fc, 9, getParam(thor.TxGas-1)), "too low")
+}
@@ -9,10 +9,12 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"sync"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
 	"github.com/vechain/thor/thor"
 )
 
@@ -23,8 +25,17 @@ type Header struct {
 
 	cache struct {
 		signingHash atomic.Value
-		signer      atomic.Value
 		id          atomic.Value
+		signer      struct {
+			once sync.Once
+			addr thor.Address
+			err  error
+		}
+		backers struct {
+			once  sync.Once
+			addrs []thor.Address
+			err   error
+		}
 	}
 }
 
@@ -43,6 +54,10 @@ type headerBody struct {
 	ReceiptsRoot thor.Bytes32
 
 	Signature []byte
+
+	// Extension optional trailing field, absent in legacy headers.
+	// It holds at most one element, and is opaque until activated by a fork.
+	Extension [][]byte `rlp:"tail"`
 }
 
 // ParentID returns id of parent block.
@@ -96,6 +111,14 @@ func (h *Header) ReceiptsRoot() thor.Bytes32 {
 	return h.body.ReceiptsRoot
 }
 
+// Extension returns the opaque header extension, or nil if absent.
+func (h *Header) Extension() []byte {
+	if len(h.body.Extension) == 0 {
+		return nil
+	}
+	return append([]byte(nil), h.body.Extension[0]...)
+}
+
 // ID computes id of block.
 // The block ID is defined as: blockNumber + hash(signingHash, signer)[4:].
 func (h *Header) ID() (id thor.Bytes32) {
@@ -128,8 +151,7 @@ func (h *Header) SigningHash() (hash thor.Bytes32) {
 	}
 	defer func() { h.cache.signingHash.Store(hash) }()
 
-	hw := thor.NewBlake2b()
-	rlp.Encode(hw, []interface{}{
+	fields := []interface{}{
 		h.body.ParentID,
 		h.body.Timestamp,
 		h.body.GasLimit,
@@ -141,7 +163,14 @@ func (h *Header) SigningHash() (hash thor.Bytes32) {
 		h.body.TxsRoot,
 		h.body.StateRoot,
 		h.body.ReceiptsRoot,
-	})
+	}
+	// extension is signed only when present, to keep hashes of legacy headers unchanged
+	if len(h.body.Extension) > 0 {
+		fields = append(fields, h.body.Extension[0])
+	}
+
+	hw := thor.NewBlake2b()
+	rlp.Encode(hw, fields)
 	hw.Sum(hash[:0])
 	return
 }
@@ -159,28 +188,23 @@ func (h *Header) withSignature(sig []byte) *Header {
 }
 
 // Signer extract signer of the block from signature.
-func (h *Header) Signer() (signer thor.Address, err error) {
+// The recovered signer is memoized, since the public key recovery is expensive
+// and the same header is usually queried by several components.
+func (h *Header) Signer() (thor.Address, error) {
 	if h.Number() == 0 {
 		// special case for genesis block
 		return thor.Address{}, nil
 	}
 
-	if cached := h.cache.signer.Load(); cached != nil {
-		return cached.(thor.Address), nil
-	}
-	defer func() {
-		if err == nil {
-			h.cache.signer.Store(signer)
+	h.cache.signer.once.Do(func() {
+		pub, err := crypto.SigToPub(h.SigningHash().Bytes(), h.body.Signature)
+		if err != nil {
+			h.cache.signer.err = err
+			return
 		}
-	}()
-
-	pub, err := crypto.SigToPub(h.SigningHash().Bytes(), h.body.Signature)
-	if err != nil {
-		return thor.Address{}, err
-	}
-
-	signer = thor.Address(crypto.PubkeyToAddress(*pub))
-	return
+		h.cache.signer.addr = thor.Address(crypto.PubkeyToAddress(*pub))
+	})
+	return h.cache.signer.addr, h.cache.signer.err
 }
 
 // EncodeRLP implements rlp.Encoder
@@ -195,6 +219,13 @@ func (h *Header) DecodeRLP(s *rlp.Stream) error {
 	if err := s.Decode(&body); err != nil {
 		return err
 	}
+	if len(body.Extension) > 1 {
+		return errors.New("rlp: too many header extensions")
+	}
+	if len(body.Extension) == 1 && len(body.Extension[0]) == 0 {
+		// absent extension is the only encoding of no extension
+		return errors.New("rlp: empty header extension")
+	}
 	*h = Header{body: body}
 	return nil
 }
@@ -219,9 +250,10 @@ func (h *Header) String() string {
 	TxsRoot:		%v
 	StateRoot:		%v
 	ReceiptsRoot:	%v
+	Extension:		0x%x
 	Signature:		0x%x`, h.ID(), h.Number(), h.body.ParentID, h.body.Timestamp, signerStr,
 		h.body.Beneficiary, h.body.GasLimit, h.body.GasUsed, h.body.TotalScore,
-		h.body.TxsRoot, h.body.StateRoot, h.body.ReceiptsRoot, h.body.Signature)
+		h.body.TxsRoot, h.body.StateRoot, h.body.ReceiptsRoot, h.Extension(), h.body.Signature)
 }
 
 // Number extract block number from block id.
@@ -0,0 +1,95 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package block_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	. "github.com/vechain/thor/block"
+	"github.com/vechain/thor/thor"
+)
+
+func TestHeaderSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	blk := new(Builder).ParentID(thor.BytesToBytes32([]byte("parent"))).Build()
+	sig, _ := crypto.Sign(blk.Header().SigningHash().Bytes(), key)
+	h := blk.WithSignature(sig).Header()
+
+	expected := thor.Address(crypto.PubkeyToAddress(key.PublicKey))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			signer, err := h.Signer()
+			assert.Nil(t, err)
+			assert.Equal(t, expected, signer)
+		}()
+	}
+	wg.Wait()
+
+	// invalid signature
+	_, err := blk.WithSignature([]byte{1, 2, 3}).Header().Signer()
+	assert.NotNil(t, err)
+}
+
+func TestHeaderExtension(t *testing.T) {
+	legacy := new(Builder).ParentID(thor.BytesToBytes32([]byte("parent"))).Build().Header()
+	assert.Nil(t, legacy.Extension())
+
+	ext := new(Builder).ParentID(thor.BytesToBytes32([]byte("parent"))).Extension([]byte("ext")).Build().Header()
+	assert.Equal(t, []byte("ext"), ext.Extension())
+	assert.NotEqual(t, legacy.SigningHash(), ext.SigningHash())
+
+	for _, h := range []*Header{legacy, ext} {
+		data, err := rlp.EncodeToBytes(h)
+		assert.Nil(t, err)
+
+		var decoded Header
+		assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+		assert.Equal(t, h.SigningHash(), decoded.SigningHash())
+		assert.Equal(t, h.Extension(), decoded.Extension())
+	}
+
+	// legacy fields followed by an empty extension
+	var fields []rlp.RawValue
+	data, _ := rlp.EncodeToBytes(legacy)
+	assert.Nil(t, rlp.DecodeBytes(data, &fields))
+	data, _ = rlp.EncodeToBytes(append(fields, rlp.RawValue{0x80}))
+	var decoded Header
+	assert.Equal(t, "rlp: empty header extension", rlp.DecodeBytes(data, &decoded).Error())
+}
+
+func TestHeaderBackers(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	builder := new(Builder).ParentID(thor.BytesToBytes32([]byte("parent"))).TotalScore(1)
+	proposal := builder.Build().Header()
+
+	backers, err := proposal.Backers()
+	assert.Nil(t, err)
+	assert.Empty(t, backers)
+
+	sig, _ := crypto.Sign(proposal.ProposalHash().Bytes(), key)
+	backed := builder.TotalScore(2).BackerSignatures([][]byte{sig}).Build().Header()
+	assert.Equal(t, proposal.ProposalHash(), backed.ProposalHash(), "total score and extension excluded")
+	assert.NotEqual(t, proposal.SigningHash(), backed.SigningHash())
+
+	sigs, err := backed.BackerSignatures()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{sig}, sigs)
+
+	backers, err = backed.Backers()
+	assert.Nil(t, err)
+	assert.Equal(t, []thor.Address{thor.Address(crypto.PubkeyToAddress(key.PublicKey))}, backers)
+
+	_, err = new(Builder).Extension([]byte("opaque")).Build().Header().Backers()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package block
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// WriteTo writes rlp encoded block into w. Unlike rlp.Encode, which buffers the whole
+// encoding, txs are encoded and written one by one, so memory usage is bounded by the largest tx.
+// The output is identical to rlp.EncodeToBytes(b).
+// It implements io.WriterTo.
+func (b *Block) WriteTo(w io.Writer) (int64, error) {
+	headerData, err := rlp.EncodeToBytes(b.header)
+	if err != nil {
+		return 0, err
+	}
+	var txsSize uint64
+	for _, tx := range b.txs {
+		txsSize += uint64(tx.Size())
+	}
+
+	cw := &countWriter{w: w}
+	if _, err := cw.Write(listHeader(uint64(len(headerData)) + uint64(len(listHeader(txsSize))) + txsSize)); err != nil {
+		return cw.n, err
+	}
+	if _, err := cw.Write(headerData); err != nil {
+		return cw.n, err
+	}
+	if _, err := cw.Write(listHeader(txsSize)); err != nil {
+		return cw.n, err
+	}
+	for _, tx := range b.txs {
+		if err := rlp.Encode(cw, tx); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+// listHeader returns rlp list header for payload of the given size.
+func listHeader(size uint64) []byte {
+	if size < 56 {
+		return []byte{0xc0 + byte(size)}
+	}
+	var buf [9]byte
+	binary.BigEndian.PutUint64(buf[1:], size)
+	i := 1
+	for buf[i] == 0 {
+		i++
+	}
+	buf[i-1] = 0xf7 + byte(9-i)
+	return buf[i-1:]
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package block
+
+import (
+	"github.com/vechain/thor/thor"
+)
+
+// Summary is a light-weight form of block, which contains header and ids of txs.
+// It's sufficient for most queries and notifications that don't need tx bodies.
+type Summary struct {
+	Header *Header
+	TxIDs  []thor.Bytes32
+	Size   uint64
+}
+
+// NewSummary create summary of the given block.
+func NewSummary(b *Block) *Summary {
+	txs := b.Transactions()
+	ids := make([]thor.Bytes32, 0, len(txs))
+	for _, tx := range txs {
+		ids = append(ids, tx.ID())
+	}
+	return &Summary{
+		Header: b.Header(),
+		TxIDs:  ids,
+		Size:   uint64(b.Size()),
+	}
+}
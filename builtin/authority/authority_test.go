@@ -52,6 +52,9 @@ func TestAuthority(t *testing.T) {
 		{M(aut.Candidates(big.NewInt(10), 2)), []interface{}{
 			[]*Candidate{{p1, p1, thor.Bytes32{}, true}, {p2, p2, thor.Bytes32{}, true}},
 		}},
+		{M(aut.AllCandidates()), []interface{}{
+			[]*Candidate{{p1, p1, thor.Bytes32{}, true}, {p2, p2, thor.Bytes32{}, true}, {p3, p3, thor.Bytes32{}, true}},
+		}},
 		{M(aut.Get(p1)), []interface{}{true, p1, thor.Bytes32{}, true}},
 		{aut.Update(p1, false), true},
 		{M(aut.Get(p1)), []interface{}{true, p1, thor.Bytes32{}, false}},
@@ -0,0 +1,182 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+// Branch describes a side chain, which competes with the trunk.
+type Branch struct {
+	Head     *block.Header // the latest block of the branch
+	Ancestor *block.Header // the trunk block the branch forked from
+}
+
+// Branches returns side branches, which are sorted by preference, i.e. total score descending.
+// Heads of branches are tracked since blocks are added, and dropped once the branches fork below
+// the finalized block. Branches whose ancestors are deleted by rewinding are omitted.
+func (c *Chain) Branches() ([]*Branch, error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	heads, err := c.sortedBranchHeads()
+	if err != nil {
+		return nil, err
+	}
+	bestID := c.bestBlock.Header().ID()
+
+	var branches []*Branch
+	for _, head := range heads {
+		if head.ID == bestID {
+			continue
+		}
+		ancestor, header, err := c.branchAncestor(head.ID)
+		if err != nil {
+			if c.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		branches = append(branches, &Branch{Head: header, Ancestor: ancestor})
+	}
+	return branches, nil
+}
+
+// Reselect re-evaluates fork choice among all branches, and switches the trunk to the preferred
+// one not dropping the finalized block, e.g. after rewinding, when side branches may outscore
+// the trunk. The returned fork has empty trunk if the best block is unchanged.
+func (c *Chain) Reselect() (*Fork, error) {
+	c.rw.Lock()
+	fork, newBest, err := c.reselect()
+	if err != nil || newBest == nil {
+		c.rw.Unlock()
+		return fork, err
+	}
+	// same as AddBlock
+	c.feedLock.Lock()
+	c.rw.Unlock()
+	defer c.feedLock.Unlock()
+
+	c.newBlockFeed.Send(newBest)
+	return fork, nil
+}
+
+func (c *Chain) reselect() (*Fork, *block.Block, error) {
+	heads, err := c.sortedBranchHeads()
+	if err != nil {
+		return nil, nil, err
+	}
+	best := c.bestBlock.Header()
+	for _, head := range heads {
+		// sorted, so that the rest are not preferred either
+		if !isPreferred(head.TotalScore, head.ID, best) {
+			break
+		}
+		header, err := c.getBlockHeader(head.ID)
+		if err != nil {
+			if c.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		fork, err := c.buildFork(header, best)
+		if err != nil {
+			if c.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		if fork.Ancestor.Number() < c.finalized.Number() {
+			continue
+		}
+		newBest, err := c.getBlock(head.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batch := c.kv.NewBatch()
+		if err := saveBestBlockID(batch, head.ID); err != nil {
+			return nil, nil, err
+		}
+		if err := batch.Write(); err != nil {
+			return nil, nil, err
+		}
+		c.bestBlock = newBest
+		if len(fork.Branch) > 0 {
+			c.reorgFeed.Send(newReorgEvent(fork.Ancestor, fork.Branch, fork.Trunk))
+		}
+		c.tick.Broadcast()
+		return fork, newBest, nil
+	}
+	return &Fork{Ancestor: best}, nil, nil
+}
+
+// pruneBranchHeads unmarks heads of side branches forked below the finalized block, which can never
+// be preferred again. Blocks of these branches are kept.
+func (c *Chain) pruneBranchHeads(w kv.Putter, finalized *block.Header) error {
+	heads, err := loadBranchHeads(c.kv)
+	if err != nil {
+		return err
+	}
+	bestID := c.bestBlock.Header().ID()
+	for _, head := range heads {
+		if head.ID == bestID {
+			continue
+		}
+		ancestor, _, err := c.branchAncestor(head.ID)
+		if err != nil {
+			if c.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if ancestor.Number() < finalized.Number() {
+			if err := deleteBranchHead(w, head.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedBranchHeads loads branch heads sorted by preference.
+func (c *Chain) sortedBranchHeads() ([]branchHead, error) {
+	heads, err := loadBranchHeads(c.kv)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(heads, func(i, j int) bool {
+		if heads[i].TotalScore != heads[j].TotalScore {
+			return heads[i].TotalScore > heads[j].TotalScore
+		}
+		return bytes.Compare(heads[i].ID[:], heads[j].ID[:]) < 0
+	})
+	return heads, nil
+}
+
+// branchAncestor returns the trunk block the branch of given head forked from, and the head.
+func (c *Chain) branchAncestor(headID thor.Bytes32) (*block.Header, *block.Header, error) {
+	head, err := c.getBlockHeader(headID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bestID := c.bestBlock.Header().ID()
+	for header := head; ; {
+		if trunkID, err := c.ancestorTrie.GetAncestor(bestID, header.Number()); err == nil && trunkID == header.ID() {
+			return header, head, nil
+		} else if err != nil && !c.IsNotFound(err) {
+			return nil, nil, err
+		}
+		if header, err = c.getBlockHeader(header.ParentID()); err != nil {
+			return nil, nil, err
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+)
+
+func TestBranches(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b3 := newBlock(b2, 1)
+	b2x := newBlock(b1, 1)
+	b2y := newBlock(b1, 0)
+	b3y := newBlock(b2y, 0)
+
+	branches, err := ch.Branches()
+	assert.Nil(t, err)
+	assert.Empty(t, branches)
+
+	for _, b := range []*block.Block{b1, b2, b3, b2x, b2y, b3y} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, b3.Header().ID(), ch.BestBlock().Header().ID())
+
+	branches, err = ch.Branches()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(branches))
+	assert.Equal(t, b2x.Header().ID(), branches[0].Head.ID(), "higher score first")
+	assert.Equal(t, b1.Header().ID(), branches[0].Ancestor.ID())
+	assert.Equal(t, b3y.Header().ID(), branches[1].Head.ID(), "head only")
+	assert.Equal(t, b1.Header().ID(), branches[1].Ancestor.ID())
+
+	// nothing preferred to the trunk
+	fork, err := ch.Reselect()
+	assert.Nil(t, err)
+	assert.Empty(t, fork.Trunk)
+
+	// side branches outscore the rewound trunk
+	assert.Nil(t, ch.Rewind(b1.Header().ID()))
+	fork, err = ch.Reselect()
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), fork.Ancestor.ID())
+	assert.Equal(t, 1, len(fork.Trunk))
+	assert.Equal(t, b2x.Header().ID(), fork.Trunk[0].ID())
+	assert.Equal(t, b2x.Header().ID(), ch.BestBlock().Header().ID())
+
+	branches, err = ch.Branches()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(branches))
+	assert.Equal(t, b3y.Header().ID(), branches[0].Head.ID())
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/block"
@@ -25,6 +26,7 @@ const (
 
 var errNotFound = errors.New("not found")
 var errBlockExist = errors.New("block already exists")
+var errFinalityViolation = errors.New("reorg past finalized block")
 
 // Chain describes a persistent block chain.
 // It's thread-safe.
@@ -33,10 +35,15 @@ type Chain struct {
 	ancestorTrie *ancestorTrie
 	genesisBlock *block.Block
 	bestBlock    *block.Block
+	finalized    *block.Header
 	tag          byte
 	caches       caches
 	rw           sync.RWMutex
 	tick         co.Signal
+	reorgFeed    event.Feed
+	newBlockFeed event.Feed
+	freezer      *Freezer
+	feedLock     sync.Mutex
 }
 
 type caches struct {
@@ -46,6 +53,12 @@ type caches struct {
 
 // New create an instance of Chain.
 func New(kv kv.GetPutter, genesisBlock *block.Block) (*Chain, error) {
+	return NewWithFreezer(kv, genesisBlock, nil)
+}
+
+// NewWithFreezer create an instance of Chain, with old blocks moved into the freezer.
+// The freezer can be empty, and nil means no freezer.
+func NewWithFreezer(kv kv.GetPutter, genesisBlock *block.Block, freezer *Freezer) (*Chain, error) {
 	if genesisBlock.Header().Number() != 0 {
 		return nil, errors.New("genesis number != 0")
 	}
@@ -102,8 +115,41 @@ func New(kv kv.GetPutter, genesisBlock *block.Block) (*Chain, error) {
 		}
 	}
 
+	// heads of side branches stored before are unknown, but the trunk one is always tracked
+	if has, err := hasBranchHead(kv, bestBlock.Header().ID()); err != nil {
+		return nil, err
+	} else if !has {
+		if err := saveBranchHead(kv, bestBlock.Header()); err != nil {
+			return nil, err
+		}
+	}
+
+	finalized := genesisBlock.Header()
+	if id, err := loadFinalizedBlockID(kv); err != nil {
+		if !kv.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		raw, err := loadBlockRaw(kv, id)
+		if err != nil {
+			return nil, err
+		}
+		if finalized, err = (&rawBlock{raw: raw}).Header(); err != nil {
+			return nil, err
+		}
+	}
+
+	if freezer != nil && freezer.Count() > 0 {
+		if _, err := freezer.entry(genesisID); err != nil {
+			if err == errNotFound {
+				return nil, errors.New("freezer genesis mismatch")
+			}
+			return nil, err
+		}
+	}
+
 	rawBlocksCache := newCache(blockCacheLimit, func(key interface{}) (interface{}, error) {
-		raw, err := loadBlockRaw(kv, key.(thor.Bytes32))
+		raw, err := loadBlockRawOrFrozen(kv, freezer, key.(thor.Bytes32))
 		if err != nil {
 			return nil, err
 		}
@@ -111,14 +157,16 @@ func New(kv kv.GetPutter, genesisBlock *block.Block) (*Chain, error) {
 	})
 
 	receiptsCache := newCache(receiptsCacheLimit, func(key interface{}) (interface{}, error) {
-		return loadBlockReceipts(kv, key.(thor.Bytes32))
+		return loadBlockReceiptsOrFrozen(kv, freezer, key.(thor.Bytes32))
 	})
 
 	return &Chain{
+		freezer:      freezer,
 		kv:           kv,
 		ancestorTrie: ancestorTrie,
 		genesisBlock: genesisBlock,
 		bestBlock:    bestBlock,
+		finalized:    finalized,
 		tag:          genesisBlock.Header().ID()[31],
 		caches: caches{
 			rawBlocks: rawBlocksCache,
@@ -148,21 +196,46 @@ func (c *Chain) BestBlock() *block.Block {
 // Once reorg happened (len(Trunk) > 0 && len(Branch) >0), Fork.Branch will be the chain transitted from trunk to branch.
 // Reorg happens when isTrunk is true.
 func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, error) {
-	c.rw.Lock()
-	defer c.rw.Unlock()
-
-	newBlockID := newBlock.Header().ID()
+	return c.AddBlocks([]*block.Block{newBlock}, []tx.Receipts{receipts})
+}
 
-	if _, err := c.getBlockHeader(newBlockID); err != nil {
-		if !c.IsNotFound(err) {
-			return nil, err
+// AddBlocks adds a contiguous run of blocks, i.e. each block is the parent of the next, with
+// receipts of each block. They are committed atomically in a single write batch, so the run is
+// either added entirely or not at all. Blocks should be validated in advance, which needs their
+// parents in the chain, so the run is not a shortcut for processing new blocks.
+// The returned fork is as if the last block is added after the others, and only the last one is
+// sent to new block subscribers.
+func (c *Chain) AddBlocks(blocks []*block.Block, receipts []tx.Receipts) (*Fork, error) {
+	if len(blocks) == 0 {
+		return nil, errors.New("no blocks")
+	}
+	if len(blocks) != len(receipts) {
+		return nil, errors.New("receipts count mismatch")
+	}
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].Header().ParentID() != blocks[i-1].Header().ID() {
+			return nil, errors.New("blocks not contiguous")
 		}
-	} else {
-		// block already there
-		return nil, errBlockExist
 	}
 
-	parent, err := c.getBlockHeader(newBlock.Header().ParentID())
+	c.rw.Lock()
+	fork, err := c.addBlocks(blocks, receipts)
+	if err != nil || len(fork.Trunk) == 0 {
+		c.rw.Unlock()
+		return fork, err
+	}
+	// new best block is sent without the chain lock, so that receivers can call into the chain,
+	// and feedLock keeps sending in the order of adding
+	c.feedLock.Lock()
+	c.rw.Unlock()
+	defer c.feedLock.Unlock()
+
+	c.newBlockFeed.Send(blocks[len(blocks)-1])
+	return fork, nil
+}
+
+func (c *Chain) addBlocks(blocks []*block.Block, receipts []tx.Receipts) (fork *Fork, err error) {
+	parent, err := c.getBlockHeader(blocks[0].Header().ParentID())
 	if err != nil {
 		if c.IsNotFound(err) {
 			return nil, errors.New("parent missing")
@@ -170,52 +243,94 @@ func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, er
 		return nil, err
 	}
 
-	raw, err := rlp.EncodeToBytes(newBlock)
-	if err != nil {
-		return nil, err
-	}
-
 	batch := c.kv.NewBatch()
+	// blocks are cached as they are written into the batch, so that the later ones can
+	// refer to them, and they are uncached if failed
+	defer func() {
+		if err != nil {
+			for _, blk := range blocks {
+				c.caches.rawBlocks.Remove(blk.Header().ID())
+			}
+		}
+	}()
+	// tx metas saved into the batch, not yet readable from kv
+	pendingMetas := make(map[thor.Bytes32][]TxMeta)
 
-	if err := saveBlockRaw(batch, newBlockID, raw); err != nil {
-		return nil, err
-	}
-	if err := saveBlockReceipts(batch, newBlockID, receipts); err != nil {
-		return nil, err
-	}
-
-	if err := c.ancestorTrie.Update(batch, newBlockID, newBlock.Header().ParentID()); err != nil {
-		return nil, err
-	}
+	for i, newBlock := range blocks {
+		newBlockID := newBlock.Header().ID()
 
-	for i, tx := range newBlock.Transactions() {
-		meta, err := loadTxMeta(c.kv, tx.ID())
-		if err != nil {
+		if _, err := c.getBlockHeader(newBlockID); err != nil {
 			if !c.IsNotFound(err) {
 				return nil, err
 			}
+		} else {
+			// block already there
+			return nil, errBlockExist
+		}
+
+		raw, err := rlp.EncodeToBytes(newBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := saveBlockRaw(batch, newBlockID, raw); err != nil {
+			return nil, err
+		}
+		if err := saveBlockReceipts(batch, newBlockID, receipts[i]); err != nil {
+			return nil, err
 		}
-		meta = append(meta, TxMeta{
-			BlockID:  newBlockID,
-			Index:    uint64(i),
-			Reverted: receipts[i].Reverted,
-		})
-		if err := saveTxMeta(batch, tx.ID(), meta); err != nil {
+
+		if err := c.ancestorTrie.Update(batch, newBlockID, newBlock.Header().ParentID()); err != nil {
+			return nil, err
+		}
+		if err := deleteBranchHead(batch, newBlock.Header().ParentID()); err != nil {
 			return nil, err
 		}
+
+		for j, tx := range newBlock.Transactions() {
+			meta, ok := pendingMetas[tx.ID()]
+			if !ok {
+				if meta, err = loadTxMeta(c.kv, tx.ID()); err != nil {
+					if !c.IsNotFound(err) {
+						return nil, err
+					}
+				}
+			}
+			meta = append(meta, TxMeta{
+				BlockID:  newBlockID,
+				Index:    uint64(j),
+				Reverted: receipts[i][j].Reverted,
+			})
+			if err := saveTxMeta(batch, tx.ID(), meta); err != nil {
+				return nil, err
+			}
+			pendingMetas[tx.ID()] = meta
+		}
+		c.caches.rawBlocks.Add(newBlockID, newRawBlock(raw, newBlock))
+	}
+
+	last := blocks[len(blocks)-1]
+	if err := saveBranchHead(batch, last.Header()); err != nil {
+		return nil, err
 	}
 
-	var fork *Fork
-	isTrunk := c.isTrunk(newBlock.Header())
+	// scores increase along the run, so that the last block decides
+	isTrunk := c.isTrunk(last.Header())
 	if isTrunk {
-		if fork, err = c.buildFork(newBlock.Header(), c.bestBlock.Header()); err != nil {
+		if fork, err = c.buildFork(last.Header(), c.bestBlock.Header()); err != nil {
 			return nil, err
 		}
-		if err := saveBestBlockID(batch, newBlockID); err != nil {
+		if fork.Ancestor.Number() < c.finalized.Number() {
+			return nil, errFinalityViolation
+		}
+		if err := saveBestBlockID(batch, last.Header().ID()); err != nil {
 			return nil, err
 		}
 	} else {
-		fork = &Fork{Ancestor: parent, Branch: []*block.Header{newBlock.Header()}}
+		fork = &Fork{Ancestor: parent}
+		for _, blk := range blocks {
+			fork.Branch = append(fork.Branch, blk.Header())
+		}
 	}
 
 	if err := batch.Write(); err != nil {
@@ -223,16 +338,75 @@ func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, er
 	}
 
 	if isTrunk {
-		c.bestBlock = newBlock
+		c.bestBlock = last
+	}
+
+	for i, blk := range blocks {
+		c.caches.receipts.Add(blk.Header().ID(), receipts[i])
 	}
 
-	c.caches.rawBlocks.Add(newBlockID, newRawBlock(raw, newBlock))
-	c.caches.receipts.Add(newBlockID, receipts)
+	if isTrunk && len(fork.Branch) > 0 {
+		// the former trunk is dropped
+		c.reorgFeed.Send(newReorgEvent(fork.Ancestor, fork.Branch, fork.Trunk))
+	}
 
 	c.tick.Broadcast()
 	return fork, nil
 }
 
+// Finalized returns header of the latest finalized block, which is genesis if none finalized yet.
+// Reorgs dropping the finalized block are refused.
+func (c *Chain) Finalized() *block.Header {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+	return c.finalized
+}
+
+// SetFinalized marks the trunk block final. The finalized block can only move forward.
+func (c *Chain) SetFinalized(id thor.Bytes32) error {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	header, err := c.getBlockHeader(id)
+	if err != nil {
+		return err
+	}
+	if header.Number() < c.finalized.Number() {
+		return errors.New("finalized block can not move backward")
+	}
+	if ancestorID, err := c.ancestorTrie.GetAncestor(c.bestBlock.Header().ID(), header.Number()); err != nil {
+		return err
+	} else if ancestorID != id {
+		return errors.New("not on trunk")
+	}
+	batch := c.kv.NewBatch()
+	if err := saveFinalizedBlockID(batch, id); err != nil {
+		return err
+	}
+	if err := c.pruneBranchHeads(batch, header); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	c.finalized = header
+	return nil
+}
+
+// SubscribeReorg subscribes events of best chain switching.
+// Events are sent synchronously with holding the chain lock, so receivers should consume them in time,
+// and must not call into the chain before that.
+func (c *Chain) SubscribeReorg(ch chan<- *ReorgEvent) event.Subscription {
+	return c.reorgFeed.Subscribe(ch)
+}
+
+// SubscribeNewBlock subscribes new best blocks, in the order of adding.
+// Unlike reorg events, they are sent without holding the chain lock, but adding blocks is
+// blocked until sent, so receivers should consume them in time.
+func (c *Chain) SubscribeNewBlock(ch chan<- *block.Block) event.Subscription {
+	return c.newBlockFeed.Subscribe(ch)
+}
+
 // GetBlockHeader get block header by block id.
 func (c *Chain) GetBlockHeader(id thor.Bytes32) (*block.Header, error) {
 	c.rw.RLock()
@@ -254,6 +428,13 @@ func (c *Chain) GetBlock(id thor.Bytes32) (*block.Block, error) {
 	return c.getBlock(id)
 }
 
+// GetBlockSummary get block summary by block id.
+func (c *Chain) GetBlockSummary(id thor.Bytes32) (*block.Summary, error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+	return c.getBlockSummary(id)
+}
+
 // GetBlockRaw get block rlp encoded bytes for given id.
 // Never modify the returned raw block.
 func (c *Chain) GetBlockRaw(id thor.Bytes32) (block.Raw, error) {
@@ -287,6 +468,27 @@ func (c *Chain) GetTransactionMeta(txID thor.Bytes32, headBlockID thor.Bytes32)
 	return c.getTransactionMeta(txID, headBlockID)
 }
 
+// LookupTransaction locates the transaction on the chain defined by head block ID, along with
+// its confirmations and whether it's on the trunk, which are consistent with each other.
+func (c *Chain) LookupTransaction(txID thor.Bytes32, headBlockID thor.Bytes32) (*TxLocation, error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+	meta, err := c.getTransactionMeta(txID, headBlockID)
+	if err != nil {
+		return nil, err
+	}
+	num := block.Number(meta.BlockID)
+	trunkID, err := c.ancestorTrie.GetAncestor(c.bestBlock.Header().ID(), num)
+	if err != nil && !c.IsNotFound(err) {
+		return nil, err
+	}
+	return &TxLocation{
+		TxMeta:        *meta,
+		Confirmations: block.Number(headBlockID) - num + 1,
+		IsTrunk:       trunkID == meta.BlockID,
+	}, nil
+}
+
 // GetTransaction get transaction for given block and index.
 func (c *Chain) GetTransaction(blockID thor.Bytes32, index uint64) (*tx.Transaction, error) {
 	c.rw.RLock()
@@ -308,6 +510,25 @@ func (c *Chain) GetTransactionReceipt(blockID thor.Bytes32, index uint64) (*tx.R
 	return receipts[index], nil
 }
 
+// GetTransactionReceiptByID get tx receipt and meta info by tx id, on the chain defined by head block ID.
+// The tx is located via the tx index saved at block commit, so no block body is read.
+func (c *Chain) GetTransactionReceiptByID(txID thor.Bytes32, headBlockID thor.Bytes32) (*tx.Receipt, *TxMeta, error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+	meta, err := c.getTransactionMeta(txID, headBlockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipts, err := c.getBlockReceipts(meta.BlockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta.Index >= uint64(len(receipts)) {
+		return nil, nil, errors.New("receipt index out of range")
+	}
+	return receipts[meta.Index], meta, nil
+}
+
 // GetTrunkBlockID get block id on trunk by given block number.
 func (c *Chain) GetTrunkBlockID(num uint32) (thor.Bytes32, error) {
 	c.rw.RLock()
@@ -337,6 +558,17 @@ func (c *Chain) GetTrunkBlock(num uint32) (*block.Block, error) {
 	return c.getBlock(id)
 }
 
+// GetTrunkBlockSummary get block summary on trunk by given block number.
+func (c *Chain) GetTrunkBlockSummary(num uint32) (*block.Summary, error) {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+	id, err := c.ancestorTrie.GetAncestor(c.bestBlock.Header().ID(), num)
+	if err != nil {
+		return nil, err
+	}
+	return c.getBlockSummary(id)
+}
+
 // GetTrunkBlockRaw get block raw on trunk by given block number.
 func (c *Chain) GetTrunkBlockRaw(num uint32) (block.Raw, error) {
 	c.rw.RLock()
@@ -374,24 +606,142 @@ func (c *Chain) GetTrunkTransaction(txID thor.Bytes32) (*tx.Transaction, *TxMeta
 	return tx, meta, nil
 }
 
+// Rewind moves the best block back to the given trunk block, e.g. to recover from an
+// inconsistent database. Trunk blocks after it are deleted, so that they can be synced
+// and processed again. Logs of them are kept outside, which the caller should delete.
+func (c *Chain) Rewind(id thor.Bytes32) error {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	target, err := c.getBlock(id)
+	if err != nil {
+		return err
+	}
+	best := c.bestBlock.Header()
+	if ancestorID, err := c.ancestorTrie.GetAncestor(best.ID(), target.Header().Number()); err != nil {
+		return err
+	} else if ancestorID != id {
+		return errors.New("not on trunk")
+	}
+	if c.freezer != nil && target.Header().Number()+1 < c.freezer.Count() {
+		return errors.New("can not rewind frozen blocks")
+	}
+
+	batch := c.kv.NewBatch()
+	dropped := make([]thor.Bytes32, best.Number()-target.Header().Number())
+	for num := best.Number(); num > target.Header().Number(); num-- {
+		blockID, err := c.ancestorTrie.GetAncestor(best.ID(), num)
+		if err != nil {
+			return err
+		}
+		dropped[num-target.Header().Number()-1] = blockID
+		// tx metas are cleaned up if possible, otherwise lookups still work via ancestry check
+		if blk, err := c.getBlock(blockID); err == nil {
+			for _, tx := range blk.Transactions() {
+				if err := c.removeTxMeta(batch, tx.ID(), blockID); err != nil {
+					return err
+				}
+			}
+		}
+		if err := deleteBlock(batch, blockID); err != nil {
+			return err
+		}
+		if err := deleteBranchHead(batch, blockID); err != nil {
+			return err
+		}
+		c.caches.rawBlocks.Remove(blockID)
+		c.caches.receipts.Remove(blockID)
+	}
+	if err := saveBestBlockID(batch, id); err != nil {
+		return err
+	}
+	if err := saveBranchHead(batch, target.Header()); err != nil {
+		return err
+	}
+	// rewinding is for recovery, so the finalized block gets rewound as well
+	if target.Header().Number() < c.finalized.Number() {
+		if err := saveFinalizedBlockID(batch, id); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	c.bestBlock = target
+	if target.Header().Number() < c.finalized.Number() {
+		c.finalized = target.Header()
+	}
+	if len(dropped) > 0 {
+		c.reorgFeed.Send(&ReorgEvent{Ancestor: target.Header(), Dropped: dropped})
+	}
+	c.tick.Broadcast()
+	return nil
+}
+
+func (c *Chain) removeTxMeta(w kv.Putter, txID, blockID thor.Bytes32) error {
+	meta, err := loadTxMeta(c.kv, txID)
+	if err != nil {
+		if c.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	kept := meta[:0]
+	for _, m := range meta {
+		if m.BlockID != blockID {
+			kept = append(kept, m)
+		}
+	}
+	if len(kept) == 0 {
+		return w.Delete(append(txMetaPrefix, txID[:]...))
+	}
+	return saveTxMeta(w, txID, kept)
+}
+
+// WarmUp loads the latest n trunk blocks and their receipts into caches.
+// n is capped by the cache limits.
+func (c *Chain) WarmUp(n int) error {
+	if n > blockCacheLimit {
+		n = blockCacheLimit
+	}
+	id := c.BestBlock().Header().ID()
+	for i := 0; i < n; i++ {
+		header, err := c.GetBlockHeader(id)
+		if err != nil {
+			return err
+		}
+		if header.Number() == 0 {
+			break
+		}
+		if _, err := c.GetBlockReceipts(id); err != nil {
+			return err
+		}
+		id = header.ParentID()
+	}
+	return nil
+}
+
 // NewSeeker returns a new seeker instance.
 func (c *Chain) NewSeeker(headBlockID thor.Bytes32) *Seeker {
 	return newSeeker(c, headBlockID)
 }
 
 func (c *Chain) isTrunk(header *block.Header) bool {
-	bestHeader := c.bestBlock.Header()
+	return isPreferred(header.TotalScore(), header.ID(), c.bestBlock.Header())
+}
 
-	if header.TotalScore() < bestHeader.TotalScore() {
+// isPreferred returns whether the block with given score and ID is preferred to the best one.
+func isPreferred(score uint64, id thor.Bytes32, bestHeader *block.Header) bool {
+	if score < bestHeader.TotalScore() {
 		return false
 	}
 
-	if header.TotalScore() > bestHeader.TotalScore() {
+	if score > bestHeader.TotalScore() {
 		return true
 	}
 
 	// total scores are equal
-	if bytes.Compare(header.ID().Bytes(), bestHeader.ID().Bytes()) < 0 {
+	if bytes.Compare(id.Bytes(), bestHeader.ID().Bytes()) < 0 {
 		// smaller ID is preferred, since block with smaller ID usually has larger average score.
 		// also, it's a deterministic decision.
 		return true
@@ -486,6 +836,14 @@ func (c *Chain) getBlock(id thor.Bytes32) (*block.Block, error) {
 	return raw.Block()
 }
 
+func (c *Chain) getBlockSummary(id thor.Bytes32) (*block.Summary, error) {
+	raw, err := c.getRawBlock(id)
+	if err != nil {
+		return nil, err
+	}
+	return raw.Summary()
+}
+
 func (c *Chain) getBlockReceipts(blockID thor.Bytes32) (tx.Receipts, error) {
 	receipts, err := c.caches.receipts.GetOrLoad(blockID)
 	if err != nil {
@@ -535,6 +893,11 @@ func (c *Chain) IsBlockExist(err error) bool {
 	return err == errBlockExist
 }
 
+// IsFinalityViolation returns if the error is about reorg past the finalized block.
+func (c *Chain) IsFinalityViolation(err error) bool {
+	return err == errFinalityViolation
+}
+
 // NewTicker create a signal Waiter to receive event of head block change.
 func (c *Chain) NewTicker() co.Waiter {
 	return c.tick.NewWaiter()
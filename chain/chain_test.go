@@ -15,6 +15,8 @@ import (
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
 )
 
 func initChain() *chain.Chain {
@@ -74,3 +76,241 @@ func TestAdd(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockSummary(t *testing.T) {
+	ch := initChain()
+	b1 := newBlock(ch.GenesisBlock(), 1)
+	_, err := ch.AddBlock(b1, nil)
+	assert.Nil(t, err)
+
+	s, err := ch.GetBlockSummary(b1.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), s.Header.ID())
+	assert.Equal(t, uint64(b1.Size()), s.Size)
+	assert.Equal(t, 0, len(s.TxIDs))
+
+	s, err = ch.GetTrunkBlockSummary(1)
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), s.Header.ID())
+}
+
+func TestRewind(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b2x := newBlock(b1, 0)
+	for _, b := range []*block.Block{b1, b2, b2x} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+
+	assert.NotNil(t, ch.Rewind(b2x.Header().ID()), "branch block")
+
+	assert.Nil(t, ch.Rewind(b1.Header().ID()))
+	assert.Equal(t, b1.Header().ID(), ch.BestBlock().Header().ID())
+	_, err := ch.GetBlock(b2.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+
+	// rewound blocks can be added again
+	_, err = ch.AddBlock(b2, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, b2.Header().ID(), ch.BestBlock().Header().ID())
+}
+
+func TestFinalized(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b2x := newBlock(b1, 0)
+	b3 := newBlock(b2, 1)
+	for _, b := range []*block.Block{b1, b2, b2x} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, b0.Header().ID(), ch.Finalized().ID())
+
+	branches, err := ch.Branches()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(branches))
+
+	assert.NotNil(t, ch.SetFinalized(b2x.Header().ID()), "branch block")
+	assert.Nil(t, ch.SetFinalized(b2.Header().ID()))
+	assert.Equal(t, b2.Header().ID(), ch.Finalized().ID())
+	assert.NotNil(t, ch.SetFinalized(b1.Header().ID()), "move backward")
+
+	// b2x forked below the finalized block
+	branches, err = ch.Branches()
+	assert.Nil(t, err)
+	assert.Empty(t, branches)
+
+	// b3x outscores b3, but would drop finalized b2
+	b3x := newBlock(b2x, 10)
+	_, err = ch.AddBlock(b3x, nil)
+	assert.True(t, ch.IsFinalityViolation(err))
+	assert.Equal(t, b2.Header().ID(), ch.BestBlock().Header().ID())
+
+	_, err = ch.AddBlock(b3, nil)
+	assert.Nil(t, err)
+
+	assert.Nil(t, ch.Rewind(b1.Header().ID()))
+	assert.Equal(t, b1.Header().ID(), ch.Finalized().ID(), "rewound with best")
+}
+
+func TestSubscribeReorg(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b3 := newBlock(b2, 1)
+	b2x := newBlock(b1, 0)
+	b3x := newBlock(b2x, 3)
+
+	evCh := make(chan *chain.ReorgEvent, 10)
+	sub := ch.SubscribeReorg(evCh)
+	defer sub.Unsubscribe()
+
+	for _, b := range []*block.Block{b1, b2, b3, b2x} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 0, len(evCh), "no reorg")
+
+	_, err := ch.AddBlock(b3x, nil)
+	assert.Nil(t, err)
+	ev := <-evCh
+	assert.Equal(t, b1.Header().ID(), ev.Ancestor.ID())
+	assert.Equal(t, []thor.Bytes32{b2.Header().ID(), b3.Header().ID()}, ev.Dropped)
+	assert.Equal(t, []thor.Bytes32{b2x.Header().ID(), b3x.Header().ID()}, ev.Adopted)
+
+	assert.Nil(t, ch.Rewind(b1.Header().ID()))
+	ev = <-evCh
+	assert.Equal(t, b1.Header().ID(), ev.Ancestor.ID())
+	assert.Equal(t, []thor.Bytes32{b2x.Header().ID(), b3x.Header().ID()}, ev.Dropped)
+	assert.Empty(t, ev.Adopted)
+}
+
+func TestSubscribeNewBlock(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b2x := newBlock(b1, 0)
+
+	blkCh := make(chan *block.Block, 10)
+	sub := ch.SubscribeNewBlock(blkCh)
+	defer sub.Unsubscribe()
+
+	for _, b := range []*block.Block{b1, b2, b2x} {
+		_, err := ch.AddBlock(b, nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, len(blkCh), "side block not sent")
+	assert.Equal(t, b1.Header().ID(), (<-blkCh).Header().ID())
+	assert.Equal(t, b2.Header().ID(), (<-blkCh).Header().ID())
+
+	// receivers can call into the chain before consuming others
+	unbuffered := make(chan *block.Block)
+	sub2 := ch.SubscribeNewBlock(unbuffered)
+	defer sub2.Unsubscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		blk := <-unbuffered
+		assert.Equal(t, blk.Header().ID(), ch.BestBlock().Header().ID())
+	}()
+	b3 := newBlock(b2, 1)
+	_, err := ch.AddBlock(b3, nil)
+	assert.Nil(t, err)
+	<-done
+	assert.Equal(t, b3.Header().ID(), (<-blkCh).Header().ID())
+}
+
+func TestLookupTransaction(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+
+	trx := new(tx.Builder).ChainTag(ch.Tag()).Build()
+	b1 := new(block.Builder).ParentID(b0.Header().ID()).TotalScore(1).Transaction(trx).Build()
+	sig, _ := crypto.Sign(b1.Header().SigningHash().Bytes(), privateKey)
+	b1 = b1.WithSignature(sig)
+	b1x := newBlock(b0, 10)
+	b2 := newBlock(b1, 1)
+	b3 := newBlock(b2, 1)
+
+	_, err := ch.AddBlock(b1, tx.Receipts{{}})
+	assert.Nil(t, err)
+	_, err = ch.AddBlock(b2, nil)
+	assert.Nil(t, err)
+
+	loc, err := ch.LookupTransaction(trx.ID(), b2.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), loc.BlockID)
+	assert.Equal(t, uint32(2), loc.Confirmations)
+	assert.True(t, loc.IsTrunk)
+
+	loc, err = ch.LookupTransaction(trx.ID(), b1.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), loc.Confirmations)
+
+	receipt, meta, err := ch.GetTransactionReceiptByID(trx.ID(), b2.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), meta.BlockID)
+	assert.False(t, receipt.Reverted)
+
+	_, err = ch.LookupTransaction(trx.ID(), b1x.Header().ID())
+	assert.True(t, ch.IsNotFound(err), "head unknown")
+
+	// b1x takes over the trunk, and b3 extends the former one as a side chain
+	_, err = ch.AddBlock(b1x, nil)
+	assert.Nil(t, err)
+	_, err = ch.AddBlock(b3, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, b1x.Header().ID(), ch.BestBlock().Header().ID())
+
+	loc, err = ch.LookupTransaction(trx.ID(), b3.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), loc.Confirmations)
+	assert.False(t, loc.IsTrunk)
+
+	_, err = ch.LookupTransaction(trx.ID(), b1x.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+}
+
+func TestAddBlocks(t *testing.T) {
+	ch := initChain()
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 1)
+	b2 := newBlock(b1, 1)
+	b3 := newBlock(b2, 1)
+	b2x := newBlock(b1, 2)
+	b3x := newBlock(b2x, 2)
+
+	_, err := ch.AddBlocks([]*block.Block{b1, b3}, []tx.Receipts{nil, nil})
+	assert.NotNil(t, err, "not contiguous")
+	_, err = ch.AddBlocks([]*block.Block{b2, b3}, []tx.Receipts{nil, nil})
+	assert.NotNil(t, err, "parent missing")
+
+	fork, err := ch.AddBlocks([]*block.Block{b1, b2, b3}, []tx.Receipts{nil, nil, nil})
+	assert.Nil(t, err)
+	assert.Equal(t, b0.Header().ID(), fork.Ancestor.ID())
+	assert.Equal(t, 3, len(fork.Trunk))
+	assert.Equal(t, b3.Header().ID(), ch.BestBlock().Header().ID())
+	for _, b := range []*block.Block{b1, b2, b3} {
+		id, err := ch.GetTrunkBlockID(b.Header().Number())
+		assert.Nil(t, err)
+		assert.Equal(t, b.Header().ID(), id)
+	}
+
+	_, err = ch.AddBlocks([]*block.Block{b2, b3}, []tx.Receipts{nil, nil})
+	assert.True(t, ch.IsBlockExist(err))
+
+	// the run outscores the trunk
+	fork, err = ch.AddBlocks([]*block.Block{b2x, b3x}, []tx.Receipts{nil, nil})
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), fork.Ancestor.ID())
+	assert.Equal(t, 2, len(fork.Trunk))
+	assert.Equal(t, 2, len(fork.Branch))
+	assert.Equal(t, b3x.Header().ID(), ch.BestBlock().Header().ID())
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/tx"
+)
+
+// Export writes trunk blocks in range [first, last] into w, as concatenated rlp encoded blocks.
+// Stored raw blocks are written as is, without decoding.
+func (c *Chain) Export(w io.Writer, first, last uint32) error {
+	if first > last {
+		return errors.Errorf("invalid range [%d, %d]", first, last)
+	}
+	if best := c.BestBlock().Header().Number(); last > best {
+		return errors.Errorf("block %d out of range, best block is %d", last, best)
+	}
+	for num := first; ; num++ {
+		raw, err := c.GetTrunkBlockRaw(num)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+		if num == last {
+			return nil
+		}
+	}
+}
+
+// Import reads blocks exported by Export from r, and adds them in order. Blocks already in
+// the chain are skipped. process is called on each new block to execute it, e.g. by consensus,
+// and the returned receipts are saved along with the block. added, if not nil, is called with
+// the fork after each block added, to update indexes out of the chain.
+// It returns the count of blocks imported.
+func (c *Chain) Import(
+	r io.Reader,
+	process func(blk *block.Block) (tx.Receipts, error),
+	added func(blk *block.Block, receipts tx.Receipts, fork *Fork) error,
+) (int, error) {
+	stream := rlp.NewStream(r, 0)
+	n := 0
+	for {
+		var blk block.Block
+		if err := stream.Decode(&blk); err != nil {
+			if err == io.EOF {
+				return n, nil
+			}
+			return n, errors.WithMessage(err, "decode block")
+		}
+		if _, err := c.GetBlockHeader(blk.Header().ID()); err == nil {
+			continue
+		} else if !c.IsNotFound(err) {
+			return n, err
+		}
+		receipts, err := process(&blk)
+		if err != nil {
+			return n, errors.WithMessage(err, "process block "+blk.Header().ID().String())
+		}
+		fork, err := c.AddBlock(&blk, receipts)
+		if err != nil {
+			return n, errors.WithMessage(err, "add block "+blk.Header().ID().String())
+		}
+		if added != nil {
+			if err := added(&blk, receipts, fork); err != nil {
+				return n, err
+			}
+		}
+		n++
+	}
+}
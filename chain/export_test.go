@@ -0,0 +1,58 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/tx"
+)
+
+func TestExportImport(t *testing.T) {
+	src := initChain()
+	parent := src.GenesisBlock()
+	for i := 0; i < 5; i++ {
+		blk := newBlock(parent, 1)
+		_, err := src.AddBlock(blk, nil)
+		assert.Nil(t, err)
+		parent = blk
+	}
+
+	var buf bytes.Buffer
+	assert.NotNil(t, src.Export(&buf, 0, 6), "out of range")
+	assert.Nil(t, src.Export(&buf, 0, 5))
+
+	dst := initChain()
+	var processed, added int
+	process := func(blk *block.Block) (tx.Receipts, error) {
+		processed++
+		return nil, nil
+	}
+	onAdded := func(blk *block.Block, receipts tx.Receipts, fork *chain.Fork) error {
+		added++
+		assert.Equal(t, blk.Header().ID(), fork.Trunk[len(fork.Trunk)-1].ID())
+		return nil
+	}
+	n, err := dst.Import(bytes.NewReader(buf.Bytes()), process, onAdded)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, n, "genesis skipped")
+	assert.Equal(t, 5, processed)
+	assert.Equal(t, 5, added)
+	assert.Equal(t, parent.Header().ID(), dst.BestBlock().Header().ID())
+
+	// import again
+	n, err = dst.Import(bytes.NewReader(buf.Bytes()), process, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	// truncated
+	_, err = initChain().Import(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), process, nil)
+	assert.NotNil(t, err)
+}
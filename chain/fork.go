@@ -7,6 +7,7 @@ package chain
 
 import (
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/thor"
 )
 
 // Fork describes forked chain.
@@ -15,3 +16,22 @@ type Fork struct {
 	Trunk    []*block.Header
 	Branch   []*block.Header
 }
+
+// ReorgEvent is emitted when the best chain switches to another branch.
+// Dropped and Adopted are IDs of blocks after Ancestor, in ascending order.
+type ReorgEvent struct {
+	Ancestor *block.Header
+	Dropped  []thor.Bytes32
+	Adopted  []thor.Bytes32
+}
+
+func newReorgEvent(ancestor *block.Header, dropped, adopted []*block.Header) *ReorgEvent {
+	ev := &ReorgEvent{Ancestor: ancestor}
+	for _, h := range dropped {
+		ev.Dropped = append(ev.Dropped, h.ID())
+	}
+	for _, h := range adopted {
+		ev.Adopted = append(ev.Adopted, h.ID())
+	}
+	return ev
+}
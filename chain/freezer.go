@@ -0,0 +1,346 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// index entry: block id, offset and length of stored block, offset and length of stored receipts
+const freezerEntrySize = 32 + 8 + 4 + 8 + 4
+
+type freezerEntry struct {
+	id             thor.Bytes32
+	blockOffset    uint64
+	blockLen       uint32
+	receiptsOffset uint64
+	receiptsLen    uint32 // zero means no receipts, e.g. genesis
+}
+
+func (e *freezerEntry) encode() []byte {
+	var b [freezerEntrySize]byte
+	copy(b[:], e.id[:])
+	binary.BigEndian.PutUint64(b[32:], e.blockOffset)
+	binary.BigEndian.PutUint32(b[40:], e.blockLen)
+	binary.BigEndian.PutUint64(b[44:], e.receiptsOffset)
+	binary.BigEndian.PutUint32(b[52:], e.receiptsLen)
+	return b[:]
+}
+
+func decodeFreezerEntry(b []byte) *freezerEntry {
+	return &freezerEntry{
+		id:             thor.BytesToBytes32(b[:32]),
+		blockOffset:    binary.BigEndian.Uint64(b[32:]),
+		blockLen:       binary.BigEndian.Uint32(b[40:]),
+		receiptsOffset: binary.BigEndian.Uint64(b[44:]),
+		receiptsLen:    binary.BigEndian.Uint32(b[52:]),
+	}
+}
+
+// Freezer is an append-only store of old trunk blocks and receipts in flat files, to keep
+// them out of leveldb. Entries are indexed by block number, starting from genesis.
+// Values are kept in the stored form of leveldb.
+type Freezer struct {
+	lock     sync.RWMutex
+	index    *os.File
+	blocks   *os.File
+	receipts *os.File
+	count    uint32
+}
+
+// OpenFreezer opens the freezer in dir, which is created if not exist. Partially appended
+// entries, e.g. interrupted by crash, are truncated.
+func OpenFreezer(dir string) (*Freezer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	var files []*os.File
+	for _, name := range []string{"index.dat", "blocks.dat", "receipts.dat"} {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	fz := &Freezer{index: files[0], blocks: files[1], receipts: files[2]}
+	if err := fz.repair(); err != nil {
+		fz.Close()
+		return nil, errors.WithMessage(err, "repair freezer")
+	}
+	return fz, nil
+}
+
+// repair truncates files to the last complete entry.
+func (fz *Freezer) repair() error {
+	stat, err := fz.index.Stat()
+	if err != nil {
+		return err
+	}
+	count := stat.Size() / freezerEntrySize
+	blocksSize, err := fileSize(fz.blocks)
+	if err != nil {
+		return err
+	}
+	receiptsSize, err := fileSize(fz.receipts)
+	if err != nil {
+		return err
+	}
+	for ; count > 0; count-- {
+		e, err := fz.readEntry(uint32(count - 1))
+		if err != nil {
+			return err
+		}
+		if int64(e.blockOffset)+int64(e.blockLen) <= blocksSize &&
+			int64(e.receiptsOffset)+int64(e.receiptsLen) <= receiptsSize {
+			blocksSize = int64(e.blockOffset) + int64(e.blockLen)
+			receiptsSize = int64(e.receiptsOffset) + int64(e.receiptsLen)
+			break
+		}
+	}
+	if count == 0 {
+		blocksSize, receiptsSize = 0, 0
+	}
+	if err := fz.index.Truncate(count * freezerEntrySize); err != nil {
+		return err
+	}
+	if err := fz.blocks.Truncate(blocksSize); err != nil {
+		return err
+	}
+	if err := fz.receipts.Truncate(receiptsSize); err != nil {
+		return err
+	}
+	fz.count = uint32(count)
+	return nil
+}
+
+func fileSize(f *os.File) (int64, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+// Count returns the count of frozen blocks, i.e. number of the next block to freeze.
+func (fz *Freezer) Count() uint32 {
+	fz.lock.RLock()
+	defer fz.lock.RUnlock()
+	return fz.count
+}
+
+// Close closes files.
+func (fz *Freezer) Close() error {
+	var firstErr error
+	for _, f := range []*os.File{fz.index, fz.blocks, fz.receipts} {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (fz *Freezer) readEntry(num uint32) (*freezerEntry, error) {
+	var b [freezerEntrySize]byte
+	if _, err := fz.index.ReadAt(b[:], int64(num)*freezerEntrySize); err != nil {
+		return nil, err
+	}
+	return decodeFreezerEntry(b[:]), nil
+}
+
+// append appends the block with given number, which should equal to count.
+// Data are not durable until sync.
+func (fz *Freezer) append(num uint32, id thor.Bytes32, blockData, receiptsData []byte) error {
+	fz.lock.Lock()
+	defer fz.lock.Unlock()
+
+	if num != fz.count {
+		return errors.Errorf("freeze block %v out of order, want %v", num, fz.count)
+	}
+	e := freezerEntry{id: id, blockLen: uint32(len(blockData)), receiptsLen: uint32(len(receiptsData))}
+	if fz.count > 0 {
+		last, err := fz.readEntry(fz.count - 1)
+		if err != nil {
+			return err
+		}
+		e.blockOffset = last.blockOffset + uint64(last.blockLen)
+		e.receiptsOffset = last.receiptsOffset + uint64(last.receiptsLen)
+	}
+	if _, err := fz.blocks.WriteAt(blockData, int64(e.blockOffset)); err != nil {
+		return err
+	}
+	if _, err := fz.receipts.WriteAt(receiptsData, int64(e.receiptsOffset)); err != nil {
+		return err
+	}
+	// index written last, so that the entry is complete once it's there
+	if _, err := fz.index.WriteAt(e.encode(), int64(num)*freezerEntrySize); err != nil {
+		return err
+	}
+	fz.count++
+	return nil
+}
+
+// sync flushes appended data to disk.
+func (fz *Freezer) sync() error {
+	for _, f := range []*os.File{fz.blocks, fz.receipts, fz.index} {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entry returns the entry of the block, or errNotFound if not frozen.
+func (fz *Freezer) entry(id thor.Bytes32) (*freezerEntry, error) {
+	fz.lock.RLock()
+	defer fz.lock.RUnlock()
+
+	num := block.Number(id)
+	if num >= fz.count {
+		return nil, errNotFound
+	}
+	e, err := fz.readEntry(num)
+	if err != nil {
+		return nil, err
+	}
+	if e.id != id {
+		// side block
+		return nil, errNotFound
+	}
+	return e, nil
+}
+
+// blockData returns the stored block.
+func (fz *Freezer) blockData(id thor.Bytes32) ([]byte, error) {
+	e, err := fz.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, e.blockLen)
+	if _, err := fz.blocks.ReadAt(data, int64(e.blockOffset)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// receiptsData returns the stored receipts.
+func (fz *Freezer) receiptsData(id thor.Bytes32) ([]byte, error) {
+	e, err := fz.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	if e.receiptsLen == 0 {
+		return nil, errNotFound
+	}
+	data := make([]byte, e.receiptsLen)
+	if _, err := fz.receipts.ReadAt(data, int64(e.receiptsOffset)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// loadBlockRawOrFrozen loads the block from kv, or the freezer if moved.
+func loadBlockRawOrFrozen(r kv.Getter, fz *Freezer, id thor.Bytes32) (block.Raw, error) {
+	raw, err := loadBlockRaw(r, id)
+	if err != nil && fz != nil && r.IsNotFound(err) {
+		data, err := fz.blockData(id)
+		if err != nil {
+			return nil, err
+		}
+		return decodeStored(data)
+	}
+	return raw, err
+}
+
+// loadBlockReceiptsOrFrozen loads receipts of the block from kv, or the freezer if moved.
+func loadBlockReceiptsOrFrozen(r kv.Getter, fz *Freezer, id thor.Bytes32) (tx.Receipts, error) {
+	receipts, err := loadBlockReceipts(r, id)
+	if err != nil && fz != nil && r.IsNotFound(err) {
+		data, err := fz.receiptsData(id)
+		if err != nil {
+			return nil, err
+		}
+		return decodeBlockReceipts(data)
+	}
+	return receipts, err
+}
+
+// Freeze moves trunk blocks and receipts older than depth from the best block into the freezer.
+// Blocks are never moved from the finalized one, since blocks to be dropped by reorgs can not
+// be removed from the freezer. It returns the count of blocks moved.
+func (c *Chain) Freeze(depth uint32) (int, error) {
+	if c.freezer == nil {
+		return 0, errors.New("no freezer")
+	}
+	// blocks in [from, to) are moved
+	c.rw.RLock()
+	best := c.bestBlock.Header().Number()
+	to := c.finalized.Number()
+	c.rw.RUnlock()
+	if best < depth {
+		return 0, nil
+	}
+	if best-depth < to {
+		to = best - depth
+	}
+
+	n := 0
+	for from := c.freezer.Count(); from < to; {
+		end := from + freezeBatchSize
+		if end > to {
+			end = to
+		}
+		if err := c.freeze(from, end); err != nil {
+			return n, err
+		}
+		n += int(end - from)
+		from = end
+	}
+	return n, nil
+}
+
+// number of blocks moved per batch
+const freezeBatchSize = 1000
+
+func (c *Chain) freeze(from, to uint32) error {
+	ids := make([]thor.Bytes32, 0, to-from)
+	for num := from; num < to; num++ {
+		id, err := c.GetTrunkBlockID(num)
+		if err != nil {
+			return err
+		}
+		blockData, receiptsData, err := loadStoredBlock(c.kv, id)
+		if err != nil {
+			return err
+		}
+		if err := c.freezer.append(num, id, blockData, receiptsData); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	// removed from kv only after durable in the freezer
+	if err := c.freezer.sync(); err != nil {
+		return err
+	}
+	batch := c.kv.NewBatch()
+	for _, id := range ids {
+		if err := deleteBlock(batch, id); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+)
+
+func TestFreezer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kv, _ := lvldb.NewMem()
+	b0, _, _ := genesis.NewDevnet().Build(state.NewCreator(kv))
+
+	freezer, err := chain.OpenFreezer(dir)
+	assert.Nil(t, err)
+	ch, err := chain.NewWithFreezer(kv, b0, freezer)
+	assert.Nil(t, err)
+
+	blocks := []*block.Block{b0}
+	for i := 0; i < 10; i++ {
+		blk := newBlock(blocks[len(blocks)-1], 1)
+		_, err := ch.AddBlock(blk, nil)
+		assert.Nil(t, err)
+		blocks = append(blocks, blk)
+	}
+
+	// nothing finalized
+	n, err := ch.Freeze(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	assert.Nil(t, ch.SetFinalized(blocks[9].Header().ID()))
+	n, err = ch.Freeze(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 8, n, "bounded by depth")
+	assert.Equal(t, uint32(8), freezer.Count())
+
+	n, err = ch.Freeze(0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n, "bounded by finalized")
+	assert.NotNil(t, ch.Rewind(blocks[5].Header().ID()), "frozen")
+	assert.Nil(t, freezer.Close())
+
+	// reopened, with blocks moved out of kv
+	freezer, err = chain.OpenFreezer(dir)
+	assert.Nil(t, err)
+	defer freezer.Close()
+	assert.Equal(t, uint32(9), freezer.Count())
+
+	noFreezer, err := chain.New(kv, b0)
+	assert.Nil(t, err)
+	_, err = noFreezer.GetBlock(blocks[1].Header().ID())
+	assert.True(t, noFreezer.IsNotFound(err), "moved out of kv")
+
+	ch, err = chain.NewWithFreezer(kv, b0, freezer)
+	assert.Nil(t, err)
+	for _, blk := range blocks {
+		got, err := ch.GetBlock(blk.Header().ID())
+		assert.Nil(t, err)
+		assert.Equal(t, blk.Header().ID(), got.Header().ID())
+	}
+	_, err = ch.GetBlockReceipts(blocks[0].Header().ID())
+	assert.True(t, ch.IsNotFound(err), "no receipts of genesis")
+	receipts, err := ch.GetBlockReceipts(blocks[1].Header().ID())
+	assert.Nil(t, err)
+	assert.Empty(t, receipts)
+
+	// side block at frozen height
+	b1x := newBlock(blocks[0], 0)
+	_, err = ch.GetBlock(b1x.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+}
@@ -6,7 +6,11 @@
 package chain
 
 import (
+	"encoding/binary"
+
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/kv"
 	"github.com/vechain/thor/thor"
@@ -15,12 +19,42 @@ import (
 
 var (
 	bestBlockKey        = []byte("best")
+	finalizedKey        = []byte("finalized")
 	blockPrefix         = []byte("b") // (prefix, block id) -> block
 	txMetaPrefix        = []byte("t") // (prefix, tx id) -> tx location
 	blockReceiptsPrefix = []byte("r") // (prefix, block id) -> receipts
 	indexTrieRootPrefix = []byte("i") // (prefix, block id) -> trie root
 )
 
+var branchHeadPrefix = []byte("head-") // (prefix, block id) -> total score
+
+// values of block bodies and receipts are stored with a leading version byte.
+// Legacy values are plain rlp lists, which always start with a byte >= 0xc0,
+// so they can be told apart from versioned ones and are read as is.
+const (
+	rawVersionSnappy = byte(1)
+)
+
+// encodeStored compresses data and prepends the version byte.
+func encodeStored(data []byte) []byte {
+	enc := make([]byte, 1+snappy.MaxEncodedLen(len(data)))
+	enc[0] = rawVersionSnappy
+	return enc[:1+len(snappy.Encode(enc[1:], data))]
+}
+
+// decodeStored reverses encodeStored. Legacy uncompressed data is returned untouched.
+func decodeStored(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] >= 0xc0 {
+		return data, nil
+	}
+	switch data[0] {
+	case rawVersionSnappy:
+		return snappy.Decode(nil, data[1:])
+	default:
+		return nil, errors.Errorf("unsupported stored value version %v", data[0])
+	}
+}
+
 // TxMeta contains information about a tx is settled.
 type TxMeta struct {
 	BlockID thor.Bytes32
@@ -31,6 +65,17 @@ type TxMeta struct {
 	Reverted bool
 }
 
+// TxLocation is the tx meta with its position relative to the head block and the trunk.
+type TxLocation struct {
+	TxMeta
+
+	// Confirmations the count of blocks from the containing one to the head, both included.
+	Confirmations uint32
+
+	// IsTrunk whether the containing block is on the trunk.
+	IsTrunk bool
+}
+
 func saveRLP(w kv.Putter, key []byte, val interface{}) error {
 	data, err := rlp.EncodeToBytes(val)
 	if err != nil {
@@ -61,14 +106,95 @@ func saveBestBlockID(w kv.Putter, id thor.Bytes32) error {
 	return w.Put(bestBlockKey, id[:])
 }
 
+// loadFinalizedBlockID returns ID of the latest finalized block.
+func loadFinalizedBlockID(r kv.Getter) (thor.Bytes32, error) {
+	data, err := r.Get(finalizedKey)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	return thor.BytesToBytes32(data), nil
+}
+
+// saveFinalizedBlockID saves ID of the latest finalized block.
+func saveFinalizedBlockID(w kv.Putter, id thor.Bytes32) error {
+	return w.Put(finalizedKey, id[:])
+}
+
+// branchHead is the head block of a branch, i.e. the block without children.
+type branchHead struct {
+	ID         thor.Bytes32
+	TotalScore uint64
+}
+
+// saveBranchHead marks the block as a branch head.
+func saveBranchHead(w kv.Putter, header *block.Header) error {
+	var score [8]byte
+	binary.BigEndian.PutUint64(score[:], header.TotalScore())
+	id := header.ID()
+	return w.Put(append(branchHeadPrefix, id[:]...), score[:])
+}
+
+// deleteBranchHead unmarks the branch head, when it gets a child or is deleted.
+func deleteBranchHead(w kv.Putter, id thor.Bytes32) error {
+	return w.Delete(append(branchHeadPrefix, id[:]...))
+}
+
+// hasBranchHead returns whether the block is marked as a branch head.
+func hasBranchHead(r kv.Getter, id thor.Bytes32) (bool, error) {
+	return r.Has(append(branchHeadPrefix, id[:]...))
+}
+
+// loadBranchHeads loads all branch heads.
+func loadBranchHeads(r kv.Getter) ([]branchHead, error) {
+	it := r.NewIterator(*kv.NewRangeWithBytesPrefix(branchHeadPrefix))
+	defer it.Release()
+
+	var heads []branchHead
+	for it.Next() {
+		if len(it.Key()) != len(branchHeadPrefix)+32 || len(it.Value()) != 8 {
+			continue
+		}
+		heads = append(heads, branchHead{
+			thor.BytesToBytes32(it.Key()[len(branchHeadPrefix):]),
+			binary.BigEndian.Uint64(it.Value()),
+		})
+	}
+	return heads, it.Error()
+}
+
 // loadBlockRaw load rlp encoded block raw data.
 func loadBlockRaw(r kv.Getter, id thor.Bytes32) (block.Raw, error) {
-	return r.Get(append(blockPrefix, id[:]...))
+	data, err := r.Get(append(blockPrefix, id[:]...))
+	if err != nil {
+		return nil, err
+	}
+	return decodeStored(data)
+}
+
+// loadStoredBlock loads block and receipts data in the stored form. Receipts data is nil if absent.
+func loadStoredBlock(r kv.Getter, id thor.Bytes32) ([]byte, []byte, error) {
+	blockData, err := r.Get(append(blockPrefix, id[:]...))
+	if err != nil {
+		return nil, nil, err
+	}
+	receiptsData, err := r.Get(append(blockReceiptsPrefix, id[:]...))
+	if err != nil && !r.IsNotFound(err) {
+		return nil, nil, err
+	}
+	return blockData, receiptsData, nil
 }
 
 // saveBlockRaw save rlp encoded block raw data.
 func saveBlockRaw(w kv.Putter, id thor.Bytes32, raw block.Raw) error {
-	return w.Put(append(blockPrefix, id[:]...), raw)
+	return w.Put(append(blockPrefix, id[:]...), encodeStored(raw))
+}
+
+// deleteBlock deletes block raw data and receipts.
+func deleteBlock(w kv.Putter, id thor.Bytes32) error {
+	if err := w.Delete(append(blockPrefix, id[:]...)); err != nil {
+		return err
+	}
+	return w.Delete(append(blockReceiptsPrefix, id[:]...))
 }
 
 // saveBlockNumberIndexTrieRoot save the root of trie that contains number to id index.
@@ -101,13 +227,30 @@ func loadTxMeta(r kv.Getter, txID thor.Bytes32) ([]TxMeta, error) {
 
 // saveBlockReceipts save tx receipts of a block.
 func saveBlockReceipts(w kv.Putter, blockID thor.Bytes32, receipts tx.Receipts) error {
-	return saveRLP(w, append(blockReceiptsPrefix, blockID[:]...), receipts)
+	data, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		return err
+	}
+	return w.Put(append(blockReceiptsPrefix, blockID[:]...), encodeStored(data))
 }
 
 // loadBlockReceipts load tx receipts of a block.
 func loadBlockReceipts(r kv.Getter, blockID thor.Bytes32) (tx.Receipts, error) {
+	data, err := r.Get(append(blockReceiptsPrefix, blockID[:]...))
+	if err != nil {
+		return nil, err
+	}
+	return decodeBlockReceipts(data)
+}
+
+// decodeBlockReceipts decodes receipts in the stored form.
+func decodeBlockReceipts(data []byte) (tx.Receipts, error) {
+	data, err := decodeStored(data)
+	if err != nil {
+		return nil, err
+	}
 	var receipts tx.Receipts
-	if err := loadRLP(r, append(blockReceiptsPrefix, blockID[:]...), &receipts); err != nil {
+	if err := rlp.DecodeBytes(data, &receipts); err != nil {
 		return nil, err
 	}
 	return receipts, nil
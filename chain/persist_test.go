@@ -0,0 +1,40 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestStoredBlockRaw(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	b := new(block.Builder).ParentID(thor.Bytes32{1, 2, 3}).Build()
+	data, _ := rlp.EncodeToBytes(b)
+	id := b.Header().ID()
+
+	assert.Nil(t, saveBlockRaw(kv, id, data))
+	stored, _ := kv.Get(append(blockPrefix, id[:]...))
+	assert.Equal(t, rawVersionSnappy, stored[0])
+
+	raw, err := loadBlockRaw(kv, id)
+	assert.Nil(t, err)
+	assert.Equal(t, block.Raw(data), raw)
+
+	// legacy uncompressed value
+	assert.Nil(t, kv.Put(append(blockPrefix, id[:]...), data))
+	raw, err = loadBlockRaw(kv, id)
+	assert.Nil(t, err)
+	assert.Equal(t, block.Raw(data), raw)
+
+	_, err = decodeStored([]byte{0x7f, 1, 2})
+	assert.NotNil(t, err)
+}
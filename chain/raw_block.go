@@ -9,13 +9,15 @@ import (
 	"sync/atomic"
 
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/thor"
 )
 
 type rawBlock struct {
-	raw    block.Raw
-	header atomic.Value
-	body   atomic.Value
-	block  atomic.Value
+	raw     block.Raw
+	header  atomic.Value
+	body    atomic.Value
+	block   atomic.Value
+	summary atomic.Value
 }
 
 func newRawBlock(raw block.Raw, block *block.Block) *rawBlock {
@@ -70,3 +72,31 @@ func (rb *rawBlock) Block() (*block.Block, error) {
 	rb.block.Store(block)
 	return block, nil
 }
+
+// Summary builds the summary from the header and tx IDs, without composing the block.
+// The size is of the raw block, which needs no re-encoding.
+func (rb *rawBlock) Summary() (*block.Summary, error) {
+	if cached := rb.summary.Load(); cached != nil {
+		return cached.(*block.Summary), nil
+	}
+
+	h, err := rb.Header()
+	if err != nil {
+		return nil, err
+	}
+	b, err := rb.Body()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]thor.Bytes32, 0, len(b.Txs))
+	for _, tx := range b.Txs {
+		ids = append(ids, tx.ID())
+	}
+	summary := &block.Summary{
+		Header: h,
+		TxIDs:  ids,
+		Size:   uint64(len(rb.raw)),
+	}
+	rb.summary.Store(summary)
+	return summary, nil
+}
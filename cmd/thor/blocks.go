@@ -0,0 +1,147 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/consensus"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// exportBlocksAction writes trunk blocks into an RLP file, which can be imported by importBlocksAction.
+func exportBlocksAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	freezer := openFreezer(instanceDir)
+	defer freezer.Close()
+
+	chain, err := openChain(gene, mainDB, freezer)
+	if err != nil {
+		return err
+	}
+
+	from := ctx.Int(exportFromFlag.Name)
+	to := int(chain.BestBlock().Header().Number())
+	if ctx.IsSet(exportToFlag.Name) {
+		to = ctx.Int(exportToFlag.Name)
+	}
+	if from < 0 || from > to {
+		return fmt.Errorf("invalid range [%d, %d]", from, to)
+	}
+
+	path := ctx.String(blocksFileFlag.Name)
+	if path == "" {
+		return fmt.Errorf("flag -%s: required", blocksFileFlag.Name)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	start := time.Now()
+	if err := chain.Export(w, uint32(from), uint32(to)); err != nil {
+		return errors.WithMessage(err, "export blocks")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	log.Info("blocks exported", "from", from, "to", to, "file", path, "elapsed", time.Since(start))
+	return nil
+}
+
+// importBlocksAction executes and adds blocks from an RLP file. Blocks are fully validated,
+// so dumps from untrusted sources can not corrupt the chain, but only the trusted ones
+// are worth importing.
+func importBlocksAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	freezer := openFreezer(instanceDir)
+	defer freezer.Close()
+
+	chain := initChain(gene, mainDB, logDB, freezer)
+
+	// states committed are referenced in full gc mode, as the node does, or nodes shared
+	// with imported states would be deleted by later pruning
+	_, pruner := setupGC(ctx, chain, mainDB)
+
+	path := ctx.String(blocksFileFlag.Name)
+	if path == "" {
+		return fmt.Errorf("flag -%s: required", blocksFileFlag.Name)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cons := consensus.New(chain, state.NewCreator(mainDB))
+	process := func(blk *block.Block) (tx.Receipts, error) {
+		stage, receipts, err := cons.Process(blk, uint64(time.Now().Unix()))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := stage.Commit(); err != nil {
+			return nil, errors.WithMessage(err, "commit state")
+		}
+		return receipts, nil
+	}
+
+	start := time.Now()
+	n, err := chain.Import(bufio.NewReader(f), process, commitLogs(logDB))
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("import blocks (%d imported)", n))
+	}
+	log.Info("blocks imported", "count", n, "best", chain.BestBlock().Header().Number(), "elapsed", time.Since(start))
+	if pruner != nil {
+		pruneStates(chain, pruner)
+	}
+	return nil
+}
+
+// commitLogs returns the callback writing logs of added blocks, as the node does.
+func commitLogs(logDB *logdb.LogDB) func(*block.Block, tx.Receipts, *chain.Fork) error {
+	return func(blk *block.Block, receipts tx.Receipts, fork *chain.Fork) error {
+		forkIDs := make([]thor.Bytes32, 0, len(fork.Branch))
+		for _, header := range fork.Branch {
+			forkIDs = append(forkIDs, header.ID())
+		}
+		batch := logDB.Prepare(blk.Header())
+		for i, tx := range blk.Transactions() {
+			origin, _ := tx.Signer()
+			txBatch := batch.ForTransaction(tx.ID(), origin)
+			for _, output := range receipts[i].Outputs {
+				txBatch.Insert(output.Events, output.Transfers)
+			}
+		}
+		return errors.Wrap(batch.Commit(forkIDs...), "commit logs")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/lvldb"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// post-sync compaction is skipped if fewer blocks synced, e.g. restarted when already synced
+const minSyncedBlocksToCompact = 10000
+
+func dbCompactAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	return compactMainDB(mainDB)
+}
+
+// compactMainDB compacts the main database, and logs progress every 5 percent.
+func compactMainDB(mainDB *lvldb.LevelDB) error {
+	log.Info("compacting main database...")
+	start := time.Now()
+	lastPercent := 0
+	if err := mainDB.CompactWithProgress(func(done, total int) {
+		if percent := done * 100 / total; percent-lastPercent >= 5 || done == total {
+			lastPercent = percent
+			log.Info("compacting main database", "progress", percent, "elapsed", time.Since(start))
+		}
+	}); err != nil {
+		return err
+	}
+	log.Info("main database compacted", "elapsed", time.Since(start))
+	return nil
+}
+
+// compactAfterSync compacts the main database once, when the initial sync is done,
+// since lots of entries are overwritten or deleted during sync.
+func compactAfterSync(ctx context.Context, chain *chain.Chain, comm *comm.Communicator, mainDB *lvldb.LevelDB) {
+	startNum := chain.BestBlock().Header().Number()
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-comm.Synced():
+		}
+		if synced := chain.BestBlock().Header().Number() - startNum; synced < minSyncedBlocksToCompact {
+			log.Debug("post-sync compaction skipped", "synced", synced)
+			return
+		}
+		if err := compactMainDB(mainDB); err != nil {
+			log.Warn("failed to compact main database", "err", err)
+		}
+	}()
+}
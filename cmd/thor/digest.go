@@ -0,0 +1,104 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// stateAttestation is the output of state-digest command.
+type stateAttestation struct {
+	Network        string       `json:"network"`
+	BlockNumber    uint32       `json:"blockNumber"`
+	BlockID        thor.Bytes32 `json:"blockID"`
+	StateRoot      thor.Bytes32 `json:"stateRoot"`
+	RecomputedRoot thor.Bytes32 `json:"recomputedRoot"`
+	Accounts       uint64       `json:"accounts"`
+	Contracts      uint64       `json:"contracts"`
+	StorageSlots   uint64       `json:"storageSlots"`
+	TotalBalance   *big.Int     `json:"totalBalance"`
+	TotalEnergy    *big.Int     `json:"totalEnergy"`
+	Digest         thor.Bytes32 `json:"digest"`
+}
+
+// openChain opens the chain stored in mainDB and freezer, for commands that don't run the node.
+func openChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, freezer *chain.Freezer) (*chain.Chain, error) {
+	genesisBlock, _, err := gene.Build(state.NewCreator(mainDB))
+	if err != nil {
+		return nil, errors.WithMessage(err, "build genesis block")
+	}
+	chain, err := chain.NewWithFreezer(mainDB, genesisBlock, freezer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "initialize block chain")
+	}
+	return chain, nil
+}
+
+func stateDigestAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	freezer := openFreezer(instanceDir)
+	defer freezer.Close()
+
+	chain, err := openChain(gene, mainDB, freezer)
+	if err != nil {
+		return err
+	}
+
+	header := chain.BestBlock().Header()
+	if ctx.IsSet(digestBlockFlag.Name) {
+		num := ctx.Int(digestBlockFlag.Name)
+		if num < 0 || num > int(header.Number()) {
+			return fmt.Errorf("block %d out of range, best block is %d", num, header.Number())
+		}
+		if header, err = chain.GetTrunkBlockHeader(uint32(num)); err != nil {
+			return err
+		}
+	}
+
+	log.Info("walking state", "block", header.Number(), "root", header.StateRoot())
+	d, err := state.ComputeDigest(header.StateRoot(), mainDB, header.Timestamp())
+	if err != nil {
+		return errors.WithMessage(err, "walk state")
+	}
+	if d.RecomputedRoot != d.Root {
+		return fmt.Errorf("state root mismatch, header has %v but state walk gives %v", d.Root, d.RecomputedRoot)
+	}
+
+	data, err := json.MarshalIndent(&stateAttestation{
+		Network:        ctx.String(networkFlag.Name),
+		BlockNumber:    header.Number(),
+		BlockID:        header.ID(),
+		StateRoot:      d.Root,
+		RecomputedRoot: d.RecomputedRoot,
+		Accounts:       d.Accounts,
+		Contracts:      d.Contracts,
+		StorageSlots:   d.StorageSlots,
+		TotalBalance:   d.TotalBalance,
+		TotalEnergy:    d.TotalEnergy,
+		Digest:         d.Hash(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
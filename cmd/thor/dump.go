@@ -0,0 +1,208 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// dumpedAccount is a line of the JSON dump. Energy is calculated at the block time.
+// Address and storage keys are present only if preimages are recorded, otherwise
+// storage slots are keyed by hashed keys in HashedStorage.
+type dumpedAccount struct {
+	AddressHash   thor.Bytes32      `json:"addressHash"`
+	Address       *thor.Address     `json:"address,omitempty"`
+	Balance       string            `json:"balance"`
+	Energy        string            `json:"energy"`
+	Master        *thor.Address     `json:"master,omitempty"`
+	Code          hexutil.Bytes     `json:"code,omitempty"`
+	Storage       map[string]string `json:"storage,omitempty"`
+	HashedStorage map[string]string `json:"hashedStorage,omitempty"`
+}
+
+var dumpCSVColumns = []string{"address_hash", "address", "balance", "energy", "master", "code_hash", "storage_root", "storage_slots"}
+
+func dumpStateAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	freezer := openFreezer(instanceDir)
+	defer freezer.Close()
+
+	chain, err := openChain(gene, mainDB, freezer)
+	if err != nil {
+		return err
+	}
+
+	header := chain.BestBlock().Header()
+	if ctx.IsSet(dumpBlockFlag.Name) {
+		num := ctx.Int(dumpBlockFlag.Name)
+		if num < 0 || num > int(header.Number()) {
+			return fmt.Errorf("block %d out of range, best block is %d", num, header.Number())
+		}
+		if header, err = chain.GetTrunkBlockHeader(uint32(num)); err != nil {
+			return err
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if path := ctx.String(dumpOutFlag.Name); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	bw := bufio.NewWriter(out)
+
+	it, err := state.NewIterator(header.StateRoot(), mainDB)
+	if err != nil {
+		return err
+	}
+	log.Info("dumping state", "block", header.Number(), "root", header.StateRoot())
+
+	var n int
+	switch format := ctx.String(dumpFormatFlag.Name); format {
+	case "json":
+		n, err = dumpJSON(bw, it, header)
+	case "csv":
+		n, err = dumpCSV(bw, it, header)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	log.Info("state dumped", "accounts", n)
+	return nil
+}
+
+// dumpJSON writes accounts with code and storage, one JSON object per line.
+func dumpJSON(w io.Writer, it *state.Iterator, header *block.Header) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	for it.Next() {
+		a := it.Account()
+		acc := dumpedAccount{
+			AddressHash: it.AddressHash(),
+			Address:     it.Address(),
+			Balance:     a.Balance.String(),
+			Energy:      a.CalcEnergy(header.Timestamp()).String(),
+		}
+		if len(a.Master) > 0 {
+			master := thor.BytesToAddress(a.Master)
+			acc.Master = &master
+		}
+		code, err := it.Code()
+		if err != nil {
+			return n, err
+		}
+		acc.Code = code
+
+		var decodeErr error
+		if err := it.Storage(func(keyHash thor.Bytes32, key *thor.Bytes32, value rlp.RawValue) bool {
+			_, content, _, err := rlp.Split(value)
+			if err != nil {
+				decodeErr = err
+				return false
+			}
+			if key == nil {
+				if acc.HashedStorage == nil {
+					acc.HashedStorage = make(map[string]string)
+				}
+				acc.HashedStorage[keyHash.String()] = hexutil.Encode(content)
+				return true
+			}
+			if acc.Storage == nil {
+				acc.Storage = make(map[string]string)
+			}
+			acc.Storage[key.String()] = hexutil.Encode(content)
+			return true
+		}); err != nil {
+			return n, err
+		}
+		if decodeErr != nil {
+			return n, decodeErr
+		}
+		if err := enc.Encode(&acc); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, it.Err()
+}
+
+// dumpCSV writes account summaries without code and storage content.
+func dumpCSV(w io.Writer, it *state.Iterator, header *block.Header) (int, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(dumpCSVColumns); err != nil {
+		return 0, err
+	}
+	n := 0
+	for it.Next() {
+		a := it.Account()
+		slots := 0
+		if err := it.Storage(func(thor.Bytes32, *thor.Bytes32, rlp.RawValue) bool {
+			slots++
+			return true
+		}); err != nil {
+			return n, err
+		}
+		var addr, master string
+		if it.Address() != nil {
+			addr = it.Address().String()
+		}
+		if len(a.Master) > 0 {
+			master = thor.BytesToAddress(a.Master).String()
+		}
+		var codeHash, storageRoot string
+		if len(a.CodeHash) > 0 {
+			codeHash = thor.BytesToBytes32(a.CodeHash).String()
+		}
+		if len(a.StorageRoot) > 0 {
+			storageRoot = thor.BytesToBytes32(a.StorageRoot).String()
+		}
+		if err := cw.Write([]string{
+			it.AddressHash().String(),
+			addr,
+			a.Balance.String(),
+			a.CalcEnergy(header.Timestamp()).String(),
+			master,
+			codeHash,
+			storageRoot,
+			strconv.Itoa(slots),
+		}); err != nil {
+			return n, err
+		}
+		n++
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return n, err
+	}
+	return n, it.Err()
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// analyticsTables defines the exported tables and their columns.
+// Each table is written into <out>/<name>.csv.
+var analyticsTables = []struct {
+	name    string
+	columns []string
+}{
+	{"blocks", []string{"number", "id", "parent_id", "timestamp", "gas_limit", "gas_used", "total_score", "beneficiary", "signer", "tx_count", "state_root", "receipts_root"}},
+	{"transactions", []string{"block_number", "block_id", "block_timestamp", "tx_index", "id", "origin", "chain_tag", "block_ref", "expiration", "clause_count", "gas", "gas_price_coef", "depends_on", "nonce", "gas_used", "gas_payer", "paid", "reward", "reverted"}},
+	{"clauses", []string{"block_number", "tx_id", "clause_index", "to", "value", "data"}},
+	{"events", []string{"block_number", "block_timestamp", "tx_id", "tx_origin", "clause_index", "event_index", "address", "topic0", "topic1", "topic2", "topic3", "topic4", "data"}},
+	{"transfers", []string{"block_number", "block_timestamp", "tx_id", "tx_origin", "clause_index", "transfer_index", "sender", "recipient", "amount"}},
+}
+
+// analyticsWriter writes rows of the analytics tables.
+type analyticsWriter struct {
+	files   []*os.File
+	writers map[string]*csv.Writer
+}
+
+func newAnalyticsWriter(dir string) (*analyticsWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	w := &analyticsWriter{writers: make(map[string]*csv.Writer)}
+	for _, table := range analyticsTables {
+		f, err := os.Create(filepath.Join(dir, table.name+".csv"))
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		w.files = append(w.files, f)
+		cw := csv.NewWriter(f)
+		if err := cw.Write(table.columns); err != nil {
+			w.Close()
+			return nil, err
+		}
+		w.writers[table.name] = cw
+	}
+	return w, nil
+}
+
+func (w *analyticsWriter) write(table string, row ...string) error {
+	return w.writers[table].Write(row)
+}
+
+// Flush flushes buffered rows of all tables.
+func (w *analyticsWriter) Flush() error {
+	for _, cw := range w.writers {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all table files.
+func (w *analyticsWriter) Close() error {
+	var firstErr error
+	for _, f := range w.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (w *analyticsWriter) writeBlock(blk *block.Block, receipts tx.Receipts) error {
+	header := blk.Header()
+	txs := blk.Transactions()
+	if len(txs) != len(receipts) {
+		return fmt.Errorf("block %v: %d txs but %d receipts", header.ID(), len(txs), len(receipts))
+	}
+
+	var signer string
+	if header.Number() > 0 {
+		addr, err := header.Signer()
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("block %v signer", header.ID()))
+		}
+		signer = addr.String()
+	}
+	var (
+		num       = formatUint(uint64(header.Number()))
+		blockTime = formatUint(header.Timestamp())
+	)
+	if err := w.write("blocks",
+		num,
+		header.ID().String(),
+		header.ParentID().String(),
+		blockTime,
+		formatUint(header.GasLimit()),
+		formatUint(header.GasUsed()),
+		formatUint(header.TotalScore()),
+		header.Beneficiary().String(),
+		signer,
+		strconv.Itoa(len(txs)),
+		header.StateRoot().String(),
+		header.ReceiptsRoot().String(),
+	); err != nil {
+		return err
+	}
+
+	for i, t := range txs {
+		origin, err := t.Signer()
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("tx %v origin", t.ID()))
+		}
+		var dependsOn string
+		if dep := t.DependsOn(); dep != nil {
+			dependsOn = dep.String()
+		}
+		blockRef := t.BlockRef()
+		r := receipts[i]
+		if err := w.write("transactions",
+			num,
+			header.ID().String(),
+			blockTime,
+			strconv.Itoa(i),
+			t.ID().String(),
+			origin.String(),
+			formatUint(uint64(t.ChainTag())),
+			"0x"+hex.EncodeToString(blockRef[:]),
+			formatUint(uint64(t.Expiration())),
+			strconv.Itoa(len(t.Clauses())),
+			formatUint(t.Gas()),
+			formatUint(uint64(t.GasPriceCoef())),
+			dependsOn,
+			formatUint(t.Nonce()),
+			formatUint(r.GasUsed),
+			r.GasPayer.String(),
+			formatBig(r.Paid),
+			formatBig(r.Reward),
+			strconv.FormatBool(r.Reverted),
+		); err != nil {
+			return err
+		}
+
+		for ci, c := range t.Clauses() {
+			var to string
+			if c.To() != nil {
+				to = c.To().String()
+			}
+			if err := w.write("clauses",
+				num,
+				t.ID().String(),
+				strconv.Itoa(ci),
+				to,
+				formatBig(c.Value()),
+				"0x"+hex.EncodeToString(c.Data()),
+			); err != nil {
+				return err
+			}
+		}
+
+		for ci, output := range r.Outputs {
+			for ei, ev := range output.Events {
+				var topics [5]string
+				for ti, topic := range ev.Topics {
+					if ti < len(topics) {
+						topics[ti] = topic.String()
+					}
+				}
+				if err := w.write("events",
+					num,
+					blockTime,
+					t.ID().String(),
+					origin.String(),
+					strconv.Itoa(ci),
+					strconv.Itoa(ei),
+					ev.Address.String(),
+					topics[0], topics[1], topics[2], topics[3], topics[4],
+					"0x"+hex.EncodeToString(ev.Data),
+				); err != nil {
+					return err
+				}
+			}
+			for ti, tr := range output.Transfers {
+				if err := w.write("transfers",
+					num,
+					blockTime,
+					t.ID().String(),
+					origin.String(),
+					strconv.Itoa(ci),
+					strconv.Itoa(ti),
+					tr.Sender.String(),
+					tr.Recipient.String(),
+					formatBig(tr.Amount),
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+func formatBig(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+func exportAnalyticsAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	freezer := openFreezer(instanceDir)
+	defer freezer.Close()
+
+	chain, err := openChain(gene, mainDB, freezer)
+	if err != nil {
+		return err
+	}
+	genesisBlock := chain.GenesisBlock()
+
+	best := chain.BestBlock().Header().Number()
+	from := ctx.Int(exportFromFlag.Name)
+	to := int(best)
+	if ctx.IsSet(exportToFlag.Name) {
+		to = ctx.Int(exportToFlag.Name)
+	}
+	if from < 0 || from > to {
+		return fmt.Errorf("invalid range [%d, %d]", from, to)
+	}
+	if to > int(best) {
+		return fmt.Errorf("block %d out of range, best block is %d", to, best)
+	}
+
+	out := ctx.String(exportOutFlag.Name)
+	w, err := newAnalyticsWriter(out)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	next := uint32(from)
+	if next == 0 {
+		if err := w.writeBlock(genesisBlock, nil); err != nil {
+			return err
+		}
+		next++
+	}
+	// the reader yields trunk blocks following the given position
+	position, err := chain.GetTrunkBlockID(next - 1)
+	if err != nil {
+		return err
+	}
+	reader := chain.NewBlockReader(position)
+	for next <= uint32(to) {
+		blocks, err := reader.Read()
+		if err != nil {
+			return err
+		}
+		if len(blocks) == 0 {
+			break
+		}
+		for _, blk := range blocks {
+			if blk.Obsolete || blk.Header().Number() > uint32(to) {
+				continue
+			}
+			receipts, err := chain.GetBlockReceipts(blk.Header().ID())
+			if err != nil {
+				return err
+			}
+			if err := w.writeBlock(blk.Block, receipts); err != nil {
+				return err
+			}
+			next = blk.Header().Number() + 1
+			if next%10000 == 0 {
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				log.Info("exporting", "block", next-1)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported blocks [%d, %d] into %s\n", from, next-1, out)
+	return nil
+}
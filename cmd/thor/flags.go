@@ -40,6 +40,45 @@ var (
 		Value: "",
 		Usage: "comma separated list of domains from which to accept cross origin requests to API",
 	}
+	apiKeysFlag = cli.StringFlag{
+		Name:  "api-keys",
+		Usage: "comma separated keys, one of which is required as 'x-api-key' header to submit txs (no check if not set)",
+	}
+	apiRateLimitFlag = cli.Float64Flag{
+		Name:  "api-rate-limit",
+		Usage: "limit requests per second of each client IP to each API route group (0 for unlimited)",
+	}
+	apiRateBurstFlag = cli.IntFlag{
+		Name:  "api-rate-burst",
+		Value: 10,
+		Usage: "max requests of each client IP to each API route group in a burst",
+	}
+	apiRouteRateLimitsFlag = cli.StringFlag{
+		Name:  "api-route-rate-limits",
+		Usage: "comma separated per route group rate limits overriding api-rate-limit (e.g. 'transactions=2,accounts=10')",
+	}
+	apiTLSCertFlag = cli.StringFlag{
+		Name:  "api-tls-cert",
+		Usage: "certificate file to serve API over HTTPS (requires api-tls-key)",
+	}
+	apiTLSKeyFlag = cli.StringFlag{
+		Name:  "api-tls-key",
+		Usage: "private key file of the API TLS certificate",
+	}
+	apiTLSReloadFlag = cli.BoolFlag{
+		Name:  "api-tls-reload",
+		Usage: "reload API TLS certificate when its files are modified",
+	}
+	apiReadyMaxLagFlag = cli.IntFlag{
+		Name:  "api-ready-max-lag",
+		Value: 60,
+		Usage: "seconds the best block can fall behind wall clock for /readyz to report ready (0 for no check)",
+	}
+	apiReadyMinPeersFlag = cli.IntFlag{
+		Name:  "api-ready-min-peers",
+		Value: 1,
+		Usage: "min peers connected for /readyz to report ready",
+	}
 	apiTimeoutFlag = cli.IntFlag{
 		Name:  "api-timeout",
 		Value: 10000,
@@ -50,11 +89,85 @@ var (
 		Value: 50000000,
 		Usage: "limit contract call gas",
 	}
+	apiMaxFilterRangeFlag = cli.IntFlag{
+		Name:  "api-max-filter-range",
+		Usage: "limit the block range of log filter queries (0 for unlimited)",
+	}
+	apiMaxFilterResultsFlag = cli.IntFlag{
+		Name:  "api-max-filter-results",
+		Usage: "limit the number of results of log filter queries (0 for unlimited)",
+	}
+	apiMaxBlockRangeFlag = cli.IntFlag{
+		Name:  "api-max-block-range",
+		Value: 100,
+		Usage: "limit the number of blocks returned by a block range query (0 for unlimited)",
+	}
+	apiLegacySunsetFlag = cli.StringFlag{
+		Name:  "api-legacy-sunset",
+		Usage: "date (YYYY-MM-DD) since when unversioned API paths are no longer served",
+	}
 	apiBacktraceLimitFlag = cli.IntFlag{
 		Name:  "api-backtrace-limit",
 		Value: 1000,
 		Usage: "limit the distance between 'position' and best block for subscriptions APIs",
 	}
+	apiMaxExecFlag = cli.IntFlag{
+		Name:  "api-max-exec",
+		Value: 16,
+		Usage: "limit concurrent executions (calls, traces) triggered by API requests, excess ones are queued then answered with 503 (0 means no limit)",
+	}
+	cacheFlag = cli.IntFlag{
+		Name:  "cache",
+		Value: 256,
+		Usage: "megabytes of memory devoted to database caches",
+	}
+	syncWorkersFlag = cli.IntFlag{
+		Name:  "sync-workers",
+		Usage: "max goroutines for parallel work of block sync (0 means number of CPUs)",
+	}
+	revokeDoubleSignersFlag = cli.BoolFlag{
+		Name:  "revoke-double-signers",
+		Usage: "revoke authority nodes caught double signing (requires node master to be the executor)",
+	}
+	apiAdminTokenFlag = cli.StringFlag{
+		Name:  "api-admin-token",
+		Usage: "token to access admin APIs (admin APIs disabled if not set)",
+	}
+	adminAddrFlag = cli.StringFlag{
+		Name:  "admin-addr",
+		Value: "localhost:8670",
+		Usage: "admin API service listening address, served apart from API service",
+	}
+	gcModeFlag = cli.StringFlag{
+		Name:  "gc-mode",
+		Value: "archive",
+		Usage: "history keeping mode (archive|full), full mode prunes states of old blocks and works only on a new database",
+	}
+	gcStateRetainFlag = cli.IntFlag{
+		Name:  "gc-state-retain",
+		Value: 8640,
+		Usage: "count of latest blocks whose states are kept in full gc mode",
+	}
+	configFileFlag = cli.StringFlag{
+		Name:  "config-file",
+		Usage: "JSON file of settings reloadable on SIGHUP or by admin API (log levels, gas price floors, origin policy, static peers)",
+	}
+	txPoolMinGasPriceFlag = cli.StringFlag{
+		Name:  "txpool-min-gas-price",
+		Usage: "minimum overall gas price (in wei) of txs accepted by the tx pool",
+	}
+	txPoolOriginMinGasPriceFlag = cli.StringFlag{
+		Name:  "txpool-origin-min-gas-price",
+		Usage: "comma separated per-origin overrides of minimum gas price, e.g. '0xabc...=0,0xdef...=1000'",
+	}
+	txPoolAllowlistFlag = cli.StringFlag{
+		Name:  "txpool-allowlist",
+		Usage: "comma separated tx origins, only txs from which are accepted",
+	}
+	txPoolBlocklistFlag = cli.StringFlag{
+		Name:  "txpool-blocklist",
+		Usage: "comma separated tx origins, txs from which are refused",
+	}
 	verbosityFlag = cli.IntFlag{
 		Name:  "verbosity",
 		Value: int(log15.LvlInfo),
@@ -76,6 +189,22 @@ var (
 		Value: "any",
 		Usage: "port mapping mechanism (any|none|upnp|pmp|extip:<IP>)",
 	}
+	preimagesFlag = cli.BoolFlag{
+		Name:  "preimages",
+		Usage: "record preimages of hashed trie keys, so that state dumps and diffs report addresses and storage keys",
+	}
+	compactRelayFlag = cli.BoolFlag{
+		Name:  "compact-relay",
+		Usage: "propagate new blocks as header plus short tx IDs to peers supporting it",
+	}
+	compactAfterSyncFlag = cli.BoolFlag{
+		Name:  "compact-after-sync",
+		Usage: "compact the main database once the initial sync is done",
+	}
+	freezeDepthFlag = cli.IntFlag{
+		Name:  "freeze-depth",
+		Usage: "move finalized blocks and receipts older than the depth into flat files (0 to disable)",
+	}
 	onDemandFlag = cli.BoolFlag{
 		Name:  "on-demand",
 		Usage: "create new block when there is pending transaction",
@@ -97,4 +226,38 @@ var (
 		Name:  "export",
 		Usage: "export master key to keystore",
 	}
+	exportFromFlag = cli.IntFlag{
+		Name:  "from",
+		Usage: "number of the first block to export",
+	}
+	exportToFlag = cli.IntFlag{
+		Name:  "to",
+		Usage: "number of the last block to export (default: best block)",
+	}
+	exportOutFlag = cli.StringFlag{
+		Name:  "out",
+		Value: "analytics",
+		Usage: "directory to write the exported CSV files",
+	}
+	blocksFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "path of the RLP file of blocks",
+	}
+	digestBlockFlag = cli.IntFlag{
+		Name:  "block",
+		Usage: "number of the block whose state to digest (default: best block)",
+	}
+	dumpBlockFlag = cli.IntFlag{
+		Name:  "block",
+		Usage: "number of the block whose state to dump (default: best block)",
+	}
+	dumpFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Value: "json",
+		Usage: "output format, 'json' for accounts with code and storage one per line, or 'csv' for account summaries",
+	}
+	dumpOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "file to write the dump (default: stdout)",
+	}
 )
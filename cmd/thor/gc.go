@@ -0,0 +1,89 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// states of recent blocks are always kept, for chain reorganizations and API queries
+const minStateRetention = 128
+
+const (
+	gcModeArchive = "archive"
+	gcModeFull    = "full"
+)
+
+// setupGC applies the gc mode to the main database. The returned pruner is nil in archive mode.
+// Blocks and receipts are kept in both modes, since they are served to syncing peers.
+// The mode is fixed once states are pruned, or archive states exist, see state.EnablePruning.
+func setupGC(ctx *cli.Context, chain *chain.Chain, mainDB *lvldb.LevelDB) (node.GCInfo, *state.Pruner) {
+	switch mode := ctx.String(gcModeFlag.Name); mode {
+	case gcModeArchive:
+		if err := state.DisablePruning(mainDB); err != nil {
+			fatal("disable state pruning:", err)
+		}
+		return node.GCInfo{Mode: mode}, nil
+	case gcModeFull:
+		retain := ctx.Int(gcStateRetainFlag.Name)
+		if retain < minStateRetention {
+			fatal(fmt.Sprintf("flag -%s: should be at least %d", gcStateRetainFlag.Name, minStateRetention))
+		}
+		pruner, err := state.EnablePruning(mainDB, chain.BestBlock().Header().Number(), uint32(retain))
+		if err != nil {
+			fatal("enable state pruning:", err)
+		}
+		return node.GCInfo{Mode: mode, StateRetention: uint32(retain)}, pruner
+	default:
+		fatal(fmt.Sprintf("flag -%s: unrecognized value '%s'", gcModeFlag.Name, mode))
+		return node.GCInfo{}, nil
+	}
+}
+
+// runPruner prunes states on every new best block, until ctx is done.
+// Pruning never passes the finalized block, see Pruner.SafeHead.
+func runPruner(ctx context.Context, chain *chain.Chain, pruner *state.Pruner) {
+	if pruner == nil {
+		return
+	}
+	go func() {
+		ticker := chain.NewTicker()
+		for {
+			pruneStates(chain, pruner)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+		}
+	}()
+}
+
+// pruneStates prunes states out of the retention window once.
+func pruneStates(chain *chain.Chain, pruner *state.Pruner) {
+	rootOf := func(num uint32) (thor.Bytes32, error) {
+		header, err := chain.GetTrunkBlockHeader(num)
+		if err != nil {
+			return thor.Bytes32{}, err
+		}
+		return header.StateRoot(), nil
+	}
+	head := pruner.SafeHead(chain.BestBlock().Header().Number(), chain.Finalized().Number())
+	n, err := pruner.Prune(head, rootOf)
+	if err != nil {
+		log.Warn("failed to prune states", "err", err)
+	} else if n > 0 {
+		log.Debug("states pruned", "count", n)
+	}
+}
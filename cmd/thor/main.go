@@ -20,9 +20,16 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/api"
+	"github.com/vechain/thor/api/admin"
+	"github.com/vechain/thor/api/health"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/cmd/thor/node"
 	"github.com/vechain/thor/cmd/thor/solo"
+	"github.com/vechain/thor/co"
+	"github.com/vechain/thor/doublesign"
 	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/liveness"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/state"
@@ -41,9 +48,13 @@ var (
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     20 * time.Minute,
+		OrphanLimit:     1000,
 	}
 )
 
+// how long API executions wait for a free slot
+const execQueueTimeout = 5 * time.Second
+
 func fullVersion() string {
 	versionMeta := "release"
 	if gitTag == "" {
@@ -65,13 +76,43 @@ func main() {
 			beneficiaryFlag,
 			apiAddrFlag,
 			apiCorsFlag,
+			apiKeysFlag,
+			apiRateLimitFlag,
+			apiRateBurstFlag,
+			apiRouteRateLimitsFlag,
+			apiTLSCertFlag,
+			apiTLSKeyFlag,
+			apiTLSReloadFlag,
 			apiTimeoutFlag,
 			apiCallGasLimitFlag,
 			apiBacktraceLimitFlag,
+			apiLegacySunsetFlag,
+			apiMaxFilterRangeFlag,
+			apiMaxFilterResultsFlag,
+			apiMaxBlockRangeFlag,
+			apiReadyMaxLagFlag,
+			apiReadyMinPeersFlag,
+			apiMaxExecFlag,
+			apiAdminTokenFlag,
+			adminAddrFlag,
+			configFileFlag,
+			cacheFlag,
+			gcModeFlag,
+			gcStateRetainFlag,
+			preimagesFlag,
+			syncWorkersFlag,
+			revokeDoubleSignersFlag,
+			txPoolMinGasPriceFlag,
+			txPoolOriginMinGasPriceFlag,
+			txPoolAllowlistFlag,
+			txPoolBlocklistFlag,
 			verbosityFlag,
 			maxPeersFlag,
 			p2pPortFlag,
 			natFlag,
+			compactRelayFlag,
+			compactAfterSyncFlag,
+			freezeDepthFlag,
 		},
 		Action: defaultAction,
 		Commands: []cli.Command{
@@ -82,12 +123,35 @@ func main() {
 					dataDirFlag,
 					apiAddrFlag,
 					apiCorsFlag,
+					apiKeysFlag,
+					apiRateLimitFlag,
+					apiRateBurstFlag,
+					apiRouteRateLimitsFlag,
+					apiTLSCertFlag,
+					apiTLSKeyFlag,
+					apiTLSReloadFlag,
 					apiTimeoutFlag,
 					apiCallGasLimitFlag,
 					apiBacktraceLimitFlag,
+					apiLegacySunsetFlag,
+					apiMaxFilterRangeFlag,
+					apiMaxFilterResultsFlag,
+					apiMaxBlockRangeFlag,
 					onDemandFlag,
 					persistFlag,
 					gasLimitFlag,
+					apiMaxExecFlag,
+					apiAdminTokenFlag,
+					adminAddrFlag,
+					configFileFlag,
+					cacheFlag,
+					gcModeFlag,
+					gcStateRetainFlag,
+					preimagesFlag,
+					txPoolMinGasPriceFlag,
+					txPoolOriginMinGasPriceFlag,
+					txPoolAllowlistFlag,
+					txPoolBlocklistFlag,
 					verbosityFlag,
 				},
 				Action: soloAction,
@@ -102,6 +166,91 @@ func main() {
 				},
 				Action: masterKeyAction,
 			},
+			{
+				Name:  "export-analytics",
+				Usage: "export blocks, transactions, receipts, events and transfers into CSV files",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					cacheFlag,
+					verbosityFlag,
+					exportFromFlag,
+					exportToFlag,
+					exportOutFlag,
+				},
+				Action: exportAnalyticsAction,
+			},
+			{
+				Name:  "export",
+				Usage: "export trunk blocks into an RLP file",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					cacheFlag,
+					verbosityFlag,
+					exportFromFlag,
+					exportToFlag,
+					blocksFileFlag,
+				},
+				Action: exportBlocksAction,
+			},
+			{
+				Name:  "import",
+				Usage: "import blocks from an RLP file, e.g. to bootstrap a node from a trusted dump",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					cacheFlag,
+					verbosityFlag,
+					blocksFileFlag,
+					gcModeFlag,
+					gcStateRetainFlag,
+				},
+				Action: importBlocksAction,
+			},
+			{
+				Name:  "db",
+				Usage: "database maintenance",
+				Subcommands: []cli.Command{
+					{
+						Name:  "compact",
+						Usage: "compact the main database to reclaim space of deleted entries",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							cacheFlag,
+							verbosityFlag,
+						},
+						Action: dbCompactAction,
+					},
+				},
+			},
+			{
+				Name:  "state-digest",
+				Usage: "walk the state at a block and output an attestation of its content",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					cacheFlag,
+					verbosityFlag,
+					digestBlockFlag,
+				},
+				Action: stateDigestAction,
+			},
+			{
+				Name:  "dump-state",
+				Usage: "dump accounts of the state at a block into JSON or CSV",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					cacheFlag,
+					verbosityFlag,
+					dumpBlockFlag,
+					dumpFormatFlag,
+					dumpOutFlag,
+				},
+				Action: dumpStateAction,
+			},
 		},
 	}
 
@@ -116,34 +265,58 @@ func defaultAction(ctx *cli.Context) error {
 
 	defer func() { log.Info("exited") }()
 
-	initLogger(ctx)
+	logHandler := initLogger(ctx)
+	co.SetParallelism(ctx.Int(syncWorkersFlag.Name))
 	gene := selectGenesis(ctx)
 	instanceDir := makeInstanceDir(ctx, gene)
 
 	mainDB := openMainDB(ctx, instanceDir)
 	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+	if ctx.Bool(preimagesFlag.Name) {
+		state.RecordPreimages(mainDB)
+	}
 
 	logDB := openLogDB(ctx, instanceDir)
 	defer func() { log.Info("closing log database..."); logDB.Close() }()
 
-	chain := initChain(gene, mainDB, logDB)
+	freezer := openFreezer(instanceDir)
+	defer func() { log.Info("closing freezer..."); freezer.Close() }()
+
+	chain := initChain(gene, mainDB, logDB, freezer)
 	master := loadNodeMaster(ctx)
+	warmUpCaches(chain, mainDB)
+	gc, pruner := setupGC(ctx, chain, mainDB)
 
-	txPool := txpool.New(chain, state.NewCreator(mainDB), defaultTxPoolOptions)
+	txPool := txpool.New(chain, state.NewCreator(mainDB), txPoolOptions(ctx))
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
 	p2pcom := newP2PComm(ctx, chain, txPool, instanceDir)
-	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, logDB, p2pcom.comm, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)))
+	configReloader := newReloader(ctx.String(configFileFlag.Name), logHandler, txPool, p2pcom.p2pSrv)
+	maintenance := newMaintenance(chain, mainDB, logDB, txPool, pruner, uint32(ctx.Int(freezeDepthFlag.Name)))
+	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, logDB, p2pcom.comm, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)), uint32(ctx.Int(apiMaxBlockRangeFlag.Name)), legacySunset(ctx), filterLimits(ctx), gc, utils.NewExecLimiter(ctx.Int(apiMaxExecFlag.Name), execQueueTimeout), mainDB, readiness(ctx), fullVersion())
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
 	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
 	defer func() { log.Info("stopping API server..."); srvCloser() }()
 
+	adminCloser := startAdminServer(ctx, admin.New(chain, txPool, &adminNetwork{p2pcom.comm, p2pcom.p2pSrv}, logHandler, configReloader.reloadFunc(), maintenance, ctx.String(apiAdminTokenFlag.Name)))
+	defer func() { log.Info("stopping admin server..."); adminCloser() }()
+
 	printStartupMessage(gene, chain, master, instanceDir, apiURL)
 
 	p2pcom.Start()
 	defer p2pcom.Stop()
 
+	// static peers can only be added to running P2P server
+	configReloader.start(exitSignal)
+	runPruner(exitSignal, chain, pruner)
+	if ctx.Bool(compactAfterSyncFlag.Name) {
+		compactAfterSync(exitSignal, chain, p2pcom.comm, mainDB)
+	}
+
+	maintenance.Start()
+	defer func() { log.Info("stopping maintenance jobs..."); maintenance.Stop() }()
+
 	return node.New(
 		master,
 		chain,
@@ -151,24 +324,30 @@ func defaultAction(ctx *cli.Context) error {
 		logDB,
 		txPool,
 		filepath.Join(instanceDir, "tx.stash"),
-		p2pcom.comm).
+		p2pcom.comm,
+		doublesign.New(mainDB),
+		ctx.Bool(revokeDoubleSignersFlag.Name),
+		liveness.New(chain, state.NewCreator(mainDB), mainDB)).
 		Run(exitSignal)
 }
 
 func soloAction(ctx *cli.Context) error {
 	defer func() { log.Info("exited") }()
 
-	initLogger(ctx)
+	logHandler := initLogger(ctx)
 	gene := genesis.NewDevnet()
 
 	var mainDB *lvldb.LevelDB
 	var logDB *logdb.LogDB
+	var freezer *chain.Freezer
 	var instanceDir string
 
 	if ctx.Bool("persist") {
 		instanceDir = makeInstanceDir(ctx, gene)
 		mainDB = openMainDB(ctx, instanceDir)
 		logDB = openLogDB(ctx, instanceDir)
+		freezer = openFreezer(instanceDir)
+		defer freezer.Close()
 	} else {
 		instanceDir = "Memory"
 		mainDB = openMemMainDB()
@@ -177,26 +356,43 @@ func soloAction(ctx *cli.Context) error {
 
 	defer func() { log.Info("closing main database..."); mainDB.Close() }()
 	defer func() { log.Info("closing log database..."); logDB.Close() }()
+	if ctx.Bool(preimagesFlag.Name) {
+		state.RecordPreimages(mainDB)
+	}
 
-	chain := initChain(gene, mainDB, logDB)
+	chain := initChain(gene, mainDB, logDB, freezer)
+	gc, pruner := setupGC(ctx, chain, mainDB)
 
-	txPool := txpool.New(chain, state.NewCreator(mainDB), defaultTxPoolOptions)
+	txPool := txpool.New(chain, state.NewCreator(mainDB), txPoolOptions(ctx))
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
-	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, logDB, solo.Communicator{}, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)))
+	configReloader := newReloader(ctx.String(configFileFlag.Name), logHandler, txPool, nil)
+	maintenance := newMaintenance(chain, mainDB, logDB, txPool, pruner, 0)
+
+	apiHandler, apiCloser := api.New(chain, state.NewCreator(mainDB), txPool, logDB, solo.Communicator{}, ctx.String(apiCorsFlag.Name), uint32(ctx.Int(apiBacktraceLimitFlag.Name)), uint64(ctx.Int(apiCallGasLimitFlag.Name)), uint32(ctx.Int(apiMaxBlockRangeFlag.Name)), legacySunset(ctx), filterLimits(ctx), gc, utils.NewExecLimiter(ctx.Int(apiMaxExecFlag.Name), execQueueTimeout), mainDB, health.Options{}, fullVersion())
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
 	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
 	defer func() { log.Info("stopping API server..."); srvCloser() }()
 
+	adminCloser := startAdminServer(ctx, admin.New(chain, txPool, nil, logHandler, configReloader.reloadFunc(), maintenance, ctx.String(apiAdminTokenFlag.Name)))
+	defer func() { log.Info("stopping admin server..."); adminCloser() }()
+
 	printSoloStartupMessage(gene, chain, instanceDir, apiURL)
 
+	exitSignal := handleExitSignal()
+	configReloader.start(exitSignal)
+	runPruner(exitSignal, chain, pruner)
+
+	maintenance.Start()
+	defer func() { log.Info("stopping maintenance jobs..."); maintenance.Stop() }()
+
 	return solo.New(chain,
 		state.NewCreator(mainDB),
 		logDB,
 		txPool,
 		uint64(ctx.Int("gas-limit")),
-		ctx.Bool("on-demand")).Run(handleExitSignal())
+		ctx.Bool("on-demand")).Run(exitSignal)
 }
 
 func masterKeyAction(ctx *cli.Context) error {
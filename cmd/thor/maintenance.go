@@ -0,0 +1,135 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/jobs"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
+)
+
+const (
+	// the node is considered quiet with fewer executable txs pending
+	quietPendingTxs = 100
+	// logs of recent blocks are checked against trunk, older ones are settled
+	logCleanupDepth = 1000
+)
+
+// newMaintenance creates the scheduler of maintenance jobs.
+// Compaction is scheduled only if states are pruned, and freezing only if freezeDepth is set.
+func newMaintenance(chain *chain.Chain, mainDB *lvldb.LevelDB, logDB *logdb.LogDB, txPool *txpool.TxPool, pruner *state.Pruner, freezeDepth uint32) *jobs.Scheduler {
+	forkConfig := thor.GetForkConfig(chain.GenesisBlock().Header().ID())
+	s := jobs.New(func() bool {
+		best := chain.BestBlock().Header()
+		st, err := state.New(best.StateRoot(), mainDB)
+		if err != nil {
+			return false
+		}
+		// not while syncing, with the block interval governed at the best state
+		timing := poa.TimingAt(forkConfig, best.Number(), builtin.Params.Native(st).Get)
+		if st.Err() != nil || uint64(time.Now().Unix())-best.Timestamp() > timing.Interval*6 {
+			return false
+		}
+		return len(txPool.Executables()) < quietPendingTxs
+	})
+
+	s.Add(jobs.Job{
+		Name:     "logdb-cleanup",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			return cleanupLogs(ctx, chain, logDB)
+		},
+	})
+	s.Add(jobs.Job{
+		Name:     "logdb-vacuum",
+		Interval: 24 * time.Hour,
+		Run:      logDB.Vacuum,
+	})
+	s.Add(jobs.Job{
+		Name:     "snapshot",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			// the snapshot follows commits on top of it, which may be side blocks,
+			// so it's moved back to trunk by changes, and only rebuilt if unusable
+			root := chain.BestBlock().Header().StateRoot()
+			if ok, err := state.ReanchorSnapshot(mainDB, root); ok || err != nil {
+				return err
+			}
+			log.Info("regenerating state snapshot", "root", root)
+			return state.RegenerateSnapshot(ctx, mainDB, root)
+		},
+	})
+	if freezeDepth > 0 {
+		s.Add(jobs.Job{
+			Name:     "freeze",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				n, err := chain.Freeze(freezeDepth)
+				if n > 0 {
+					log.Info("blocks frozen", "count", n)
+				}
+				return err
+			},
+		})
+	}
+	if pruner != nil {
+		s.Add(jobs.Job{
+			Name:     "compaction",
+			Interval: 24 * time.Hour,
+			Run: func(ctx context.Context) error {
+				return mainDB.Compact()
+			},
+		})
+	}
+	return s
+}
+
+// cleanupLogs deletes logs of recent blocks which are no longer in trunk,
+// e.g. left by interrupted chain reorganizations.
+func cleanupLogs(ctx context.Context, chain *chain.Chain, logDB *logdb.LogDB) error {
+	best := chain.BestBlock().Header()
+	from := uint32(0)
+	if best.Number() > logCleanupDepth {
+		from = best.Number() - logCleanupDepth
+	}
+	ids, err := logDB.BlockIDsSince(ctx, from)
+	if err != nil {
+		return err
+	}
+	var (
+		stale  []thor.Bytes32
+		seeker = chain.NewSeeker(best.ID())
+	)
+	for _, id := range ids {
+		num := block.Number(id)
+		// may be newer than best
+		if num > best.Number() {
+			continue
+		}
+		if seeker.GetID(num) != id {
+			stale = append(stale, id)
+		}
+	}
+	if err := seeker.Err(); err != nil {
+		return err
+	}
+	// trunk may be switched meanwhile, retry next time
+	if len(stale) == 0 || chain.BestBlock().Header().ID() != best.ID() {
+		return nil
+	}
+	log.Info("deleting stale logs", "blocks", len(stale))
+	return logDB.DeleteBlocks(stale...)
+}
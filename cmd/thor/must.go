@@ -6,13 +6,17 @@
 package main
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"math/rand"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -21,13 +25,19 @@ import (
 	ethlog "github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/api/admin"
+	"github.com/vechain/thor/api/health"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/cmd/thor/node"
 	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/comm"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/loglevel"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/p2psrv"
 	"github.com/vechain/thor/state"
@@ -36,13 +46,15 @@ import (
 	cli "gopkg.in/urfave/cli.v1"
 )
 
-func initLogger(ctx *cli.Context) {
+func initLogger(ctx *cli.Context) *loglevel.Handler {
 	logLevel := ctx.Int(verbosityFlag.Name)
-	log15.Root().SetHandler(log15.LvlFilterHandler(log15.Lvl(logLevel), log15.StderrHandler))
+	logHandler := loglevel.NewHandler(log15.StderrHandler, log15.Lvl(logLevel))
+	log15.Root().SetHandler(logHandler)
 	// set go-ethereum log lvl to Warn
 	ethLogHandler := ethlog.NewGlogHandler(ethlog.StreamHandler(os.Stderr, ethlog.TerminalFormat(true)))
 	ethLogHandler.Verbosity(ethlog.LvlWarn)
 	ethlog.Root().SetHandler(ethLogHandler)
+	return logHandler
 }
 
 func selectGenesis(ctx *cli.Context) *genesis.Genesis {
@@ -86,16 +98,48 @@ func makeDataDir(ctx *cli.Context) string {
 	return dataDir
 }
 
+// makeInstanceDir creates the dir holding databases of the network identified by genesis ID.
+// The full genesis ID is recorded in the dir, to refuse databases created for another network.
 func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) string {
 	dataDir := makeDataDir(ctx)
 
 	instanceDir := filepath.Join(dataDir, fmt.Sprintf("instance-%x", gene.ID().Bytes()[24:]))
-	if err := os.MkdirAll(dataDir, 0700); err != nil {
-		fatal(fmt.Sprintf("create data dir [%v]: %v", instanceDir, err))
+	if err := os.MkdirAll(instanceDir, 0700); err != nil {
+		fatal(fmt.Sprintf("create instance dir [%v]: %v", instanceDir, err))
+	}
+
+	genesisFile := filepath.Join(instanceDir, "genesis")
+	data, err := ioutil.ReadFile(genesisFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fatal(fmt.Sprintf("read genesis file [%v]: %v", genesisFile, err))
+		}
+		// record it only for a fresh dir, since existing databases may belong to any network
+		if hasDatabase(instanceDir) {
+			log.Warn("genesis of existing databases unrecorded", "dir", instanceDir)
+			return instanceDir
+		}
+		if err := ioutil.WriteFile(genesisFile, []byte(gene.ID().String()), 0600); err != nil {
+			fatal(fmt.Sprintf("write genesis file [%v]: %v", genesisFile, err))
+		}
+		return instanceDir
+	}
+	if recorded := strings.TrimSpace(string(data)); recorded != gene.ID().String() {
+		fatal(fmt.Sprintf("instance dir [%v] was created for genesis %v, which mismatches %v of network '%v'",
+			instanceDir, recorded, gene.ID(), gene.Name()))
 	}
 	return instanceDir
 }
 
+func hasDatabase(instanceDir string) bool {
+	for _, name := range []string{"main.db", "logs.db"} {
+		if _, err := os.Stat(filepath.Join(instanceDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func openMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
 	limit, err := fdlimit.Current()
 	if err != nil {
@@ -110,14 +154,18 @@ func openMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
 		fileCache = 1024
 	}
 
+	// half of the cache to leveldb, and the other half to trie nodes
+	cacheSize := ctx.Int(cacheFlag.Name) / 2
+
 	dir := filepath.Join(dataDir, "main.db")
 	db, err := lvldb.New(dir, lvldb.Options{
-		CacheSize:              256,
+		CacheSize:              cacheSize,
 		OpenFilesCacheCapacity: fileCache,
 	})
 	if err != nil {
 		fatal(fmt.Sprintf("open chain database [%v]: %v", dir, err))
 	}
+	state.SetNodeCache(db, cacheSize*1024*1024)
 	return db
 }
 
@@ -130,16 +178,119 @@ func openLogDB(ctx *cli.Context, dataDir string) *logdb.LogDB {
 	return db
 }
 
-func initChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, logDB *logdb.LogDB) *chain.Chain {
+// checkChainConsistency ensures the best block has its state, and rolls back to the
+// latest consistent block otherwise. The search is bounded, beyond which it fails.
+// Logs of blocks rolled back are deleted as well, since those blocks may be replaced.
+func checkChainConsistency(chain *chain.Chain, mainDB *lvldb.LevelDB, logDB *logdb.LogDB) {
+	const maxRollback = 1000
+
+	best := chain.BestBlock().Header()
+	header := best
+	var dropped []thor.Bytes32
+	for i := 0; ; i++ {
+		if err := probeState(header.StateRoot(), mainDB); err == nil {
+			break
+		} else if i == 0 {
+			log.Warn("state of best block missing", "number", best.Number(), "err", err)
+		}
+		if header.Number() == 0 || i >= maxRollback {
+			fatal(fmt.Sprintf("no consistent block found within %v blocks below #%v, remove the instance dir and sync again", maxRollback, best.Number()))
+		}
+		parent, err := chain.GetBlockHeader(header.ParentID())
+		if err != nil {
+			fatal(fmt.Sprintf("read block %v: %v, remove the instance dir and sync again", header.ParentID(), err))
+		}
+		dropped = append(dropped, header.ID())
+		header = parent
+	}
+	if header.ID() == best.ID() {
+		return
+	}
+	// logs go first, so that an interruption is retried on next start
+	if err := logDB.DeleteBlocks(dropped...); err != nil {
+		fatal("delete logs of rolled back blocks:", err)
+	}
+	if err := chain.Rewind(header.ID()); err != nil {
+		fatal("roll back chain:", err)
+	}
+	log.Warn("chain rolled back to latest consistent block", "from", best.Number(), "to", header.Number())
+}
+
+// probeState checks whether the state at root is usable, by loading top levels of the account trie,
+// and reading builtin storage needed to process the next block. It's not a proof of every node
+// existing, which takes a full trie walk, but catches lost writes of recent states.
+func probeState(root thor.Bytes32, mainDB *lvldb.LevelDB) error {
+	if _, err := state.WarmUp(root, mainDB, 2); err != nil {
+		return err
+	}
+	st, err := state.New(root, mainDB)
+	if err != nil {
+		return err
+	}
+	params := builtin.Params.Native(st)
+	builtin.Authority.Native(st).Candidates(params.Get(thor.KeyProposerEndorsement), thor.MaxBlockProposers)
+	return st.Err()
+}
+
+// warmUpCaches pre-loads recent blocks, top levels of head state and hot builtin storage,
+// to avoid serving at cold-cache latency right after boot.
+func warmUpCaches(chain *chain.Chain, mainDB *lvldb.LevelDB) {
+	startTime := time.Now()
+	if err := chain.WarmUp(512); err != nil {
+		log.Warn("failed to warm up block caches", "err", err)
+		return
+	}
+	best := chain.BestBlock().Header()
+	nodes, err := state.WarmUp(best.StateRoot(), mainDB, 3)
+	if err != nil {
+		log.Warn("failed to warm up state caches", "err", err)
+		return
+	}
+	st, err := state.New(best.StateRoot(), mainDB)
+	if err != nil {
+		log.Warn("failed to warm up state caches", "err", err)
+		return
+	}
+	// storage read by every block
+	params := builtin.Params.Native(st)
+	params.Get(thor.KeyBaseGasPrice)
+	params.Get(thor.KeyRewardRatio)
+	builtin.Authority.Native(st).Candidates(params.Get(thor.KeyProposerEndorsement), thor.MaxBlockProposers)
+	if err := st.Err(); err != nil {
+		log.Warn("failed to warm up state caches", "err", err)
+		return
+	}
+	log.Info("caches warmed up", "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(startTime)))
+}
+
+// openFreezer opens the freezer of old blocks, which is always attached to the chain,
+// since blocks may have been moved there.
+func openFreezer(dataDir string) *chain.Freezer {
+	dir := filepath.Join(dataDir, "freezer")
+	freezer, err := chain.OpenFreezer(dir)
+	if err != nil {
+		fatal(fmt.Sprintf("open freezer [%v]: %v", dir, err))
+	}
+	return freezer
+}
+
+func initChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, logDB *logdb.LogDB, freezer *chain.Freezer) *chain.Chain {
 	genesisBlock, genesisEvents, err := gene.Build(state.NewCreator(mainDB))
 	if err != nil {
 		fatal("build genesis block: ", err)
 	}
 
-	chain, err := chain.New(mainDB, genesisBlock)
+	chain, err := chain.NewWithFreezer(mainDB, genesisBlock, freezer)
 	if err != nil {
+		if mainDB.Recovered() {
+			fatal(fmt.Sprintf("initialize block chain: %v (database corrupted, remove the instance dir and sync again)", err))
+		}
 		fatal("initialize block chain:", err)
 	}
+	if mainDB.Recovered() {
+		log.Warn("main database recovered from corruption, checking consistency")
+	}
+	checkChainConsistency(chain, mainDB, logDB)
 
 	if err := logDB.Prepare(genesisBlock.Header()).
 		ForTransaction(thor.Bytes32{}, thor.Address{}).
@@ -223,7 +374,7 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 	}
 
 	return &p2pComm{
-		comm:           comm.New(chain, txPool),
+		comm:           comm.New(chain, txPool, ctx.Bool(compactRelayFlag.Name)),
 		p2pSrv:         p2psrv.New(opts),
 		peersCachePath: peersCachePath,
 	}
@@ -256,25 +407,185 @@ func (p *p2pComm) Stop() {
 	}
 }
 
+func legacySunset(ctx *cli.Context) time.Time {
+	str := ctx.String(apiLegacySunsetFlag.Name)
+	if str == "" {
+		return time.Time{}
+	}
+	sunset, err := time.Parse("2006-01-02", str)
+	if err != nil {
+		fatal(fmt.Sprintf("parse API legacy sunset: %v", err))
+	}
+	return sunset
+}
+
+func filterLimits(ctx *cli.Context) utils.FilterLimits {
+	return utils.FilterLimits{
+		MaxBlockRange: uint64(ctx.Int(apiMaxFilterRangeFlag.Name)),
+		MaxResults:    uint64(ctx.Int(apiMaxFilterResultsFlag.Name)),
+	}
+}
+
+func txPoolOptions(ctx *cli.Context) txpool.Options {
+	options := defaultTxPoolOptions
+	parsePrice := func(str string) *big.Int {
+		price, ok := new(big.Int).SetString(strings.TrimSpace(str), 10)
+		if !ok || price.Sign() < 0 {
+			fatal(fmt.Sprintf("parse tx pool min gas price: invalid value '%v'", str))
+		}
+		return price
+	}
+	if str := ctx.String(txPoolMinGasPriceFlag.Name); str != "" {
+		options.MinGasPrice = parsePrice(str)
+	}
+	if str := ctx.String(txPoolOriginMinGasPriceFlag.Name); str != "" {
+		options.MinGasPriceByOrigin = make(map[thor.Address]*big.Int)
+		for _, item := range strings.Split(str, ",") {
+			parts := strings.Split(item, "=")
+			if len(parts) != 2 {
+				fatal(fmt.Sprintf("parse tx pool origin min gas price: invalid item '%v'", item))
+			}
+			origin, err := thor.ParseAddress(strings.TrimSpace(parts[0]))
+			if err != nil {
+				fatal(fmt.Sprintf("parse tx pool origin min gas price: %v", err))
+			}
+			options.MinGasPriceByOrigin[origin] = parsePrice(parts[1])
+		}
+	}
+	options.OriginPolicy = txpool.OriginPolicy{
+		Allowlist: parseAddressList(ctx.String(txPoolAllowlistFlag.Name), txPoolAllowlistFlag.Name),
+		Blocklist: parseAddressList(ctx.String(txPoolBlocklistFlag.Name), txPoolBlocklistFlag.Name),
+	}
+	return options
+}
+
+func parseAddressList(str string, flagName string) []thor.Address {
+	var addrs []thor.Address
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		addr, err := thor.ParseAddress(item)
+		if err != nil {
+			fatal(fmt.Sprintf("parse flag -%s: %v", flagName, err))
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+func readiness(ctx *cli.Context) health.Options {
+	return health.Options{
+		MaxLag:   time.Duration(ctx.Int(apiReadyMaxLagFlag.Name)) * time.Second,
+		MinPeers: ctx.Int(apiReadyMinPeersFlag.Name),
+	}
+}
+
+func apiKeys(ctx *cli.Context) []string {
+	var keys []string
+	for _, key := range strings.Split(ctx.String(apiKeysFlag.Name), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func rateLimiter(ctx *cli.Context) *utils.RateLimiter {
+	routeRates := make(map[string]float64)
+	for _, item := range strings.Split(ctx.String(apiRouteRateLimitsFlag.Name), ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			fatal(fmt.Sprintf("parse flag -%s: invalid item '%s'", apiRouteRateLimitsFlag.Name, item))
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			fatal(fmt.Sprintf("parse flag -%s: %v", apiRouteRateLimitsFlag.Name, err))
+		}
+		routeRates[strings.TrimSpace(parts[0])] = rate
+	}
+	return utils.NewRateLimiter(ctx.Float64(apiRateLimitFlag.Name), ctx.Int(apiRateBurstFlag.Name), routeRates)
+}
+
+func apiTLSConfig(ctx *cli.Context) *tls.Config {
+	certFile, keyFile := ctx.String(apiTLSCertFlag.Name), ctx.String(apiTLSKeyFlag.Name)
+	if certFile == "" && keyFile == "" {
+		return nil
+	}
+	if certFile == "" || keyFile == "" {
+		fatal(fmt.Sprintf("flags -%s and -%s should be set together", apiTLSCertFlag.Name, apiTLSKeyFlag.Name))
+	}
+	loader, err := newCertLoader(certFile, keyFile, ctx.Bool(apiTLSReloadFlag.Name))
+	if err != nil {
+		fatal(fmt.Sprintf("load API TLS cert: %v", err))
+	}
+	return &tls.Config{GetCertificate: loader.GetCertificate}
+}
+
 func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID thor.Bytes32) (string, func()) {
+	tlsConfig := apiTLSConfig(ctx)
 	addr := ctx.String(apiAddrFlag.Name)
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		fatal(fmt.Sprintf("listen API addr [%v]: %v", addr, err))
 	}
+	scheme := "http"
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+		scheme = "https"
+	}
 	timeout := ctx.Int(apiTimeoutFlag.Name)
 	if timeout > 0 {
 		handler = handleAPITimeout(handler, time.Duration(timeout)*time.Millisecond)
 	}
 	handler = handleXGenesisID(handler, genesisID)
+	if keys := apiKeys(ctx); len(keys) > 0 {
+		handler = handleAPIKey(handler, keys)
+	}
+	handler = rateLimiter(ctx).Handler(handler)
 	handler = handleXThorestVersion(handler)
 	handler = requestBodyLimit(handler)
+	handler = utils.Compress(handler)
 	srv := &http.Server{Handler: handler}
 	var goes co.Goes
 	goes.Go(func() {
 		srv.Serve(listener)
 	})
-	return "http://" + listener.Addr().String() + "/", func() {
+	return scheme + "://" + listener.Addr().String() + "/", func() {
+		srv.Close()
+		goes.Wait()
+	}
+}
+
+// adminNetwork manages peers for admin APIs.
+type adminNetwork struct {
+	*comm.Communicator
+	*p2psrv.Server
+}
+
+// startAdminServer serves admin APIs on its own listener, so that they can be kept from public access.
+// Nothing is started if admin token is not set.
+func startAdminServer(ctx *cli.Context, a *admin.Admin) func() {
+	if ctx.String(apiAdminTokenFlag.Name) == "" {
+		return func() {}
+	}
+	addr := ctx.String(adminAddrFlag.Name)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal(fmt.Sprintf("listen admin addr [%v]: %v", addr, err))
+	}
+	router := mux.NewRouter()
+	a.Mount(router, "/admin")
+	srv := &http.Server{Handler: requestBodyLimit(router)}
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	log.Info("admin API server started", "url", "http://"+listener.Addr().String()+"/admin")
+	return func() {
 		srv.Close()
 		goes.Wait()
 	}
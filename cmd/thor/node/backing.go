@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/thor"
+)
+
+// time to wait for backers before packing the block
+const backingTimeout = 500 * time.Millisecond
+
+// handleBacking backs the proposal of another node, if the master is a member of its committee
+// and the proposal is valid.
+func (n *Node) handleBacking(ctx context.Context, proposal *block.Block) ([]byte, error) {
+	now := uint64(time.Now().Unix())
+	if err := n.cons.VerifyProposal(proposal, n.master.Address(), now); err != nil {
+		return nil, err
+	}
+	return crypto.Sign(proposal.Header().ProposalHash().Bytes(), n.master.PrivateKey)
+}
+
+// collectBackers requests peers to back the proposal of the flow, and returns signatures
+// of distinct committee members. Nothing collected before VIP193.
+func (n *Node) collectBackers(ctx context.Context, flow *packer.Flow) ([][]byte, error) {
+	committee := flow.Committee()
+	if len(committee) == 0 {
+		return nil, nil
+	}
+
+	proposal, err := flow.Proposal(n.master.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backingTimeout)
+	defer cancel()
+
+	members := make(map[thor.Address]bool, len(committee))
+	for _, member := range committee {
+		members[member] = true
+	}
+	hash := proposal.Header().ProposalHash()
+
+	var sigs [][]byte
+	for _, sig := range n.comm.RequestBacking(ctx, proposal) {
+		pub, err := crypto.SigToPub(hash.Bytes(), sig)
+		if err != nil {
+			continue
+		}
+		backer := thor.Address(crypto.PubkeyToAddress(*pub))
+		if members[backer] {
+			// each member backs once
+			delete(members, backer)
+			sigs = append(sigs, sig)
+		}
+	}
+	return sigs, nil
+}
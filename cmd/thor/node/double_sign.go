@@ -0,0 +1,77 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// checkDoubleSign records the evidence if the block conflicts with another one signed by the same signer
+// for the same slot, and revokes the signer if enabled.
+func (n *Node) checkDoubleSign(header *block.Header) {
+	ev, err := n.doubleSign.Observe(header)
+	if err != nil {
+		log.Warn("failed to check double sign", "err", err)
+		return
+	}
+	if ev == nil {
+		return
+	}
+	log.Warn("double sign detected", "signer", ev.Signer, "first", ev.First.ID(), "second", ev.Second.ID())
+	if n.revokeDoubleSigners {
+		if err := n.revoke(ev.Signer); err != nil {
+			log.Warn("failed to revoke double signer", "signer", ev.Signer, "err", err)
+		} else {
+			log.Info("sent tx to revoke double signer", "signer", ev.Signer)
+		}
+	}
+}
+
+// revoke sends the tx revoking the node master from authority. It requires the master of
+// this node to be the executor.
+func (n *Node) revoke(nodeMaster thor.Address) error {
+	best := n.chain.BestBlock().Header()
+	st, err := n.stateCreator.NewState(best.StateRoot())
+	if err != nil {
+		return err
+	}
+	executor := thor.BytesToAddress(builtin.Params.Native(st).Get(thor.KeyExecutorAddress).Bytes())
+	if err := st.Err(); err != nil {
+		return err
+	}
+	if executor != n.master.Address() {
+		return errors.New("node master is not the executor")
+	}
+
+	method, found := builtin.Authority.ABI.MethodByName("revoke")
+	if !found {
+		return errors.New("method revoke not found")
+	}
+	data, err := method.EncodeInput(nodeMaster)
+	if err != nil {
+		return err
+	}
+	trx := new(tx.Builder).
+		ChainTag(n.chain.Tag()).
+		BlockRef(tx.NewBlockRef(best.Number())).
+		Expiration(720).
+		Gas(200000).
+		Nonce(uint64(time.Now().UnixNano())).
+		Clause(tx.NewClause(&builtin.Authority.Address).WithData(data)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), n.master.PrivateKey)
+	if err != nil {
+		return err
+	}
+	return n.txPool.AddLocal(trx.WithSignature(sig))
+}
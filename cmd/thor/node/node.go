@@ -24,6 +24,9 @@ import (
 	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/comm"
 	"github.com/vechain/thor/consensus"
+	"github.com/vechain/thor/doublesign"
+	"github.com/vechain/thor/finality"
+	"github.com/vechain/thor/liveness"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/packer"
@@ -35,18 +38,27 @@ import (
 
 var log = log15.New("pkg", "node")
 
+// number of blocks prevalidated ahead of processing
+const prevalidateAhead = 8
+
 type Node struct {
 	goes   co.Goes
 	packer *packer.Packer
 	cons   *consensus.Consensus
-
-	master      *Master
-	chain       *chain.Chain
-	logDB       *logdb.LogDB
-	txPool      *txpool.TxPool
-	txStashPath string
-	comm        *comm.Communicator
-	commitLock  sync.Mutex
+	final  *finality.Gadget
+
+	master       *Master
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	logDB        *logdb.LogDB
+	txPool       *txpool.TxPool
+	txStashPath  string
+	comm         *comm.Communicator
+	commitLock   sync.Mutex
+
+	doubleSign          *doublesign.Detector
+	revokeDoubleSigners bool
+	liveness            *liveness.Tracker
 }
 
 func New(
@@ -57,21 +69,30 @@ func New(
 	txPool *txpool.TxPool,
 	txStashPath string,
 	comm *comm.Communicator,
+	doubleSign *doublesign.Detector,
+	revokeDoubleSigners bool,
+	liveness *liveness.Tracker,
 ) *Node {
 	return &Node{
-		packer:      packer.New(chain, stateCreator, master.Address(), master.Beneficiary),
-		cons:        consensus.New(chain, stateCreator),
-		master:      master,
-		chain:       chain,
-		logDB:       logDB,
-		txPool:      txPool,
-		txStashPath: txStashPath,
-		comm:        comm,
+		packer:              packer.New(chain, stateCreator, master.Address(), master.Beneficiary),
+		cons:                consensus.New(chain, stateCreator),
+		final:               finality.New(chain, stateCreator, finality.DefaultWindow),
+		master:              master,
+		chain:               chain,
+		stateCreator:        stateCreator,
+		logDB:               logDB,
+		txPool:              txPool,
+		txStashPath:         txStashPath,
+		comm:                comm,
+		doubleSign:          doubleSign,
+		revokeDoubleSigners: revokeDoubleSigners,
+		liveness:            liveness,
 	}
 }
 
 func (n *Node) Run(ctx context.Context) error {
 	n.comm.Sync(n.handleBlockStream)
+	n.comm.ServeBacking(n.handleBacking)
 
 	n.goes.Go(func() { n.houseKeeping(ctx) })
 	n.goes.Go(func() { n.txStashLoop(ctx) })
@@ -93,8 +114,11 @@ func (n *Node) handleBlockStream(ctx context.Context, stream <-chan *block.Block
 		startTime = mclock.Now()
 	}
 
+	done := make(chan struct{})
+	defer close(done)
+
 	var blk *block.Block
-	for blk = range stream {
+	for blk = range n.prevalidate(stream, done) {
 		if _, err := n.processBlock(blk, &stats); err != nil {
 			return err
 		}
@@ -116,6 +140,37 @@ func (n *Node) handleBlockStream(ctx context.Context, stream <-chan *block.Block
 	return nil
 }
 
+// prevalidate runs the stateless stage of validation for blocks of the stream ahead, so that
+// header checks and signer recovery of the next block overlap execution of the previous one.
+// Blocks are passed on in order whatever the result, and errors are reported by processing.
+func (n *Node) prevalidate(stream <-chan *block.Block, done <-chan struct{}) <-chan *block.Block {
+	out := make(chan *block.Block, prevalidateAhead)
+	go func() {
+		defer close(out)
+		var last *block.Header
+		for blk := range stream {
+			parentID := blk.Header().ParentID()
+			parent := last
+			if parent == nil || parent.ID() != parentID {
+				parent, _ = n.chain.GetBlockHeader(parentID)
+			}
+			if parent != nil {
+				if err := n.cons.Prevalidate(blk, parent); err != nil {
+					log.Debug("failed to prevalidate block", "id", blk.Header().ID(), "err", err)
+				}
+			}
+			last = blk.Header()
+
+			select {
+			case out <- blk:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
 func (n *Node) houseKeeping(ctx context.Context) {
 	log.Debug("enter house keeping")
 	defer log.Debug("leave house keeping")
@@ -213,11 +268,18 @@ func (n *Node) txStashLoop(ctx context.Context) {
 	defer scope.Close()
 
 	txCh := make(chan *txpool.TxEvent)
+	dropCh := make(chan *txpool.TxDropEvent)
 	scope.Track(n.txPool.SubscribeTxEvent(txCh))
+	scope.Track(n.txPool.SubscribeTxDropEvent(dropCh))
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case dropEv := <-dropCh:
+			// dropped txs should not come back after restart
+			if err := stash.Delete(dropEv.Tx.ID()); err != nil {
+				log.Warn("delete stashed tx", "id", dropEv.Tx.ID(), "err", err)
+			}
 		case txEv := <-txCh:
 			// skip executables
 			if txEv.Executable != nil && *txEv.Executable {
@@ -262,7 +324,11 @@ func (n *Node) processBlock(blk *block.Block, stats *blockStats) (bool, error) {
 
 	fork, err := n.commitBlock(blk, receipts)
 	if err != nil {
-		if !n.chain.IsBlockExist(err) {
+		switch {
+		case n.chain.IsBlockExist(err):
+		case n.chain.IsFinalityViolation(err):
+			log.Warn("refused reorg past finalized block", "id", blk.Header().ID(), "finalized", n.chain.Finalized().ID())
+		default:
 			log.Error("failed to commit block", "err", err)
 		}
 		return false, err
@@ -270,6 +336,7 @@ func (n *Node) processBlock(blk *block.Block, stats *blockStats) (bool, error) {
 	commitElapsed := mclock.Now() - startTime - execElapsed
 	stats.UpdateProcessed(1, len(receipts), execElapsed, commitElapsed, blk.Header().GasUsed())
 	n.processFork(fork)
+	n.checkDoubleSign(blk.Header())
 	return len(fork.Trunk) > 0, nil
 }
 
@@ -281,6 +348,16 @@ func (n *Node) commitBlock(newBlock *block.Block, receipts tx.Receipts) (*chain.
 	if err != nil {
 		return nil, err
 	}
+	if err := n.liveness.Apply(fork); err != nil {
+		log.Warn("failed to track liveness", "err", err)
+	}
+	if len(fork.Trunk) > 0 {
+		if moved, err := n.final.Update(newBlock.Header()); err != nil {
+			log.Warn("failed to update finality", "err", err)
+		} else if moved {
+			log.Debug("block finalized", "id", n.chain.Finalized().ID())
+		}
+	}
 
 	forkIDs := make([]thor.Bytes32, 0, len(fork.Branch))
 	for _, header := range fork.Branch {
@@ -71,7 +71,7 @@ func (n *Node) packerLoop(ctx context.Context) {
 		}
 
 		if now+1 >= flow.When() {
-			if err := n.pack(flow); err != nil {
+			if err := n.pack(ctx, flow); err != nil {
 				log.Error("failed to pack block", "err", err)
 			}
 			flow = nil
@@ -79,7 +79,7 @@ func (n *Node) packerLoop(ctx context.Context) {
 	}
 }
 
-func (n *Node) pack(flow *packer.Flow) error {
+func (n *Node) pack(ctx context.Context, flow *packer.Flow) error {
 	txs := n.txPool.Executables()
 	var txsToRemove []thor.Bytes32
 	defer func() {
@@ -101,11 +101,19 @@ func (n *Node) pack(flow *packer.Flow) error {
 		}
 	}
 
-	newBlock, stage, receipts, err := flow.Pack(n.master.PrivateKey)
+	// waiting for backers is not counted in execution time
+	backingStartTime := mclock.Now()
+	backerSigs, err := n.collectBackers(ctx, flow)
+	if err != nil {
+		log.Warn("failed to collect backers", "err", err)
+	}
+	backingElapsed := mclock.Now() - backingStartTime
+
+	newBlock, stage, receipts, err := flow.PackWithBackers(n.master.PrivateKey, backerSigs)
 	if err != nil {
 		return err
 	}
-	execElapsed := mclock.Now() - startTime
+	execElapsed := mclock.Now() - startTime - backingElapsed
 
 	if _, err := stage.Commit(); err != nil {
 		return errors.WithMessage(err, "commit state")
@@ -115,7 +123,7 @@ func (n *Node) pack(flow *packer.Flow) error {
 	if err != nil {
 		return errors.WithMessage(err, "commit block")
 	}
-	commitElapsed := mclock.Now() - startTime - execElapsed
+	commitElapsed := mclock.Now() - startTime - backingElapsed - execElapsed
 
 	n.processFork(fork)
 
@@ -123,6 +131,7 @@ func (n *Node) pack(flow *packer.Flow) error {
 		n.comm.BroadcastBlock(newBlock)
 		log.Info("📦 new block packed",
 			"txs", len(receipts),
+			"backers", len(backerSigs),
 			"mgas", float64(newBlock.Header().GasUsed())/1000/1000,
 			"et", fmt.Sprintf("%v|%v", common.PrettyDuration(execElapsed), common.PrettyDuration(commitElapsed)),
 			"id", shortID(newBlock.Header().ID()),
@@ -53,6 +53,11 @@ func (ts *txStash) Save(tx *tx.Transaction) error {
 	return nil
 }
 
+// Delete deletes the stashed tx, e.g. cancelled one.
+func (ts *txStash) Delete(txID thor.Bytes32) error {
+	return ts.kv.Delete(txID.Bytes())
+}
+
 func (ts *txStash) LoadAll() tx.Transactions {
 	var txs tx.Transactions
 	iter := ts.kv.NewIterator(*kv.NewRangeWithBytesPrefix(nil))
@@ -0,0 +1,180 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/loglevel"
+	"github.com/vechain/thor/p2psrv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
+)
+
+// reloadableConfig is the part of configuration that can be changed without restarting
+// the node. It's read from the JSON file given by the config-file flag.
+// Absent fields leave the current settings untouched.
+type reloadableConfig struct {
+	LogLevels          *loglevel.Levels     `json:"logLevels"`
+	MinGasPrice        *string              `json:"minGasPrice"`
+	OriginMinGasPrices map[string]string    `json:"originMinGasPrices"`
+	OriginPolicy       *txpool.OriginPolicy `json:"originPolicy"`
+	StaticPeers        *[]string            `json:"staticPeers"`
+}
+
+// reloader applies the config file to running components.
+type reloader struct {
+	path        string
+	logHandler  *loglevel.Handler
+	txPool      *txpool.TxPool
+	p2pSrv      *p2psrv.Server // nil in solo mode
+	lock        sync.Mutex
+	staticPeers map[discover.NodeID]*discover.Node
+}
+
+// newReloader creates the reloader if config file is specified, or returns nil.
+func newReloader(path string, logHandler *loglevel.Handler, txPool *txpool.TxPool, p2pSrv *p2psrv.Server) *reloader {
+	if path == "" {
+		return nil
+	}
+	return &reloader{
+		path:        path,
+		logHandler:  logHandler,
+		txPool:      txPool,
+		p2pSrv:      p2pSrv,
+		staticPeers: make(map[discover.NodeID]*discover.Node),
+	}
+}
+
+// reloadFunc returns the function to reload config, or nil if config file is not specified.
+func (r *reloader) reloadFunc() func() error {
+	if r == nil {
+		return nil
+	}
+	return r.Reload
+}
+
+func parseGasPrice(str string) (*big.Int, error) {
+	price, ok := new(big.Int).SetString(strings.TrimSpace(str), 0)
+	if !ok || price.Sign() < 0 {
+		return nil, fmt.Errorf("invalid gas price '%v'", str)
+	}
+	return price, nil
+}
+
+// Reload reads the config file and applies it. Nothing is applied if the file is invalid.
+func (r *reloader) Reload() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	data, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	var cfg reloadableConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return errors.WithMessage(err, "parse config file")
+	}
+
+	// validate all before applying any
+	var (
+		minGasPrice    *big.Int
+		originMinPrice = make(map[thor.Address]*big.Int)
+		staticPeers    = make(map[discover.NodeID]*discover.Node)
+	)
+	if cfg.MinGasPrice != nil && *cfg.MinGasPrice != "" {
+		if minGasPrice, err = parseGasPrice(*cfg.MinGasPrice); err != nil {
+			return errors.WithMessage(err, "minGasPrice")
+		}
+	}
+	for addr, str := range cfg.OriginMinGasPrices {
+		origin, err := thor.ParseAddress(addr)
+		if err != nil {
+			return errors.WithMessage(err, "originMinGasPrices")
+		}
+		if originMinPrice[origin], err = parseGasPrice(str); err != nil {
+			return errors.WithMessage(err, "originMinGasPrices")
+		}
+	}
+	if cfg.StaticPeers != nil {
+		if r.p2pSrv == nil {
+			return errors.New("staticPeers: P2P networking not enabled")
+		}
+		for _, url := range *cfg.StaticPeers {
+			node, err := discover.ParseNode(url)
+			if err != nil {
+				return errors.WithMessage(err, "staticPeers")
+			}
+			staticPeers[node.ID] = node
+		}
+	}
+	if cfg.LogLevels != nil {
+		// Apply validates levels before changing anything
+		if err := r.logHandler.Apply(*cfg.LogLevels); err != nil {
+			return errors.WithMessage(err, "logLevels")
+		}
+	}
+
+	if cfg.MinGasPrice != nil || cfg.OriginMinGasPrices != nil {
+		r.txPool.SetMinGasPrice(minGasPrice, originMinPrice)
+	}
+	if cfg.OriginPolicy != nil {
+		r.txPool.SetOriginPolicy(*cfg.OriginPolicy)
+	}
+	if cfg.StaticPeers != nil {
+		for id, node := range r.staticPeers {
+			if _, ok := staticPeers[id]; !ok {
+				r.p2pSrv.RemoveStatic(node)
+			}
+		}
+		for id, node := range staticPeers {
+			if _, ok := r.staticPeers[id]; !ok {
+				r.p2pSrv.AddStatic(node)
+			}
+		}
+		r.staticPeers = staticPeers
+	}
+	log.Info("config reloaded", "path", r.path)
+	return nil
+}
+
+// start loads the config file for the first time, then reloads it on SIGHUP until ctx is done.
+// It's a no-op if config file is not specified.
+func (r *reloader) start(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	if err := r.Reload(); err != nil {
+		fatal(fmt.Sprintf("load config file [%v]: %v", r.path, err))
+	}
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGHUP)
+		defer signal.Stop(sigCh)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigCh:
+				if err := r.Reload(); err != nil {
+					log.Warn("failed to reload config", "path", r.path, "err", err)
+				}
+			}
+		}
+	}()
+}
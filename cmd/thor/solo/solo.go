@@ -18,6 +18,7 @@ import (
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/co"
+	"github.com/vechain/thor/finality"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/packer"
@@ -34,6 +35,7 @@ type Solo struct {
 	chain       *chain.Chain
 	txPool      *txpool.TxPool
 	packer      *packer.Packer
+	finality    *finality.Gadget
 	logDB       *logdb.LogDB
 	bestBlockCh chan *block.Block
 	gasLimit    uint64
@@ -53,6 +55,7 @@ func New(
 		chain:    chain,
 		txPool:   txPool,
 		packer:   packer.New(chain, stateCreator, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address),
+		finality: finality.New(chain, stateCreator, finality.DefaultWindow),
 		logDB:    logDB,
 		gasLimit: gasLimit,
 		onDemand: onDemand,
@@ -166,6 +169,9 @@ func (s *Solo) packing(pendingTxs tx.Transactions) error {
 	if err != nil {
 		return errors.WithMessage(err, "commit block")
 	}
+	if _, err := s.finality.Update(b.Header()); err != nil {
+		log.Warn("failed to update finality", "err", err)
+	}
 
 	batch := s.logDB.Prepare(b.Header())
 	for i, tx := range b.Transactions() {
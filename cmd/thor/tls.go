@@ -0,0 +1,89 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+)
+
+// how often cert files are checked for rotation
+const certCheckInterval = 10 * time.Second
+
+// certLoader provides the TLS certificate of API server.
+// If reload is set, cert files are re-read once modified, so rotated certs take effect without restart.
+type certLoader struct {
+	certFile, keyFile string
+	reload            bool
+
+	lock      sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func newCertLoader(certFile, keyFile string, reload bool) (*certLoader, error) {
+	l := &certLoader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		reload:   reload,
+	}
+	modTime, err := l.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.load(modTime); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *certLoader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{l.certFile, l.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (l *certLoader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return err
+	}
+	l.cert = &cert
+	l.modTime = modTime
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (l *certLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.reload && time.Since(l.lastCheck) >= certCheckInterval {
+		l.lastCheck = time.Now()
+		// the current cert is kept in use if new files are not ready
+		if modTime, err := l.latestModTime(); err != nil {
+			log.Warn("failed to check API TLS cert", "err", err)
+		} else if !modTime.Equal(l.modTime) {
+			if err := l.load(modTime); err != nil {
+				log.Warn("failed to reload API TLS cert", "err", err)
+			} else {
+				log.Info("API TLS cert reloaded")
+			}
+		}
+	}
+	return l.cert, nil
+}
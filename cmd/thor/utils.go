@@ -8,6 +8,7 @@ package main
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/subtle"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -17,6 +18,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
@@ -148,9 +150,44 @@ func handleXThorestVersion(h http.Handler) http.Handler {
 	})
 }
 
+// middleware to require a valid 'x-api-key' header for write requests (tx submission).
+// Other requests are served as is.
+func handleAPIKey(h http.Handler, keys []string) http.Handler {
+	const headerKey = "x-api-key"
+	isWrite := func(r *http.Request) bool {
+		if r.Method != http.MethodPost {
+			return false
+		}
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		return path == "/transactions" || path == "/v1/transactions"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWrite(r) {
+			key := []byte(r.Header.Get(headerKey))
+			valid := false
+			for _, k := range keys {
+				if subtle.ConstantTimeCompare(key, []byte(k)) == 1 {
+					valid = true
+				}
+			}
+			if !valid {
+				io.Copy(ioutil.Discard, r.Body)
+				http.Error(w, "invalid api key", http.StatusUnauthorized)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 // middleware for http request timeout.
+// Event streams are long-lived by nature, so they are not limited.
 func handleAPITimeout(h http.Handler, timeout time.Duration) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			h.ServeHTTP(w, r)
+			return
+		}
 		ctx, cancel := context.WithTimeout(r.Context(), timeout)
 		defer cancel()
 		r = r.WithContext(ctx)
@@ -12,15 +12,27 @@ import (
 
 var numCPU = runtime.NumCPU()
 
+var parallelism = int32(numCPU)
+
+// SetParallelism sets max number of goroutines used by Parallel.
+// Non-positive n resets it to the number of CPUs.
+func SetParallelism(n int) {
+	if n <= 0 {
+		n = numCPU
+	}
+	atomic.StoreInt32(&parallelism, int32(n))
+}
+
 // Parallel to run a batch of work using as many CPU as it can.
 func Parallel(cb func(chan<- func())) <-chan struct{} {
-	queue := make(chan func(), numCPU*16)
+	n := int(atomic.LoadInt32(&parallelism))
+	queue := make(chan func(), n*16)
 	defer close(queue)
 
 	done := make(chan struct{})
 
-	nGo := int32(numCPU)
-	for i := 0; i < numCPU; i++ {
+	nGo := int32(n)
+	for i := 0; i < n; i++ {
 		go func() {
 			for work := range queue {
 				work()
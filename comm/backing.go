@@ -0,0 +1,113 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/comm/proto"
+)
+
+// HandleBacking to back the block proposal received from remote peers.
+// It returns the backer signature, or error if declined.
+type HandleBacking func(ctx context.Context, proposal *block.Block) ([]byte, error)
+
+// ServeBacking sets the handler to back proposals. Proposals are declined without the handler.
+func (c *Communicator) ServeBacking(handler HandleBacking) {
+	c.backingHandler.Store(handler)
+}
+
+const (
+	maxBackingJobs = 4   // proposals being verified at the same time, from all peers
+	maxBackings    = 256 // proposals handled to keep results of, to dedupe requests
+)
+
+// serveBacking backs the proposal requested by the peer asynchronously, since verifying it executes
+// the block. Proposals are verified one at a time per peer, and at most maxBackingJobs in total,
+// others are declined at once.
+func (c *Communicator) serveBacking(peer *Peer, proposal *block.Block, write func(interface{})) {
+	if !atomic.CompareAndSwapInt32(&peer.backing, 0, 1) {
+		write([]byte(nil))
+		return
+	}
+	select {
+	case c.backingJobs <- struct{}{}:
+	default:
+		atomic.StoreInt32(&peer.backing, 0)
+		write([]byte(nil))
+		return
+	}
+	c.goes.Go(func() {
+		defer func() {
+			<-c.backingJobs
+			atomic.StoreInt32(&peer.backing, 0)
+		}()
+		write(c.backProposal(peer, proposal))
+	})
+}
+
+// backProposal returns the signature backing the proposal, or nil if declined.
+// Each proposal is verified once, and requests for it again are replied with the result.
+func (c *Communicator) backProposal(peer *Peer, proposal *block.Block) []byte {
+	handler, _ := c.backingHandler.Load().(HandleBacking)
+	if handler == nil {
+		return nil
+	}
+	hash := proposal.Header().ProposalHash()
+	c.backings.Lock()
+	if sig, ok := c.backings.handled.Get(hash); ok {
+		c.backings.Unlock()
+		return sig.([]byte)
+	}
+	// declined while being handled
+	c.backings.handled.Add(hash, []byte(nil))
+	c.backings.Unlock()
+
+	sig, err := handler(c.ctx, proposal)
+	if err != nil {
+		peer.logger.Debug("declined to back proposal", "id", proposal.Header().ID(), "err", err)
+		return nil
+	}
+	c.backings.Lock()
+	c.backings.handled.Add(hash, sig)
+	c.backings.Unlock()
+	return sig
+}
+
+// RequestBacking asks peers to back the block proposal, and returns signatures collected
+// until all peers replied or ctx done.
+func (c *Communicator) RequestBacking(ctx context.Context, proposal *block.Block) [][]byte {
+	peers := c.peerSet.Slice().Filter(func(p *Peer) bool {
+		return p.ProtoVersion() >= proto.BackingVersion
+	})
+
+	sigCh := make(chan []byte, len(peers))
+	for _, peer := range peers {
+		peer := peer
+		c.goes.Go(func() {
+			sig, err := proto.GetBacking(ctx, peer, proposal)
+			if err != nil {
+				peer.logger.Debug("failed to request backing", "err", err)
+			}
+			sigCh <- sig
+		})
+	}
+
+	var sigs [][]byte
+	for range peers {
+		select {
+		case <-ctx.Done():
+			return sigs
+		case sig := <-sigCh:
+			if len(sig) > 0 {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	return sigs
+}
@@ -11,10 +11,12 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
@@ -41,12 +43,20 @@ type Communicator struct {
 	feedScope      event.SubscriptionScope
 	goes           co.Goes
 	onceSynced     sync.Once
+	compactRelay   bool
+	backingHandler atomic.Value // HandleBacking
+	backingJobs    chan struct{}
+	backings       struct {
+		sync.Mutex
+		handled *lru.Cache // proposal hash -> signature, nil if declined
+	}
 }
 
 // New create a new Communicator instance.
-func New(chain *chain.Chain, txPool *txpool.TxPool) *Communicator {
+// If compactRelay is true, new blocks are propagated in compact form to peers which support it.
+func New(chain *chain.Chain, txPool *txpool.TxPool, compactRelay bool) *Communicator {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Communicator{
+	c := &Communicator{
 		chain:          chain,
 		txPool:         txPool,
 		ctx:            ctx,
@@ -54,7 +64,11 @@ func New(chain *chain.Chain, txPool *txpool.TxPool) *Communicator {
 		peerSet:        newPeerSet(),
 		syncedCh:       make(chan struct{}),
 		announcementCh: make(chan *announcement),
+		compactRelay:   compactRelay,
+		backingJobs:    make(chan struct{}, maxBackingJobs),
 	}
+	c.backings.handled, _ = lru.New(maxBackings)
+	return c
 }
 
 // Synced returns a channel indicates if synchronization process passed.
@@ -130,15 +144,35 @@ func (c *Communicator) Sync(handler HandleBlockStream) {
 // Protocols returns all supported protocols.
 func (c *Communicator) Protocols() []*p2psrv.Protocol {
 	genesisID := c.chain.GenesisBlock().Header().ID()
+	// all versions share the same topic, to be discoverable by legacy nodes
+	discTopic := fmt.Sprintf("%v%v@%x", proto.Name, proto.Version, genesisID[24:])
 	return []*p2psrv.Protocol{
 		&p2psrv.Protocol{
 			Protocol: p2p.Protocol{
 				Name:    proto.Name,
 				Version: proto.Version,
 				Length:  proto.Length,
-				Run:     c.servePeer,
+				Run:     c.servePeer(proto.Version),
+			},
+			DiscTopic: discTopic,
+		},
+		&p2psrv.Protocol{
+			Protocol: p2p.Protocol{
+				Name:    proto.Name,
+				Version: proto.CompactVersion,
+				Length:  proto.CompactLength,
+				Run:     c.servePeer(proto.CompactVersion),
+			},
+			DiscTopic: discTopic,
+		},
+		&p2psrv.Protocol{
+			Protocol: p2p.Protocol{
+				Name:    proto.Name,
+				Version: proto.BackingVersion,
+				Length:  proto.BackingLength,
+				Run:     c.servePeer(proto.BackingVersion),
 			},
-			DiscTopic: fmt.Sprintf("%v%v@%x", proto.Name, proto.Version, genesisID[24:]),
+			DiscTopic: discTopic,
 		}}
 }
 
@@ -160,17 +194,20 @@ type txsToSync struct {
 	synced bool
 }
 
-func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := newPeer(p, rw)
-	c.goes.Go(func() {
-		c.runPeer(peer)
-	})
+// servePeer returns the function to serve peers negotiated the given protocol version.
+func (c *Communicator) servePeer(version uint) func(*p2p.Peer, p2p.MsgReadWriter) error {
+	return func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+		peer := newPeer(p, rw, version)
+		c.goes.Go(func() {
+			c.runPeer(peer)
+		})
 
-	var txsToSync txsToSync
+		var txsToSync txsToSync
 
-	return peer.Serve(func(msg *p2p.Msg, w func(interface{})) error {
-		return c.handleRPC(peer, msg, w, &txsToSync)
-	}, proto.MaxMsgSize)
+		return peer.Serve(func(msg *p2p.Msg, w func(interface{})) error {
+			return c.handleRPC(peer, msg, w, &txsToSync)
+		}, proto.MaxMsgSize)
+	}
 }
 
 func (c *Communicator) runPeer(peer *Peer) {
@@ -237,9 +274,22 @@ func (c *Communicator) BroadcastBlock(blk *block.Block) {
 	toPropagate := peers[:p]
 	toAnnounce := peers[p:]
 
+	var summary *block.Summary
+	if c.compactRelay {
+		summary = block.NewSummary(blk)
+	}
+
 	for _, peer := range toPropagate {
 		peer := peer
 		peer.MarkBlock(blk.Header().ID())
+		if c.compactRelay && peer.ProtoVersion() >= proto.CompactVersion {
+			c.goes.Go(func() {
+				if err := proto.NotifyNewCompactBlock(c.ctx, peer, summary); err != nil {
+					peer.logger.Debug("failed to broadcast new compact block", "err", err)
+				}
+			})
+			continue
+		}
 		c.goes.Go(func() {
 			if err := proto.NotifyNewBlock(c.ctx, peer, blk); err != nil {
 				peer.logger.Debug("failed to broadcast new block", "err", err)
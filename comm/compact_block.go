@@ -0,0 +1,70 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/comm/proto"
+	"github.com/vechain/thor/tx"
+)
+
+// reconstructCompactBlock rebuilds block body from txs in pool, and fetches missing txs from the peer.
+// Once reconstruction failed, it falls back to fetch the full block.
+func (c *Communicator) reconstructCompactBlock(peer *Peer, cb *proto.CompactBlock) {
+	blockID := cb.Header.ID()
+	if _, err := c.chain.GetBlockHeader(blockID); err != nil {
+		if !c.chain.IsNotFound(err) {
+			peer.logger.Error("failed to get block header", "err", err)
+			return
+		}
+	} else {
+		// already in chain
+		return
+	}
+
+	pending := make(map[proto.ShortTxID]*tx.Transaction)
+	for _, tx := range c.txPool.Dump() {
+		pending[proto.NewShortTxID(tx.ID())] = tx
+	}
+
+	txs := make(tx.Transactions, len(cb.ShortIDs))
+	var missing []uint64
+	for i, id := range cb.ShortIDs {
+		if tx, ok := pending[id]; ok {
+			txs[i] = tx
+		} else {
+			missing = append(missing, uint64(i))
+		}
+	}
+
+	if len(missing) > 0 {
+		fetched, err := proto.GetBlockTxs(c.ctx, peer, blockID, missing)
+		if err != nil {
+			peer.logger.Debug("failed to get block txs", "err", err)
+			return
+		}
+		if len(fetched) != len(missing) {
+			peer.logger.Debug("incomplete block txs, fallback to fetch full block")
+			c.fetchBlockByID(peer, blockID)
+			return
+		}
+		for i, index := range missing {
+			txs[index] = fetched[i]
+		}
+	}
+
+	if txs.RootHash() != cb.Header.TxsRoot() {
+		// short ID collision
+		peer.logger.Debug("compact block txs root mismatch, fallback to fetch full block")
+		c.fetchBlockByID(peer, blockID)
+		return
+	}
+
+	peer.logger.Debug("compact block reconstructed", "txs", len(txs), "fetched", len(missing))
+	c.newBlockFeed.Send(&NewBlockEvent{
+		Block: block.Compose(cb.Header, txs),
+	})
+}
@@ -164,6 +164,47 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 			}
 			write(toSend)
 		}
+	case proto.MsgNewCompactBlock:
+		var cb proto.CompactBlock
+		if err := msg.Decode(&cb); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if cb.Header == nil {
+			return errors.New("nil header")
+		}
+		peer.MarkBlock(cb.Header.ID())
+		peer.UpdateHead(cb.Header.ID(), cb.Header.TotalScore())
+		// reconstruction may call back to the peer, so do it asynchronously
+		c.goes.Go(func() {
+			c.reconstructCompactBlock(peer, &cb)
+		})
+		write(&struct{}{})
+	case proto.MsgGetBlockTxs:
+		var req proto.BlockTxsRequest
+		if err := msg.Decode(&req); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		var result tx.Transactions
+		body, err := c.chain.GetBlockBody(req.BlockID)
+		if err != nil {
+			if !c.chain.IsNotFound(err) {
+				log.Error("failed to get block body", "err", err)
+			}
+		} else {
+			for _, i := range req.Indexes {
+				if i >= uint64(len(body.Txs)) {
+					return errors.New("tx index out of range")
+				}
+				result = append(result, body.Txs[i])
+			}
+		}
+		write(result)
+	case proto.MsgGetBacking:
+		var proposal *block.Block
+		if err := msg.Decode(&proposal); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		c.serveBacking(peer, proposal, write)
 	default:
 		return fmt.Errorf("unknown message (%v)", msg.Code)
 	}
@@ -32,11 +32,13 @@ func init() {
 type Peer struct {
 	*p2p.Peer
 	*rpc.RPC
-	logger log15.Logger
+	logger  log15.Logger
+	version uint
 
 	createdTime mclock.AbsTime
 	knownTxs    *lru.Cache
 	knownBlocks *lru.Cache
+	backing     int32 // 1 if a proposal requested by the peer is being backed
 	head        struct {
 		sync.Mutex
 		id         thor.Bytes32
@@ -44,7 +46,7 @@ type Peer struct {
 	}
 }
 
-func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter, version uint) *Peer {
 	dir := "outbound"
 	if peer.Inbound() {
 		dir = "inbound"
@@ -59,12 +61,18 @@ func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 		Peer:        peer,
 		RPC:         rpc.New(peer, rw),
 		logger:      log.New(ctx...),
+		version:     version,
 		createdTime: mclock.Now(),
 		knownTxs:    knownTxs,
 		knownBlocks: knownBlocks,
 	}
 }
 
+// ProtoVersion returns the negotiated version of thor protocol.
+func (p *Peer) ProtoVersion() uint {
+	return p.version
+}
+
 // Head returns head block ID and total score.
 func (p *Peer) Head() (id thor.Bytes32, totalScore uint64) {
 	p.head.Lock()
@@ -15,6 +15,14 @@ const (
 	Version    uint   = 1
 	Length     uint64 = 8
 	MaxMsgSize        = 10 * 1024 * 1024
+
+	// CompactVersion is the protocol version which supports compact block relay.
+	CompactVersion uint   = 2
+	CompactLength  uint64 = 10
+
+	// BackingVersion is the protocol version which supports backing block proposals.
+	BackingVersion uint   = 3
+	BackingLength  uint64 = 11
 )
 
 // Protocol messages of thor
@@ -27,6 +35,9 @@ const (
 	MsgGetBlockIDByNumber
 	MsgGetBlocksFromNumber // fetch blocks from given number (including given number)
 	MsgGetTxs
+	MsgNewCompactBlock // since CompactVersion
+	MsgGetBlockTxs     // since CompactVersion
+	MsgGetBacking      // since BackingVersion
 )
 
 // MsgName convert msg code to string.
@@ -48,6 +59,12 @@ func MsgName(msgCode uint64) string {
 		return "MsgGetBlocksFromNumber"
 	case MsgGetTxs:
 		return "MsgGetTxs"
+	case MsgNewCompactBlock:
+		return "MsgNewCompactBlock"
+	case MsgGetBlockTxs:
+		return "MsgGetBlockTxs"
+	case MsgGetBacking:
+		return "MsgGetBacking"
 	default:
 		return fmt.Sprintf("unknown msg code(%v)", msgCode)
 	}
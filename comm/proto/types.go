@@ -23,8 +23,42 @@ type (
 		BestBlockID    thor.Bytes32
 		TotalScore     uint64
 	}
+
+	// ShortTxID leading bytes of tx ID, used to identify txs in compact block.
+	ShortTxID [8]byte
+
+	// CompactBlock block header along with short IDs of txs.
+	// Receivers are expected to reconstruct the block body from their tx pool.
+	CompactBlock struct {
+		Header   *block.Header
+		ShortIDs []ShortTxID
+	}
+
+	// BlockTxsRequest arg of MsgGetBlockTxs.
+	BlockTxsRequest struct {
+		BlockID thor.Bytes32
+		Indexes []uint64
+	}
 )
 
+// NewShortTxID create short tx ID for the given tx ID.
+func NewShortTxID(txID thor.Bytes32) (id ShortTxID) {
+	copy(id[:], txID[:])
+	return
+}
+
+// NewCompactBlock create compact block from the given block summary.
+func NewCompactBlock(summary *block.Summary) *CompactBlock {
+	ids := make([]ShortTxID, 0, len(summary.TxIDs))
+	for _, id := range summary.TxIDs {
+		ids = append(ids, NewShortTxID(id))
+	}
+	return &CompactBlock{
+		Header:   summary.Header,
+		ShortIDs: ids,
+	}
+}
+
 // RPC defines RPC interface.
 type RPC interface {
 	Notify(ctx context.Context, msgCode uint64, arg interface{}) error
@@ -94,3 +128,27 @@ func GetTxs(ctx context.Context, rpc RPC) (tx.Transactions, error) {
 	}
 	return txs, nil
 }
+
+// NotifyNewCompactBlock notify new block in compact form to remote peer.
+func NotifyNewCompactBlock(ctx context.Context, rpc RPC, summary *block.Summary) error {
+	return rpc.Notify(ctx, MsgNewCompactBlock, NewCompactBlock(summary))
+}
+
+// GetBlockTxs get txs of the block at given indexes from remote peer.
+func GetBlockTxs(ctx context.Context, rpc RPC, blockID thor.Bytes32, indexes []uint64) (tx.Transactions, error) {
+	var txs tx.Transactions
+	if err := rpc.Call(ctx, MsgGetBlockTxs, &BlockTxsRequest{blockID, indexes}, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// GetBacking asks the remote peer to back the block proposal.
+// It returns the backer signature, which is empty if the peer declined.
+func GetBacking(ctx context.Context, rpc RPC, proposal *block.Block) ([]byte, error) {
+	var sig []byte
+	if err := rpc.Call(ctx, MsgGetBacking, proposal, &sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
@@ -6,26 +6,51 @@
 package consensus
 
 import (
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/poa"
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/xenv"
 )
 
+const (
+	// siblings of recent blocks share parent states
+	proposersCacheLimit = 64
+	// blocks prevalidated ahead of processing
+	prevalidatedLimit = 64
+)
+
 // Consensus check whether the block is verified,
 // and predicate which trunk it belong to.
 type Consensus struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   thor.ForkConfig
+	feeds        feeds
+	// proposers (candidates satisfying endorsement) keyed by parent state root
+	proposersCache *lru.Cache
+	// blocks passed the stateless stage of validation, keyed by id
+	prevalidated *lru.Cache
 }
 
 // New create a Consensus instance.
 func New(chain *chain.Chain, stateCreator *state.Creator) *Consensus {
+	proposersCache, _ := lru.New(proposersCacheLimit)
+	prevalidated, _ := lru.New(prevalidatedLimit)
 	return &Consensus{
-		chain:        chain,
-		stateCreator: stateCreator}
+		chain:          chain,
+		stateCreator:   stateCreator,
+		forkConfig:     thor.GetForkConfig(chain.GenesisBlock().Header().ID()),
+		proposersCache: proposersCache,
+		prevalidated:   prevalidated,
+	}
 }
 
 // Process process a block.
@@ -53,11 +78,19 @@ func (c *Consensus) Process(blk *block.Block, nowTimestamp uint64) (*state.Stage
 		return nil, nil, err
 	}
 
+	startTime := time.Now()
 	stage, receipts, err := c.validate(state, blk, parentHeader, nowTimestamp)
 	if err != nil {
+		if IsFutureBlock(err) || IsCritical(err) {
+			c.feeds.rejected.Send(&BlockRejectedEvent{blk, err})
+		}
 		return nil, nil, err
 	}
+	c.feeds.processed.Send(&BlockProcessedEvent{blk, receipts, time.Since(startTime)})
 
+	if bestID := c.chain.BestBlock().Header().ID(); bestID != header.ParentID() {
+		c.feeds.fork.Send(&ForkObservedEvent{blk, bestID})
+	}
 	return stage, receipts, nil
 }
 
@@ -77,7 +110,8 @@ func (c *Consensus) NewRuntimeForReplay(header *block.Header) (*runtime.Runtime,
 	if err != nil {
 		return nil, err
 	}
-	if err := c.validateProposer(header, parentHeader, state); err != nil {
+	timing := poa.TimingAt(c.forkConfig, parentHeader.Number(), builtin.Params.Native(state).Get)
+	if err := c.validateProposer(header, parentHeader, state, timing); err != nil {
 		return nil, err
 	}
 
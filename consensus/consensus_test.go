@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/builtin"
@@ -153,6 +154,29 @@ func (tc *testConsensus) consent(blk *block.Block) error {
 	return err
 }
 
+func (tc *testConsensus) TestEvents() {
+	processed := make(chan *BlockProcessedEvent, 1)
+	rejected := make(chan *BlockRejectedEvent, 1)
+	fork := make(chan *ForkObservedEvent, 1)
+	defer tc.con.SubscribeBlockProcessed(processed).Unsubscribe()
+	defer tc.con.SubscribeBlockRejected(rejected).Unsubscribe()
+	defer tc.con.SubscribeForkObserved(fork).Unsubscribe()
+
+	tc.assert.Nil(tc.consent(tc.original))
+	ev := <-processed
+	tc.assert.Equal(tc.original.Header().ID(), ev.Block.Header().ID())
+	tc.assert.Equal(len(tc.original.Transactions()), len(ev.Receipts))
+	tc.assert.Equal(0, len(fork), "original extends the best block")
+
+	blk := tc.sign(tc.originalBuilder().Timestamp(tc.original.Header().Timestamp() + 1).Build())
+	err := tc.consent(blk)
+	tc.assert.NotNil(err)
+	rej := <-rejected
+	tc.assert.Equal(blk.Header().ID(), rej.Block.Header().ID())
+	tc.assert.Equal(err, rej.Err)
+	tc.assert.True(IsCritical(rej.Err))
+}
+
 func (tc *testConsensus) TestValidateBlockHeader() {
 	triggers := make(map[string]func())
 	triggers["triggerErrTimestampBehindParent"] = func() {
@@ -285,6 +309,19 @@ func (tc *testConsensus) TestValidateBlockBody() {
 		expect := consensusError("tx signer unavailable: invalid signature length")
 		tc.assert.Equal(err, expect)
 	}
+	triggers["triggerErrTxSignerUnavailableAmongMany"] = func() {
+		builder := tc.originalBuilder()
+		for i := 0; i < 32; i++ {
+			if i == 20 {
+				builder.Transaction(txBuilder(tc.tag).Nonce(uint64(i)).Build())
+				continue
+			}
+			builder.Transaction(txSign(txBuilder(tc.tag).Nonce(uint64(i))))
+		}
+		err := tc.consent(tc.sign(builder.Build()))
+		expect := consensusError("tx signer unavailable: invalid signature length")
+		tc.assert.Equal(err, expect)
+	}
 
 	triggers["triggerErrTxsRootMismatch"] = func() {
 		transaction := txSign(txBuilder(tc.tag))
@@ -334,6 +371,124 @@ func (tc *testConsensus) TestValidateBlockBody() {
 	}
 }
 
+func (tc *testConsensus) TestPrevalidate() {
+	parent := tc.parent.Header()
+	tc.assert.Nil(tc.con.Prevalidate(tc.original, parent))
+	tc.assert.Nil(tc.consent(tc.original))
+
+	// the same header with different body
+	trx := txSign(txBuilder(tc.tag))
+	blk := tc.sign(tc.originalBuilder().Build())
+	tc.assert.Nil(tc.con.Prevalidate(blk, parent))
+	forged := block.Compose(blk.Header(), tx.Transactions{trx})
+	tc.assert.False(tc.con.isPrevalidated(forged))
+
+	err := tc.con.Prevalidate(forged, parent)
+	tc.assert.Equal(consensusError(fmt.Sprintf("block txs root mismatch: want %v, have %v", forged.Header().TxsRoot(), forged.Transactions().RootHash())), err)
+
+	tc.assert.NotNil(tc.con.Prevalidate(blk, tc.original.Header()), "parent mismatch")
+}
+
+func (tc *testConsensus) TestBackers() {
+	proposer := genesis.DevAccounts()[0]
+	keys := make(map[thor.Address]*ecdsa.PrivateKey)
+	for _, acc := range genesis.DevAccounts() {
+		keys[acc.Address] = acc.PrivateKey
+	}
+
+	flow, err := packer.New(tc.con.chain, tc.con.stateCreator, proposer.Address, &proposer.Address).Schedule(tc.parent.Header(), tc.time)
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	committee := flow.Committee()
+	tc.assert.Equal(len(genesis.DevAccounts())-1, len(committee))
+
+	proposal, err := flow.Proposal(proposer.PrivateKey)
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	tc.assert.Nil(tc.con.VerifyProposal(proposal, committee[0], tc.time))
+	tc.assert.Equal(
+		consensusError(fmt.Sprintf("backer not in committee: %v", proposer.Address)),
+		tc.con.VerifyProposal(proposal, proposer.Address, tc.time))
+
+	// declined before executed if not from the scheduled proposer
+	sig, _ := crypto.Sign(proposal.Header().SigningHash().Bytes(), keys[committee[0]])
+	tc.assert.NotNil(tc.con.VerifyProposal(proposal.WithSignature(sig), committee[1], tc.time))
+
+	var sigs [][]byte
+	for _, member := range committee[:3] {
+		sig, _ := crypto.Sign(proposal.Header().ProposalHash().Bytes(), keys[member])
+		sigs = append(sigs, sig)
+	}
+
+	blk, _, _, err := flow.PackWithBackers(proposer.PrivateKey, sigs)
+	tc.assert.Nil(err)
+	tc.assert.Equal(tc.original.Header().TotalScore()+3, blk.Header().TotalScore())
+	tc.assert.Nil(tc.consent(blk))
+
+	// duplicated backer
+	_, _, _, err = flow.PackWithBackers(proposer.PrivateKey, append(sigs, sigs[0]))
+	tc.assert.NotNil(err)
+
+	// backer out of the committee
+	outsider, _ := crypto.Sign(proposal.Header().ProposalHash().Bytes(), proposer.PrivateKey)
+	forged := tc.sign(tc.originalBuilder().
+		TotalScore(tc.original.Header().TotalScore() + 1).
+		BackerSignatures([][]byte{outsider}).
+		Build())
+	err = tc.consent(forged)
+	tc.assert.Equal(consensusError(fmt.Sprintf("block backers invalid: backer not in committee: %v", proposer.Address)), err)
+}
+
+func (tc *testConsensus) TestProposersCache() {
+	parent := tc.parent.Header()
+	st, err := tc.con.stateCreator.NewState(parent.StateRoot())
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	proposers, err := tc.con.proposers(parent, st)
+	tc.assert.Nil(err)
+	tc.assert.Equal(len(genesis.DevAccounts()), len(proposers))
+
+	// cached ones are returned even if state changed
+	builtin.Authority.Native(st).Revoke(genesis.DevAccounts()[0].Address)
+	cached, err := tc.con.proposers(parent, st)
+	tc.assert.Nil(err)
+	tc.assert.Equal(proposers, cached)
+}
+
+func (tc *testConsensus) TestWitness() {
+	witness, err := tc.con.Witness(tc.original, tc.time)
+	tc.assert.Nil(err)
+	tc.assert.NotEmpty(witness)
+
+	data, err := rlp.EncodeToBytes(witness)
+	tc.assert.Nil(err)
+	var decoded state.Witness
+	tc.assert.Nil(rlp.DecodeBytes(data, &decoded))
+	tc.assert.Equal(witness, decoded)
+
+	stage, _, err := tc.con.VerifyStateless(tc.original, decoded, tc.time)
+	tc.assert.Nil(err)
+	root, err := stage.Hash()
+	tc.assert.Nil(err)
+	tc.assert.Equal(tc.original.Header().StateRoot(), root)
+
+	for k, v := range decoded {
+		// forged entry
+		forged := state.Witness{k: append([]byte{1}, v...)}
+		_, _, err = tc.con.VerifyStateless(tc.original, forged, tc.time)
+		tc.assert.NotNil(err)
+
+		// incomplete witness
+		delete(decoded, k)
+		_, _, err = tc.con.VerifyStateless(tc.original, decoded, tc.time)
+		tc.assert.NotNil(err)
+		break
+	}
+}
+
 func (tc *testConsensus) TestValidateProposer() {
 	triggers := make(map[string]func())
 	triggers["triggerErrSignerUnavailable"] = func() {
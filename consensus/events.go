@@ -0,0 +1,64 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// BlockProcessedEvent will be posted when a block passed verification.
+type BlockProcessedEvent struct {
+	Block    *block.Block
+	Receipts tx.Receipts
+	Elapsed  time.Duration // time spent on verification and execution
+}
+
+// BlockRejectedEvent will be posted when a block failed verification.
+// Err can be checked by IsFutureBlock and IsCritical.
+type BlockRejectedEvent struct {
+	Block *block.Block
+	Err   error
+}
+
+// ForkObservedEvent will be posted when a verified block does not extend the best block.
+type ForkObservedEvent struct {
+	Block  *block.Block
+	BestID thor.Bytes32 // id of best block at the time
+}
+
+// feeds event feeds of consensus.
+// Events are sent synchronously with processing, so subscribers should drain channels promptly.
+type feeds struct {
+	processed event.Feed
+	rejected  event.Feed
+	fork      event.Feed
+	scope     event.SubscriptionScope
+}
+
+// SubscribeBlockProcessed subscribes events of verified blocks.
+func (c *Consensus) SubscribeBlockProcessed(ch chan *BlockProcessedEvent) event.Subscription {
+	return c.feeds.scope.Track(c.feeds.processed.Subscribe(ch))
+}
+
+// SubscribeBlockRejected subscribes events of rejected blocks.
+func (c *Consensus) SubscribeBlockRejected(ch chan *BlockRejectedEvent) event.Subscription {
+	return c.feeds.scope.Track(c.feeds.rejected.Subscribe(ch))
+}
+
+// SubscribeForkObserved subscribes events of verified blocks off the best chain.
+func (c *Consensus) SubscribeForkObserved(ch chan *ForkObservedEvent) event.Subscription {
+	return c.feeds.scope.Track(c.feeds.fork.Subscribe(ch))
+}
+
+// Close unsubscribes all subscriptions.
+func (c *Consensus) Close() {
+	c.feeds.scope.Close()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/thor"
+)
+
+// VerifyProposal checks the proposal, which is a signed block without backers, before the backer
+// signs its proposal hash. The proposal should be valid as a block upon the best block, and the backer
+// should be a member of the committee selected for it. Cheap checks go first, and the proposal is
+// executed only if it's from the scheduled proposer.
+func (c *Consensus) VerifyProposal(proposal *block.Block, backer thor.Address, nowTimestamp uint64) error {
+	header := proposal.Header()
+	if header.Number() < c.forkConfig.VIP193 {
+		return consensusError("proposal before VIP193")
+	}
+	if len(header.Extension()) > 0 {
+		return consensusError("proposal already backed")
+	}
+	if bestID := c.chain.BestBlock().Header().ID(); header.ParentID() != bestID {
+		return consensusError(fmt.Sprintf("proposal not upon best block: parent %v, best %v", header.ParentID(), bestID))
+	}
+
+	parentHeader, err := c.parentHeader(header)
+	if err != nil {
+		return err
+	}
+	st, err := c.stateCreator.NewState(parentHeader.StateRoot())
+	if err != nil {
+		return err
+	}
+
+	timing := poa.TimingAt(c.forkConfig, parentHeader.Number(), builtin.Params.Native(st).Get)
+	if err := st.Err(); err != nil {
+		return err
+	}
+	if err := c.validateProposer(header, parentHeader, st, timing); err != nil {
+		return err
+	}
+
+	signer, err := header.Signer()
+	if err != nil {
+		return consensusError(fmt.Sprintf("proposal signer unavailable: %v", err))
+	}
+	proposers, err := c.proposers(parentHeader, st)
+	if err != nil {
+		return err
+	}
+	isMember := false
+	for _, member := range poa.Committee(header.ParentID(), proposers, signer, thor.CommitteeSize) {
+		if member == backer {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return consensusError(fmt.Sprintf("backer not in committee: %v", backer))
+	}
+
+	_, _, err = c.validate(st, proposal, parentHeader, nowTimestamp)
+	return err
+}
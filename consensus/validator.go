@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/poa"
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
@@ -21,25 +22,35 @@ import (
 
 func (c *Consensus) validate(
 	state *state.State,
-	block *block.Block,
+	blk *block.Block,
 	parentHeader *block.Header,
 	nowTimestamp uint64,
 ) (*state.Stage, tx.Receipts, error) {
-	header := block.Header()
+	header := blk.Header()
+
+	if !c.isPrevalidated(blk) {
+		if err := c.prevalidate(blk, parentHeader); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	if err := c.validateBlockHeader(header, parentHeader, nowTimestamp); err != nil {
+	// the interval and gas limit floor active at the parent block
+	getParam := builtin.Params.Native(state).Get
+	timing := poa.TimingAt(c.forkConfig, parentHeader.Number(), getParam)
+	minGasLimit := block.MinGasLimitAt(c.forkConfig, parentHeader.Number(), getParam)
+	if err := state.Err(); err != nil {
 		return nil, nil, err
 	}
 
-	if err := c.validateProposer(header, parentHeader, state); err != nil {
+	if err := c.validateBlockGoverned(header, parentHeader, nowTimestamp, timing, minGasLimit); err != nil {
 		return nil, nil, err
 	}
 
-	if err := c.validateBlockBody(block); err != nil {
+	if err := c.validateProposer(header, parentHeader, state, timing); err != nil {
 		return nil, nil, err
 	}
 
-	stage, receipts, err := c.verifyBlock(block, state)
+	stage, receipts, err := c.verifyBlock(blk, state)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -47,20 +58,55 @@ func (c *Consensus) validate(
 	return stage, receipts, nil
 }
 
-func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Header, nowTimestamp uint64) error {
-	if header.Timestamp() <= parent.Timestamp() {
-		return consensusError(fmt.Sprintf("block timestamp behind parents: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
+// Prevalidate runs the stateless stage of validation, i.e. header checks against the parent header,
+// and body checks including signer recovery of the header and txs. As neither the parent state nor
+// the parent in the chain is required, it can run for the next block while the previous one is executing.
+// Once passed, the stage is skipped when the same block is processed.
+func (c *Consensus) Prevalidate(blk *block.Block, parentHeader *block.Header) error {
+	if blk.Header().ParentID() != parentHeader.ID() {
+		return errors.New("parent header mismatch")
 	}
+	if err := c.prevalidate(blk, parentHeader); err != nil {
+		return err
+	}
+	c.prevalidated.Add(blk.Header().ID(), blk)
+	return nil
+}
 
-	if (header.Timestamp()-parent.Timestamp())%thor.BlockInterval != 0 {
-		return consensusError(fmt.Sprintf("block interval not rounded: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
+// isPrevalidated returns whether the very block object passed Prevalidate.
+// A block with the same header but different body is not the one.
+func (c *Consensus) isPrevalidated(blk *block.Block) bool {
+	v, ok := c.prevalidated.Get(blk.Header().ID())
+	if ok {
+		c.prevalidated.Remove(blk.Header().ID())
 	}
+	return ok && v.(*block.Block) == blk
+}
 
-	if header.Timestamp() > nowTimestamp+thor.BlockInterval {
-		return errFutureBlock
+func (c *Consensus) prevalidate(blk *block.Block, parentHeader *block.Header) error {
+	header := blk.Header()
+	if err := c.validateBlockHeader(header, parentHeader); err != nil {
+		return err
+	}
+	if _, err := header.Signer(); err != nil {
+		return consensusError(fmt.Sprintf("block signer unavailable: %v", err))
+	}
+	if header.Number() >= c.forkConfig.VIP193 {
+		// recover backers ahead, which are memoized
+		if _, err := header.Backers(); err != nil {
+			return consensusError(fmt.Sprintf("block backers unavailable: %v", err))
+		}
+	}
+	return c.validateBlockBody(blk)
+}
+
+func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Header) error {
+	if header.Timestamp() <= parent.Timestamp() {
+		return consensusError(fmt.Sprintf("block timestamp behind parents: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
 	}
 
-	if !block.GasLimit(header.GasLimit()).IsValid(parent.GasLimit()) {
+	// the floor is checked along with the governed ones
+	if !block.GasLimit(header.GasLimit()).IsValidAbove(parent.GasLimit(), 0) {
 		return consensusError(fmt.Sprintf("block gas limit invalid: parent %v, current %v", parent.GasLimit(), header.GasLimit()))
 	}
 
@@ -72,28 +118,43 @@ func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Head
 		return consensusError(fmt.Sprintf("block total score invalid: parent %v, current %v", parent.TotalScore(), header.TotalScore()))
 	}
 
+	if len(header.Extension()) > 0 && header.Number() < c.forkConfig.HeaderExtension {
+		return consensusError(fmt.Sprintf("block header extension not allowed before fork: fork %v, current %v", c.forkConfig.HeaderExtension, header.Number()))
+	}
+
 	return nil
 }
 
-func (c *Consensus) validateProposer(header *block.Header, parent *block.Header, st *state.State) error {
+// validateBlockGoverned checks the header with the timing and gas limit floor, which are governed by the parent state.
+func (c *Consensus) validateBlockGoverned(header *block.Header, parent *block.Header, nowTimestamp uint64, timing poa.Timing, minGasLimit uint64) error {
+	if _, ok := timing.SlotOf(parent.Timestamp(), header.Timestamp()); !ok {
+		return consensusError(fmt.Sprintf("block interval not rounded: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
+	}
+
+	if timing.IsFutureTime(header.Timestamp(), nowTimestamp) {
+		return errFutureBlock
+	}
+
+	// below the floor, only allowed when approaching it after the floor raised
+	if header.GasLimit() < minGasLimit && header.GasLimit() < parent.GasLimit() {
+		return consensusError(fmt.Sprintf("block gas limit invalid: parent %v, current %v", parent.GasLimit(), header.GasLimit()))
+	}
+
+	return nil
+}
+
+func (c *Consensus) validateProposer(header *block.Header, parent *block.Header, st *state.State, timing poa.Timing) error {
 	signer, err := header.Signer()
 	if err != nil {
 		return consensusError(fmt.Sprintf("block signer unavailable: %v", err))
 	}
 
-	authority := builtin.Authority.Native(st)
-	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
-
-	candidates := authority.Candidates(endorsement, thor.MaxBlockProposers)
-	proposers := make([]poa.Proposer, 0, len(candidates))
-	for _, c := range candidates {
-		proposers = append(proposers, poa.Proposer{
-			Address: c.NodeMaster,
-			Active:  c.Active,
-		})
+	proposers, err := c.proposers(parent, st)
+	if err != nil {
+		return err
 	}
 
-	sched, err := poa.NewScheduler(signer, proposers, parent.Number(), parent.Timestamp())
+	sched, err := poa.NewSchedulerWithTiming(timing, signer, proposers, parent.Number(), parent.Timestamp())
 	if err != nil {
 		return consensusError(fmt.Sprintf("block signer invalid: %v %v", signer, err))
 	}
@@ -103,10 +164,23 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 	}
 
 	updates, score := sched.Updates(header.Timestamp())
+	if header.Number() >= c.forkConfig.VIP193 {
+		// each backer weights the block in fork choice
+		backers, err := header.Backers()
+		if err != nil {
+			return consensusError(fmt.Sprintf("block backers unavailable: %v", err))
+		}
+		committee := poa.Committee(header.ParentID(), proposers, signer, thor.CommitteeSize)
+		if err := poa.VerifyBackers(committee, backers); err != nil {
+			return consensusError(fmt.Sprintf("block backers invalid: %v", err))
+		}
+		score += uint64(len(backers))
+	}
 	if parent.TotalScore()+score != header.TotalScore() {
 		return consensusError(fmt.Sprintf("block total score invalid: want %v, have %v", parent.TotalScore()+score, header.TotalScore()))
 	}
 
+	authority := builtin.Authority.Native(st)
 	for _, proposer := range updates {
 		authority.Update(proposer.Address, proposer.Active)
 	}
@@ -114,6 +188,30 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 	return nil
 }
 
+// proposers returns candidates satisfying endorsement at the parent state.
+// The state is read only once per parent state root, as blocks sharing the parent, like rivals during
+// sync or future blocks retried, come with the same candidates.
+func (c *Consensus) proposers(parent *block.Header, st *state.State) ([]poa.Proposer, error) {
+	if cached, ok := c.proposersCache.Get(parent.StateRoot()); ok {
+		return cached.([]poa.Proposer), nil
+	}
+
+	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
+	candidates := builtin.Authority.Native(st).Candidates(endorsement, thor.MaxBlockProposers)
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	proposers := make([]poa.Proposer, 0, len(candidates))
+	for _, c := range candidates {
+		proposers = append(proposers, poa.Proposer{
+			Address: c.NodeMaster,
+			Active:  c.Active,
+		})
+	}
+	c.proposersCache.Add(parent.StateRoot(), proposers)
+	return proposers, nil
+}
+
 func (c *Consensus) validateBlockBody(blk *block.Block) error {
 	header := blk.Header()
 	txs := blk.Transactions()
@@ -121,6 +219,17 @@ func (c *Consensus) validateBlockBody(blk *block.Block) error {
 		return consensusError(fmt.Sprintf("block txs root mismatch: want %v, have %v", header.TxsRoot(), txs.RootHash()))
 	}
 
+	// signer recovery dominates the cost of body validation, so do it concurrently ahead.
+	// recovered signers are cached by txs.
+	if len(txs) > 1 {
+		<-co.Parallel(func(queue chan<- func()) {
+			for _, tx := range txs {
+				tx := tx
+				queue <- func() { tx.Signer() }
+			}
+		})
+	}
+
 	for _, tx := range txs {
 		if _, err := tx.Signer(); err != nil {
 			return consensusError(fmt.Sprintf("tx signer unavailable: %v", err))
@@ -0,0 +1,75 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/trie"
+	"github.com/vechain/thor/tx"
+)
+
+// Witness validates the block upon the full parent state, and records trie nodes and codes touched.
+// With the witness, the block can be verified by VerifyStateless without the state store.
+// Unlike Process, blocks already in the chain are accepted, for historical verification.
+func (c *Consensus) Witness(blk *block.Block, nowTimestamp uint64) (state.Witness, error) {
+	parentHeader, err := c.parentHeader(blk.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	// the cached proposers would leave their reads unrecorded
+	c.proposersCache.Remove(parentHeader.StateRoot())
+
+	recorder := c.stateCreator.NewWitnessRecorder()
+	st, err := state.New(parentHeader.StateRoot(), recorder)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := c.validate(st, blk, parentHeader, nowTimestamp); err != nil {
+		return nil, err
+	}
+	return recorder.Witness(), nil
+}
+
+// VerifyStateless verifies the block, with parent states served by the witness instead of the state store.
+// Block headers and tx metas are still read from the chain.
+// The returned stage is backed by the witness, and should not be committed.
+func (c *Consensus) VerifyStateless(blk *block.Block, witness state.Witness, nowTimestamp uint64) (*state.Stage, tx.Receipts, error) {
+	parentHeader, err := c.parentHeader(blk.Header())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creator, err := state.NewWitnessCreator(witness)
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := creator.NewState(parentHeader.StateRoot())
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "witness incomplete")
+	}
+	stage, receipts, err := c.validate(st, blk, parentHeader, nowTimestamp)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*trie.MissingNodeError); ok {
+			return nil, nil, errors.WithMessage(err, "witness incomplete")
+		}
+		return nil, nil, err
+	}
+	return stage, receipts, nil
+}
+
+func (c *Consensus) parentHeader(header *block.Header) (*block.Header, error) {
+	parentHeader, err := c.chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		if !c.chain.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, errParentMissing
+	}
+	return parentHeader, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package doublesign detects authority nodes signing different blocks for the same slot,
+// and records the conflicting headers as evidences.
+package doublesign
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+var evidencePrefix = []byte("double-sign-") // (prefix, block id, block id) -> evidence
+
+// number of recent slots remembered
+const recentLimit = 1024
+
+// Evidence proves that the signer signed two different blocks with the same parent and timestamp.
+type Evidence struct {
+	Signer thor.Address
+	First  *block.Header
+	Second *block.Header
+}
+
+func (ev *Evidence) key() []byte {
+	id1, id2 := ev.First.ID(), ev.Second.ID()
+	// in deterministic order, so that the same pair is saved once
+	if bytes.Compare(id1[:], id2[:]) > 0 {
+		id1, id2 = id2, id1
+	}
+	key := append([]byte(nil), evidencePrefix...)
+	key = append(key, id1[:]...)
+	return append(key, id2[:]...)
+}
+
+type slot struct {
+	parentID  thor.Bytes32
+	timestamp uint64
+	signer    thor.Address
+}
+
+// Detector detects double signing among blocks observed.
+type Detector struct {
+	kv     kv.GetPutter
+	recent *lru.Cache
+}
+
+// New creates a detector saving evidences into kv.
+func New(kv kv.GetPutter) *Detector {
+	recent, _ := lru.New(recentLimit)
+	return &Detector{
+		kv:     kv,
+		recent: recent,
+	}
+}
+
+// Observe checks the header against recently observed ones. If another block was signed by the
+// same signer for the same slot, the evidence is saved and returned.
+func (d *Detector) Observe(header *block.Header) (*Evidence, error) {
+	signer, err := header.Signer()
+	if err != nil {
+		return nil, err
+	}
+	key := slot{header.ParentID(), header.Timestamp(), signer}
+	if v, ok := d.recent.Get(key); ok {
+		first := v.(*block.Header)
+		if first.ID() == header.ID() {
+			return nil, nil
+		}
+		ev := &Evidence{Signer: signer, First: first, Second: header}
+		data, err := rlp.EncodeToBytes([]*block.Header{first, header})
+		if err != nil {
+			return nil, err
+		}
+		if err := d.kv.Put(ev.key(), data); err != nil {
+			return nil, err
+		}
+		return ev, nil
+	}
+	d.recent.Add(key, header)
+	return nil, nil
+}
+
+// Load loads all evidences saved in kv.
+func Load(r kv.Getter) ([]*Evidence, error) {
+	it := r.NewIterator(*kv.NewRangeWithBytesPrefix(evidencePrefix))
+	defer it.Release()
+
+	var evs []*Evidence
+	for it.Next() {
+		// skip keys of other kinds sharing the prefix, e.g. trie nodes
+		if len(it.Key()) != len(evidencePrefix)+64 {
+			continue
+		}
+		var headers []*block.Header
+		if err := rlp.DecodeBytes(it.Value(), &headers); err != nil {
+			return nil, err
+		}
+		if len(headers) != 2 {
+			continue
+		}
+		signer, err := headers[0].Signer()
+		if err != nil {
+			return nil, err
+		}
+		evs = append(evs, &Evidence{Signer: signer, First: headers[0], Second: headers[1]})
+	}
+	return evs, it.Error()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package doublesign_test
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/doublesign"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func newHeader(key *ecdsa.PrivateKey, parentID thor.Bytes32, timestamp uint64, gasLimit uint64) *block.Header {
+	b := new(block.Builder).
+		ParentID(parentID).
+		Timestamp(timestamp).
+		GasLimit(gasLimit).
+		Build()
+	sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), key)
+	return b.WithSignature(sig).Header()
+}
+
+func TestDetector(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	d := doublesign.New(db)
+
+	k1, _ := crypto.GenerateKey()
+	k2, _ := crypto.GenerateKey()
+	parentID := thor.BytesToBytes32([]byte("parent"))
+
+	h1 := newHeader(k1, parentID, 10, 1)
+	h1x := newHeader(k1, parentID, 10, 2)
+	h2 := newHeader(k2, parentID, 10, 3)
+	h1y := newHeader(k1, parentID, 20, 4)
+
+	for _, h := range []*block.Header{h1, h1, h2, h1y} {
+		ev, err := d.Observe(h)
+		assert.Nil(t, err)
+		assert.Nil(t, ev, "no conflict")
+	}
+
+	ev, err := d.Observe(h1x)
+	assert.Nil(t, err)
+	if assert.NotNil(t, ev) {
+		assert.Equal(t, thor.Address(crypto.PubkeyToAddress(k1.PublicKey)), ev.Signer)
+		assert.Equal(t, h1.ID(), ev.First.ID())
+		assert.Equal(t, h1x.ID(), ev.Second.ID())
+	}
+	// observed again
+	_, err = d.Observe(h1x)
+	assert.Nil(t, err)
+
+	evs, err := doublesign.Load(db)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(evs)) {
+		assert.Equal(t, ev.Signer, evs[0].Signer)
+		assert.Equal(t, h1.ID(), evs[0].First.ID())
+		assert.Equal(t, h1x.ID(), evs[0].Second.ID())
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package finality marks trunk blocks final, once enough authority nodes have built on top of them.
+package finality
+
+import (
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// DefaultWindow is the default number of blocks looked back to collect signers.
+// It covers two rounds of full proposer set.
+const DefaultWindow = uint32(thor.MaxBlockProposers * 2)
+
+// Gadget finalizes blocks. A block is final once blocks built on top of it within
+// the window are signed by a supermajority (more than 2/3) of active authority nodes.
+type Gadget struct {
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	window       uint32
+}
+
+// New creates a gadget.
+func New(chain *chain.Chain, stateCreator *state.Creator, window uint32) *Gadget {
+	return &Gadget{
+		chain:        chain,
+		stateCreator: stateCreator,
+		window:       window,
+	}
+}
+
+// Update checks finality with the new trunk head, and moves the finalized block of chain forward if any.
+// It returns whether the finalized block moved.
+func (g *Gadget) Update(head *block.Header) (bool, error) {
+	quorum, err := g.quorum(head)
+	if err != nil {
+		return false, err
+	}
+	return g.update(head, quorum)
+}
+
+// quorum returns count of distinct signers required, by active authority nodes at the head.
+func (g *Gadget) quorum(head *block.Header) (int, error) {
+	st, err := g.stateCreator.NewState(head.StateRoot())
+	if err != nil {
+		return 0, err
+	}
+	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
+	candidates := builtin.Authority.Native(st).Candidates(endorsement, thor.MaxBlockProposers)
+	if err := st.Err(); err != nil {
+		return 0, err
+	}
+	active := 0
+	for _, c := range candidates {
+		if c.Active {
+			active++
+		}
+	}
+	return active*2/3 + 1, nil
+}
+
+func (g *Gadget) update(head *block.Header, quorum int) (bool, error) {
+	finalized := g.chain.Finalized()
+	signers := make(map[thor.Address]struct{})
+
+	header := head
+	for i := uint32(0); i < g.window && header.Number() > finalized.Number()+1; i++ {
+		signer, err := header.Signer()
+		if err != nil {
+			return false, err
+		}
+		signers[signer] = struct{}{}
+		if len(signers) >= quorum {
+			// the parent is the latest block built on by the quorum
+			if err := g.chain.SetFinalized(header.ParentID()); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+		if header, err = g.chain.GetBlockHeader(header.ParentID()); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package finality
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+)
+
+func newBlock(parent *block.Block, key *ecdsa.PrivateKey) *block.Block {
+	b := new(block.Builder).
+		ParentID(parent.Header().ID()).
+		TotalScore(parent.Header().TotalScore() + 1).
+		Build()
+	sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), key)
+	return b.WithSignature(sig)
+}
+
+func TestUpdate(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	stateCreator := state.NewCreator(kv)
+	b0, _, _ := genesis.NewDevnet().Build(stateCreator)
+	c, err := chain.New(kv, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k1, _ := crypto.GenerateKey()
+	k2, _ := crypto.GenerateKey()
+	k3, _ := crypto.GenerateKey()
+
+	add := func(parent *block.Block, key *ecdsa.PrivateKey) *block.Block {
+		b := newBlock(parent, key)
+		if _, err := c.AddBlock(b, nil); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	b1 := add(b0, k1)
+	b2 := add(b1, k2)
+	b3 := add(b2, k1)
+	b4 := add(b3, k3)
+
+	g := New(c, stateCreator, 3)
+
+	moved, err := g.update(b4.Header(), 3)
+	assert.Nil(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, b1.Header().ID(), c.Finalized().ID(), "b2, b3, b4 signed by 3 nodes")
+
+	moved, err = g.update(b4.Header(), 3)
+	assert.Nil(t, err)
+	assert.False(t, moved, "no new signer")
+
+	b5 := add(b4, k2)
+	moved, err = g.update(b5.Header(), 3)
+	assert.Nil(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, b2.Header().ID(), c.Finalized().ID())
+
+	b6 := add(b5, k2)
+	b7 := add(b6, k2)
+	moved, err = g.update(b7.Header(), 3)
+	assert.Nil(t, err)
+	assert.False(t, moved, "quorum not reached within window")
+	assert.Equal(t, b2.Header().ID(), c.Finalized().ID())
+
+	// devnet has the only solo block signer
+	quorum, err := g.quorum(b0.Header())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, quorum)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package jobs runs periodic maintenance work of the node.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/co"
+)
+
+var log = log15.New("pkg", "jobs")
+
+// how often due jobs are checked
+var checkInterval = 10 * time.Second
+
+// Job is a periodic maintenance task.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Status reports state of a job. Times are unix timestamps, zero if never.
+type Status struct {
+	Name         string  `json:"name"`
+	Interval     string  `json:"interval"`
+	Running      bool    `json:"running"`
+	Runs         uint64  `json:"runs"`
+	LastRun      int64   `json:"lastRun"`
+	LastDuration float64 `json:"lastDuration"` // in seconds
+	LastError    string  `json:"lastError,omitempty"`
+	NextRun      int64   `json:"nextRun"`
+}
+
+type job struct {
+	Job
+	status    Status
+	next      time.Time
+	triggered bool
+}
+
+// Scheduler runs jobs one at a time. A due job waits for a low-activity window,
+// but no longer than one more interval.
+type Scheduler struct {
+	lock   sync.Mutex
+	jobs   []*job
+	idle   func() bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+	goes   co.Goes
+}
+
+// New creates a scheduler. idle reports whether the node is in a low-activity window.
+func New(idle func() bool) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		idle:   idle,
+		ctx:    ctx,
+		cancel: cancel,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Add adds a job, which is first due one interval later.
+func (s *Scheduler) Add(j Job) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.jobs = append(s.jobs, &job{
+		Job: j,
+		status: Status{
+			Name:     j.Name,
+			Interval: j.Interval.String(),
+		},
+		next: time.Now().Add(j.Interval),
+	})
+}
+
+// Start starts the scheduling loop.
+func (s *Scheduler) Start() {
+	s.goes.Go(s.loop)
+}
+
+// Stop cancels the running job and waits for the loop to exit.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.goes.Wait()
+}
+
+// Trigger makes the named job run as soon as possible, regardless of activity.
+func (s *Scheduler) Trigger(name string) error {
+	s.lock.Lock()
+	found := false
+	for _, j := range s.jobs {
+		if j.Name == name {
+			j.triggered = true
+			found = true
+		}
+	}
+	s.lock.Unlock()
+
+	if !found {
+		return errors.New("no such job")
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Status returns status of all jobs.
+func (s *Scheduler) Status() []Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	all := make([]Status, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status := j.status
+		status.NextRun = j.next.Unix()
+		all = append(all, status)
+	}
+	return all
+}
+
+// pick returns the next job to run, or nil if none.
+func (s *Scheduler) pick(now time.Time, idle func() bool) *job {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var due []*job
+	for _, j := range s.jobs {
+		if j.triggered {
+			return j
+		}
+		if !now.Before(j.next) {
+			due = append(due, j)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	for _, j := range due {
+		// waited too long for a quiet moment
+		if !now.Before(j.next.Add(j.Interval)) {
+			return j
+		}
+	}
+	if idle() {
+		return due[0]
+	}
+	return nil
+}
+
+func (s *Scheduler) run(j *job) {
+	s.lock.Lock()
+	j.status.Running = true
+	j.triggered = false
+	s.lock.Unlock()
+
+	start := time.Now()
+	err := j.Run(s.ctx)
+	elapsed := time.Since(start)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	j.status.Running = false
+	j.status.Runs++
+	j.status.LastRun = start.Unix()
+	j.status.LastDuration = elapsed.Seconds()
+	j.status.LastError = ""
+	if err != nil {
+		j.status.LastError = err.Error()
+		log.Warn("job failed", "name", j.Name, "err", err)
+	} else {
+		log.Debug("job done", "name", j.Name, "elapsed", elapsed)
+	}
+	j.next = time.Now().Add(j.Interval)
+}
+
+func (s *Scheduler) loop() {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		// run all picked jobs in a row
+		for s.ctx.Err() == nil {
+			j := s.pick(time.Now(), s.idle)
+			if j == nil {
+				break
+			}
+			s.run(j)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	var (
+		busy = func() bool { return false }
+		idle = func() bool { return true }
+		runs = 0
+	)
+	s := New(busy)
+	s.Add(Job{
+		Name:     "test",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			runs++
+			if runs > 1 {
+				return errors.New("failed")
+			}
+			return nil
+		},
+	})
+	next := s.jobs[0].next
+
+	assert.Nil(t, s.pick(next.Add(-time.Second), idle), "not due")
+	assert.Nil(t, s.pick(next, busy), "waiting for idle")
+	assert.NotNil(t, s.pick(next, idle))
+	assert.NotNil(t, s.pick(next.Add(time.Minute), busy), "overdue")
+
+	j := s.pick(next, idle)
+	s.run(j)
+	status := s.Status()[0]
+	assert.Equal(t, uint64(1), status.Runs)
+	assert.Equal(t, "", status.LastError)
+	assert.Nil(t, s.pick(next, idle), "rescheduled")
+
+	assert.NotNil(t, s.Trigger("none"))
+	assert.Nil(t, s.Trigger("test"))
+	j = s.pick(next, busy)
+	assert.NotNil(t, j, "triggered")
+	s.run(j)
+	status = s.Status()[0]
+	assert.Equal(t, uint64(2), status.Runs)
+	assert.Equal(t, "failed", status.LastError)
+	assert.False(t, status.Running)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package light verifies header chains without executing transactions, for clients that only need
+// header validity and merkle proofs against verified headers.
+//
+// Starting from a trusted checkpoint header and the proposer set at it, each header is checked
+// for linkage, timestamp, gas limit, signer schedule, backers and total score. The active flags of proposers
+// are tracked along headers, as the full node does. Changes of the proposer set or block interval
+// made by transactions are not observable from headers, and should be fed by SetProposers and SetTiming,
+// e.g. with values proven by account proofs.
+package light
+
+import (
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// number of verified headers kept for proof verification
+const recentLimit = 1024
+
+var errFutureHeader = errors.New("header in the future")
+
+// IsFutureHeader returns if the error indicates that the header should be verified later.
+func IsFutureHeader(err error) bool {
+	return err == errFutureHeader
+}
+
+// Verifier verifies headers extending the head one by one.
+type Verifier struct {
+	forkConfig thor.ForkConfig
+	lock       sync.Mutex
+	head       *block.Header
+	proposers  []poa.Proposer
+	timing     poa.Timing
+	recent     *lru.Cache
+}
+
+// New creates a verifier starting from the trusted checkpoint, with the proposer set at it.
+func New(forkConfig thor.ForkConfig, checkpoint *block.Header, proposers []poa.Proposer) *Verifier {
+	recent, _ := lru.New(recentLimit)
+	recent.Add(checkpoint.ID(), checkpoint)
+	return &Verifier{
+		forkConfig: forkConfig,
+		head:       checkpoint,
+		proposers:  append([]poa.Proposer(nil), proposers...),
+		timing:     poa.DefaultTiming,
+		recent:     recent,
+	}
+}
+
+// Head returns the latest verified header.
+func (v *Verifier) Head() *block.Header {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.head
+}
+
+// Proposers returns the proposer set at the head.
+func (v *Verifier) Proposers() []poa.Proposer {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return append([]poa.Proposer(nil), v.proposers...)
+}
+
+// SetProposers replaces the proposer set at the head, when it's changed by transactions.
+func (v *Verifier) SetProposers(proposers []poa.Proposer) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.proposers = append([]poa.Proposer(nil), proposers...)
+}
+
+// SetTiming replaces the timing for children of the head, when the block interval is changed by params.
+func (v *Verifier) SetTiming(timing poa.Timing) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.timing = timing
+}
+
+// Verify verifies the header as the child of the head, and makes it the new head if valid.
+func (v *Verifier) Verify(header *block.Header, nowTimestamp uint64) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	parent := v.head
+	if header.ParentID() != parent.ID() {
+		return fmt.Errorf("header not linked: parent %v, want %v", header.ParentID(), parent.ID())
+	}
+	if err := v.verifyHeader(header, parent, nowTimestamp); err != nil {
+		return err
+	}
+	updates, err := v.verifyProposer(header, parent)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range updates {
+		for i := range v.proposers {
+			if v.proposers[i].Address == u.Address {
+				v.proposers[i].Active = u.Active
+			}
+		}
+	}
+	v.head = header
+	v.recent.Add(header.ID(), header)
+	return nil
+}
+
+// VerifyChain verifies headers in order.
+func (v *Verifier) VerifyChain(headers []*block.Header, nowTimestamp uint64) error {
+	for _, header := range headers {
+		if err := v.Verify(header, nowTimestamp); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("header %v", header.ID()))
+		}
+	}
+	return nil
+}
+
+// VerifyAccount verifies the account proof against the state root of a recently verified header.
+func (v *Verifier) VerifyAccount(blockID thor.Bytes32, addr thor.Address, proof [][]byte) (*state.Account, error) {
+	header, ok := v.recent.Get(blockID)
+	if !ok {
+		return nil, fmt.Errorf("header %v not verified", blockID)
+	}
+	return state.VerifyAccountProof(header.(*block.Header).StateRoot(), addr, proof)
+}
+
+func (v *Verifier) verifyHeader(header *block.Header, parent *block.Header, nowTimestamp uint64) error {
+	if header.Timestamp() <= parent.Timestamp() {
+		return fmt.Errorf("header timestamp behind parents: parent %v, current %v", parent.Timestamp(), header.Timestamp())
+	}
+	if _, ok := v.timing.SlotOf(parent.Timestamp(), header.Timestamp()); !ok {
+		return fmt.Errorf("header interval not rounded: parent %v, current %v", parent.Timestamp(), header.Timestamp())
+	}
+	if v.timing.IsFutureTime(header.Timestamp(), nowTimestamp) {
+		return errFutureHeader
+	}
+	// the floor is governed by params, which is not observable from headers
+	if !block.GasLimit(header.GasLimit()).IsValidAbove(parent.GasLimit(), 0) {
+		return fmt.Errorf("header gas limit invalid: parent %v, current %v", parent.GasLimit(), header.GasLimit())
+	}
+	if header.GasUsed() > header.GasLimit() {
+		return fmt.Errorf("header gas used exceeds limit: limit %v, used %v", header.GasLimit(), header.GasUsed())
+	}
+	if len(header.Extension()) > 0 && header.Number() < v.forkConfig.HeaderExtension {
+		return fmt.Errorf("header extension not allowed before fork: fork %v, current %v", v.forkConfig.HeaderExtension, header.Number())
+	}
+	return nil
+}
+
+func (v *Verifier) verifyProposer(header *block.Header, parent *block.Header) ([]poa.Proposer, error) {
+	signer, err := header.Signer()
+	if err != nil {
+		return nil, fmt.Errorf("header signer unavailable: %v", err)
+	}
+	sched, err := poa.NewSchedulerWithTiming(v.timing, signer, v.proposers, parent.Number(), parent.Timestamp())
+	if err != nil {
+		return nil, fmt.Errorf("header signer invalid: %v %v", signer, err)
+	}
+	if !sched.IsTheTime(header.Timestamp()) {
+		return nil, fmt.Errorf("header timestamp unscheduled: t %v, s %v", header.Timestamp(), signer)
+	}
+	updates, score := sched.Updates(header.Timestamp())
+	if header.Number() >= v.forkConfig.VIP193 {
+		backers, err := header.Backers()
+		if err != nil {
+			return nil, fmt.Errorf("header backers unavailable: %v", err)
+		}
+		committee := poa.Committee(header.ParentID(), v.proposers, signer, thor.CommitteeSize)
+		if err := poa.VerifyBackers(committee, backers); err != nil {
+			return nil, fmt.Errorf("header backers invalid: %v", err)
+		}
+		score += uint64(len(backers))
+	}
+	if parent.TotalScore()+score != header.TotalScore() {
+		return nil, fmt.Errorf("header total score invalid: want %v, have %v", parent.TotalScore()+score, header.TotalScore())
+	}
+	return updates, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package light
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/thor"
+)
+
+// newChild builds the child header by the proposer scheduled earliest.
+func newChild(t *testing.T, parent *block.Header, proposers []poa.Proposer) *block.Header {
+	var (
+		best  genesis.DevAccount
+		when  uint64 = math.MaxUint64
+		score uint64
+	)
+	for _, acc := range genesis.DevAccounts() {
+		sched, err := poa.NewScheduler(acc.Address, proposers, parent.Number(), parent.Timestamp())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts := sched.Schedule(parent.Timestamp() + thor.BlockInterval); ts < when {
+			when = ts
+			best = acc
+			_, score = sched.Updates(ts)
+		}
+	}
+	b := new(block.Builder).
+		ParentID(parent.ID()).
+		Timestamp(when).
+		TotalScore(parent.TotalScore() + score).
+		GasLimit(parent.GasLimit()).
+		Build()
+	sig, err := crypto.Sign(b.Header().SigningHash().Bytes(), best.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.WithSignature(sig).Header()
+}
+
+func TestVerifier(t *testing.T) {
+	checkpoint := new(block.Builder).
+		ParentID(thor.Bytes32{0xff, 0xff, 0xff, 0xff}).
+		Timestamp(1526400000).
+		GasLimit(thor.InitialGasLimit).
+		Build().Header()
+
+	var proposers []poa.Proposer
+	for _, acc := range genesis.DevAccounts() {
+		proposers = append(proposers, poa.Proposer{Address: acc.Address, Active: true})
+	}
+
+	v := New(thor.NoFork, checkpoint, proposers)
+
+	var headers []*block.Header
+	parent := checkpoint
+	for i := 0; i < 5; i++ {
+		h := newChild(t, parent, proposers)
+		headers = append(headers, h)
+		parent = h
+	}
+	now := parent.Timestamp()
+
+	assert.Nil(t, v.Verify(headers[0], now))
+	assert.Equal(t, headers[0].ID(), v.Head().ID())
+
+	// not linked
+	assert.NotNil(t, v.Verify(headers[2], now))
+
+	// future
+	assert.True(t, IsFutureHeader(v.Verify(headers[1], headers[0].Timestamp()-thor.BlockInterval)))
+
+	assert.Nil(t, v.VerifyChain(headers[1:], now))
+	assert.Equal(t, parent.ID(), v.Head().ID())
+
+	// forged total score
+	b := new(block.Builder).
+		ParentID(parent.ID()).
+		Timestamp(parent.Timestamp() + thor.BlockInterval).
+		TotalScore(parent.TotalScore() + 100).
+		GasLimit(parent.GasLimit()).
+		Build()
+	sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	assert.NotNil(t, v.Verify(b.WithSignature(sig).Header(), math.MaxUint64))
+
+	// unauthorized signer
+	key, _ := crypto.GenerateKey()
+	sig, _ = crypto.Sign(b.Header().SigningHash().Bytes(), key)
+	assert.NotNil(t, v.Verify(b.WithSignature(sig).Header(), math.MaxUint64))
+
+	assert.Equal(t, parent.ID(), v.Head().ID())
+
+	_, err := v.VerifyAccount(thor.Bytes32{}, thor.Address{}, nil)
+	assert.NotNil(t, err, "unknown header")
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package liveness tracks blocks signed and slots missed by authority nodes along the trunk,
+// to monitor health of authority nodes.
+package liveness
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+var recordPrefix = []byte("liveness-") // (prefix, address) -> record
+
+var (
+	signedCounter = metrics.NewRegisteredCounter("liveness/signed", nil)
+	missedCounter = metrics.NewRegisteredCounter("liveness/missed", nil)
+)
+
+// Record cumulative liveness of an authority node.
+type Record struct {
+	Signed uint64 // blocks signed
+	Missed uint64 // slots missed
+}
+
+// Uptime returns ratio of slots taken to all slots scheduled.
+func (r *Record) Uptime() float64 {
+	if total := r.Signed + r.Missed; total > 0 {
+		return float64(r.Signed) / float64(total)
+	}
+	return 0
+}
+
+// Tracker accumulates records with trunk changes.
+type Tracker struct {
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	forkConfig   thor.ForkConfig
+	kv           kv.GetPutter
+}
+
+// New creates a tracker saving records into kv.
+func New(chain *chain.Chain, stateCreator *state.Creator, kv kv.GetPutter) *Tracker {
+	return &Tracker{
+		chain:        chain,
+		stateCreator: stateCreator,
+		forkConfig:   thor.GetForkConfig(chain.GenesisBlock().Header().ID()),
+		kv:           kv,
+	}
+}
+
+type delta struct {
+	signed int64
+	missed int64
+}
+
+// Apply updates records with the fork returned by adding a block. Blocks dropped from the trunk
+// are reverted, and blocks adopted are counted. Forks of side blocks are ignored.
+// It should be called in the order of blocks added.
+func (t *Tracker) Apply(fork *chain.Fork) error {
+	if len(fork.Trunk) == 0 {
+		return nil
+	}
+	deltas := make(map[thor.Address]*delta)
+	get := func(addr thor.Address) *delta {
+		d, ok := deltas[addr]
+		if !ok {
+			d = &delta{}
+			deltas[addr] = d
+		}
+		return d
+	}
+	count := func(headers []*block.Header, sign int64) error {
+		for _, header := range headers {
+			signer, missed, err := t.observe(header)
+			if err != nil {
+				return err
+			}
+			get(signer).signed += sign
+			for _, addr := range missed {
+				get(addr).missed += sign
+			}
+			signedCounter.Inc(sign)
+			missedCounter.Inc(sign * int64(len(missed)))
+		}
+		return nil
+	}
+	if err := count(fork.Branch, -1); err != nil {
+		return err
+	}
+	if err := count(fork.Trunk, 1); err != nil {
+		return err
+	}
+
+	batch := t.kv.NewBatch()
+	for addr, d := range deltas {
+		r, err := loadRecord(t.kv, addr)
+		if err != nil {
+			return err
+		}
+		r.Signed = uint64(int64(r.Signed) + d.signed)
+		r.Missed = uint64(int64(r.Missed) + d.missed)
+		data, err := rlp.EncodeToBytes(r)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(recordKey(addr), data); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// observe returns the signer of the block, and proposers who missed their slots before it,
+// by replaying the schedule on the parent state.
+func (t *Tracker) observe(header *block.Header) (thor.Address, []thor.Address, error) {
+	signer, err := header.Signer()
+	if err != nil {
+		return thor.Address{}, nil, err
+	}
+	parent, err := t.chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		return thor.Address{}, nil, err
+	}
+	st, err := t.stateCreator.NewState(parent.StateRoot())
+	if err != nil {
+		return thor.Address{}, nil, err
+	}
+	_, missed, err := poa.ReplaySchedule(t.forkConfig, st, parent, header)
+	if err != nil {
+		return thor.Address{}, nil, err
+	}
+	return signer, missed, nil
+}
+
+func recordKey(addr thor.Address) []byte {
+	return append(append([]byte(nil), recordPrefix...), addr[:]...)
+}
+
+func loadRecord(r kv.Getter, addr thor.Address) (*Record, error) {
+	data, err := r.Get(recordKey(addr))
+	if err != nil {
+		if r.IsNotFound(err) {
+			return &Record{}, nil
+		}
+		return nil, err
+	}
+	var rec Record
+	if err := rlp.DecodeBytes(data, &rec); err != nil {
+		return nil, errors.WithMessage(err, "decode record")
+	}
+	return &rec, nil
+}
+
+// Load loads records of all authority nodes ever tracked, keyed by node master address.
+func Load(r kv.Getter) (map[thor.Address]*Record, error) {
+	it := r.NewIterator(*kv.NewRangeWithBytesPrefix(recordPrefix))
+	defer it.Release()
+
+	records := make(map[thor.Address]*Record)
+	for it.Next() {
+		// skip keys of other kinds sharing the prefix
+		if len(it.Key()) != len(recordPrefix)+len(thor.Address{}) {
+			continue
+		}
+		var rec Record
+		if err := rlp.DecodeBytes(it.Value(), &rec); err != nil {
+			return nil, errors.WithMessage(err, "decode record")
+		}
+		records[thor.BytesToAddress(it.Key()[len(recordPrefix):])] = &rec
+	}
+	return records, it.Error()
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package liveness_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/liveness"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+func TestTracker(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	launchTime := uint64(1526400000)
+	gen := new(genesis.Builder).
+		GasLimit(thor.InitialGasLimit).
+		Timestamp(launchTime).
+		State(func(state *state.State) error {
+			state.SetCode(builtin.Authority.Address, builtin.Authority.RuntimeBytecodes())
+			for _, acc := range genesis.DevAccounts() {
+				builtin.Authority.Native(state).Add(acc.Address, acc.Address, thor.Bytes32{})
+			}
+			return nil
+		})
+	stateCreator := state.NewCreator(db)
+	b0, _, err := gen.Build(stateCreator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := chain.New(db, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := liveness.New(c, stateCreator, db)
+
+	// packs blocks by the proposer taking the earliest slot and the latest one among all
+	var first, last *block.Block
+	for _, acc := range genesis.DevAccounts() {
+		flow, err := packer.New(c, stateCreator, acc.Address, &acc.Address).Schedule(b0.Header(), launchTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blk, stage, _, err := flow.Pack(acc.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stage.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if first == nil || blk.Header().Timestamp() < first.Header().Timestamp() {
+			first = blk
+		}
+		if last == nil || blk.Header().Timestamp() > last.Header().Timestamp() {
+			last = blk
+		}
+	}
+
+	add := func(blk *block.Block) {
+		fork, err := c.AddBlock(blk, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, tracker.Apply(fork))
+	}
+	signerOf := func(blk *block.Block) thor.Address {
+		signer, _ := blk.Header().Signer()
+		return signer
+	}
+
+	add(last)
+	records, err := liveness.Load(db)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), records[signerOf(last)].Signed)
+	assert.Equal(t, 1.0, records[signerOf(last)].Uptime())
+	var missed uint64
+	for _, r := range records {
+		missed += r.Missed
+	}
+	assert.True(t, missed > 0, "slots before the latest one missed")
+
+	// the earliest one has higher score, and takes over the trunk
+	add(first)
+	records, err = liveness.Load(db)
+	assert.Nil(t, err)
+	for addr, r := range records {
+		assert.Equal(t, uint64(0), r.Missed)
+		if addr == signerOf(first) {
+			assert.Equal(t, uint64(1), r.Signed)
+		} else {
+			assert.Equal(t, uint64(0), r.Signed)
+		}
+	}
+}
+
+func TestRecordUptime(t *testing.T) {
+	assert.Equal(t, 0.0, (&liveness.Record{}).Uptime())
+	assert.Equal(t, 0.75, (&liveness.Record{Signed: 3, Missed: 1}).Uptime())
+}
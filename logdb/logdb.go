@@ -60,6 +60,36 @@ func (db *LogDB) Path() string {
 	return db.path
 }
 
+// Vacuum rebuilds the database file to reclaim free pages.
+func (db *LogDB) Vacuum(ctx context.Context) error {
+	_, err := db.db.ExecContext(ctx, "VACUUM;")
+	return err
+}
+
+// BlockIDsSince returns IDs of blocks which have logs, with number not less than num.
+func (db *LogDB) BlockIDsSince(ctx context.Context, num uint32) ([]thor.Bytes32, error) {
+	rows, err := db.db.QueryContext(ctx, "SELECT blockID FROM event WHERE blockNumber >= ? UNION SELECT blockID FROM transfer WHERE blockNumber >= ?;", num, num)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []thor.Bytes32
+	for rows.Next() {
+		var id []byte
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, thor.BytesToBytes32(id))
+	}
+	return ids, rows.Err()
+}
+
+// DeleteBlocks deletes logs of the given blocks.
+func (db *LogDB) DeleteBlocks(ids ...thor.Bytes32) error {
+	return (&BlockBatch{db: db.db}).Commit(ids...)
+}
+
 func (db *LogDB) Prepare(header *block.Header) *BlockBatch {
 	return &BlockBatch{
 		db:     db.db,
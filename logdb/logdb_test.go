@@ -183,3 +183,34 @@ func BenchmarkLog(b *testing.B) {
 		}
 	}
 }
+
+func TestDeleteBlocks(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var ids []thor.Bytes32
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 3; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		ids = append(ids, header.ID())
+		if err := db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).
+			Insert(tx.Events{{Address: thor.BytesToAddress([]byte("addr"))}}, tx.Transfers{{Amount: big.NewInt(1)}}).
+			Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := db.BlockIDsSince(context.Background(), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(got))
+
+	assert.Nil(t, db.DeleteBlocks(ids[2]))
+	got, err = db.BlockIDsSince(context.Background(), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []thor.Bytes32{ids[1]}, got)
+
+	assert.Nil(t, db.Vacuum(context.Background()))
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package loglevel provides log level filtering adjustable at runtime.
+package loglevel
+
+import (
+	"sync"
+
+	"github.com/inconshreveable/log15"
+)
+
+// moduleKey is the context key of loggers naming their module, e.g. log15.New("pkg", "txpool").
+const moduleKey = "pkg"
+
+// Handler filters log records by the global level, which can be overridden per module.
+type Handler struct {
+	next    log15.Handler
+	lock    sync.RWMutex
+	global  log15.Lvl
+	modules map[string]log15.Lvl
+}
+
+// Levels snapshot of log levels.
+type Levels struct {
+	Global  string            `json:"global"`
+	Modules map[string]string `json:"modules"`
+}
+
+// NewHandler creates a handler passing records to next.
+func NewHandler(next log15.Handler, global log15.Lvl) *Handler {
+	return &Handler{
+		next:    next,
+		global:  global,
+		modules: make(map[string]log15.Lvl),
+	}
+}
+
+// Log implements log15.Handler.
+func (h *Handler) Log(r *log15.Record) error {
+	if r.Lvl > h.levelOf(r.Ctx) {
+		return nil
+	}
+	return h.next.Log(r)
+}
+
+func (h *Handler) levelOf(ctx []interface{}) log15.Lvl {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if len(h.modules) > 0 {
+		for i := 0; i+1 < len(ctx); i += 2 {
+			if key, ok := ctx[i].(string); ok && key == moduleKey {
+				if module, ok := ctx[i+1].(string); ok {
+					if lvl, ok := h.modules[module]; ok {
+						return lvl
+					}
+				}
+				break
+			}
+		}
+	}
+	return h.global
+}
+
+// SetGlobal sets the global level.
+func (h *Handler) SetGlobal(lvl log15.Lvl) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.global = lvl
+}
+
+// SetModule overrides the level of the module.
+func (h *Handler) SetModule(module string, lvl log15.Lvl) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.modules[module] = lvl
+}
+
+// ResetModule removes the override of the module, to follow the global level.
+func (h *Handler) ResetModule(module string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.modules, module)
+}
+
+// Levels returns current levels.
+func (h *Handler) Levels() Levels {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	levels := Levels{
+		Global:  h.global.String(),
+		Modules: make(map[string]string),
+	}
+	for module, lvl := range h.modules {
+		levels.Modules[module] = lvl.String()
+	}
+	return levels
+}
+
+// Apply replaces all levels with the given ones.
+// Modules absent from levels follow the global level afterwards.
+func (h *Handler) Apply(levels Levels) error {
+	global, err := log15.LvlFromString(levels.Global)
+	if err != nil {
+		return err
+	}
+	modules := make(map[string]log15.Lvl)
+	for module, str := range levels.Modules {
+		lvl, err := log15.LvlFromString(str)
+		if err != nil {
+			return err
+		}
+		modules[module] = lvl
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.global = global
+	h.modules = modules
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package loglevel_test
+
+import (
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/loglevel"
+)
+
+func TestHandler(t *testing.T) {
+	var records []*log15.Record
+	h := loglevel.NewHandler(log15.FuncHandler(func(r *log15.Record) error {
+		records = append(records, r)
+		return nil
+	}), log15.LvlInfo)
+
+	logger := log15.New()
+	logger.SetHandler(h)
+	txpool := logger.New("pkg", "txpool")
+
+	txpool.Debug("dropped")
+	logger.Info("kept")
+	assert.Equal(t, 1, len(records))
+
+	h.SetModule("txpool", log15.LvlDebug)
+	txpool.Debug("kept")
+	logger.Debug("dropped")
+	assert.Equal(t, 2, len(records))
+
+	assert.Equal(t, loglevel.Levels{Global: "info", Modules: map[string]string{"txpool": "dbug"}}, h.Levels())
+
+	assert.NotNil(t, h.Apply(loglevel.Levels{Global: "bad"}))
+	assert.Nil(t, h.Apply(loglevel.Levels{Global: "error"}))
+	txpool.Info("dropped")
+	logger.Error("kept")
+	assert.Equal(t, 3, len(records))
+}
@@ -28,7 +28,8 @@ var readOpt = opt.ReadOptions{}
 
 // LevelDB wraps level db impls.
 type LevelDB struct {
-	db *leveldb.DB
+	db        *leveldb.DB
+	recovered bool
 }
 
 // New create a persistent level db instance.
@@ -42,22 +43,32 @@ func New(path string, opts Options) (*LevelDB, error) {
 		opts.OpenFilesCacheCapacity = 16
 	}
 
-	db, err := leveldb.OpenFile(path, &opt.Options{
+	o := &opt.Options{
 		CompactionTableSize:    64 * opt.MiB,
 		OpenFilesCacheCapacity: opts.OpenFilesCacheCapacity,
 		BlockCacheCapacity:     opts.CacheSize / 2 * opt.MiB,
 		WriteBuffer:            opts.CacheSize / 4 * opt.MiB, // Two of these are used internally
 		Filter:                 filter.NewBloomFilter(10),
-	})
+	}
+	db, err := leveldb.OpenFile(path, o)
 
-	if _, corrupted := err.(*dberrors.ErrCorrupted); corrupted {
-		db, err = leveldb.RecoverFile(path, nil)
+	var recovered bool
+	if dberrors.IsCorrupted(err) {
+		// rebuild the manifest from table files, which may lose recent writes
+		db, err = leveldb.RecoverFile(path, o)
+		recovered = true
 	}
 
 	if err != nil {
 		return nil, err
 	}
-	return &LevelDB{db: db}, nil
+	return &LevelDB{db: db, recovered: recovered}, nil
+}
+
+// Recovered returns whether the db was recovered from corruption when opened.
+// Data consistency should be checked by the caller in that case.
+func (ldb *LevelDB) Recovered() bool {
+	return ldb.recovered
 }
 
 // NewMem create a level db in memory.
@@ -95,6 +106,33 @@ func (ldb *LevelDB) Delete(key []byte) error {
 	return ldb.db.Delete(key, &writeOpt)
 }
 
+// Compact compacts the whole key space, to reclaim space of deleted entries.
+func (ldb *LevelDB) Compact() error {
+	return ldb.db.CompactRange(util.Range{})
+}
+
+// CompactWithProgress compacts the whole key space in 256 steps, split by the leading key byte,
+// and calls progress after each step. It's as effective as Compact, but reports progress.
+func (ldb *LevelDB) CompactWithProgress(progress func(done, total int)) error {
+	const total = 256
+	for i := 0; i < total; i++ {
+		var r util.Range
+		if i > 0 {
+			r.Start = []byte{byte(i)}
+		}
+		if i < total-1 {
+			r.Limit = []byte{byte(i + 1)}
+		}
+		if err := ldb.db.CompactRange(r); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(i+1, total)
+		}
+	}
+	return nil
+}
+
 // Close close the level db.
 // Later operations will all fail.
 func (ldb *LevelDB) Close() error {
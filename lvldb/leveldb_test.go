@@ -108,3 +108,22 @@ func TestLevelDBBach(t *testing.T) {
 		assert.Equal(t, tt.expected, tt.ret)
 	}
 }
+
+func TestCompactWithProgress(t *testing.T) {
+	db, err := NewMem()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	for i := 0; i < 256; i++ {
+		assert.Nil(t, db.Put([]byte{byte(i), 1}, []byte{1}))
+		assert.Nil(t, db.Delete([]byte{byte(i), 1}))
+	}
+	var steps, last int
+	assert.Nil(t, db.CompactWithProgress(func(done, total int) {
+		steps++
+		last = done
+		assert.Equal(t, 256, total)
+	}))
+	assert.Equal(t, 256, steps)
+	assert.Equal(t, 256, last)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/poa"
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
@@ -26,18 +27,21 @@ type Flow struct {
 	gasUsed      uint64
 	txs          tx.Transactions
 	receipts     tx.Receipts
+	committee    []thor.Address // nil before VIP193
 }
 
 func newFlow(
 	packer *Packer,
 	parentHeader *block.Header,
 	runtime *runtime.Runtime,
+	committee []thor.Address,
 ) *Flow {
 	return &Flow{
 		packer:       packer,
 		parentHeader: parentHeader,
 		runtime:      runtime,
 		processedTxs: make(map[thor.Bytes32]bool),
+		committee:    committee,
 	}
 }
 
@@ -122,20 +126,80 @@ func (f *Flow) Adopt(tx *tx.Transaction) error {
 	return nil
 }
 
+// Committee returns members of the committee to back the new block. It's empty before VIP193.
+func (f *Flow) Committee() []thor.Address {
+	return append([]thor.Address(nil), f.committee...)
+}
+
+// Proposal builds and signs the new block without backers, to be sent to committee members.
+// It's a valid block on its own, so members can verify it before backing, which is signing
+// its proposal hash.
+func (f *Flow) Proposal(privateKey *ecdsa.PrivateKey) (*block.Block, error) {
+	if f.packer.nodeMaster != thor.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
+		return nil, errors.New("private key mismatch")
+	}
+
+	builder, _, err := f.build(nil)
+	if err != nil {
+		return nil, err
+	}
+	proposal := builder.Build()
+
+	sig, err := crypto.Sign(proposal.Header().SigningHash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return proposal.WithSignature(sig), nil
+}
+
 // Pack build and sign the new block.
 func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, tx.Receipts, error) {
+	return f.PackWithBackers(privateKey, nil)
+}
+
+// PackWithBackers build and sign the new block, carrying signatures of backers on the proposal.
+// Each backer adds to the total score of the new block.
+func (f *Flow) PackWithBackers(privateKey *ecdsa.PrivateKey, backerSigs [][]byte) (*block.Block, *state.Stage, tx.Receipts, error) {
 	if f.packer.nodeMaster != thor.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
 		return nil, nil, nil, errors.New("private key mismatch")
 	}
 
-	if err := f.runtime.Seeker().Err(); err != nil {
+	builder, stage, err := f.build(backerSigs)
+	if err != nil {
 		return nil, nil, nil, err
 	}
+	newBlock := builder.Build()
+
+	if len(backerSigs) > 0 {
+		backers, err := newBlock.Header().Backers()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := poa.VerifyBackers(f.committee, backers); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	sig, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return newBlock.WithSignature(sig), stage, f.receipts, nil
+}
+
+func (f *Flow) build(backerSigs [][]byte) (*block.Builder, *state.Stage, error) {
+	if len(backerSigs) > 0 && f.committee == nil {
+		return nil, nil, errors.New("backers not allowed before VIP193")
+	}
+
+	if err := f.runtime.Seeker().Err(); err != nil {
+		return nil, nil, err
+	}
 
 	stage := f.runtime.State().Stage()
 	stateRoot, err := stage.Hash()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	builder := new(block.Builder).
@@ -143,18 +207,15 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, t
 		GasLimit(f.runtime.Context().GasLimit).
 		ParentID(f.parentHeader.ID()).
 		Timestamp(f.runtime.Context().Time).
-		TotalScore(f.runtime.Context().TotalScore).
-		GasUsed(f.gasUsed).
-		ReceiptsRoot(f.receipts.RootHash()).
-		StateRoot(stateRoot)
+		TotalScore(f.runtime.Context().TotalScore + uint64(len(backerSigs))).
+		Receipts(f.receipts).
+		StateRoot(stateRoot).
+		BackerSignatures(backerSigs)
 	for _, tx := range f.txs {
 		builder.Transaction(tx)
 	}
-	newBlock := builder.Build()
-
-	sig, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
-	if err != nil {
-		return nil, nil, nil, err
+	if err := builder.Finalize(); err != nil {
+		return nil, nil, err
 	}
-	return newBlock.WithSignature(sig), stage, f.receipts, nil
+	return builder, stage, nil
 }
@@ -24,6 +24,7 @@ type Packer struct {
 	nodeMaster     thor.Address
 	beneficiary    *thor.Address
 	targetGasLimit uint64
+	forkConfig     thor.ForkConfig
 }
 
 // New create a new Packer instance.
@@ -40,6 +41,7 @@ func New(
 		nodeMaster,
 		beneficiary,
 		0,
+		thor.GetForkConfig(chain.GenesisBlock().Header().ID()),
 	}
 }
 
@@ -73,7 +75,9 @@ func (p *Packer) Schedule(parent *block.Header, nowTimestamp uint64) (flow *Flow
 	}
 
 	// calc the time when it's turn to produce block
-	sched, err := poa.NewScheduler(p.nodeMaster, proposers, parent.Number(), parent.Timestamp())
+	getParam := builtin.Params.Native(state).Get
+	timing := poa.TimingAt(p.forkConfig, parent.Number(), getParam)
+	sched, err := poa.NewSchedulerWithTiming(timing, p.nodeMaster, proposers, parent.Number(), parent.Timestamp())
 	if err != nil {
 		return nil, err
 	}
@@ -93,11 +97,16 @@ func (p *Packer) Schedule(parent *block.Header, nowTimestamp uint64) (flow *Flow
 			Signer:      p.nodeMaster,
 			Number:      parent.Number() + 1,
 			Time:        newBlockTime,
-			GasLimit:    p.gasLimit(parent.GasLimit()),
+			GasLimit:    p.gasLimit(parent.GasLimit(), block.MinGasLimitAt(p.forkConfig, parent.Number(), getParam)),
 			TotalScore:  parent.TotalScore() + score,
 		})
 
-	return newFlow(p, parent, rt), nil
+	var committee []thor.Address
+	if parent.Number()+1 >= p.forkConfig.VIP193 {
+		committee = poa.Committee(parent.ID(), proposers, p.nodeMaster, thor.CommitteeSize)
+	}
+
+	return newFlow(p, parent, rt, committee), nil
 }
 
 // Mock create a packing flow upon given parent, but with a designated timestamp.
@@ -121,12 +130,20 @@ func (p *Packer) Mock(parent *block.Header, targetTime uint64, gasLimit uint64)
 			TotalScore:  parent.TotalScore() + 1,
 		})
 
-	return newFlow(p, parent, rt), nil
+	return newFlow(p, parent, rt, nil), nil
 }
 
-func (p *Packer) gasLimit(parentGasLimit uint64) uint64 {
-	if p.targetGasLimit != 0 {
-		return block.GasLimit(p.targetGasLimit).Qualify(parentGasLimit)
+func (p *Packer) gasLimit(parentGasLimit uint64, minGasLimit uint64) uint64 {
+	target := p.targetGasLimit
+	if parentGasLimit < minGasLimit {
+		// the floor was raised above the parent, approach it
+		if target < minGasLimit {
+			target = minGasLimit
+		}
+		minGasLimit = parentGasLimit
+	}
+	if target != 0 {
+		return block.GasLimit(target).QualifyAbove(parentGasLimit, minGasLimit)
 	}
 	return parentGasLimit
 }
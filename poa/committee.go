@@ -0,0 +1,63 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/vechain/thor/thor"
+)
+
+// Committee selects the committee of backers for the block proposed upon the parent, among active
+// proposers except the block proposer. Members are ranked by H(parentID, address), so the selection
+// is random but deterministic for the parent.
+func Committee(parentID thor.Bytes32, proposers []Proposer, blockProposer thor.Address, size int) []thor.Address {
+	type ranked struct {
+		addr thor.Address
+		rank thor.Bytes32
+	}
+	var candidates []ranked
+	for _, p := range proposers {
+		if p.Active && p.Address != blockProposer {
+			candidates = append(candidates, ranked{p.Address, thor.Blake2b(parentID[:], p.Address[:])})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return bytes.Compare(candidates[i].rank[:], candidates[j].rank[:]) < 0
+	})
+	if len(candidates) > size {
+		candidates = candidates[:size]
+	}
+	members := make([]thor.Address, 0, len(candidates))
+	for _, c := range candidates {
+		members = append(members, c.addr)
+	}
+	return members
+}
+
+// VerifyBackers checks that backers are distinct members of the committee.
+func VerifyBackers(committee []thor.Address, backers []thor.Address) error {
+	if len(backers) == 0 {
+		return nil
+	}
+	members := make(map[thor.Address]bool, len(committee))
+	for _, m := range committee {
+		members[m] = true
+	}
+	seen := make(map[thor.Address]bool, len(backers))
+	for _, b := range backers {
+		if !members[b] {
+			return errors.New("backer not in committee: " + b.String())
+		}
+		if seen[b] {
+			return errors.New("duplicated backer: " + b.String())
+		}
+		seen[b] = true
+	}
+	return nil
+}
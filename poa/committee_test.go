@@ -0,0 +1,34 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/thor"
+)
+
+func TestCommittee(t *testing.T) {
+	all := []poa.Proposer{{p1, true}, {p2, true}, {p3, false}, {p4, true}, {p5, true}}
+	parentID := thor.BytesToBytes32([]byte("parent"))
+
+	members := poa.Committee(parentID, all, p1, 10)
+	assert.ElementsMatch(t, []thor.Address{p2, p4, p5}, members, "inactive and the proposer excluded")
+
+	assert.Equal(t, members[:2], poa.Committee(parentID, all, p1, 2), "deterministic")
+	assert.Equal(t, 0, len(poa.Committee(parentID, all, p1, 0)))
+}
+
+func TestVerifyBackers(t *testing.T) {
+	committee := []thor.Address{p2, p4}
+
+	assert.Nil(t, poa.VerifyBackers(committee, nil))
+	assert.Nil(t, poa.VerifyBackers(committee, []thor.Address{p4, p2}))
+	assert.NotNil(t, poa.VerifyBackers(committee, []thor.Address{p2, p2}), "duplicated")
+	assert.NotNil(t, poa.VerifyBackers(committee, []thor.Address{p3}), "not in committee")
+}
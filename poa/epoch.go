@@ -0,0 +1,84 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"encoding/binary"
+
+	"github.com/vechain/thor/thor"
+)
+
+// Epoch is a fixed length span of consecutive blocks.
+// The genesis block belongs to epoch 0, and epoch n covers block numbers
+// [n*length, (n+1)*length).
+type Epoch struct {
+	number uint32
+	length uint32
+}
+
+// EpochOf returns the epoch the block number belongs to, with default epoch length.
+func EpochOf(blockNumber uint32) Epoch {
+	return EpochOfLength(blockNumber, thor.EpochLength)
+}
+
+// EpochOfLength returns the epoch the block number belongs to, with given epoch length.
+func EpochOfLength(blockNumber uint32, length uint32) Epoch {
+	if length == 0 {
+		panic("zero epoch length")
+	}
+	return Epoch{blockNumber / length, length}
+}
+
+// Number returns the sequence number of the epoch.
+func (e Epoch) Number() uint32 { return e.number }
+
+// Length returns count of blocks in the epoch.
+func (e Epoch) Length() uint32 { return e.length }
+
+// Start returns the number of the first block in the epoch.
+func (e Epoch) Start() uint32 { return e.number * e.length }
+
+// End returns the number of the last block in the epoch.
+func (e Epoch) End() uint32 { return e.Start() + e.length - 1 }
+
+// Contains returns whether the block number is in the epoch.
+func (e Epoch) Contains(blockNumber uint32) bool {
+	return blockNumber >= e.Start() && blockNumber <= e.End()
+}
+
+// IsBoundary returns whether the block number is the first block of an epoch.
+func (e Epoch) IsBoundary(blockNumber uint32) bool {
+	return blockNumber%e.length == 0
+}
+
+// Next returns the next epoch.
+func (e Epoch) Next() Epoch { return Epoch{e.number + 1, e.length} }
+
+// Prev returns the previous epoch. The previous epoch of epoch 0 is itself.
+func (e Epoch) Prev() Epoch {
+	if e.number == 0 {
+		return e
+	}
+	return Epoch{e.number - 1, e.length}
+}
+
+// SeedBlockNumber returns the number of block whose ID seeds the epoch.
+// It's the last block of the previous epoch, or the genesis block for epoch 0,
+// so the seed is settled before the epoch starts.
+func (e Epoch) SeedBlockNumber() uint32 {
+	if e.number == 0 {
+		return 0
+	}
+	return e.Start() - 1
+}
+
+// Seed derives the epoch seed from the ID of the seed block.
+// H(seedBlockID, epochNumber)
+func (e Epoch) Seed(seedBlockID thor.Bytes32) thor.Bytes32 {
+	var b4 [4]byte
+	binary.BigEndian.PutUint32(b4[:], e.number)
+	return thor.Blake2b(seedBlockID[:], b4[:])
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/thor"
+)
+
+func TestEpoch(t *testing.T) {
+	e := poa.EpochOfLength(0, 10)
+	assert.Equal(t, uint32(0), e.Number())
+	assert.Equal(t, uint32(0), e.Start())
+	assert.Equal(t, uint32(9), e.End())
+	assert.Equal(t, uint32(0), e.SeedBlockNumber())
+	assert.Equal(t, e, e.Prev())
+
+	e = poa.EpochOfLength(25, 10)
+	assert.Equal(t, uint32(2), e.Number())
+	assert.Equal(t, uint32(20), e.Start())
+	assert.Equal(t, uint32(29), e.End())
+	assert.Equal(t, uint32(19), e.SeedBlockNumber())
+	assert.True(t, e.Contains(20))
+	assert.True(t, e.Contains(29))
+	assert.False(t, e.Contains(30))
+	assert.True(t, e.IsBoundary(30))
+	assert.False(t, e.IsBoundary(25))
+	assert.Equal(t, poa.EpochOfLength(30, 10), e.Next())
+	assert.Equal(t, poa.EpochOfLength(19, 10), e.Prev())
+
+	id := thor.BytesToBytes32([]byte("seed"))
+	assert.Equal(t, e.Seed(id), e.Seed(id))
+	assert.NotEqual(t, e.Seed(id), e.Next().Seed(id))
+
+	assert.Equal(t, thor.EpochLength, poa.EpochOf(1).Length())
+}
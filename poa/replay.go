@@ -0,0 +1,49 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// ReplaySchedule replays the schedule of the block on the parent state st. It returns the timing
+// active for the block, and proposers who missed their slots between the parent and the block.
+func ReplaySchedule(forkConfig thor.ForkConfig, st *state.State, parent *block.Header, header *block.Header) (Timing, []thor.Address, error) {
+	signer, err := header.Signer()
+	if err != nil {
+		return Timing{}, nil, err
+	}
+	timing := TimingAt(forkConfig, parent.Number(), builtin.Params.Native(st).Get)
+	if header.Timestamp()-parent.Timestamp() <= timing.Interval {
+		// no slot missed
+		return timing, nil, st.Err()
+	}
+
+	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
+	candidates := builtin.Authority.Native(st).Candidates(endorsement, thor.MaxBlockProposers)
+	if err := st.Err(); err != nil {
+		return Timing{}, nil, err
+	}
+	proposers := make([]Proposer, 0, len(candidates))
+	for _, c := range candidates {
+		proposers = append(proposers, Proposer{Address: c.NodeMaster, Active: c.Active})
+	}
+	sched, err := NewSchedulerWithTiming(timing, signer, proposers, parent.Number(), parent.Timestamp())
+	if err != nil {
+		return Timing{}, nil, err
+	}
+	updates, _ := sched.Updates(header.Timestamp())
+	var missed []thor.Address
+	for _, p := range updates {
+		if !p.Active {
+			missed = append(missed, p.Address)
+		}
+	}
+	return timing, missed, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+func TestReplaySchedule(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	gen := new(genesis.Builder).
+		GasLimit(thor.InitialGasLimit).
+		Timestamp(1526400000).
+		State(func(state *state.State) error {
+			state.SetCode(builtin.Authority.Address, builtin.Authority.RuntimeBytecodes())
+			for _, acc := range genesis.DevAccounts() {
+				builtin.Authority.Native(state).Add(acc.Address, acc.Address, thor.Bytes32{})
+			}
+			return nil
+		})
+	b0, _, err := gen.Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := b0.Header()
+	st, _ := state.New(parent.StateRoot(), kv)
+
+	signer := genesis.DevAccounts()[0]
+	newHeader := func(timestamp uint64) *block.Header {
+		blk := new(block.Builder).ParentID(parent.ID()).Timestamp(timestamp).Build()
+		sig, _ := crypto.Sign(blk.Header().SigningHash().Bytes(), signer.PrivateKey)
+		return blk.WithSignature(sig).Header()
+	}
+	var forkConfig thor.ForkConfig
+
+	// no slot missed
+	timing, missed, err := poa.ReplaySchedule(forkConfig, st, parent, newHeader(parent.Timestamp()+thor.BlockInterval))
+	assert.Nil(t, err)
+	assert.Equal(t, poa.DefaultTiming, timing)
+	assert.Empty(t, missed)
+
+	// the first slot at least is missed
+	var proposers []poa.Proposer
+	for _, acc := range genesis.DevAccounts() {
+		proposers = append(proposers, poa.Proposer{Address: acc.Address, Active: true})
+	}
+	sched, _ := poa.NewScheduler(signer.Address, proposers, parent.Number(), parent.Timestamp())
+	blockTime := sched.Schedule(parent.Timestamp() + thor.BlockInterval*2)
+
+	_, missed, err = poa.ReplaySchedule(forkConfig, st, parent, newHeader(blockTime))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, missed)
+	assert.NotContains(t, missed, signer.Address)
+}
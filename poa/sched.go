@@ -14,13 +14,14 @@ import (
 
 // Scheduler to schedule the time when a proposer to produce a block.
 type Scheduler struct {
+	timing            Timing
 	proposer          Proposer
 	actives           []Proposer
 	parentBlockNumber uint32
 	parentBlockTime   uint64
 }
 
-// NewScheduler create a Scheduler object.
+// NewScheduler create a Scheduler object with default timing.
 // `addr` is the proposer to be scheduled.
 // If `addr` is not listed in `proposers`, an error returned.
 func NewScheduler(
@@ -28,6 +29,16 @@ func NewScheduler(
 	proposers []Proposer,
 	parentBlockNumber uint32,
 	parentBlockTime uint64) (*Scheduler, error) {
+	return NewSchedulerWithTiming(DefaultTiming, addr, proposers, parentBlockNumber, parentBlockTime)
+}
+
+// NewSchedulerWithTiming create a Scheduler object with the given timing.
+func NewSchedulerWithTiming(
+	timing Timing,
+	addr thor.Address,
+	proposers []Proposer,
+	parentBlockNumber uint32,
+	parentBlockTime uint64) (*Scheduler, error) {
 
 	actives := make([]Proposer, 0, len(proposers))
 	listed := false
@@ -47,6 +58,7 @@ func NewScheduler(
 	}
 
 	return &Scheduler{
+		timing,
 		proposer,
 		actives,
 		parentBlockNumber,
@@ -54,6 +66,11 @@ func NewScheduler(
 	}, nil
 }
 
+// Epoch returns the epoch the new block belongs to.
+func (s *Scheduler) Epoch() Epoch {
+	return EpochOf(s.parentBlockNumber + 1)
+}
+
 func (s *Scheduler) whoseTurn(t uint64) Proposer {
 	index := dprp(s.parentBlockNumber, t) % uint64(len(s.actives))
 	return s.actives[index]
@@ -62,34 +79,18 @@ func (s *Scheduler) whoseTurn(t uint64) Proposer {
 // Schedule to determine time of the proposer to produce a block, according to `nowTime`.
 // `newBlockTime` is promised to be >= nowTime and > parentBlockTime
 func (s *Scheduler) Schedule(nowTime uint64) (newBlockTime uint64) {
-	const T = thor.BlockInterval
-
-	newBlockTime = s.parentBlockTime + T
-
-	if nowTime > newBlockTime {
-		// ensure T aligned, and >= nowTime
-		newBlockTime += (nowTime - newBlockTime + T - 1) / T * T
-	}
-
-	for {
-		p := s.whoseTurn(newBlockTime)
-		if p.Address == s.proposer.Address {
+	// try slots one by one, starting from the earliest one not before nowTime
+	for slot := s.timing.NextSlot(s.parentBlockTime, nowTime); ; slot++ {
+		newBlockTime = s.timing.SlotTime(s.parentBlockTime, slot)
+		if s.whoseTurn(newBlockTime).Address == s.proposer.Address {
 			return newBlockTime
 		}
-
-		// try next time slot
-		newBlockTime += T
 	}
 }
 
 // IsTheTime returns if the newBlockTime is correct for the proposer.
 func (s *Scheduler) IsTheTime(newBlockTime uint64) bool {
-	if s.parentBlockTime >= newBlockTime {
-		// invalid block time
-		return false
-	}
-
-	if (newBlockTime-s.parentBlockTime)%thor.BlockInterval != 0 {
+	if _, ok := s.timing.SlotOf(s.parentBlockTime, newBlockTime); !ok {
 		// invalid block time
 		return false
 	}
@@ -102,13 +103,13 @@ func (s *Scheduler) Updates(newBlockTime uint64) (updates []Proposer, score uint
 
 	toDeactivate := make(map[thor.Address]Proposer)
 
-	t := newBlockTime - thor.BlockInterval
+	t := newBlockTime - s.timing.Interval
 	for i := uint64(0); i < thor.MaxBlockProposers && t > s.parentBlockTime; i++ {
 		p := s.whoseTurn(t)
 		if p.Address != s.proposer.Address {
 			toDeactivate[p.Address] = p
 		}
-		t -= thor.BlockInterval
+		t -= s.timing.Interval
 	}
 
 	updates = make([]Proposer, 0, len(toDeactivate)+1)
@@ -0,0 +1,87 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/thor"
+)
+
+// Block time slots are counted from the parent block: slot n is at
+// parentTime + n*interval, and slot 1 is the earliest time a child block can take.
+
+// Timing maps timestamps to slots with a block interval.
+type Timing struct {
+	Interval uint64
+}
+
+// DefaultTiming timing with the default block interval.
+var DefaultTiming = Timing{thor.BlockInterval}
+
+// TimingAt returns the timing active for the child block of the parent with given number.
+// Since the fork, the block interval is governed by the Params key thor.KeyBlockInterval,
+// read from the parent state. Zero or out of range values fall back to the default.
+func TimingAt(forkConfig thor.ForkConfig, parentNumber uint32, getParam func(key thor.Bytes32) *big.Int) Timing {
+	if parentNumber+1 < forkConfig.BlockInterval {
+		return DefaultTiming
+	}
+	v := getParam(thor.KeyBlockInterval)
+	if v.Sign() <= 0 || v.Cmp(new(big.Int).SetUint64(thor.MaxBlockInterval)) > 0 {
+		return DefaultTiming
+	}
+	return Timing{v.Uint64()}
+}
+
+// SlotTime returns the timestamp of the slot after the parent block time.
+func (t Timing) SlotTime(parentTime uint64, slot uint64) uint64 {
+	return parentTime + slot*t.Interval
+}
+
+// SlotOf returns the slot of timestamp ts after the parent block time.
+// ok is false if ts is not after parent time, or not aligned to a slot. In the latter
+// case, the slot right before ts is returned.
+func (t Timing) SlotOf(parentTime uint64, ts uint64) (slot uint64, ok bool) {
+	if ts <= parentTime {
+		return 0, false
+	}
+	d := ts - parentTime
+	return d / t.Interval, d%t.Interval == 0
+}
+
+// NextSlot returns the earliest slot whose time is not before nowTime.
+func (t Timing) NextSlot(parentTime uint64, nowTime uint64) uint64 {
+	if nowTime <= parentTime+t.Interval {
+		return 1
+	}
+	return (nowTime - parentTime + t.Interval - 1) / t.Interval
+}
+
+// IsFutureTime returns whether timestamp ts is too far ahead of nowTime to be accepted now.
+// One slot of clock drift is tolerated.
+func (t Timing) IsFutureTime(ts uint64, nowTime uint64) bool {
+	return ts > nowTime+t.Interval
+}
+
+// SlotTime returns the timestamp of the slot after the parent block time, with default timing.
+func SlotTime(parentTime uint64, slot uint64) uint64 {
+	return DefaultTiming.SlotTime(parentTime, slot)
+}
+
+// SlotOf returns the slot of timestamp t after the parent block time, with default timing.
+func SlotOf(parentTime uint64, t uint64) (slot uint64, ok bool) {
+	return DefaultTiming.SlotOf(parentTime, t)
+}
+
+// NextSlot returns the earliest slot whose time is not before nowTime, with default timing.
+func NextSlot(parentTime uint64, nowTime uint64) uint64 {
+	return DefaultTiming.NextSlot(parentTime, nowTime)
+}
+
+// IsFutureTime returns whether timestamp t is too far ahead of nowTime, with default timing.
+func IsFutureTime(t uint64, nowTime uint64) bool {
+	return DefaultTiming.IsFutureTime(t, nowTime)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/thor"
+)
+
+func TestSlot(t *testing.T) {
+	const T = thor.BlockInterval
+
+	assert.Equal(t, parentTime+3*T, poa.SlotTime(parentTime, 3))
+
+	tests := []struct {
+		t    uint64
+		slot uint64
+		ok   bool
+	}{
+		{parentTime, 0, false},
+		{parentTime + 1, 0, false},
+		{parentTime + T, 1, true},
+		{parentTime + T + 1, 1, false},
+		{parentTime + 5*T, 5, true},
+	}
+	for _, tt := range tests {
+		slot, ok := poa.SlotOf(parentTime, tt.t)
+		assert.Equal(t, tt.slot, slot)
+		assert.Equal(t, tt.ok, ok)
+	}
+
+	assert.Equal(t, uint64(1), poa.NextSlot(parentTime, 0))
+	assert.Equal(t, uint64(1), poa.NextSlot(parentTime, parentTime+T))
+	assert.Equal(t, uint64(2), poa.NextSlot(parentTime, parentTime+T+1))
+	assert.Equal(t, uint64(2), poa.NextSlot(parentTime, parentTime+2*T))
+
+	assert.False(t, poa.IsFutureTime(parentTime+T, parentTime))
+	assert.True(t, poa.IsFutureTime(parentTime+T+1, parentTime))
+}
+
+func TestTimingAt(t *testing.T) {
+	params := map[thor.Bytes32]*big.Int{thor.KeyBlockInterval: big.NewInt(5)}
+	getParam := func(key thor.Bytes32) *big.Int {
+		if v, ok := params[key]; ok {
+			return v
+		}
+		return &big.Int{}
+	}
+	fc := thor.ForkConfig{BlockInterval: 10}
+
+	assert.Equal(t, poa.DefaultTiming, poa.TimingAt(fc, 8, getParam), "before fork")
+	assert.Equal(t, poa.Timing{Interval: 5}, poa.TimingAt(fc, 9, getParam), "since fork")
+
+	params[thor.KeyBlockInterval] = &big.Int{}
+	assert.Equal(t, poa.DefaultTiming, poa.TimingAt(fc, 9, getParam), "unset")
+	params[thor.KeyBlockInterval] = new(big.Int).SetUint64(thor.MaxBlockInterval + 1)
+	assert.Equal(t, poa.DefaultTiming, poa.TimingAt(fc, 9, getParam), "out of range")
+
+	timing := poa.Timing{Interval: 5}
+	slot, ok := timing.SlotOf(parentTime, parentTime+15)
+	assert.Equal(t, uint64(3), slot)
+	assert.True(t, ok)
+	_, ok = poa.DefaultTiming.SlotOf(parentTime, parentTime+15)
+	assert.False(t, ok)
+}
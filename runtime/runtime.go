@@ -113,7 +113,7 @@ func (rt *Runtime) SetVMConfig(config vm.Config) *Runtime {
 	return rt
 }
 
-func (rt *Runtime) newEVM(stateDB *statedb.StateDB, clauseIndex uint32, txCtx *xenv.TransactionContext) *vm.EVM {
+func (rt *Runtime) newEVM(stateDB *statedb.StateDB, clauseIndex uint32, txCtx *xenv.TransactionContext, vmConfig vm.Config) *vm.EVM {
 	var lastNonNativeCallGas uint64
 	return vm.NewEVM(vm.Context{
 		CanTransfer: func(_ vm.StateDB, addr common.Address, amount *big.Int) bool {
@@ -251,7 +251,7 @@ func (rt *Runtime) newEVM(stateDB *statedb.StateDB, clauseIndex uint32, txCtx *x
 		BlockNumber: new(big.Int).SetUint64(uint64(rt.ctx.Number)),
 		Time:        new(big.Int).SetUint64(rt.ctx.Time),
 		Difficulty:  &big.Int{},
-	}, stateDB, &chainConfig, rt.vmConfig)
+	}, stateDB, &chainConfig, vmConfig)
 }
 
 // ExecuteClause executes single clause.
@@ -273,10 +273,20 @@ func (rt *Runtime) PrepareClause(
 	clauseIndex uint32,
 	gas uint64,
 	txCtx *xenv.TransactionContext,
+) (exec func() (output *Output, interrupted bool), interrupt func()) {
+	return rt.prepareClause(clause, clauseIndex, gas, txCtx, rt.vmConfig)
+}
+
+func (rt *Runtime) prepareClause(
+	clause *tx.Clause,
+	clauseIndex uint32,
+	gas uint64,
+	txCtx *xenv.TransactionContext,
+	vmConfig vm.Config,
 ) (exec func() (output *Output, interrupted bool), interrupt func()) {
 	var (
 		stateDB       = statedb.New(rt.state)
-		evm           = rt.newEVM(stateDB, clauseIndex, txCtx)
+		evm           = rt.newEVM(stateDB, clauseIndex, txCtx, vmConfig)
 		data          []byte
 		leftOverGas   uint64
 		vmErr         error
@@ -327,13 +337,49 @@ func (rt *Runtime) ExecuteTransaction(tx *tx.Transaction) (receipt *tx.Receipt,
 	return executor.Finalize()
 }
 
+// TraceTransaction executes a transaction like ExecuteTransaction, with steps of all clauses
+// captured by the tracer, e.g. a vm.StructLogger, or a custom Tracer which is notified of
+// the transaction lifecycle as well. The VM config of the runtime is left as is,
+// so that tracing is toggled per execution.
+func (rt *Runtime) TraceTransaction(tx *tx.Transaction, tracer vm.Tracer) (receipt *tx.Receipt, err error) {
+	executor, err := rt.PrepareTracedTransaction(tx, tracer)
+	if err != nil {
+		return nil, err
+	}
+	for executor.HasNextClause() {
+		if _, _, err := executor.NextClause(); err != nil {
+			return nil, err
+		}
+	}
+	return executor.Finalize()
+}
+
 // PrepareTransaction prepare to execute tx.
 func (rt *Runtime) PrepareTransaction(tx *tx.Transaction) (*TransactionExecutor, error) {
+	return rt.prepareTransaction(tx, nil)
+}
+
+// PrepareTracedTransaction prepare to execute tx, with steps captured by the tracer.
+// If the tracer implements Tracer, it's notified of the transaction lifecycle as well.
+func (rt *Runtime) PrepareTracedTransaction(tx *tx.Transaction, tracer vm.Tracer) (*TransactionExecutor, error) {
+	if tracer == nil {
+		return nil, errors.New("nil tracer")
+	}
+	return rt.prepareTransaction(tx, tracer)
+}
+
+// prepareTransaction prepare to execute tx. The VM config of the runtime is used if tracer is nil.
+func (rt *Runtime) prepareTransaction(tx *tx.Transaction, tracer vm.Tracer) (*TransactionExecutor, error) {
 	resolvedTx, err := ResolveTransaction(tx)
 	if err != nil {
 		return nil, err
 	}
 
+	txTracer, _ := tracer.(Tracer)
+	if txTracer != nil {
+		txTracer.CaptureTxStart(rt, tx, resolvedTx.Origin)
+	}
+
 	baseGasPrice, gasPrice, payer, returnGas, err := resolvedTx.BuyGas(rt.state, rt.ctx.Time)
 	if err != nil {
 		return nil, err
@@ -361,7 +407,13 @@ func (rt *Runtime) PrepareTransaction(tx *tx.Transaction) (*TransactionExecutor,
 				return 0, nil, errors.New("no more clause")
 			}
 			nextClauseIndex := uint32(len(txOutputs))
-			output = rt.ExecuteClause(resolvedTx.Clauses[nextClauseIndex], nextClauseIndex, leftOverGas, txCtx)
+			// read at execution time, as the VM config may be set between clauses
+			vmConfig := rt.vmConfig
+			if tracer != nil {
+				vmConfig = vm.Config{Debug: true, Tracer: tracer}
+			}
+			exec, _ := rt.prepareClause(resolvedTx.Clauses[nextClauseIndex], nextClauseIndex, leftOverGas, txCtx, vmConfig)
+			output, _ = exec()
 			gasUsed = leftOverGas - output.LeftOverGas
 			leftOverGas = output.LeftOverGas
 
@@ -416,6 +468,9 @@ func (rt *Runtime) PrepareTransaction(tx *tx.Transaction) (*TransactionExecutor,
 			builtin.Energy.Native(rt.state, rt.ctx.Time).Add(rt.ctx.Beneficiary, reward)
 
 			receipt.Reward = reward
+			if txTracer != nil {
+				txTracer.CaptureTxEnd(receipt)
+			}
 			return receipt, nil
 		},
 	}, nil
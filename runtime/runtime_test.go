@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/abi"
 	"github.com/vechain/thor/builtin"
@@ -22,6 +23,7 @@ import (
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
 	"github.com/vechain/thor/xenv"
 )
 
@@ -156,6 +158,54 @@ func TestCall(t *testing.T) {
 	assert.True(t, interrupted)
 }
 
+func TestTraceTransaction(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	g := genesis.NewDevnet()
+	b0, _, err := g.Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := chain.New(kv, b0)
+	st, _ := state.New(b0.Header().StateRoot(), kv)
+
+	rt := runtime.New(ch.NewSeeker(b0.Header().ID()), st, &xenv.BlockContext{
+		Number:   1,
+		Time:     b0.Header().Timestamp() + thor.BlockInterval,
+		GasLimit: b0.Header().GasLimit(),
+	})
+
+	// PUSH1 1 PUSH1 0 SSTORE STOP
+	data, _ := hex.DecodeString("600160005500")
+	newTx := func(nonce uint64) *tx.Transaction {
+		trx := new(tx.Builder).
+			ChainTag(ch.Tag()).
+			Gas(1000000).
+			Expiration(100).
+			Nonce(nonce).
+			Clause(tx.NewClause(nil).WithData(data)).
+			Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		return trx.WithSignature(sig)
+	}
+
+	logger := vm.NewStructLogger(nil)
+	receipt, err := rt.TraceTransaction(newTx(0), logger)
+	assert.Nil(t, err)
+	assert.False(t, receipt.Reverted)
+
+	logs := logger.StructLogs()
+	assert.Equal(t, 4, len(logs))
+	assert.Equal(t, []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.SSTORE, vm.STOP}, []vm.OpCode{logs[0].Op, logs[1].Op, logs[2].Op, logs[3].Op})
+	assert.Equal(t, 2, len(logs[2].Stack))
+	assert.Equal(t, common.BigToHash(big.NewInt(1)), logs[2].Storage[common.Hash{}])
+
+	// tracing is per execution
+	_, err = rt.ExecuteTransaction(newTx(1))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(logger.StructLogs()))
+}
+
 func TestExecuteTransaction(t *testing.T) {
 
 	// kv, _ := lvldb.NewMem()
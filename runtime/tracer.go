@@ -0,0 +1,30 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime
+
+import (
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
+)
+
+// Tracer is a custom tracer, e.g. call tree, prestate or 4-byte tracer, which can be attached
+// when executing or re-executing transactions, see TraceTransaction and PrepareTracedTransaction.
+//
+// Besides opcode steps, it's notified of call frames and the transaction lifecycle:
+// the top frame of each clause is reported by CaptureStart and CaptureEnd, nested frames
+// by CaptureEnter and CaptureExit.
+type Tracer interface {
+	vm.Tracer
+	vm.FrameTracer
+
+	// CaptureTxStart is called before gas bought. State and context of the runtime can
+	// be read during the execution, for values prior to each step.
+	CaptureTxStart(rt *Runtime, tx *tx.Transaction, origin thor.Address)
+	// CaptureTxEnd is called after the transaction finalized. It's not called if the
+	// transaction is rejected, e.g. for insufficient energy.
+	CaptureTxEnd(receipt *tx.Receipt)
+}
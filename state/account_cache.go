@@ -0,0 +1,85 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+var accCache = newAccountCache(4096, 16384)
+
+// accountCache caches decoded accounts and storage values shared across State instances.
+// Since roots address immutable tries, entries never go stale. Entries are bound to the kv
+// they were read from, so that reads upon e.g. a witness recorder are not short-circuited.
+type accountCache struct {
+	accounts *lru.Cache // (state root, address) -> account
+	storage  *lru.Cache // (storage root, key) -> value
+}
+
+type accountCacheKey struct {
+	root thor.Bytes32
+	addr thor.Address
+}
+
+type storageCacheKey struct {
+	root thor.Bytes32
+	key  thor.Bytes32
+}
+
+type accountCacheEntry struct {
+	acc *Account
+	kv  kv.GetPutter
+}
+
+type storageCacheEntry struct {
+	value rlp.RawValue
+	kv    kv.GetPutter
+}
+
+func newAccountCache(accountsLimit, storageLimit int) *accountCache {
+	accounts, _ := lru.New(accountsLimit)
+	storage, _ := lru.New(storageLimit)
+	return &accountCache{accounts: accounts, storage: storage}
+}
+
+// GetAccount returns a copy of the cached account, which is safe to modify.
+func (ac *accountCache) GetAccount(root thor.Bytes32, addr thor.Address, kv kv.GetPutter) (*Account, bool) {
+	if v, ok := ac.accounts.Get(accountCacheKey{root, addr}); ok {
+		if entry := v.(*accountCacheEntry); entry.kv == kv {
+			return copyAccount(entry.acc), true
+		}
+	}
+	return nil, false
+}
+
+func (ac *accountCache) AddAccount(root thor.Bytes32, addr thor.Address, acc *Account, kv kv.GetPutter) {
+	ac.accounts.Add(accountCacheKey{root, addr}, &accountCacheEntry{copyAccount(acc), kv})
+}
+
+func (ac *accountCache) GetStorage(root thor.Bytes32, key thor.Bytes32, kv kv.GetPutter) (rlp.RawValue, bool) {
+	if v, ok := ac.storage.Get(storageCacheKey{root, key}); ok {
+		if entry := v.(*storageCacheEntry); entry.kv == kv {
+			return entry.value, true
+		}
+	}
+	return nil, false
+}
+
+func (ac *accountCache) AddStorage(root thor.Bytes32, key thor.Bytes32, value rlp.RawValue, kv kv.GetPutter) {
+	ac.storage.Add(storageCacheKey{root, key}, &storageCacheEntry{value, kv})
+}
+
+func copyAccount(a *Account) *Account {
+	cpy := *a
+	cpy.Balance = new(big.Int).Set(a.Balance)
+	cpy.Energy = new(big.Int).Set(a.Energy)
+	return &cpy
+}
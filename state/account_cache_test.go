@@ -0,0 +1,51 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestAccountCache(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+
+	addr := thor.BytesToAddress([]byte("account1"))
+	key := thor.BytesToBytes32([]byte("key"))
+	state.SetBalance(addr, big.NewInt(10))
+	state.SetStorage(addr, key, thor.BytesToBytes32([]byte("value")))
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+
+	state, _ = New(root, kv)
+	assert.Equal(t, big.NewInt(10), state.GetBalance(addr))
+	assert.Equal(t, thor.BytesToBytes32([]byte("value")), state.GetStorage(addr, key))
+
+	acc, ok := accCache.GetAccount(root, addr, kv)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(10), acc.Balance)
+	acc.Balance.SetInt64(1)
+
+	storageRoot := thor.BytesToBytes32(acc.StorageRoot)
+	_, ok = accCache.GetStorage(storageRoot, key, kv)
+	assert.True(t, ok)
+
+	// shared by other states
+	state, _ = New(root, kv)
+	assert.Equal(t, big.NewInt(10), state.GetBalance(addr), "cached copy modified")
+
+	// bound to kv
+	other, _ := lvldb.NewMem()
+	_, ok = accCache.GetAccount(root, addr, other)
+	assert.False(t, ok)
+	_, ok = accCache.GetStorage(storageRoot, key, other)
+	assert.False(t, ok)
+}
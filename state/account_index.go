@@ -0,0 +1,39 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+// The account index is a flat set of addresses that have ever been non-empty
+// in any committed state. An address absent from the index is known to be empty
+// at every revision, so lookups can skip descending the accounts trie.
+//
+// The index only grows; it is never consulted until it's known to be complete,
+// i.e. it was maintained since the genesis state was first committed to the store.
+var (
+	accountIndexPrefix      = []byte("acc-idx-")
+	accountIndexCompleteKey = []byte("acc-idx-complete")
+)
+
+func accountIndexKey(addr thor.Address) []byte {
+	return append(append([]byte(nil), accountIndexPrefix...), addr[:]...)
+}
+
+// accountIndexComplete returns whether the index covers all accounts in kv.
+func accountIndexComplete(kv kv.Getter) bool {
+	has, err := kv.Has(accountIndexCompleteKey)
+	return err == nil && has
+}
+
+// accountMayExist returns false only if the address is surely never touched.
+func accountMayExist(kv kv.Getter, addr thor.Address) bool {
+	has, err := kv.Has(accountIndexKey(addr))
+	// fallback to trie on error
+	return err != nil || has
+}
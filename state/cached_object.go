@@ -16,6 +16,9 @@ type cachedObject struct {
 	kv   kv.GetPutter
 	data Account
 
+	// optional flat storage reader, ok is false if not available
+	flatStorage func(key thor.Bytes32) (value rlp.RawValue, ok bool, err error)
+
 	cache struct {
 		code        []byte
 		storageTrie trieReader
@@ -55,18 +58,33 @@ func (co *cachedObject) GetStorage(key thor.Bytes32) (rlp.RawValue, error) {
 	}
 	// not found in cache
 
-	trie, err := co.getOrCreateStorageTrie()
-	if err != nil {
-		return nil, err
+	root := thor.BytesToBytes32(co.data.StorageRoot)
+	v, ok := accCache.GetStorage(root, key, co.kv)
+	if ok {
+		cache.storage[key] = v
+		return v, nil
 	}
 
-	// load from trie
-	v, err := loadStorage(trie, key)
-	if err != nil {
-		return nil, err
+	var err error
+	if co.flatStorage != nil {
+		if v, ok, err = co.flatStorage(key); err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		trie, err := co.getOrCreateStorageTrie()
+		if err != nil {
+			return nil, err
+		}
+
+		// load from trie
+		if v, err = loadStorage(trie, key); err != nil {
+			return nil, err
+		}
 	}
 	// put into cache
 	cache.storage[key] = v
+	accCache.AddStorage(root, key, v, co.kv)
 	return v, nil
 }
 
@@ -24,3 +24,29 @@ func NewCreator(kv kv.GetPutter) *Creator {
 func (c *Creator) NewState(root thor.Bytes32) (*State, error) {
 	return New(root, c.kv)
 }
+
+// NewReadOnly create a read-only state view, which is safe for concurrent use.
+func (c *Creator) NewReadOnly(root thor.Bytes32) (*ReadOnly, error) {
+	return NewReadOnly(root, c.kv)
+}
+
+// NewWitnessRecorder create a witness recorder upon the underlying store.
+// States created upon the recorder record trie nodes and codes read.
+func (c *Creator) NewWitnessRecorder() *WitnessRecorder {
+	return NewWitnessRecorder(c.kv)
+}
+
+// Diff computes accounts and storage slots changed from one state root to another.
+func (c *Creator) Diff(fromRoot, toRoot thor.Bytes32) ([]*AccountDiff, error) {
+	return Diff(c.kv, fromRoot, toRoot)
+}
+
+// CheckPruned returns *PrunedError if the state of block num has been pruned.
+func (c *Creator) CheckPruned(num uint32) error {
+	return CheckPruned(c.kv, num)
+}
+
+// NewIterator create an iterator of accounts at the given root.
+func (c *Creator) NewIterator(root thor.Bytes32) (*Iterator, error) {
+	return NewIterator(root, c.kv)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// AccountDiff describes how an account changed between two states.
+type AccountDiff struct {
+	AddressHash thor.Bytes32
+	Address     *thor.Address // nil if the preimage not recorded
+	From        *Account      // nil if not existed
+	To          *Account      // nil if deleted
+	Storage     []*StorageDiff
+}
+
+// StorageDiff describes how a storage slot changed between two states.
+// Zero length value means the slot not set.
+type StorageDiff struct {
+	KeyHash thor.Bytes32
+	Key     *thor.Bytes32 // nil if the preimage not recorded
+	From    rlp.RawValue
+	To      rlp.RawValue
+}
+
+// Diff computes accounts and storage slots changed from one state root to another, by walking
+// only subtries that differ. Results are sorted by hashed addresses and keys, i.e. the trie order.
+// Original addresses and keys are reported only if preimages are recorded, see RecordPreimages.
+func Diff(kv kv.GetPutter, fromRoot, toRoot thor.Bytes32) ([]*AccountDiff, error) {
+	fromTrie, err := trie.NewSecure(fromRoot, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	toTrie, err := trie.NewSecure(toRoot, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := diffTries(fromTrie, toTrie)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]*AccountDiff, 0, len(changes))
+	for _, c := range changes {
+		diff := AccountDiff{AddressHash: thor.BytesToBytes32(c.hashedKey)}
+		if addr := preimage(fromTrie, toTrie, c.hashedKey); addr != nil {
+			a := thor.BytesToAddress(addr)
+			diff.Address = &a
+		}
+		if diff.From, err = decodeAccountLeaf(c.from); err != nil {
+			return nil, err
+		}
+		if diff.To, err = decodeAccountLeaf(c.to); err != nil {
+			return nil, err
+		}
+		if diff.Storage, err = diffStorage(kv, diff.From, diff.To); err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, &diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return bytes.Compare(diffs[i].AddressHash[:], diffs[j].AddressHash[:]) < 0
+	})
+	return diffs, nil
+}
+
+func diffStorage(kv kv.GetPutter, from, to *Account) ([]*StorageDiff, error) {
+	var fromRoot, toRoot thor.Bytes32
+	if from != nil {
+		fromRoot = thor.BytesToBytes32(from.StorageRoot)
+	}
+	if to != nil {
+		toRoot = thor.BytesToBytes32(to.StorageRoot)
+	}
+	if fromRoot == toRoot {
+		return nil, nil
+	}
+	fromTrie, err := trie.NewSecure(fromRoot, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	toTrie, err := trie.NewSecure(toRoot, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := diffTries(fromTrie, toTrie)
+	if err != nil {
+		return nil, err
+	}
+	diffs := make([]*StorageDiff, 0, len(changes))
+	for _, c := range changes {
+		diff := StorageDiff{KeyHash: thor.BytesToBytes32(c.hashedKey), From: c.from, To: c.to}
+		if key := preimage(fromTrie, toTrie, c.hashedKey); key != nil {
+			k := thor.BytesToBytes32(key)
+			diff.Key = &k
+		}
+		diffs = append(diffs, &diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return bytes.Compare(diffs[i].KeyHash[:], diffs[j].KeyHash[:]) < 0
+	})
+	return diffs, nil
+}
+
+type leafChange struct {
+	hashedKey []byte
+	from, to  []byte // nil if absent
+}
+
+// diffTries returns leaves changed, by iterating nodes of each trie absent in the other.
+func diffTries(from, to *trie.SecureTrie) ([]*leafChange, error) {
+	changes := make(map[string]*leafChange)
+	collect := func(a, b *trie.SecureTrie, set func(c *leafChange, value []byte)) error {
+		it, _ := trie.NewDifferenceIterator(a.NodeIterator(nil), b.NodeIterator(nil))
+		for it.Next(true) {
+			if !it.Leaf() {
+				continue
+			}
+			key := string(it.LeafKey())
+			c, ok := changes[key]
+			if !ok {
+				c = &leafChange{hashedKey: []byte(key)}
+				changes[key] = c
+			}
+			set(c, append([]byte(nil), it.LeafBlob()...))
+		}
+		return it.Error()
+	}
+	if err := collect(from, to, func(c *leafChange, v []byte) { c.to = v }); err != nil {
+		return nil, err
+	}
+	if err := collect(to, from, func(c *leafChange, v []byte) { c.from = v }); err != nil {
+		return nil, err
+	}
+
+	result := make([]*leafChange, 0, len(changes))
+	for _, c := range changes {
+		// a leaf node moved to another path without value changed
+		if bytes.Equal(c.from, c.to) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// preimage returns the original key of the hashed one, or nil if not recorded.
+func preimage(a, b *trie.SecureTrie, hashedKey []byte) []byte {
+	if key := a.GetKey(hashedKey); len(key) > 0 {
+		return key
+	}
+	if key := b.GetKey(hashedKey); len(key) > 0 {
+		return key
+	}
+	return nil
+}
+
+func decodeAccountLeaf(data []byte) (*Account, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var a Account
+	if err := rlp.DecodeBytes(data, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestDiff(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	RecordPreimages(kv)
+	addr1 := thor.BytesToAddress([]byte("account1"))
+	addr2 := thor.BytesToAddress([]byte("account2"))
+	addr3 := thor.BytesToAddress([]byte("account3"))
+	key1 := thor.BytesToBytes32([]byte("key1"))
+	key2 := thor.BytesToBytes32([]byte("key2"))
+
+	st, _ := New(thor.Bytes32{}, kv)
+	for i := 0; i < 50; i++ {
+		st.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+	}
+	st.SetBalance(addr1, big.NewInt(1))
+	st.SetBalance(addr2, big.NewInt(2))
+	st.SetStorage(addr2, key1, thor.BytesToBytes32([]byte("v1")))
+	root1, err := st.Stage().Commit()
+	assert.Nil(t, err)
+
+	st, _ = New(root1, kv)
+	st.Delete(addr1)
+	st.SetStorage(addr2, key1, thor.Bytes32{})
+	st.SetStorage(addr2, key2, thor.BytesToBytes32([]byte("v2")))
+	st.SetBalance(addr3, big.NewInt(3))
+	root2, err := st.Stage().Commit()
+	assert.Nil(t, err)
+
+	diffs, err := Diff(kv, root1, root1)
+	assert.Nil(t, err)
+	assert.Empty(t, diffs)
+
+	byAddr := func(diffs []*AccountDiff) map[thor.Address]*AccountDiff {
+		m := make(map[thor.Address]*AccountDiff)
+		for _, d := range diffs {
+			assert.Equal(t, thor.Blake2b(d.Address[:]), d.AddressHash)
+			m[*d.Address] = d
+		}
+		return m
+	}
+
+	diffs, err = Diff(kv, root1, root2)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(diffs))
+	m := byAddr(diffs)
+
+	assert.Equal(t, big.NewInt(1), m[addr1].From.Balance)
+	assert.Nil(t, m[addr1].To)
+	assert.Empty(t, m[addr1].Storage)
+
+	assert.Equal(t, big.NewInt(2), m[addr2].To.Balance)
+	v1, _ := rlp.EncodeToBytes([]byte("v1"))
+	v2, _ := rlp.EncodeToBytes([]byte("v2"))
+	storage := make(map[thor.Bytes32]*StorageDiff)
+	for _, sd := range m[addr2].Storage {
+		assert.Equal(t, thor.Blake2b(sd.Key[:]), sd.KeyHash)
+		storage[*sd.Key] = sd
+	}
+	assert.Equal(t, 2, len(storage))
+	assert.Equal(t, rlp.RawValue(v1), storage[key1].From)
+	assert.Empty(t, storage[key1].To)
+	assert.Empty(t, storage[key2].From)
+	assert.Equal(t, rlp.RawValue(v2), storage[key2].To)
+
+	assert.Nil(t, m[addr3].From)
+	assert.Equal(t, big.NewInt(3), m[addr3].To.Balance)
+
+	// reversed
+	diffs, err = Diff(kv, root2, root1)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(diffs))
+	m = byAddr(diffs)
+	assert.Nil(t, m[addr1].From)
+	assert.Equal(t, big.NewInt(1), m[addr1].To.Balance)
+}
+
+func TestDiffWithoutPreimages(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	addr := thor.BytesToAddress([]byte("account"))
+
+	st, _ := New(thor.Bytes32{}, kv)
+	st.SetBalance(addr, big.NewInt(1))
+	root, err := st.Stage().Commit()
+	assert.Nil(t, err)
+
+	diffs, err := Diff(kv, thor.Bytes32{}, root)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(diffs))
+	assert.Nil(t, diffs[0].Address)
+	assert.Equal(t, thor.Blake2b(addr[:]), diffs[0].AddressHash)
+}
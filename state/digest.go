@@ -0,0 +1,114 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// Digest summarizes the whole state at a root. It's computed by walking every
+// account and storage entry, so two nodes holding identical state always produce
+// identical digests.
+type Digest struct {
+	Root           thor.Bytes32 // the claimed state root
+	RecomputedRoot thor.Bytes32 // root rebuilt from the walked leaves
+	Accounts       uint64
+	Contracts      uint64 // accounts with code
+	StorageSlots   uint64
+	TotalBalance   *big.Int
+	TotalEnergy    *big.Int // energy grown to the given block time
+}
+
+// Hash returns the hash of the digest, as a compact fingerprint to be compared.
+func (d *Digest) Hash() thor.Bytes32 {
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		d.Root,
+		d.RecomputedRoot,
+		d.Accounts,
+		d.Contracts,
+		d.StorageSlots,
+		d.TotalBalance,
+		d.TotalEnergy,
+	})
+	return thor.Blake2b(data)
+}
+
+// ComputeDigest walks the state at root and computes its digest. Energy is
+// evaluated at blockTime. Every storage trie is rebuilt from its leaves as well,
+// and an error is returned if it doesn't match the root held by the account.
+func ComputeDigest(root thor.Bytes32, kv kv.GetPutter, blockTime uint64) (*Digest, error) {
+	accTrie, err := trie.NewSecure(root, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	d := &Digest{
+		Root:         root,
+		TotalBalance: new(big.Int),
+		TotalEnergy:  new(big.Int),
+	}
+	var (
+		accStack = trie.NewStackTrie()
+		accIt    = trie.NewIterator(accTrie.NodeIterator(nil))
+	)
+	for accIt.Next() {
+		accStack.Update(accIt.Key, accIt.Value)
+
+		var a Account
+		if err := rlp.DecodeBytes(accIt.Value, &a); err != nil {
+			return nil, err
+		}
+		d.Accounts++
+		if len(a.CodeHash) > 0 {
+			d.Contracts++
+		}
+		d.TotalBalance.Add(d.TotalBalance, a.Balance)
+		d.TotalEnergy.Add(d.TotalEnergy, a.CalcEnergy(blockTime))
+
+		if len(a.StorageRoot) == 0 {
+			continue
+		}
+		storageRoot := thor.BytesToBytes32(a.StorageRoot)
+		slots, recomputed, err := walkStorage(storageRoot, kv)
+		if err != nil {
+			return nil, err
+		}
+		if recomputed != storageRoot {
+			return nil, fmt.Errorf("storage trie of account %x: root mismatch, want %v got %v", accIt.Key, storageRoot, recomputed)
+		}
+		d.StorageSlots += slots
+	}
+	if accIt.Err != nil {
+		return nil, accIt.Err
+	}
+	d.RecomputedRoot = accStack.Hash()
+	return d, nil
+}
+
+func walkStorage(root thor.Bytes32, kv kv.GetPutter) (uint64, thor.Bytes32, error) {
+	strie, err := trie.NewSecure(root, kv, 0)
+	if err != nil {
+		return 0, thor.Bytes32{}, err
+	}
+	var (
+		count uint64
+		stack = trie.NewStackTrie()
+		it    = trie.NewIterator(strie.NodeIterator(nil))
+	)
+	for it.Next() {
+		stack.Update(it.Key, it.Value)
+		count++
+	}
+	if it.Err != nil {
+		return 0, thor.Bytes32{}, it.Err
+	}
+	return count, stack.Hash(), nil
+}
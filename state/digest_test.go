@@ -0,0 +1,55 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestComputeDigest(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+	for i := 1; i <= 10; i++ {
+		state.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i)))
+	}
+	contract := thor.BytesToAddress([]byte("contract"))
+	state.SetCode(contract, []byte{1, 2, 3})
+	state.SetStorage(contract, thor.Bytes32{1}, thor.Bytes32{2})
+	state.SetStorage(contract, thor.Bytes32{2}, thor.Bytes32{3})
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+
+	d, err := ComputeDigest(root, kv, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, root, d.RecomputedRoot)
+	assert.Equal(t, uint64(11), d.Accounts)
+	assert.Equal(t, uint64(1), d.Contracts)
+	assert.Equal(t, uint64(2), d.StorageSlots)
+	assert.Equal(t, big.NewInt(55), d.TotalBalance)
+
+	// same state in another store yields the same digest
+	kv2, _ := lvldb.NewMem()
+	state2, _ := New(thor.Bytes32{}, kv2)
+	for i := 10; i >= 1; i-- {
+		state2.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i)))
+	}
+	state2.SetStorage(contract, thor.Bytes32{2}, thor.Bytes32{3})
+	state2.SetStorage(contract, thor.Bytes32{1}, thor.Bytes32{2})
+	state2.SetCode(contract, []byte{1, 2, 3})
+	root2, err := state2.Stage().Commit()
+	assert.Nil(t, err)
+	d2, err := ComputeDigest(root2, kv2, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, d.Hash(), d2.Hash())
+
+	_, err = ComputeDigest(thor.Bytes32{1}, kv, 0)
+	assert.NotNil(t, err)
+}
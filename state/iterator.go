@@ -0,0 +1,113 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// Iterator walks all accounts of the state at a root, in order of hashed addresses.
+// Original addresses and storage keys are available only if preimages are recorded, see RecordPreimages.
+type Iterator struct {
+	kv       kv.GetPutter
+	trie     *trie.SecureTrie
+	it       *trie.Iterator
+	addrHash thor.Bytes32
+	addr     *thor.Address
+	account  *Account
+	err      error
+}
+
+// NewIterator creates an iterator of accounts at the given root.
+func NewIterator(root thor.Bytes32, kv kv.GetPutter) (*Iterator, error) {
+	tr, err := trie.NewSecure(root, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator{
+		kv:   kv,
+		trie: tr,
+		it:   trie.NewIterator(tr.NodeIterator(nil)),
+	}, nil
+}
+
+// Next moves to the next account. It returns false when done or an error occurred.
+func (i *Iterator) Next() bool {
+	if i.err != nil || !i.it.Next() {
+		return false
+	}
+	var a Account
+	if err := rlp.DecodeBytes(i.it.Value, &a); err != nil {
+		i.err = err
+		return false
+	}
+	i.addrHash = thor.BytesToBytes32(i.it.Key)
+	i.addr = nil
+	if key := i.trie.GetKey(i.it.Key); len(key) > 0 {
+		addr := thor.BytesToAddress(key)
+		i.addr = &addr
+	}
+	i.account = &a
+	return true
+}
+
+// AddressHash returns the hashed address of the current account.
+func (i *Iterator) AddressHash() thor.Bytes32 {
+	return i.addrHash
+}
+
+// Address returns the address of the current account, or nil if the preimage not recorded.
+func (i *Iterator) Address() *thor.Address {
+	return i.addr
+}
+
+// Account returns the current account.
+func (i *Iterator) Account() *Account {
+	return i.account
+}
+
+// Code returns code of the current account.
+func (i *Iterator) Code() ([]byte, error) {
+	if len(i.account.CodeHash) == 0 {
+		return nil, nil
+	}
+	return i.kv.Get(i.account.CodeHash)
+}
+
+// Storage walks storage slots of the current account, until cb returns false.
+// key is nil if the preimage of keyHash not recorded.
+func (i *Iterator) Storage(cb func(keyHash thor.Bytes32, key *thor.Bytes32, value rlp.RawValue) bool) error {
+	if len(i.account.StorageRoot) == 0 {
+		return nil
+	}
+	strie, err := trie.NewSecure(thor.BytesToBytes32(i.account.StorageRoot), i.kv, 0)
+	if err != nil {
+		return err
+	}
+	it := trie.NewIterator(strie.NodeIterator(nil))
+	for it.Next() {
+		var key *thor.Bytes32
+		if k := strie.GetKey(it.Key); len(k) > 0 {
+			kk := thor.BytesToBytes32(k)
+			key = &kk
+		}
+		if !cb(thor.BytesToBytes32(it.Key), key, it.Value) {
+			return nil
+		}
+	}
+	return it.Err
+}
+
+// Err returns the error occurred during iteration.
+func (i *Iterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.it.Err
+}
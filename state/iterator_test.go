@@ -0,0 +1,81 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestIterator(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	RecordPreimages(kv)
+	st, _ := New(thor.Bytes32{}, kv)
+
+	contract := thor.BytesToAddress([]byte("contract"))
+	for i := 0; i < 10; i++ {
+		st.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+	}
+	st.SetCode(contract, []byte("code"))
+	st.SetStorage(contract, thor.BytesToBytes32([]byte("k1")), thor.BytesToBytes32([]byte("v1")))
+	st.SetStorage(contract, thor.BytesToBytes32([]byte("k2")), thor.BytesToBytes32([]byte("v2")))
+	root, err := st.Stage().Commit()
+	assert.Nil(t, err)
+
+	it, err := NewIterator(root, kv)
+	assert.Nil(t, err)
+	balances := make(map[thor.Address]*big.Int)
+	for it.Next() {
+		balances[*it.Address()] = it.Account().Balance
+		code, err := it.Code()
+		assert.Nil(t, err)
+		storage := make(map[thor.Bytes32]rlp.RawValue)
+		assert.Nil(t, it.Storage(func(_ thor.Bytes32, key *thor.Bytes32, value rlp.RawValue) bool {
+			storage[*key] = value
+			return true
+		}))
+		if *it.Address() == contract {
+			assert.Equal(t, []byte("code"), code)
+			assert.Equal(t, 2, len(storage))
+		} else {
+			assert.Nil(t, code)
+			assert.Empty(t, storage)
+		}
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 11, len(balances))
+	assert.Equal(t, big.NewInt(5), balances[thor.BytesToAddress([]byte{4})])
+}
+
+func TestIteratorWithoutPreimages(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	st, _ := New(thor.Bytes32{}, kv)
+
+	addr := thor.BytesToAddress([]byte("account"))
+	key := thor.BytesToBytes32([]byte("key"))
+	st.SetBalance(addr, big.NewInt(1))
+	st.SetStorage(addr, key, thor.BytesToBytes32([]byte("value")))
+	root, err := st.Stage().Commit()
+	assert.Nil(t, err)
+
+	it, err := NewIterator(root, kv)
+	assert.Nil(t, err)
+	assert.True(t, it.Next())
+	assert.Nil(t, it.Address())
+	assert.Equal(t, thor.Blake2b(addr[:]), it.AddressHash())
+	assert.Nil(t, it.Storage(func(keyHash thor.Bytes32, k *thor.Bytes32, _ rlp.RawValue) bool {
+		assert.Nil(t, k)
+		assert.Equal(t, thor.Blake2b(key[:]), keyHash)
+		return true
+	}))
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+}
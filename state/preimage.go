@@ -0,0 +1,33 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"sync"
+
+	"github.com/vechain/thor/kv"
+)
+
+var preimageStores = struct {
+	sync.Mutex
+	m map[kv.GetPutter]bool
+}{m: make(map[kv.GetPutter]bool)}
+
+// RecordPreimages makes states committed into the store record preimages of hashed trie keys,
+// i.e. account addresses and storage keys, which are stored under their hashes. It costs extra
+// space, but lets state iteration and diffs report original keys instead of hashed ones.
+// States committed before keep lacking preimages.
+func RecordPreimages(kv kv.GetPutter) {
+	preimageStores.Lock()
+	defer preimageStores.Unlock()
+	preimageStores.m[kv] = true
+}
+
+func recordsPreimages(kv kv.GetPutter) bool {
+	preimageStores.Lock()
+	defer preimageStores.Unlock()
+	return preimageStores.m[kv]
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// AccountProof merkle proof of an account and some of its storage.
+// Account trie and storage tries are secure tries, so nodes are on path of blake2b hash of address or storage key.
+type AccountProof struct {
+	Account       *Account
+	Proof         [][]byte // rlp encoded trie nodes from state root to the account
+	StorageProofs []*StorageProof
+}
+
+// StorageProof merkle proof of a storage value against storage root of the account.
+type StorageProof struct {
+	Key   thor.Bytes32
+	Value thor.Bytes32
+	Raw   rlp.RawValue // raw value in the trie, empty if absent
+	Proof [][]byte     // rlp encoded trie nodes from storage root to the value
+}
+
+// proofList collects proof nodes in order of path.
+type proofList [][]byte
+
+func (l *proofList) Put(key, value []byte) error {
+	*l = append(*l, append([]byte(nil), value...))
+	return nil
+}
+
+// proofReader serves proof nodes by hash, to verify proofs.
+type proofReader map[thor.Bytes32][]byte
+
+func newProofReader(proof [][]byte) proofReader {
+	r := make(proofReader, len(proof))
+	for _, node := range proof {
+		r[thor.Blake2b(node)] = node
+	}
+	return r
+}
+
+func (r proofReader) Get(key []byte) ([]byte, error) {
+	if node, ok := r[thor.BytesToBytes32(key)]; ok {
+		return node, nil
+	}
+	return nil, errors.New("proof node missing")
+}
+
+func (r proofReader) Has(key []byte) (bool, error) {
+	_, ok := r[thor.BytesToBytes32(key)]
+	return ok, nil
+}
+
+// Prove builds proofs of the account and its storage values of keys. A proof of absent account or key proves the absence.
+// Proofs are built from tries rather than snapshot, so they're available only if tries of the root are kept.
+func (r *ReadOnly) Prove(addr thor.Address, keys []thor.Bytes32) (*AccountProof, error) {
+	tr := r.trie.Copy()
+	acc, err := loadAccount(tr, addr)
+	if err != nil {
+		return nil, err
+	}
+	var proof proofList
+	if err := tr.Prove(addr[:], 0, &proof); err != nil {
+		return nil, err
+	}
+
+	storageProofs := make([]*StorageProof, 0, len(keys))
+	var strie *trie.SecureTrie
+	if len(acc.StorageRoot) > 0 && len(keys) > 0 {
+		if strie, err = trie.NewSecure(thor.BytesToBytes32(acc.StorageRoot), r.kv, 0); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range keys {
+		sp := &StorageProof{Key: key, Proof: [][]byte{}}
+		if strie != nil {
+			if sp.Raw, err = loadStorage(strie, key); err != nil {
+				return nil, err
+			}
+			if sp.Value, err = decodeStorageValue(sp.Raw); err != nil {
+				return nil, err
+			}
+			var proof proofList
+			if err := strie.Prove(key[:], 0, &proof); err != nil {
+				return nil, err
+			}
+			sp.Proof = proof
+		}
+		storageProofs = append(storageProofs, sp)
+	}
+	return &AccountProof{
+		Account:       acc,
+		Proof:         proof,
+		StorageProofs: storageProofs,
+	}, nil
+}
+
+// VerifyAccountProof verifies the account proof against state root, and returns the proven account.
+func VerifyAccountProof(root thor.Bytes32, addr thor.Address, proof [][]byte) (*Account, error) {
+	data, err, _ := trie.VerifyProof(root, thor.Blake2b(addr[:]).Bytes(), newProofReader(proof))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return emptyAccount(), nil
+	}
+	var a Account
+	if err := rlp.DecodeBytes(data, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// VerifyStorageProof verifies the storage proof against storage root of the account, and returns the proven raw value.
+func VerifyStorageProof(storageRoot thor.Bytes32, key thor.Bytes32, proof [][]byte) (rlp.RawValue, error) {
+	// account without storage
+	if storageRoot.IsZero() && len(proof) == 0 {
+		return nil, nil
+	}
+	data, err, _ := trie.VerifyProof(storageRoot, thor.Blake2b(key[:]).Bytes(), newProofReader(proof))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
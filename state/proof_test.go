@@ -0,0 +1,70 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestProve(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+
+	addr := thor.BytesToAddress([]byte("account1"))
+	key := thor.BytesToBytes32([]byte("key"))
+	value := thor.BytesToBytes32([]byte("value"))
+	for i := 0; i < 10; i++ {
+		state.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+		state.SetStorage(addr, thor.BytesToBytes32([]byte{byte(i)}), value)
+	}
+	state.SetBalance(addr, big.NewInt(100))
+	state.SetStorage(addr, key, value)
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+
+	ro, err := NewReadOnly(root, kv)
+	assert.Nil(t, err)
+
+	missingKey := thor.BytesToBytes32([]byte("missing"))
+	proof, err := ro.Prove(addr, []thor.Bytes32{key, missingKey})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), proof.Account.Balance)
+
+	acc, err := VerifyAccountProof(root, addr, proof.Proof)
+	assert.Nil(t, err)
+	assert.Equal(t, proof.Account, acc)
+
+	storageRoot := thor.BytesToBytes32(acc.StorageRoot)
+	raw, err := VerifyStorageProof(storageRoot, key, proof.StorageProofs[0].Proof)
+	assert.Nil(t, err)
+	assert.Equal(t, proof.StorageProofs[0].Raw, raw)
+	assert.Equal(t, value, proof.StorageProofs[0].Value)
+
+	raw, err = VerifyStorageProof(storageRoot, missingKey, proof.StorageProofs[1].Proof)
+	assert.Nil(t, err)
+	assert.Empty(t, raw, "proof of absence")
+	assert.Equal(t, thor.Bytes32{}, proof.StorageProofs[1].Value)
+
+	// proof doesn't match another root
+	_, err = VerifyAccountProof(thor.Blake2b([]byte("other")), addr, proof.Proof)
+	assert.NotNil(t, err)
+
+	// absent account
+	absent := thor.BytesToAddress([]byte("absent"))
+	proof, err = ro.Prove(absent, []thor.Bytes32{key})
+	assert.Nil(t, err)
+	acc, err = VerifyAccountProof(root, absent, proof.Proof)
+	assert.Nil(t, err)
+	assert.True(t, acc.IsEmpty())
+	raw, err = VerifyStorageProof(thor.Bytes32{}, key, proof.StorageProofs[0].Proof)
+	assert.Nil(t, err)
+	assert.Empty(t, raw)
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// prunedToKey stores the number of the last block whose state has been released.
+// Its presence means state roots are reference counted since the following block.
+var prunedToKey = []byte("state-pruned-to")
+
+var pruners = struct {
+	sync.Mutex
+	m map[kv.GetPutter]*Pruner
+}{m: make(map[kv.GetPutter]*Pruner)}
+
+// Pruner deletes trie nodes of states which fall out of the retention window.
+//
+// Once enabled on a store, every committed state root is referenced, and the root
+// of each trunk block is dereferenced when the block becomes older than the window.
+// States of side-chain blocks are never released, which leaks a little space.
+type Pruner struct {
+	kv     kv.GetPutter
+	lock   sync.Mutex // serializes commits and pruning, see Stage.Commit
+	retain uint32
+}
+
+// EnablePruning enables pruning on the store, which keeps states of the latest
+// retain blocks. best is the number of current best block.
+//
+// Pruning can only be enabled on a store holding no state but the genesis one, since
+// states committed before are not reference counted, and counting them all at once on
+// the next commit takes unbounded time and memory.
+func EnablePruning(kv kv.GetPutter, best uint32, retain uint32) (*Pruner, error) {
+	pruners.Lock()
+	defer pruners.Unlock()
+
+	if p, ok := pruners.m[kv]; ok {
+		return p, nil
+	}
+	has, err := kv.Has(prunedToKey)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		if best > 0 {
+			return nil, errors.New("archive states exist, pruning can only be enabled on a new database")
+		}
+		if err := savePrunedTo(kv, best); err != nil {
+			return nil, err
+		}
+	}
+	p := &Pruner{
+		kv:     kv,
+		retain: retain,
+	}
+	pruners.m[kv] = p
+	return p, nil
+}
+
+// DisablePruning switches the store back to archive mode, which is possible only if
+// no state has been released yet, since pruned states are never recovered.
+func DisablePruning(kv kv.GetPutter) error {
+	pruners.Lock()
+	defer pruners.Unlock()
+
+	prunedTo, err := loadPrunedTo(kv)
+	if err != nil {
+		if kv.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if prunedTo > 0 {
+		return errors.New("states already pruned, archive mode requires a new database")
+	}
+	delete(pruners.m, kv)
+	return kv.Delete(prunedToKey)
+}
+
+func getPruner(kv kv.GetPutter) *Pruner {
+	pruners.Lock()
+	defer pruners.Unlock()
+	return pruners.m[kv]
+}
+
+func loadPrunedTo(kv kv.Getter) (uint32, error) {
+	data, err := kv.Get(prunedToKey)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func savePrunedTo(kv kv.Putter, num uint32) error {
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], num)
+	return kv.Put(prunedToKey, data[:])
+}
+
+// Retain returns the number of latest blocks whose states are kept.
+func (p *Pruner) Retain() uint32 {
+	return p.retain
+}
+
+// PrunedTo returns the number of the last block whose state has been released.
+func (p *Pruner) PrunedTo() (uint32, error) {
+	return loadPrunedTo(p.kv)
+}
+
+// SafeHead returns the head to prune with, given numbers of the best and the finalized block.
+// Blocks after the finalized one may be dropped by reorgs, and states of their ancestors
+// are required to process competing branches, so the released states never pass the
+// finalized block. Before anything finalized, the retention window alone applies.
+func (p *Pruner) SafeHead(best, finalized uint32) uint32 {
+	if finalized == 0 {
+		return best
+	}
+	if safe := finalized + p.retain; safe < best {
+		return safe
+	}
+	return best
+}
+
+// Prune releases states of trunk blocks older than the retention window of head.
+// rootOf returns the state root of trunk block with given number.
+// It returns the count of released states.
+func (p *Pruner) Prune(head uint32, rootOf func(num uint32) (thor.Bytes32, error)) (int, error) {
+	if head <= p.retain {
+		return 0, nil
+	}
+	target := head - p.retain
+
+	prunedTo, err := p.PrunedTo()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for num := prunedTo + 1; num <= target; num++ {
+		root, err := rootOf(num)
+		if err != nil {
+			return n, err
+		}
+		if err := p.release(num, root); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// release dereferences the state root of block num. Nodes deleted, counters changed and
+// the progress are written in one batch, so that an interruption never leaves counters
+// inconsistent. The lock is held per block, to not block commits for long.
+func (p *Pruner) release(num uint32, root thor.Bytes32) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	batch := p.kv.NewBatch()
+	rc := NewRefCounter(trie.NewBatchDatabase(p.kv, batch))
+	count, err := rc.Count(root)
+	if err != nil {
+		return err
+	}
+	// untracked if committed by earlier versions, which referenced after writing commits,
+	// and interrupted in between
+	if count > 0 {
+		if err := rc.Dereference(root); err != nil {
+			return err
+		}
+	}
+	if err := savePrunedTo(batch, num); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// PrunedError is returned when the state of a block out of the retention window is requested.
+type PrunedError struct {
+	Num      uint32
+	PrunedTo uint32
+}
+
+func (e *PrunedError) Error() string {
+	return fmt.Sprintf("state of block %d is pruned, only states after block %d are retained", e.Num, e.PrunedTo)
+}
+
+// CheckPruned returns *PrunedError if the state of block num may have been released.
+// States committed before pruning enabled are treated as released, though nodes not shared
+// with later states are leaked, so that availability never depends on when pruning started.
+// It always passes in archive mode, where pruning is disabled.
+func CheckPruned(kv kv.Getter, num uint32) error {
+	prunedTo, err := loadPrunedTo(kv)
+	if err != nil {
+		if kv.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if num <= prunedTo {
+		return &PrunedError{num, prunedTo}
+	}
+	return nil
+}
+
+// IsPruned returns whether the error is caused by reading pruned state.
+func IsPruned(err error) bool {
+	switch err.(type) {
+	case *PrunedError, *trie.MissingNodeError:
+		return true
+	}
+	return false
+}
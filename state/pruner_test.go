@@ -0,0 +1,204 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestPruner(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	pruner, err := EnablePruning(kv, 0, 1)
+	assert.Nil(t, err)
+	defer DisablePruning(kv)
+
+	var roots []thor.Bytes32 // roots[i] is state of block i+1
+	root := thor.Bytes32{}
+	for i := 0; i < 4; i++ {
+		st, _ := New(root, kv)
+		if i == 0 {
+			for j := 0; j < 20; j++ {
+				st.SetBalance(thor.BytesToAddress([]byte{byte(j)}), big.NewInt(1))
+			}
+		} else {
+			st.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+		}
+		root, err = st.Stage().Commit()
+		assert.Nil(t, err)
+		roots = append(roots, root)
+	}
+	rootOf := func(num uint32) (thor.Bytes32, error) { return roots[num-1], nil }
+
+	n, err := pruner.Prune(1, rootOf)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = pruner.Prune(4, rootOf)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	prunedTo, _ := pruner.PrunedTo()
+	assert.Equal(t, uint32(3), prunedTo)
+
+	for _, r := range roots[:3] {
+		_, err := ComputeDigest(r, kv, 0)
+		assert.True(t, IsPruned(err))
+	}
+	d, err := ComputeDigest(roots[3], kv, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(20), d.Accounts)
+
+	err = CheckPruned(kv, 3)
+	assert.True(t, IsPruned(err))
+	assert.Equal(t, &PrunedError{3, 3}, err)
+	assert.Nil(t, CheckPruned(kv, 4))
+
+	// resumed from where it stopped
+	n, err = pruner.Prune(4, rootOf)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	// never back to archive once pruned
+	assert.NotNil(t, DisablePruning(kv))
+	assert.Equal(t, &PrunedError{3, 3}, CheckPruned(kv, 3))
+}
+
+func TestPrunerSafeHead(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	pruner, err := EnablePruning(kv, 0, 10)
+	assert.Nil(t, err)
+	defer DisablePruning(kv)
+
+	// nothing finalized
+	assert.Equal(t, uint32(100), pruner.SafeHead(100, 0))
+	// finalized far behind
+	assert.Equal(t, uint32(60), pruner.SafeHead(100, 50))
+	// finalized within the window
+	assert.Equal(t, uint32(100), pruner.SafeHead(100, 95))
+}
+
+func TestCheckPrunedArchive(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	assert.Nil(t, CheckPruned(kv, 0))
+
+	_, err := EnablePruning(kv, 0, 1)
+	assert.Nil(t, err)
+	assert.True(t, IsPruned(CheckPruned(kv, 0)))
+	assert.Nil(t, CheckPruned(kv, 1))
+
+	assert.Nil(t, DisablePruning(kv))
+	assert.Nil(t, CheckPruned(kv, 0))
+}
+
+func TestEnablePruningOnArchive(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	_, err := EnablePruning(kv, 10, 1)
+	assert.NotNil(t, err, "archive states exist")
+	has, _ := kv.Has(prunedToKey)
+	assert.False(t, has)
+
+	// resumed on a store already pruning
+	_, err = EnablePruning(kv, 0, 1)
+	assert.Nil(t, err)
+	pruners.Lock()
+	delete(pruners.m, kv)
+	pruners.Unlock()
+	_, err = EnablePruning(kv, 10, 1)
+	assert.Nil(t, err)
+}
+
+func TestPrunerStorageAndCode(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	pruner, err := EnablePruning(kv, 0, 1)
+	assert.Nil(t, err)
+	defer DisablePruning(kv)
+
+	contract := thor.BytesToAddress([]byte("contract"))
+	code := []byte{0x60, 0x01, 0x60, 0x00, 0x55}
+	slot := func(i int) thor.Bytes32 { return thor.BytesToBytes32([]byte{byte(i)}) }
+
+	var roots []thor.Bytes32 // roots[i] is state of block i+1
+	root := thor.Bytes32{}
+	for i := 0; i < 4; i++ {
+		st, _ := New(root, kv)
+		if i == 0 {
+			st.SetCode(contract, code)
+			for j := 0; j < 20; j++ {
+				st.SetStorage(contract, slot(j), thor.BytesToBytes32([]byte{1}))
+			}
+		} else {
+			st.SetStorage(contract, slot(i), thor.BytesToBytes32([]byte{byte(i + 1)}))
+		}
+		st.SetBalance(contract, big.NewInt(int64(i+1)))
+		root, err = st.Stage().Commit()
+		assert.Nil(t, err)
+		roots = append(roots, root)
+	}
+	rootOf := func(num uint32) (thor.Bytes32, error) { return roots[num-1], nil }
+
+	n, err := pruner.Prune(4, rootOf)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	for _, r := range roots[:3] {
+		_, err := ComputeDigest(r, kv, 0)
+		assert.True(t, IsPruned(err))
+	}
+
+	st, err := New(roots[3], kv)
+	assert.Nil(t, err)
+	assert.Equal(t, code, st.GetCode(contract))
+	assert.Equal(t, big.NewInt(4), st.GetBalance(contract))
+	for j := 0; j < 20; j++ {
+		want := thor.BytesToBytes32([]byte{1})
+		if j >= 1 && j <= 3 {
+			want = thor.BytesToBytes32([]byte{byte(j + 1)})
+		}
+		assert.Equal(t, want, st.GetStorage(contract, slot(j)))
+	}
+	assert.Nil(t, st.Err())
+}
+
+func TestPrunerKeepsCodeKeys(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	pruner, err := EnablePruning(kv, 0, 1)
+	assert.Nil(t, err)
+	defer DisablePruning(kv)
+
+	contract := thor.BytesToAddress([]byte("contract"))
+	st, _ := New(thor.Bytes32{}, kv)
+	st.SetBalance(contract, big.NewInt(1))
+	for j := 0; j < 20; j++ {
+		st.SetStorage(contract, thor.BytesToBytes32([]byte{byte(j)}), thor.BytesToBytes32([]byte{1}))
+	}
+	root1, err := st.Stage().Commit()
+	assert.Nil(t, err)
+	st, _ = New(root1, kv)
+	storageRoot := thor.BytesToBytes32(st.getAccount(contract).StorageRoot)
+
+	// pretend a code is stored under the key of the storage root node
+	assert.Nil(t, kv.Put(codeMarkKey(storageRoot[:]), nil))
+
+	st.SetStorage(contract, thor.BytesToBytes32([]byte{0}), thor.BytesToBytes32([]byte{2}))
+	root2, err := st.Stage().Commit()
+	assert.Nil(t, err)
+
+	roots := []thor.Bytes32{root1, root2}
+	n, err := pruner.Prune(2, func(num uint32) (thor.Bytes32, error) { return roots[num-1], nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	has, err := kv.Has(root1[:])
+	assert.Nil(t, err)
+	assert.False(t, has)
+	has, err = kv.Has(storageRoot[:])
+	assert.Nil(t, err)
+	assert.True(t, has)
+}
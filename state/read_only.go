@@ -0,0 +1,144 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// ReadOnly is a read-only view of accounts state, which is safe for concurrent use.
+//
+// Unlike State, it never touches the shared trie cache, and each read is served
+// by the flat snapshot or by its own copy of a private accounts trie. Nodes resolved by one read are not
+// retained, so readers neither block nor race with each other or with State
+// instances used by block processing.
+type ReadOnly struct {
+	root    thor.Bytes32
+	kv      kv.GetPutter
+	trie    *trie.SecureTrie // never accessed directly, only copied
+	indexed bool
+}
+
+// NewReadOnly creates a read-only state view.
+func NewReadOnly(root thor.Bytes32, kv kv.GetPutter) (*ReadOnly, error) {
+	tr, err := trie.NewSecure(root, kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadOnly{
+		root:    root,
+		kv:      kv,
+		trie:    tr,
+		indexed: accountIndexComplete(kv),
+	}, nil
+}
+
+// Root returns the state root of the view.
+func (r *ReadOnly) Root() thor.Bytes32 {
+	return r.root
+}
+
+// GetAccount returns the account at the given address.
+// An empty account is returned if there's no account.
+func (r *ReadOnly) GetAccount(addr thor.Address) (*Account, error) {
+	if r.indexed && !accountMayExist(r.kv, addr) {
+		return emptyAccount(), nil
+	}
+	if a, ok, err := getSnapshot(r.kv).Account(r.root, addr); ok || err != nil {
+		return a, err
+	}
+	return loadAccount(r.trie.Copy(), addr)
+}
+
+// GetBalance returns balance for the given address.
+func (r *ReadOnly) GetBalance(addr thor.Address) (*big.Int, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	return a.Balance, nil
+}
+
+// GetEnergy returns energy for the given address at block time specified.
+func (r *ReadOnly) GetEnergy(addr thor.Address, blockTime uint64) (*big.Int, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	return a.CalcEnergy(blockTime), nil
+}
+
+// GetMaster returns master for the given address.
+func (r *ReadOnly) GetMaster(addr thor.Address) (thor.Address, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return thor.Address{}, err
+	}
+	return thor.BytesToAddress(a.Master), nil
+}
+
+// GetCodeHash returns code hash for the given address.
+func (r *ReadOnly) GetCodeHash(addr thor.Address) (thor.Bytes32, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	return thor.BytesToBytes32(a.CodeHash), nil
+}
+
+// GetCode returns code for the given address.
+func (r *ReadOnly) GetCode(addr thor.Address) ([]byte, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.CodeHash) == 0 {
+		return nil, nil
+	}
+	return r.kv.Get(a.CodeHash)
+}
+
+// GetRawStorage returns storage value in rlp raw for the given address and key.
+func (r *ReadOnly) GetRawStorage(addr thor.Address, key thor.Bytes32) (rlp.RawValue, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.StorageRoot) == 0 {
+		return nil, nil
+	}
+	if v, ok, err := getSnapshot(r.kv).Storage(r.root, addr, key); ok || err != nil {
+		return v, err
+	}
+	strie, err := trie.NewSecure(thor.BytesToBytes32(a.StorageRoot), r.kv, 0)
+	if err != nil {
+		return nil, err
+	}
+	return loadStorage(strie, key)
+}
+
+// GetStorage returns storage value for the given address and key.
+func (r *ReadOnly) GetStorage(addr thor.Address, key thor.Bytes32) (thor.Bytes32, error) {
+	raw, err := r.GetRawStorage(addr, key)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	return decodeStorageValue(raw)
+}
+
+// Exists returns whether an account exists at the given address.
+func (r *ReadOnly) Exists(addr thor.Address) (bool, error) {
+	a, err := r.GetAccount(addr)
+	if err != nil {
+		return false, err
+	}
+	return !a.IsEmpty(), nil
+}
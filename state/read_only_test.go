@@ -0,0 +1,60 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestReadOnly(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+
+	addr := thor.BytesToAddress([]byte("account1"))
+	key := thor.BytesToBytes32([]byte("key"))
+	value := thor.BytesToBytes32([]byte("value"))
+
+	state.SetBalance(addr, big.NewInt(100))
+	state.SetCode(addr, []byte("code"))
+	state.SetStorage(addr, key, value)
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+
+	ro, err := NewReadOnly(root, kv)
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				balance, err := ro.GetBalance(addr)
+				assert.Nil(t, err)
+				assert.Equal(t, big.NewInt(100), balance)
+
+				code, err := ro.GetCode(addr)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("code"), code)
+
+				v, err := ro.GetStorage(addr, key)
+				assert.Nil(t, err)
+				assert.Equal(t, value, v)
+
+				exists, err := ro.Exists(thor.BytesToAddress([]byte("account2")))
+				assert.Nil(t, err)
+				assert.False(t, exists)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// codeMarkPrefix prefixes markers of contract codes, which are stored by hash
+// in the same key space as trie nodes.
+var codeMarkPrefix = []byte("code-mark-")
+
+func codeMarkKey(hash []byte) []byte {
+	return append(append([]byte(nil), codeMarkPrefix...), hash...)
+}
+
+// NewRefCounter creates a trie node reference counter for state roots.
+// Storage tries hung under accounts are counted as children of the account leaves,
+// so a storage trie shared by consecutive state roots is only freed with the last one.
+// Nodes whose keys are held by contract codes as well are freed without the keys deleted.
+// db is either the store, or a trie.BatchDatabase upon it to apply counting atomically.
+func NewRefCounter(db trie.RefCountDatabase) *trie.RefCounter {
+	return trie.NewRefCounter(db, accountStorageRoots, func(hash thor.Bytes32) (bool, error) {
+		return db.Has(codeMarkKey(hash[:]))
+	})
+}
+
+func accountStorageRoots(value []byte) ([]thor.Bytes32, error) {
+	var a Account
+	if err := rlp.DecodeBytes(value, &a); err != nil {
+		return nil, err
+	}
+	if len(a.StorageRoot) == 0 {
+		return nil, nil
+	}
+	return []thor.Bytes32{thor.BytesToBytes32(a.StorageRoot)}, nil
+}
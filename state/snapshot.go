@@ -0,0 +1,413 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+)
+
+// The snapshot is a flat copy of the accounts and storage of a single state root,
+// normally the head. Entries are keyed by the same hashed keys used in tries:
+//
+//	snap-a-<blake2b(addr)>                    -> rlp encoded account
+//	snap-s-<blake2b(addr)><blake2b(key)>      -> raw storage value
+//
+// A missing entry means empty. Reads at the snapshot root are served by a single
+// lookup, while other roots fall back to the tries.
+//
+// Entries live in one of two slots. Regeneration builds the new layer into the spare
+// slot, while the other keeps serving reads and commits until switched over. The snapshot
+// root is saved along with the slot in use, which is the first one if omitted.
+var (
+	snapshotRootKey = []byte("snap-root")
+	snapshotSlots   = [2]struct{ account, storage []byte }{
+		{[]byte("snap-a-"), []byte("snap-s-")},
+		{[]byte("snap-a1-"), []byte("snap-s1-")},
+	}
+)
+
+var snapshots = struct {
+	sync.Mutex
+	m map[kv.GetPutter]*snapshot
+}{m: make(map[kv.GetPutter]*snapshot)}
+
+type snapshot struct {
+	kv        kv.GetPutter
+	lock      sync.RWMutex // guards root, slot and flat entries in use against commits
+	root      thor.Bytes32 // zero if there's no usable snapshot
+	slot      int          // slot of entries in use
+	regenLock sync.Mutex   // one regeneration at a time, which owns the spare slot
+}
+
+// getSnapshot returns the snapshot of the given store. Stores which never opened a snapshot,
+// i.e. without the snapshot root saved, are not tracked, so that short-lived stores are not kept
+// alive by the registry. The untracked snapshot is never usable.
+func getSnapshot(kv kv.GetPutter) *snapshot {
+	return lookupSnapshot(kv, false)
+}
+
+// openSnapshot returns the tracked snapshot of the given store, to be advanced or regenerated.
+func openSnapshot(kv kv.GetPutter) *snapshot {
+	return lookupSnapshot(kv, true)
+}
+
+func lookupSnapshot(kv kv.GetPutter, open bool) *snapshot {
+	if _, ok := kv.(trieOnlyStore); ok {
+		// never usable, and not tracked since such stores are short-lived
+		return &snapshot{kv: kv}
+	}
+	snapshots.Lock()
+	defer snapshots.Unlock()
+
+	if snap, ok := snapshots.m[kv]; ok {
+		return snap
+	}
+	snap := &snapshot{kv: kv}
+	if data, err := kv.Get(snapshotRootKey); err == nil {
+		n := len(snap.root)
+		snap.root = thor.BytesToBytes32(data[:n])
+		if len(data) > n {
+			snap.slot = int(data[n])
+		}
+	} else if !open {
+		return snap
+	}
+	snapshots.m[kv] = snap
+	return snap
+}
+
+func encodeSnapshotRoot(root thor.Bytes32, slot int) []byte {
+	return append(append([]byte(nil), root[:]...), byte(slot))
+}
+
+func snapshotAccountKey(slot int, addrHash thor.Bytes32) []byte {
+	return append(append([]byte(nil), snapshotSlots[slot].account...), addrHash[:]...)
+}
+
+func snapshotStorageKey(slot int, addrHash, keyHash thor.Bytes32) []byte {
+	return append(append(append([]byte(nil), snapshotSlots[slot].storage...), addrHash[:]...), keyHash[:]...)
+}
+
+// Root returns the state root the snapshot reflects.
+func (s *snapshot) Root() thor.Bytes32 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.root
+}
+
+// get reads a flat entry keyed in the slot in use. ok is false if the snapshot is not at root.
+func (s *snapshot) get(root thor.Bytes32, key func(slot int) []byte) (data []byte, ok bool, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if root.IsZero() || root != s.root {
+		return nil, false, nil
+	}
+	data, err = s.kv.Get(key(s.slot))
+	if err != nil {
+		if s.kv.IsNotFound(err) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Account returns the account at root from the flat layer.
+func (s *snapshot) Account(root thor.Bytes32, addr thor.Address) (*Account, bool, error) {
+	addrHash := thor.Blake2b(addr[:])
+	data, ok, err := s.get(root, func(slot int) []byte {
+		return snapshotAccountKey(slot, addrHash)
+	})
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	if len(data) == 0 {
+		return emptyAccount(), true, nil
+	}
+	var a Account
+	if err := rlp.DecodeBytes(data, &a); err != nil {
+		return nil, false, err
+	}
+	return &a, true, nil
+}
+
+// Storage returns the raw storage value at root from the flat layer.
+func (s *snapshot) Storage(root thor.Bytes32, addr thor.Address, key thor.Bytes32) (rlp.RawValue, bool, error) {
+	addrHash, keyHash := thor.Blake2b(addr[:]), thor.Blake2b(key[:])
+	data, ok, err := s.get(root, func(slot int) []byte {
+		return snapshotStorageKey(slot, addrHash, keyHash)
+	})
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	return data, true, nil
+}
+
+// snapshotChange is the final change of an account to be applied to the flat layer.
+type snapshotChange struct {
+	addrHash    thor.Bytes32
+	data        Account
+	wipeStorage bool                          // storage was cleared, e.g. account deleted
+	storage     map[thor.Bytes32]rlp.RawValue // keyed by hashed keys
+}
+
+// apply writes changes into batch, which advances the snapshot to root once written.
+// The caller must hold the write lock.
+func (s *snapshot) apply(batch kv.Putter, root thor.Bytes32, changes []snapshotChange) error {
+	for _, c := range changes {
+		addrHash := c.addrHash
+		if c.wipeStorage {
+			if err := s.wipeStorage(batch, addrHash); err != nil {
+				return err
+			}
+		}
+		if c.data.IsEmpty() {
+			// storage changes of empty accounts are discarded
+			if err := batch.Delete(snapshotAccountKey(s.slot, addrHash)); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := rlp.EncodeToBytes(&c.data)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(snapshotAccountKey(s.slot, addrHash), data); err != nil {
+			return err
+		}
+		for k, v := range c.storage {
+			key := snapshotStorageKey(s.slot, addrHash, k)
+			if len(v) == 0 {
+				err = batch.Delete(key)
+			} else {
+				err = batch.Put(key, v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return batch.Put(snapshotRootKey, encodeSnapshotRoot(root, s.slot))
+}
+
+func (s *snapshot) wipeStorage(batch kv.Putter, addrHash thor.Bytes32) error {
+	it := s.kv.NewIterator(*kv.NewRangeWithBytesPrefix(append(append([]byte(nil), snapshotSlots[s.slot].storage...), addrHash[:]...)))
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(append([]byte(nil), it.Key()...)); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// Reanchor moves the flat layer to root by applying changes between the snapshot root and root,
+// which is cheap as long as they are close, e.g. the head moved onto a side block. It returns false
+// if there's no usable snapshot to start from, i.e. it's missing or its root state incomplete.
+func (s *snapshot) Reanchor(root thor.Bytes32) (bool, error) {
+	from := s.Root()
+	if from.IsZero() {
+		return false, nil
+	}
+	if from == root {
+		return true, nil
+	}
+	diffs, err := Diff(s.kv, from, root)
+	if err != nil {
+		if _, missing := err.(*trie.MissingNodeError); missing {
+			return false, nil
+		}
+		return false, err
+	}
+	changes := make([]snapshotChange, 0, len(diffs))
+	for _, d := range diffs {
+		c := snapshotChange{
+			addrHash:    d.AddressHash,
+			wipeStorage: d.To == nil || len(d.To.StorageRoot) == 0,
+			storage:     make(map[thor.Bytes32]rlp.RawValue, len(d.Storage)),
+		}
+		if d.To != nil {
+			c.data = *d.To
+		} else {
+			c.data = *emptyAccount()
+		}
+		for _, sd := range d.Storage {
+			c.storage[sd.KeyHash] = sd.To
+		}
+		changes = append(changes, c)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.root != from {
+		// moved by commits meanwhile
+		return true, nil
+	}
+	batch := s.kv.NewBatch()
+	if err := s.apply(batch, root, changes); err != nil {
+		return false, err
+	}
+	if err := batch.Write(); err != nil {
+		return false, err
+	}
+	s.root = root
+	return true, nil
+}
+
+// Regenerate rebuilds the flat layer from the tries at root. It is an expensive operation,
+// which is needed when there's no usable snapshot. The new layer is built into the spare slot
+// without blocking reads and commits, and switched over at last. It's aborted once ctx is done.
+func (s *snapshot) Regenerate(ctx context.Context, root thor.Bytes32) error {
+	s.regenLock.Lock()
+	defer s.regenLock.Unlock()
+
+	s.lock.RLock()
+	slot := 1 - s.slot
+	s.lock.RUnlock()
+
+	// clear leftovers of the previous layer or an interrupted regeneration
+	if err := s.wipeSlot(ctx, slot); err != nil {
+		return err
+	}
+
+	w := newSnapshotWriter(s.kv)
+	accTrie, err := trie.NewSecure(root, s.kv, 0)
+	if err != nil {
+		return err
+	}
+	accIt := trie.NewIterator(accTrie.NodeIterator(nil))
+	for accIt.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		addrHash := thor.BytesToBytes32(accIt.Key)
+		if err := w.put(snapshotAccountKey(slot, addrHash), accIt.Value); err != nil {
+			return err
+		}
+		var a Account
+		if err := rlp.DecodeBytes(accIt.Value, &a); err != nil {
+			return err
+		}
+		if len(a.StorageRoot) == 0 {
+			continue
+		}
+		strie, err := trie.NewSecure(thor.BytesToBytes32(a.StorageRoot), s.kv, 0)
+		if err != nil {
+			return err
+		}
+		stoIt := trie.NewIterator(strie.NodeIterator(nil))
+		for stoIt.Next() {
+			if err := w.put(snapshotStorageKey(slot, addrHash, thor.BytesToBytes32(stoIt.Key)), stoIt.Value); err != nil {
+				return err
+			}
+		}
+		if stoIt.Err != nil {
+			return stoIt.Err
+		}
+	}
+	if accIt.Err != nil {
+		return accIt.Err
+	}
+	if err := w.batch.Write(); err != nil {
+		return err
+	}
+
+	// switch over, commits meanwhile advanced the previous layer only
+	s.lock.Lock()
+	if err := s.kv.Put(snapshotRootKey, encodeSnapshotRoot(root, slot)); err != nil {
+		s.lock.Unlock()
+		return err
+	}
+	s.root, s.slot = root, slot
+	s.lock.Unlock()
+
+	return s.wipeSlot(ctx, 1-slot)
+}
+
+// wipeSlot deletes all entries in the slot, which must not be in use.
+func (s *snapshot) wipeSlot(ctx context.Context, slot int) error {
+	w := newSnapshotWriter(s.kv)
+	for _, prefix := range [][]byte{snapshotSlots[slot].account, snapshotSlots[slot].storage} {
+		it := s.kv.NewIterator(*kv.NewRangeWithBytesPrefix(prefix))
+		for it.Next() {
+			if err := ctx.Err(); err != nil {
+				it.Release()
+				return err
+			}
+			if err := w.delete(append([]byte(nil), it.Key()...)); err != nil {
+				it.Release()
+				return err
+			}
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+	}
+	return w.batch.Write()
+}
+
+// snapshotWriter writes entries through batches, which are flushed once large enough
+// to bound memory usage.
+type snapshotWriter struct {
+	kv    kv.GetPutter
+	batch kv.Batch
+}
+
+func newSnapshotWriter(kv kv.GetPutter) *snapshotWriter {
+	return &snapshotWriter{kv, kv.NewBatch()}
+}
+
+func (w *snapshotWriter) put(key, value []byte) error {
+	if err := w.batch.Put(key, value); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+func (w *snapshotWriter) delete(key []byte) error {
+	if err := w.batch.Delete(key); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+func (w *snapshotWriter) flush() error {
+	if w.batch.Len() < 4096 {
+		return nil
+	}
+	if err := w.batch.Write(); err != nil {
+		return err
+	}
+	w.batch = w.kv.NewBatch()
+	return nil
+}
+
+// ReanchorSnapshot moves the flat state snapshot of the store to the given root cheaply, by applying
+// changes since the snapshot root. It returns false if there's no usable snapshot, which needs
+// to be regenerated.
+func ReanchorSnapshot(kv kv.GetPutter, root thor.Bytes32) (bool, error) {
+	return getSnapshot(kv).Reanchor(root)
+}
+
+// RegenerateSnapshot rebuilds the flat state snapshot of the store at the given root.
+// Reads and commits go on meanwhile, and it's aborted once ctx is done.
+func RegenerateSnapshot(ctx context.Context, kv kv.GetPutter, root thor.Bytes32) error {
+	return openSnapshot(kv).Regenerate(ctx, root)
+}
+
+// SnapshotRoot returns the state root the flat snapshot of the store reflects.
+// Zero is returned if there's no usable snapshot.
+func SnapshotRoot(kv kv.GetPutter) thor.Bytes32 {
+	return getSnapshot(kv).Root()
+}
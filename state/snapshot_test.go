@@ -0,0 +1,193 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestSnapshot(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	addr1 := thor.BytesToAddress([]byte("account1"))
+	addr2 := thor.BytesToAddress([]byte("account2"))
+	key := thor.BytesToBytes32([]byte("key"))
+	value := thor.BytesToBytes32([]byte("value"))
+
+	state, _ := New(thor.Bytes32{}, kv)
+	state.SetBalance(addr1, big.NewInt(1))
+	state.SetBalance(addr2, big.NewInt(2))
+	state.SetStorage(addr2, key, value)
+	root1, err := state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, root1, SnapshotRoot(kv), "snapshot should start from genesis")
+
+	acc, ok, err := getSnapshot(kv).Account(root1, addr2)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(2), acc.Balance)
+
+	state, _ = New(root1, kv)
+	state.SetBalance(addr1, big.NewInt(10))
+	state.Delete(addr2)
+	root2, err := state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, root2, SnapshotRoot(kv), "snapshot should follow the head")
+
+	_, ok, _ = getSnapshot(kv).Account(root1, addr1)
+	assert.False(t, ok, "historical reads should fall back to tries")
+
+	v, ok, err := getSnapshot(kv).Storage(root2, addr2, key)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, v, "storage of deleted account should be wiped")
+
+	// both paths give the same result
+	for _, root := range []thor.Bytes32{root1, root2} {
+		state, _ := New(root, kv)
+		ro, _ := NewReadOnly(root, kv)
+		for _, addr := range []thor.Address{addr1, addr2} {
+			balance, err := ro.GetBalance(addr)
+			assert.Nil(t, err)
+			assert.Equal(t, state.GetBalance(addr), balance)
+
+			v, err := ro.GetStorage(addr, key)
+			assert.Nil(t, err)
+			assert.Equal(t, state.GetStorage(addr, key), v)
+		}
+	}
+	state, _ = New(root1, kv)
+	assert.Equal(t, value, state.GetStorage(addr2, key))
+
+	// a commit not based on the snapshot root leaves it untouched
+	state, _ = New(root1, kv)
+	state.SetBalance(addr1, big.NewInt(100))
+	_, err = state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, root2, SnapshotRoot(kv))
+
+	// an aborted regeneration leaves the snapshot in use untouched
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, RegenerateSnapshot(ctx, kv, root1))
+	assert.Equal(t, root2, SnapshotRoot(kv))
+	acc, ok, err = getSnapshot(kv).Account(root2, addr1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(10), acc.Balance)
+
+	assert.Nil(t, RegenerateSnapshot(context.Background(), kv, root1))
+	assert.Equal(t, root1, SnapshotRoot(kv))
+	v, ok, err = getSnapshot(kv).Storage(root1, addr2, key)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	stateValue, _ := decodeStorageValue(v)
+	assert.Equal(t, value, stateValue)
+
+	// entries of the previous layer are wiped after switched over
+	has, _ := kv.Has(snapshotAccountKey(0, thor.Blake2b(addr1[:])))
+	assert.False(t, has)
+	has, _ = kv.Has(snapshotAccountKey(1, thor.Blake2b(addr1[:])))
+	assert.True(t, has)
+
+	// the slot in use survives reopening
+	snapshots.Lock()
+	delete(snapshots.m, kv)
+	snapshots.Unlock()
+	acc, ok, err = getSnapshot(kv).Account(root1, addr2)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(2), acc.Balance)
+}
+
+func TestSnapshotTracking(t *testing.T) {
+	tracked := func(kv *lvldb.LevelDB) bool {
+		snapshots.Lock()
+		defer snapshots.Unlock()
+		_, ok := snapshots.m[kv]
+		return ok
+	}
+
+	// a store without snapshot
+	kv, _ := lvldb.NewMem()
+	assert.True(t, getSnapshot(kv).Root().IsZero())
+	assert.True(t, SnapshotRoot(kv).IsZero())
+	assert.False(t, tracked(kv), "never opened")
+
+	// a fresh store opens the snapshot on first commit
+	state, _ := New(thor.Bytes32{}, kv)
+	state.SetBalance(thor.BytesToAddress([]byte("account1")), big.NewInt(1))
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.True(t, tracked(kv))
+	assert.Equal(t, root, SnapshotRoot(kv))
+
+	// regenerating opens the snapshot as well
+	kv2, _ := lvldb.NewMem()
+	assert.Nil(t, RegenerateSnapshot(context.Background(), kv2, thor.Bytes32{}))
+	assert.True(t, tracked(kv2))
+}
+
+func TestReanchorSnapshot(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	addr1 := thor.BytesToAddress([]byte("account1"))
+	addr2 := thor.BytesToAddress([]byte("account2"))
+	key := thor.BytesToBytes32([]byte("key"))
+	value := thor.BytesToBytes32([]byte("value"))
+
+	// no snapshot yet
+	ok, err := ReanchorSnapshot(kv, thor.Bytes32{})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	state, _ := New(thor.Bytes32{}, kv)
+	state.SetBalance(addr1, big.NewInt(1))
+	state.SetStorage(addr1, key, value)
+	root1, _ := state.Stage().Commit()
+
+	// a side block moves the snapshot off the trunk
+	state, _ = New(root1, kv)
+	state.SetBalance(addr1, big.NewInt(10))
+	state.SetStorage(addr1, key, thor.Bytes32{})
+	state.SetBalance(addr2, big.NewInt(2))
+	side, _ := state.Stage().Commit()
+	assert.Equal(t, side, SnapshotRoot(kv))
+
+	state, _ = New(root1, kv)
+	state.SetBalance(addr1, big.NewInt(100))
+	trunk, _ := state.Stage().Commit()
+	assert.Equal(t, side, SnapshotRoot(kv))
+
+	ok, err = ReanchorSnapshot(kv, trunk)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, trunk, SnapshotRoot(kv))
+
+	acc, ok, err := getSnapshot(kv).Account(trunk, addr1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(100), acc.Balance)
+	acc, ok, _ = getSnapshot(kv).Account(trunk, addr2)
+	assert.True(t, ok)
+	assert.True(t, acc.IsEmpty(), "account of side block removed")
+	v, ok, _ := getSnapshot(kv).Storage(trunk, addr1, key)
+	assert.True(t, ok)
+	stateValue, _ := decodeStorageValue(v)
+	assert.Equal(t, value, stateValue, "storage restored")
+
+	// unusable if the snapshot root state is incomplete
+	assert.Nil(t, kv.Delete(trunk[:]))
+	ok, err = ReanchorSnapshot(kv, root1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
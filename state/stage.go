@@ -6,6 +6,8 @@
 package state
 
 import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/kv"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/trie"
@@ -16,9 +18,13 @@ type Stage struct {
 	err error
 
 	kv           kv.GetPutter
+	base         thor.Bytes32
 	accountTrie  *trie.SecureTrie
 	storageTries []*trie.SecureTrie
 	codes        []codeWithHash
+	touched      []thor.Address // non-empty accounts to be put into the account index
+	snapChanges  []snapshotChange
+	preimages    bool // whether to record preimages of hashed keys
 }
 
 type codeWithHash struct {
@@ -35,42 +41,96 @@ func newStage(root thor.Bytes32, kv kv.GetPutter, changes map[thor.Address]*chan
 
 	storageTries := make([]*trie.SecureTrie, 0, len(changes))
 	codes := make([]codeWithHash, 0, len(changes))
+	touched := make([]thor.Address, 0, len(changes))
+	snapChanges := make([]snapshotChange, 0, len(changes))
+
+	type accountChange struct {
+		addr  thor.Address
+		obj   *changedObject
+		data  Account
+		strie *trie.SecureTrie
+		err   error
+	}
+	accChanges := make([]*accountChange, 0, len(changes))
+	var storageChanges []*accountChange
 
 	for addr, obj := range changes {
-		dataCpy := obj.data
+		c := &accountChange{addr: addr, obj: obj, data: obj.data}
+		accChanges = append(accChanges, c)
 
 		if len(obj.code) > 0 {
 			codes = append(codes, codeWithHash{
 				code: obj.code,
-				hash: dataCpy.CodeHash})
+				hash: c.data.CodeHash})
 		}
 
 		// skip storage changes if account is empty
-		if !dataCpy.IsEmpty() {
+		if !c.data.IsEmpty() {
+			touched = append(touched, addr)
 			if len(obj.storage) > 0 {
-				strie, err := trCache.Get(thor.BytesToBytes32(dataCpy.StorageRoot), kv, true)
-				if err != nil {
-					return &Stage{err: err}
-				}
-				storageTries = append(storageTries, strie)
-				for k, v := range obj.storage {
-					if err := saveStorage(strie, k, v); err != nil {
-						return &Stage{err: err}
-					}
-				}
-				dataCpy.StorageRoot = strie.Hash().Bytes()
+				storageChanges = append(storageChanges, c)
+			}
+		}
+	}
+
+	// storage tries are independent, so updated and hashed in parallel
+	for _, c := range storageChanges {
+		strie, err := trCache.Get(thor.BytesToBytes32(c.data.StorageRoot), kv, true)
+		if err != nil {
+			return &Stage{err: err}
+		}
+		c.strie = strie
+		storageTries = append(storageTries, strie)
+	}
+	updateStorage := func(c *accountChange) {
+		for k, v := range c.obj.storage {
+			if err := saveStorage(c.strie, k, v); err != nil {
+				c.err = err
+				return
+			}
+		}
+		c.data.StorageRoot = c.strie.Hash().Bytes()
+	}
+	if len(storageChanges) > 1 {
+		<-co.Parallel(func(queue chan<- func()) {
+			for _, c := range storageChanges {
+				c := c
+				queue <- func() { updateStorage(c) }
 			}
+		})
+	} else {
+		for _, c := range storageChanges {
+			updateStorage(c)
 		}
+	}
 
-		if err := saveAccount(accountTrie, addr, &dataCpy); err != nil {
+	for _, c := range accChanges {
+		if c.err != nil {
+			return &Stage{err: c.err}
+		}
+		if err := saveAccount(accountTrie, c.addr, &c.data); err != nil {
 			return &Stage{err: err}
 		}
+		storage := make(map[thor.Bytes32]rlp.RawValue, len(c.obj.storage))
+		for k, v := range c.obj.storage {
+			storage[thor.Blake2b(k[:])] = v
+		}
+		snapChanges = append(snapChanges, snapshotChange{
+			addrHash:    thor.Blake2b(c.addr[:]),
+			data:        c.data,
+			wipeStorage: c.data.IsEmpty() || len(c.obj.data.StorageRoot) == 0,
+			storage:     storage,
+		})
 	}
 	return &Stage{
 		kv:           kv,
+		base:         root,
 		accountTrie:  accountTrie,
 		storageTries: storageTries,
 		codes:        codes,
+		touched:      touched,
+		snapChanges:  snapChanges,
+		preimages:    recordsPreimages(kv),
 	}
 }
 
@@ -79,7 +139,7 @@ func (s *Stage) Hash() (thor.Bytes32, error) {
 	if s.err != nil {
 		return thor.Bytes32{}, s.err
 	}
-	return s.accountTrie.Hash(), nil
+	return s.accountTrie.HashParallel(), nil
 }
 
 // Commit commits all changes into main accounts trie and storage tries.
@@ -88,33 +148,103 @@ func (s *Stage) Commit() (thor.Bytes32, error) {
 		return thor.Bytes32{}, s.err
 	}
 	batch := s.kv.NewBatch()
-	// write codes
+	// with pruning enabled, trie nodes are written through the batch database, so that
+	// they can be referenced in the same batch, and never persisted untracked
+	pruner := getPruner(s.kv)
+	var w trie.BatchWriter = batch
+	var bdb *trie.BatchDatabase
+	if pruner != nil {
+		bdb = trie.NewBatchDatabase(s.kv, batch)
+		w = bdb
+	}
+
+	// write codes, marked to be never deleted along with trie nodes of the same hash
 	for _, code := range s.codes {
 		if err := batch.Put(code.hash, code.code); err != nil {
 			return thor.Bytes32{}, err
 		}
+		if err := batch.Put(codeMarkKey(code.hash), nil); err != nil {
+			return thor.Bytes32{}, err
+		}
 	}
 
 	// commit storage tries
 	for _, strie := range s.storageTries {
-		root, err := strie.CommitTo(batch)
+		root, err := s.commitTrie(strie, w)
 		if err != nil {
 			return thor.Bytes32{}, err
 		}
 		trCache.Add(root, strie, s.kv)
 	}
 
+	// write account index
+	for _, addr := range s.touched {
+		if err := batch.Put(accountIndexKey(addr), nil); err != nil {
+			return thor.Bytes32{}, err
+		}
+	}
+
 	// commit accounts trie
-	root, err := s.accountTrie.CommitTo(batch)
+	root, err := s.commitTrie(s.accountTrie, w)
 	if err != nil {
 		return thor.Bytes32{}, err
 	}
 
+	// a brand new store is indexed from the very first state, which is the genesis
+	// state based on the zero root, while a store already holding it predates the index
+	fresh := false
+	if s.base.IsZero() && !accountIndexComplete(s.kv) {
+		existed, err := s.kv.Has(root[:])
+		if err != nil {
+			return thor.Bytes32{}, err
+		}
+		fresh = !existed
+	}
+	if fresh {
+		if err := batch.Put(accountIndexCompleteKey, nil); err != nil {
+			return thor.Bytes32{}, err
+		}
+	}
+
+	// advance the flat snapshot if it's at the base state, and a fresh store opens one
+	snap := getSnapshot(s.kv)
+	if fresh {
+		snap = openSnapshot(s.kv)
+	}
+	snap.lock.Lock()
+	defer snap.lock.Unlock()
+
+	advance := fresh || (!snap.root.IsZero() && snap.root == s.base)
+	if advance {
+		if err := snap.apply(batch, root, s.snapChanges); err != nil {
+			return thor.Bytes32{}, err
+		}
+	}
+
+	// with pruning enabled, the root must be referenced before any pruning, or nodes
+	// shared with pruned states could be deleted right after written
+	if pruner != nil {
+		pruner.lock.Lock()
+		defer pruner.lock.Unlock()
+		if err := NewRefCounter(bdb).Reference(root); err != nil {
+			return thor.Bytes32{}, err
+		}
+	}
 	if err := batch.Write(); err != nil {
 		return thor.Bytes32{}, err
 	}
+	if advance {
+		snap.root = root
+	}
 
 	trCache.Add(root, s.accountTrie, s.kv)
 
 	return root, nil
 }
+
+func (s *Stage) commitTrie(t *trie.SecureTrie, w trie.DatabaseWriter) (thor.Bytes32, error) {
+	if s.preimages {
+		return t.CommitTo(w)
+	}
+	return t.CommitNodesTo(w)
+}
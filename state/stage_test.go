@@ -53,3 +53,31 @@ func TestStage(t *testing.T) {
 		assert.Equal(t, v, state.GetStorage(addr, k))
 	}
 }
+
+func TestStageMultipleStorage(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+
+	for i := 0; i < 10; i++ {
+		addr := thor.BytesToAddress([]byte{byte(i)})
+		state.SetBalance(addr, big.NewInt(int64(i+1)))
+		for j := 0; j < 10; j++ {
+			state.SetStorage(addr, thor.BytesToBytes32([]byte{byte(j)}), thor.BytesToBytes32([]byte{byte(i), byte(j)}))
+		}
+	}
+
+	hash, err := state.Stage().Hash()
+	assert.Nil(t, err)
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, hash, root)
+
+	state, _ = New(root, kv)
+	for i := 0; i < 10; i++ {
+		addr := thor.BytesToAddress([]byte{byte(i)})
+		assert.Equal(t, big.NewInt(int64(i+1)), state.GetBalance(addr))
+		for j := 0; j < 10; j++ {
+			assert.Equal(t, thor.BytesToBytes32([]byte{byte(i), byte(j)}), state.GetStorage(addr, thor.BytesToBytes32([]byte{byte(j)})))
+		}
+	}
+}
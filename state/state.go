@@ -25,6 +25,7 @@ type State struct {
 	trie     trieReader                     // the accounts trie reader
 	cache    map[thor.Address]*cachedObject // cache of accounts trie
 	sm       *stackedmap.StackedMap         // keeps revisions of accounts state
+	indexed  bool                           // whether the account index can be trusted
 	err      error
 	setError func(err error)
 }
@@ -48,10 +49,11 @@ func New(root thor.Bytes32, kv kv.GetPutter) (*State, error) {
 	}
 
 	state := State{
-		root:  root,
-		kv:    kv,
-		trie:  trie,
-		cache: make(map[thor.Address]*cachedObject),
+		root:    root,
+		kv:      kv,
+		trie:    trie,
+		cache:   make(map[thor.Address]*cachedObject),
+		indexed: accountIndexComplete(kv),
 	}
 	state.setError = func(err error) {
 		if state.err == nil {
@@ -138,12 +140,30 @@ func (s *State) getCachedObject(addr thor.Address) *cachedObject {
 	if co, ok := s.cache[addr]; ok {
 		return co
 	}
-	a, err := loadAccount(s.trie, addr)
-	if err != nil {
-		s.setError(err)
-		return newCachedObject(s.kv, emptyAccount())
+	if s.indexed && !accountMayExist(s.kv, addr) {
+		// never touched, skip the trie descent
+		co := newCachedObject(s.kv, emptyAccount())
+		s.cache[addr] = co
+		return co
+	}
+	snap := getSnapshot(s.kv)
+	a, ok := accCache.GetAccount(s.root, addr, s.kv)
+	if !ok {
+		var err error
+		a, ok, err = snap.Account(s.root, addr)
+		if err == nil && !ok {
+			a, err = loadAccount(s.trie, addr)
+		}
+		if err != nil {
+			s.setError(err)
+			return newCachedObject(s.kv, emptyAccount())
+		}
+		accCache.AddAccount(s.root, addr, a, s.kv)
 	}
 	co := newCachedObject(s.kv, a)
+	co.flatStorage = func(key thor.Bytes32) (rlp.RawValue, bool, error) {
+		return snap.Storage(s.root, addr, key)
+	}
 	s.cache[addr] = co
 	return co
 }
@@ -257,21 +277,27 @@ func (s *State) SetMaster(addr thor.Address, master thor.Address) {
 
 // GetStorage returns storage value for the given address and key.
 func (s *State) GetStorage(addr thor.Address, key thor.Bytes32) thor.Bytes32 {
-	raw := s.GetRawStorage(addr, key)
+	v, err := decodeStorageValue(s.GetRawStorage(addr, key))
+	if err != nil {
+		s.setError(err)
+	}
+	return v
+}
+
+func decodeStorageValue(raw rlp.RawValue) (thor.Bytes32, error) {
 	if len(raw) == 0 {
-		return thor.Bytes32{}
+		return thor.Bytes32{}, nil
 	}
 	kind, content, _, err := rlp.Split(raw)
 	if err != nil {
-		s.setError(err)
-		return thor.Bytes32{}
+		return thor.Bytes32{}, err
 	}
 	if kind == rlp.List {
 		// special case for rlp list, it should be customized storage value
 		// return hash of raw data
-		return thor.Blake2b(raw)
+		return thor.Blake2b(raw), nil
 	}
-	return thor.BytesToBytes32(content)
+	return thor.BytesToBytes32(content), nil
 }
 
 // SetStorage set storage value for the given address and key.
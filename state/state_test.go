@@ -149,3 +149,31 @@ func TestStorage(t *testing.T) {
 
 	assert.Equal(t, thor.Blake2b(data), st.GetStorage(addr, key))
 }
+
+func TestAccountIndex(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	addr := thor.BytesToAddress([]byte("account1"))
+
+	state, _ := New(thor.Bytes32{}, kv)
+	state.SetBalance(addr, big.NewInt(1))
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.True(t, accountIndexComplete(kv))
+	assert.True(t, accountMayExist(kv, addr))
+	assert.False(t, accountMayExist(kv, thor.BytesToAddress([]byte("account2"))))
+
+	state, _ = New(root, kv)
+	assert.True(t, state.indexed)
+	assert.Equal(t, big.NewInt(1), state.GetBalance(addr))
+	assert.False(t, state.Exists(thor.BytesToAddress([]byte("account2"))))
+
+	// rebuilding an already stored state must not mark a legacy store as indexed
+	legacy, _ := lvldb.NewMem()
+	state, _ = New(thor.Bytes32{}, legacy)
+	state.SetBalance(addr, big.NewInt(1))
+	enc, _ := kv.Get(root[:])
+	legacy.Put(root[:], enc)
+	_, err = state.Stage().Commit()
+	assert.Nil(t, err)
+	assert.False(t, accountIndexComplete(legacy))
+}
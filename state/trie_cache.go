@@ -40,7 +40,7 @@ func (tc *trieCache) Get(root thor.Bytes32, kv kv.GetPutter, copy bool) (*trie.S
 			return entry.trie, nil
 		}
 	}
-	tr, err := trie.NewSecure(root, kv, 16)
+	tr, err := trie.NewSecure(root, trieDatabase(kv), 16)
 	if err != nil {
 		return nil, err
 	}
@@ -54,3 +54,23 @@ func (tc *trieCache) Get(root thor.Bytes32, kv kv.GetPutter, copy bool) (*trie.S
 func (tc *trieCache) Add(root thor.Bytes32, trie *trie.SecureTrie, kv kv.GetPutter) {
 	tc.cache.Add(root, &trieCacheEntry{trie.Copy(), kv})
 }
+
+var nodeCache struct {
+	cache *trie.NodeCache
+	kv    kv.GetPutter
+}
+
+// SetNodeCache sets up the cache of trie nodes read from kv, shared by states created upon it.
+// It should be called before any state created.
+func SetNodeCache(kv kv.GetPutter, maxBytes int) {
+	nodeCache.cache = trie.NewNodeCache(maxBytes)
+	nodeCache.kv = kv
+}
+
+// trieDatabase returns the database for tries of states, with nodes cached if set up for kv.
+func trieDatabase(kv kv.GetPutter) trie.Database {
+	if nodeCache.cache != nil && nodeCache.kv == kv {
+		return nodeCache.cache.Wrap(kv)
+	}
+	return kv
+}
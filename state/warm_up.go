@@ -0,0 +1,33 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+// WarmUp loads nodes of the account trie at root, down to the given depth in nibbles,
+// so that early reads don't pay for cold storage. The trie is also put into the trie cache.
+// It returns count of nodes loaded.
+func WarmUp(root thor.Bytes32, kv kv.GetPutter, depth int) (int, error) {
+	trie, err := trCache.Get(root, kv, false)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		it    = trie.NodeIterator(nil)
+		count = 0
+	)
+	// descend only while above the depth
+	for descend := true; it.Next(descend); {
+		if !it.Hash().IsZero() {
+			count++
+		}
+		descend = len(it.Path()) < depth
+	}
+	return count, it.Error()
+}
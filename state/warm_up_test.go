@@ -0,0 +1,38 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestWarmUp(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+	for i := 0; i < 100; i++ {
+		state.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(1))
+	}
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+
+	n0, err := WarmUp(root, kv, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n0, "root only")
+
+	n1, err := WarmUp(root, kv, 1)
+	assert.Nil(t, err)
+	all, err := WarmUp(root, kv, 64)
+	assert.Nil(t, err)
+	assert.True(t, n0 < n1 && n1 < all)
+
+	_, err = WarmUp(thor.Bytes32{1}, kv, 1)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+var errNotInWitness = errors.New("not in witness")
+
+// Witness is the set of trie nodes and contract codes touched while accessing states, keyed by their hashes.
+// It's sufficient to repeat the same access without the full state store.
+type Witness map[thor.Bytes32][]byte
+
+type witnessEntry struct {
+	Key   thor.Bytes32
+	Value []byte
+}
+
+// EncodeRLP implements rlp.Encoder. Entries are sorted by key to make the encoding deterministic.
+func (w Witness) EncodeRLP(out io.Writer) error {
+	entries := make([]witnessEntry, 0, len(w))
+	for k, v := range w {
+		entries = append(entries, witnessEntry{k, v})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Key[:], entries[j].Key[:]) < 0
+	})
+	return rlp.Encode(out, entries)
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (w *Witness) DecodeRLP(s *rlp.Stream) error {
+	var entries []witnessEntry
+	if err := s.Decode(&entries); err != nil {
+		return err
+	}
+	m := make(Witness, len(entries))
+	for _, e := range entries {
+		m[e.Key] = e.Value
+	}
+	*w = m
+	return nil
+}
+
+// verify checks that every entry is keyed by the hash of its value, either a trie node (blake2b)
+// or a contract code (keccak256). So a forged entry can't be consulted.
+func (w Witness) verify() error {
+	for k, v := range w {
+		if thor.Blake2b(v) != k && thor.BytesToBytes32(crypto.Keccak256(v)) != k {
+			return errors.Errorf("witness entry %v: hash mismatch", k)
+		}
+	}
+	return nil
+}
+
+// trieOnlyStore is a store exposing nothing but hash keyed entries. States upon it skip the flat layers.
+type trieOnlyStore interface {
+	kv.GetPutter
+	trieOnly()
+}
+
+// WitnessRecorder wraps a store to record trie nodes and codes read through it.
+// Flat layers like the snapshot and account index are hidden, so that all reads descend tries
+// and get recorded. It's meant for reading. Writes pass through to the underlying store.
+type WitnessRecorder struct {
+	kv.GetPutter
+	lock    sync.Mutex
+	witness Witness
+}
+
+// NewWitnessRecorder create a witness recorder upon kv.
+func NewWitnessRecorder(kv kv.GetPutter) *WitnessRecorder {
+	return &WitnessRecorder{
+		GetPutter: kv,
+		witness:   make(Witness),
+	}
+}
+
+func (r *WitnessRecorder) trieOnly() {}
+
+// Get implements kv.Getter.
+func (r *WitnessRecorder) Get(key []byte) ([]byte, error) {
+	if len(key) != len(thor.Bytes32{}) {
+		return nil, errNotInWitness
+	}
+	value, err := r.GetPutter.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	r.witness[thor.BytesToBytes32(key)] = value
+	r.lock.Unlock()
+	return value, nil
+}
+
+// Has implements kv.Getter.
+func (r *WitnessRecorder) Has(key []byte) (bool, error) {
+	if len(key) != len(thor.Bytes32{}) {
+		return false, nil
+	}
+	// a trie node or code reported to exist should be available in the witness
+	if _, err := r.Get(key); err != nil {
+		if r.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// IsNotFound implements kv.Getter.
+func (r *WitnessRecorder) IsNotFound(err error) bool {
+	return err == errNotInWitness || r.GetPutter.IsNotFound(err)
+}
+
+// Witness returns a copy of the recorded witness.
+func (r *WitnessRecorder) Witness() Witness {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	w := make(Witness, len(r.witness))
+	for k, v := range r.witness {
+		w[k] = v
+	}
+	return w
+}
+
+// witnessStore is an in-memory store of witness entries.
+type witnessStore struct {
+	db *memdb.DB
+}
+
+func (s *witnessStore) trieOnly() {}
+
+func (s *witnessStore) Get(key []byte) ([]byte, error) { return s.db.Get(key) }
+func (s *witnessStore) Has(key []byte) (bool, error)   { return s.db.Contains(key), nil }
+func (s *witnessStore) IsNotFound(err error) bool      { return err == memdb.ErrNotFound }
+func (s *witnessStore) Put(key, value []byte) error    { return s.db.Put(key, value) }
+func (s *witnessStore) Delete(key []byte) error        { return s.db.Delete(key) }
+func (s *witnessStore) NewBatch() kv.Batch             { return &witnessBatch{db: s.db} }
+func (s *witnessStore) NewIterator(r kv.Range) kv.Iterator {
+	return s.db.NewIterator(&util.Range{Start: r.From, Limit: r.To})
+}
+
+type witnessBatch struct {
+	db  *memdb.DB
+	ops []func() error
+}
+
+func (b *witnessBatch) Put(key, value []byte) error {
+	key, value = append([]byte(nil), key...), append([]byte(nil), value...)
+	b.ops = append(b.ops, func() error { return b.db.Put(key, value) })
+	return nil
+}
+
+func (b *witnessBatch) Delete(key []byte) error {
+	key = append([]byte(nil), key...)
+	b.ops = append(b.ops, func() error { return b.db.Delete(key) })
+	return nil
+}
+
+func (b *witnessBatch) NewBatch() kv.Batch { return &witnessBatch{db: b.db} }
+func (b *witnessBatch) Len() int           { return len(b.ops) }
+
+func (b *witnessBatch) Write() error {
+	for _, op := range b.ops {
+		if err := op(); err != nil {
+			return err
+		}
+	}
+	b.ops = nil
+	return nil
+}
+
+// NewWitnessCreator create a state creator serving states from the witness only.
+// Accessing anything not covered by the witness results in missing node errors.
+func NewWitnessCreator(w Witness) (*Creator, error) {
+	if err := w.verify(); err != nil {
+		return nil, err
+	}
+	db := memdb.New(comparer.DefaultComparer, 0)
+	for k, v := range w {
+		if err := db.Put(k[:], v); err != nil {
+			return nil, err
+		}
+	}
+	return NewCreator(&witnessStore{db}), nil
+}
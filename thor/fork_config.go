@@ -7,27 +7,39 @@ import (
 
 // ForkConfig config for a fork.
 type ForkConfig struct {
-	FixTransferLog uint32
+	FixTransferLog  uint32
+	HeaderExtension uint32
+	BlockInterval   uint32 // block interval governed by params
+	VIP193          uint32 // committee backer signatures
 }
 
 func (fc ForkConfig) String() string {
-	return fmt.Sprintf("FTRL: #%v", fc.FixTransferLog)
+	return fmt.Sprintf("FTRL: #%v, HDEXT: #%v, BITV: #%v, VIP193: #%v", fc.FixTransferLog, fc.HeaderExtension, fc.BlockInterval, fc.VIP193)
 }
 
 // NoFork a special config without any forks.
 var NoFork = ForkConfig{
-	FixTransferLog: math.MaxUint32,
+	FixTransferLog:  math.MaxUint32,
+	HeaderExtension: math.MaxUint32,
+	BlockInterval:   math.MaxUint32,
+	VIP193:          math.MaxUint32,
 }
 
 // for well-known networks
 var forkConfigs = map[Bytes32]ForkConfig{
 	// mainnet
 	MustParseBytes32("0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"): {
-		FixTransferLog: 1072000,
+		FixTransferLog:  1072000,
+		HeaderExtension: math.MaxUint32,
+		BlockInterval:   math.MaxUint32,
+		VIP193:          math.MaxUint32,
 	},
 	// testnet
 	MustParseBytes32("0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127"): {
-		FixTransferLog: 1080000,
+		FixTransferLog:  1080000,
+		HeaderExtension: math.MaxUint32,
+		BlockInterval:   math.MaxUint32,
+		VIP193:          math.MaxUint32,
 	},
 }
 
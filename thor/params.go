@@ -14,7 +14,8 @@ import (
 
 // Constants of block chain.
 const (
-	BlockInterval uint64 = 10 // time interval between two consecutive blocks.
+	BlockInterval    uint64 = 10   // time interval between two consecutive blocks.
+	MaxBlockInterval uint64 = 3600 // upper bound of block interval set via params
 
 	TxGas                     uint64 = 5000
 	ClauseGas                 uint64 = params.TxGas - TxGas
@@ -31,6 +32,9 @@ const (
 	MaxTxWorkDelay uint32 = 30 // (unit: block) if tx delay exceeds this value, no energy can be exchanged.
 
 	MaxBlockProposers uint64 = 101
+	CommitteeSize     int    = 15 // max number of backers co-signing a block, since VIP193
+
+	EpochLength uint32 = 180 // (unit: block) about half an hour
 
 	TolerableBlockPackingTime = 2 * time.Second // the indicator to adjust target block gas limit
 
@@ -43,6 +47,8 @@ var (
 	KeyRewardRatio         = BytesToBytes32([]byte("reward-ratio"))
 	KeyBaseGasPrice        = BytesToBytes32([]byte("base-gas-price"))
 	KeyProposerEndorsement = BytesToBytes32([]byte("proposer-endorsement"))
+	KeyBlockInterval       = BytesToBytes32([]byte("block-interval")) // effective since fork
+	KeyMinGasLimit         = BytesToBytes32([]byte("min-gas-limit"))  // effective since fork
 
 	InitialRewardRatio         = big.NewInt(3e17) // 30%
 	InitialBaseGasPrice        = big.NewInt(1e15)
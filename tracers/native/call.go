@@ -0,0 +1,104 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/vm"
+)
+
+type callFrame struct {
+	Type    string         `json:"type"`
+	From    thor.Address   `json:"from"`
+	To      thor.Address   `json:"to"`
+	Value   *hexutil.Big   `json:"value,omitempty"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []*callFrame   `json:"calls,omitempty"`
+}
+
+func newCallFrame(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) *callFrame {
+	f := &callFrame{
+		Type:  typ.String(),
+		From:  thor.Address(from),
+		To:    thor.Address(to),
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		f.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return f
+}
+
+func (f *callFrame) exit(output []byte, gasUsed uint64, err error) {
+	f.GasUsed = hexutil.Uint64(gasUsed)
+	f.Output = common.CopyBytes(output)
+	if err != nil {
+		f.Error = err.Error()
+	}
+}
+
+// callTracer builds the call tree of each clause.
+type callTracer struct {
+	noopTracer
+	clauses []*callFrame
+	stack   []*callFrame // frames not exited yet
+}
+
+func newCallTracer() *callTracer {
+	return &callTracer{clauses: []*callFrame{}}
+}
+
+func (t *callTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.stack = []*callFrame{newCallFrame(typ, from, to, input, gas, value)}
+	return nil
+}
+
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	if len(t.stack) != 1 {
+		return nil
+	}
+	root := t.stack[0]
+	root.exit(output, gasUsed, err)
+	t.clauses = append(t.clauses, root)
+	t.stack = nil
+	return nil
+}
+
+func (t *callTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.stack = append(t.stack, newCallFrame(typ, from, to, input, gas, value))
+}
+
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) < 2 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	frame.exit(output, gasUsed, err)
+	t.stack = t.stack[:len(t.stack)-1]
+
+	parent := t.stack[len(t.stack)-1]
+	parent.Calls = append(parent.Calls, frame)
+}
+
+// GetResult returns call trees, one per executed clause.
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	return json.Marshal(t.clauses)
+}
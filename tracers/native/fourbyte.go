@@ -0,0 +1,51 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vechain/thor/vm"
+)
+
+// fourByteTracer counts method calls by 4-byte selector and size of the arguments,
+// keyed as "0x<selector>-<size>", to help finding out method signatures.
+type fourByteTracer struct {
+	noopTracer
+	ids map[string]int
+}
+
+func newFourByteTracer() *fourByteTracer {
+	return &fourByteTracer{ids: make(map[string]int)}
+}
+
+func (t *fourByteTracer) record(input []byte) {
+	if len(input) < 4 {
+		return
+	}
+	t.ids[fmt.Sprintf("0x%x-%d", input[:4], len(input)-4)]++
+}
+
+func (t *fourByteTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	if !create {
+		t.record(input)
+	}
+	return nil
+}
+
+func (t *fourByteTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if typ != vm.CREATE {
+		t.record(input)
+	}
+}
+
+// GetResult returns counts of selectors.
+func (t *fourByteTracer) GetResult() (json.RawMessage, error) {
+	return json.Marshal(t.ids)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/runtime"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tracers/native"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/xenv"
+)
+
+// traceExecutorCall executes a tx calling executor() of the params contract, which
+// calls its native implementation internally.
+func traceExecutorCall(t *testing.T, name string) ([]byte, []byte) {
+	kv, _ := lvldb.NewMem()
+	b0, _, err := genesis.NewDevnet().Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := chain.New(kv, b0)
+	st, _ := state.New(b0.Header().StateRoot(), kv)
+
+	rt := runtime.New(ch.NewSeeker(b0.Header().ID()), st, &xenv.BlockContext{
+		Number:   1,
+		Time:     b0.Header().Timestamp() + thor.BlockInterval,
+		GasLimit: b0.Header().GasLimit(),
+	})
+
+	method, _ := builtin.Params.ABI.MethodByName("executor")
+	data, _ := method.EncodeInput()
+	trx := new(tx.Builder).
+		ChainTag(ch.Tag()).
+		Gas(1000000).
+		Expiration(100).
+		Clause(tx.NewClause(&builtin.Params.Address).WithData(data)).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	tracer, ok := native.New(name)
+	assert.True(t, ok)
+	receipt, err := rt.TraceTransaction(trx, tracer)
+	assert.Nil(t, err)
+	assert.False(t, receipt.Reverted)
+
+	res, err := tracer.GetResult()
+	assert.Nil(t, err)
+	return res, data
+}
+
+func TestUnknownTracer(t *testing.T) {
+	_, ok := native.New("unknown")
+	assert.False(t, ok)
+}
+
+func TestCallTracer(t *testing.T) {
+	res, data := traceExecutorCall(t, "call")
+
+	var clauses []struct {
+		Type  string
+		From  thor.Address
+		To    thor.Address
+		Input string
+		Calls []struct {
+			To thor.Address
+		}
+	}
+	assert.Nil(t, json.Unmarshal(res, &clauses))
+	assert.Equal(t, 1, len(clauses))
+
+	root := clauses[0]
+	assert.Equal(t, "CALL", root.Type)
+	assert.Equal(t, genesis.DevAccounts()[0].Address, root.From)
+	assert.Equal(t, builtin.Params.Address, root.To)
+	assert.Equal(t, fmt.Sprintf("0x%x", data), root.Input)
+	// the native call
+	assert.NotEmpty(t, root.Calls)
+	assert.Equal(t, builtin.Params.Address, root.Calls[0].To)
+}
+
+func TestPrestateTracer(t *testing.T) {
+	res, _ := traceExecutorCall(t, "prestate")
+
+	var accounts map[string]struct {
+		Balance string
+		Energy  string
+		Code    string
+	}
+	assert.Nil(t, json.Unmarshal(res, &accounts))
+
+	origin, ok := accounts[genesis.DevAccounts()[0].Address.String()]
+	assert.True(t, ok)
+	assert.NotEqual(t, "0x0", origin.Energy)
+
+	params, ok := accounts[builtin.Params.Address.String()]
+	assert.True(t, ok)
+	assert.NotEmpty(t, params.Code)
+}
+
+func TestFourByteTracer(t *testing.T) {
+	res, data := traceExecutorCall(t, "4byte")
+
+	var ids map[string]int
+	assert.Nil(t, json.Unmarshal(res, &ids))
+	assert.Equal(t, 1, ids[fmt.Sprintf("0x%x-0", data[:4])])
+}
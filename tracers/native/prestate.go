@@ -0,0 +1,115 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vechain/thor/runtime"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
+)
+
+type prestateAccount struct {
+	Balance *hexutil.Big      `json:"balance"`
+	Energy  *hexutil.Big      `json:"energy"`
+	Code    hexutil.Bytes     `json:"code,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// prestateTracer collects accounts and storage slots touched by the transaction,
+// with values prior to the execution. Accounts and slots are read from the state
+// right before the step touching them, which never changed them yet.
+// Contracts created are not included, as they didn't exist before.
+type prestateTracer struct {
+	noopTracer
+	rt       *runtime.Runtime
+	accounts map[thor.Address]*prestateAccount
+}
+
+func newPrestateTracer() *prestateTracer {
+	return &prestateTracer{accounts: make(map[thor.Address]*prestateAccount)}
+}
+
+func (t *prestateTracer) CaptureTxStart(rt *runtime.Runtime, tx *tx.Transaction, origin thor.Address) {
+	t.rt = rt
+	t.lookupAccount(origin)
+	for _, clause := range tx.Clauses() {
+		if to := clause.To(); to != nil {
+			t.lookupAccount(*to)
+		}
+	}
+}
+
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.rt == nil {
+		return nil
+	}
+	// the stack is not validated for faulted steps
+	back := func(n int) (*big.Int, bool) {
+		if len(stack.Data()) <= n {
+			return nil, false
+		}
+		return stack.Back(n), true
+	}
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		if key, ok := back(0); ok {
+			t.lookupStorage(thor.Address(contract.Address()), thor.Bytes32(common.BigToHash(key)))
+		}
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.SELFDESTRUCT:
+		if addr, ok := back(0); ok {
+			t.lookupAccount(thor.Address(common.BigToAddress(addr)))
+		}
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if addr, ok := back(1); ok {
+			t.lookupAccount(thor.Address(common.BigToAddress(addr)))
+		}
+	}
+	return nil
+}
+
+func (t *prestateTracer) lookupAccount(addr thor.Address) {
+	if _, ok := t.accounts[addr]; ok {
+		return
+	}
+	st := t.rt.State()
+	t.accounts[addr] = &prestateAccount{
+		Balance: (*hexutil.Big)(st.GetBalance(addr)),
+		Energy:  (*hexutil.Big)(st.GetEnergy(addr, t.rt.Context().Time)),
+		Code:    st.GetCode(addr),
+	}
+}
+
+func (t *prestateTracer) lookupStorage(addr thor.Address, key thor.Bytes32) {
+	t.lookupAccount(addr)
+	acc := t.accounts[addr]
+	if acc.Storage == nil {
+		acc.Storage = make(map[string]string)
+	}
+	if _, ok := acc.Storage[key.String()]; ok {
+		return
+	}
+	acc.Storage[key.String()] = t.rt.State().GetStorage(addr, key).String()
+}
+
+// GetResult returns touched accounts keyed by address.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	if t.rt != nil {
+		if err := t.rt.State().Err(); err != nil {
+			return nil, err
+		}
+	}
+	result := make(map[string]*prestateAccount, len(t.accounts))
+	for addr, acc := range t.accounts {
+		result[addr.String()] = acc
+	}
+	return json.Marshal(result)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package native is a collection of transaction tracers written in Go,
+// which run much faster than their JavaScript counterparts.
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vechain/thor/runtime"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
+)
+
+// Tracer is a native tracer producing a JSON result.
+type Tracer interface {
+	runtime.Tracer
+	GetResult() (json.RawMessage, error)
+}
+
+var ctors = map[string]func() Tracer{
+	"call":     func() Tracer { return newCallTracer() },
+	"prestate": func() Tracer { return newPrestateTracer() },
+	"4byte":    func() Tracer { return newFourByteTracer() },
+}
+
+// New creates a tracer by name, which is one of call, prestate and 4byte.
+func New(name string) (Tracer, bool) {
+	if ctor, ok := ctors[name]; ok {
+		return ctor(), true
+	}
+	return nil, false
+}
+
+// noopTracer ignores all events, to be embedded by tracers interested in some of them.
+type noopTracer struct{}
+
+func (noopTracer) CaptureTxStart(rt *runtime.Runtime, tx *tx.Transaction, origin thor.Address) {}
+func (noopTracer) CaptureTxEnd(receipt *tx.Receipt)                                            {}
+
+func (noopTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+func (noopTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (noopTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (noopTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	return nil
+}
+
+func (noopTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (noopTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
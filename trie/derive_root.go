@@ -14,13 +14,27 @@ type DerivableList interface {
 	GetRlp(i int) []byte
 }
 
+// DeriveRoot computes the root of the trie keyed by RLP encoded list indexes.
+// Items are inserted in key order into a stack trie, that is 1..127, 0, 128...
 func DeriveRoot(list DerivableList) thor.Bytes32 {
-	keybuf := new(bytes.Buffer)
-	trie := new(Trie)
-	for i := 0; i < list.Len(); i++ {
+	var (
+		keybuf = new(bytes.Buffer)
+		trie   = NewStackTrie()
+		n      = list.Len()
+	)
+	update := func(i int) {
 		keybuf.Reset()
 		rlp.Encode(keybuf, uint(i))
 		trie.Update(keybuf.Bytes(), list.GetRlp(i))
 	}
+	for i := 1; i < n && i <= 0x7f; i++ {
+		update(i)
+	}
+	if n > 0 {
+		update(0)
+	}
+	for i := 0x80; i < n; i++ {
+		update(i)
+	}
 	return trie.Hash()
 }
@@ -30,6 +30,8 @@ type hasher struct {
 	tmp                  *bytes.Buffer
 	sha                  hash.Hash
 	cachegen, cachelimit uint16
+	// parallel hashes children of the topmost full node concurrently, only when not storing.
+	parallel bool
 }
 
 // hashers live in a global pool.
@@ -42,6 +44,7 @@ var hasherPool = sync.Pool{
 func newHasher(cachegen, cachelimit uint16) *hasher {
 	h := hasherPool.Get().(*hasher)
 	h.cachegen, h.cachelimit = cachegen, cachelimit
+	h.parallel = false
 	return h
 }
 
@@ -123,6 +126,10 @@ func (h *hasher) hashChildren(original node, db DatabaseWriter) (node, node, err
 		// Hash the full node's children, caching the newly hashed subtrees
 		collapsed, cached := n.copy(), n.copy()
 
+		if h.parallel && db == nil {
+			h.hashFullChildrenParallel(n, collapsed, cached)
+			return collapsed, cached, nil
+		}
 		for i := 0; i < 16; i++ {
 			if n.Children[i] != nil {
 				collapsed.Children[i], cached.Children[i], err = h.hash(n.Children[i], db, false)
@@ -145,6 +152,31 @@ func (h *hasher) hashChildren(original node, db DatabaseWriter) (node, node, err
 	}
 }
 
+// hashFullChildrenParallel hashes children of a full node, each in its own goroutine.
+// It's only for hashing without storing, which never fails.
+func (h *hasher) hashFullChildrenParallel(n, collapsed, cached *fullNode) {
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		if n.Children[i] == nil {
+			collapsed.Children[i] = valueNode(nil) // Ensure that nil children are encoded as empty strings.
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ch := newHasher(h.cachegen, h.cachelimit)
+			defer returnHasherToPool(ch)
+			collapsed.Children[i], cached.Children[i], _ = ch.hash(n.Children[i], nil, false)
+		}(i)
+	}
+	wg.Wait()
+
+	cached.Children[16] = n.Children[16]
+	if collapsed.Children[16] == nil {
+		collapsed.Children[16] = valueNode(nil)
+	}
+}
+
 func (h *hasher) store(n node, db DatabaseWriter, force bool) (node, error) {
 	// Don't store hashes or empty nodes.
 	if _, isHash := n.(hashNode); n == nil || isHash {
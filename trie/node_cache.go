@@ -0,0 +1,124 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package trie
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	nodeCacheHitCounter  = metrics.NewRegisteredCounter("trie/nodecache/hit", nil)
+	nodeCacheMissCounter = metrics.NewRegisteredCounter("trie/nodecache/miss", nil)
+)
+
+// NodeCache is a LRU cache of encoded nodes keyed by node hash, bounded by total bytes
+// of keys and values. It's safe for concurrent use.
+type NodeCache struct {
+	lock     sync.Mutex
+	maxBytes int
+	size     int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type nodeCacheItem struct {
+	key   string
+	value []byte
+}
+
+// NewNodeCache creates a node cache holding at most maxBytes.
+func NewNodeCache(maxBytes int) *NodeCache {
+	return &NodeCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the encoded node. The returned bytes must not be modified.
+func (c *NodeCache) Get(hash []byte) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.items[string(hash)]; ok {
+		c.ll.MoveToFront(elem)
+		nodeCacheHitCounter.Inc(1)
+		return elem.Value.(*nodeCacheItem).value, true
+	}
+	nodeCacheMissCounter.Inc(1)
+	return nil, false
+}
+
+// Add adds the encoded node, and evicts least recently used ones if oversized.
+func (c *NodeCache) Add(hash, enc []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.items[string(hash)]; ok {
+		// content addressed, never changed
+		return
+	}
+	item := &nodeCacheItem{string(hash), append([]byte(nil), enc...)}
+	c.items[item.key] = c.ll.PushFront(item)
+	c.size += len(item.key) + len(item.value)
+
+	for c.size > c.maxBytes && c.ll.Len() > 0 {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Remove removes the node, e.g. when it's deleted from the database.
+func (c *NodeCache) Remove(hash []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.items[string(hash)]; ok {
+		c.removeElement(elem)
+	}
+}
+
+func (c *NodeCache) removeElement(elem *list.Element) {
+	item := c.ll.Remove(elem).(*nodeCacheItem)
+	delete(c.items, item.key)
+	c.size -= len(item.key) + len(item.value)
+}
+
+// Size returns total bytes held.
+func (c *NodeCache) Size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.size
+}
+
+// Wrap returns the database whose node reads are served by the cache first.
+// Writes go to db directly.
+func (c *NodeCache) Wrap(db Database) Database {
+	return &cachedDatabase{db, c}
+}
+
+type cachedDatabase struct {
+	Database
+	cache *NodeCache
+}
+
+func (d *cachedDatabase) Get(key []byte) ([]byte, error) {
+	// only hash keyed entries, i.e. nodes and preimages of secure keys, which never change
+	if len(key) != 32 {
+		return d.Database.Get(key)
+	}
+	if enc, ok := d.cache.Get(key); ok {
+		return enc, nil
+	}
+	enc, err := d.Database.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	d.cache.Add(key, enc)
+	return enc, nil
+}
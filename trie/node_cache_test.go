@@ -0,0 +1,77 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestNodeCache(t *testing.T) {
+	c := NewNodeCache(100)
+	key := func(i int) []byte { return bytes.Repeat([]byte{byte(i)}, 32) }
+
+	c.Add(key(1), make([]byte, 18))
+	c.Add(key(2), make([]byte, 18))
+	if c.Size() != 100 {
+		t.Fatalf("size %v, want 100", c.Size())
+	}
+	c.Get(key(1))
+	// evicts the least recently used one
+	c.Add(key(3), make([]byte, 18))
+	if _, ok := c.Get(key(2)); ok {
+		t.Fatal("key 2 should be evicted")
+	}
+	if _, ok := c.Get(key(1)); !ok {
+		t.Fatal("key 1 should be kept")
+	}
+	c.Remove(key(1))
+	if _, ok := c.Get(key(1)); ok {
+		t.Fatal("key 1 should be removed")
+	}
+	if c.Size() != 50 {
+		t.Fatalf("size %v, want 50", c.Size())
+	}
+}
+
+func TestNodeCacheWrap(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	tr, _ := New(emptyRoot, db)
+	for i := 0; i < 100; i++ {
+		tr.Update([]byte(fmt.Sprintf("key-%03d", i)), []byte(fmt.Sprintf("value-%03d", i)))
+	}
+	root, err := tr.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewNodeCache(1024 * 1024)
+	read := func() {
+		tr, err := New(root, cache.Wrap(db))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			if v := tr.Get([]byte(fmt.Sprintf("key-%03d", i))); string(v) != fmt.Sprintf("value-%03d", i) {
+				t.Fatalf("key-%03d: got %q", i, v)
+			}
+		}
+	}
+	read()
+	hits := nodeCacheHitCounter.Count()
+
+	// served by the cache
+	for _, k := range db.Keys() {
+		db.Delete(k)
+	}
+	read()
+	if nodeCacheHitCounter.Count() == hits {
+		t.Fatal("nodes should be read from the cache")
+	}
+}
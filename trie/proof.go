@@ -92,7 +92,14 @@ func (t *Trie) Prove(key []byte, fromLevel uint, proofDb DatabaseWriter) error {
 // value for key in a trie with the given root hash. VerifyProof
 // returns an error if the proof contains invalid trie nodes or the
 // wrong value.
+//
+// A nil value with nil error means the proof is a valid proof of absence,
+// i.e. the trie with the given root does not contain key. Proof of absence
+// for the empty trie needs no node.
 func VerifyProof(rootHash thor.Bytes32, key []byte, proofDb DatabaseReader) (value []byte, err error, nodes int) {
+	if rootHash == emptyRoot {
+		return nil, nil, 0
+	}
 	key = keybytesToHex(key)
 	wantHash := rootHash[:]
 	for i := 0; ; i++ {
@@ -108,7 +115,7 @@ func VerifyProof(rootHash thor.Bytes32, key []byte, proofDb DatabaseReader) (val
 		switch cld := cld.(type) {
 		case nil:
 			// The trie doesn't contain the key.
-			return nil, nil, i
+			return nil, nil, i + 1
 		case hashNode:
 			key = keyrest
 			wantHash = cld
@@ -69,6 +69,47 @@ func TestOneElementProof(t *testing.T) {
 	}
 }
 
+func TestMissingKeyProof(t *testing.T) {
+	trie := new(Trie)
+	// start with the empty trie
+	for i, key := range []string{"", "key1", "key2", "zzz"} {
+		if key != "" {
+			updateString(trie, key, fmt.Sprintf("value%d", i))
+		}
+		for _, missing := range []string{"a", "k1", "key", "key3", "zz"} {
+			proofs := ethdb.NewMemDatabase()
+			if err := trie.Prove([]byte(missing), 0, proofs); err != nil {
+				t.Fatal(err)
+			}
+			val, err, _ := VerifyProof(trie.Hash(), []byte(missing), proofs)
+			if err != nil {
+				t.Fatalf("VerifyProof error for missing key %q: %v", missing, err)
+			}
+			if val != nil {
+				t.Fatalf("VerifyProof returned value %x for missing key %q", val, missing)
+			}
+		}
+	}
+}
+
+func TestSecureTrieMissingKeyProof(t *testing.T) {
+	trie := newEmptySecure()
+	trie.Update([]byte("foo"), []byte("bar"))
+	root := trie.Hash()
+
+	proofs := ethdb.NewMemDatabase()
+	trie.Prove([]byte("foo"), 0, proofs)
+	if val, err, _ := VerifyProof(root, thor.Blake2b([]byte("foo")).Bytes(), proofs); err != nil || !bytes.Equal(val, []byte("bar")) {
+		t.Fatalf("VerifyProof for existing key: got %x, %v", val, err)
+	}
+
+	proofs = ethdb.NewMemDatabase()
+	trie.Prove([]byte("baz"), 0, proofs)
+	if val, err, _ := VerifyProof(root, thor.Blake2b([]byte("baz")).Bytes(), proofs); err != nil || val != nil {
+		t.Fatalf("VerifyProof for missing key: got %x, %v", val, err)
+	}
+}
+
 func TestVerifyBadProof(t *testing.T) {
 	trie, vals := randomTrie(800)
 	root := trie.Hash()
@@ -0,0 +1,247 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package trie
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/vechain/thor/thor"
+)
+
+// refCountPrefix prefixes keys of node reference counters.
+var refCountPrefix = []byte("trie-ref-")
+
+// RefCountDatabase is the backing store required by RefCounter.
+type RefCountDatabase interface {
+	Database
+	Delete(key []byte) error
+}
+
+// LeafRefFunc extracts roots of sub-tries referenced by a leaf value,
+// e.g. storage roots held in account leaves.
+type LeafRefFunc func(value []byte) ([]thor.Bytes32, error)
+
+// KeepFunc reports whether the key of a node is also held by other data, e.g. contract
+// code stored by hash, so that the key is kept when the node is freed.
+type KeepFunc func(hash thor.Bytes32) (bool, error)
+
+// RefCounter tracks how many parents (or external holders) refer to each
+// persisted trie node. A node is deleted only when its count drops to zero,
+// so sub-tries shared between roots can be pruned safely.
+//
+// RefCounter is not safe for concurrent use.
+type RefCounter struct {
+	db       RefCountDatabase
+	leafRefs LeafRefFunc
+	keep     KeepFunc
+}
+
+// NewRefCounter creates a reference counter on top of db.
+// leafRefs is applied to leaves of the top-level trie only, as sub-tries are assumed
+// to hold no further references. leafRefs and keep are optional.
+func NewRefCounter(db RefCountDatabase, leafRefs LeafRefFunc, keep KeepFunc) *RefCounter {
+	return &RefCounter{db, leafRefs, keep}
+}
+
+// Count returns the reference count of the node with given hash.
+func (rc *RefCounter) Count(hash thor.Bytes32) (uint32, error) {
+	key := refCountKey(hash)
+	has, err := rc.db.Has(key)
+	if err != nil || !has {
+		return 0, err
+	}
+	data, err := rc.db.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 4 {
+		return 0, fmt.Errorf("invalid ref count of node %v", hash)
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func (rc *RefCounter) setCount(hash thor.Bytes32, count uint32) error {
+	key := refCountKey(hash)
+	if count == 0 {
+		return rc.db.Delete(key)
+	}
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], count)
+	return rc.db.Put(key, data[:])
+}
+
+// Reference adds one reference to the trie with given root.
+// Children are referenced only the first time a node is seen, so every
+// node's count equals the number of distinct holders of it.
+func (rc *RefCounter) Reference(root thor.Bytes32) error {
+	return rc.reference(root, rc.leafRefs)
+}
+
+// reference references the trie with leafRefs applied to its leaves, which is nil for sub-tries.
+func (rc *RefCounter) reference(root thor.Bytes32, leafRefs LeafRefFunc) error {
+	if root == emptyRoot || root.IsZero() {
+		return nil
+	}
+	count, err := rc.Count(root)
+	if err != nil {
+		return err
+	}
+	if err := rc.setCount(root, count+1); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	n, err := rc.loadNode(root)
+	if err != nil {
+		return err
+	}
+	return rc.walkChildren(n, leafRefs, rc.reference)
+}
+
+// Dereference drops one reference to the trie with given root. Nodes
+// whose count reaches zero are deleted together with their counters,
+// and their children are dereferenced in turn.
+func (rc *RefCounter) Dereference(root thor.Bytes32) error {
+	return rc.dereference(root, rc.leafRefs)
+}
+
+// dereference dereferences the trie with leafRefs applied to its leaves, which is nil for sub-tries.
+func (rc *RefCounter) dereference(root thor.Bytes32, leafRefs LeafRefFunc) error {
+	if root == emptyRoot || root.IsZero() {
+		return nil
+	}
+	count, err := rc.Count(root)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("dereference untracked node %v", root)
+	}
+	if err := rc.setCount(root, count-1); err != nil {
+		return err
+	}
+	if count > 1 {
+		return nil
+	}
+	n, err := rc.loadNode(root)
+	if err != nil {
+		return err
+	}
+	if err := rc.walkChildren(n, leafRefs, rc.dereference); err != nil {
+		return err
+	}
+	if rc.keep != nil {
+		keep, err := rc.keep(root)
+		if err != nil {
+			return err
+		}
+		if keep {
+			return nil
+		}
+	}
+	return rc.db.Delete(root[:])
+}
+
+func (rc *RefCounter) loadNode(hash thor.Bytes32) (node, error) {
+	enc, err := rc.db.Get(hash[:])
+	if err != nil {
+		return nil, &MissingNodeError{NodeHash: hash}
+	}
+	return decodeNode(hash[:], enc, 0)
+}
+
+// walkChildren calls fn for every hashed child of n, and for every sub-trie root extracted
+// from leaves by leafRefs, with no leafRefs for the sub-trie. Embedded children live inside
+// their parent and are descended into directly.
+func (rc *RefCounter) walkChildren(n node, leafRefs LeafRefFunc, fn func(thor.Bytes32, LeafRefFunc) error) error {
+	switch n := n.(type) {
+	case *shortNode:
+		return rc.walkChildren(n.Val, leafRefs, fn)
+	case *fullNode:
+		for _, child := range n.Children {
+			if err := rc.walkChildren(child, leafRefs, fn); err != nil {
+				return err
+			}
+		}
+	case hashNode:
+		return fn(thor.BytesToBytes32(n), leafRefs)
+	case valueNode:
+		if leafRefs == nil {
+			return nil
+		}
+		roots, err := leafRefs(n)
+		if err != nil {
+			return err
+		}
+		for _, root := range roots {
+			if err := fn(root, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BatchWriter is a batch of writes, e.g. kv.Batch.
+type BatchWriter interface {
+	DatabaseWriter
+	Delete(key []byte) error
+}
+
+// BatchDatabase puts writes into a batch, and serves reads with pending writes of the batch
+// first, then the backing store. It lets trie commits and reference counting of them, which
+// reads nodes just committed, land in one atomic write.
+type BatchDatabase struct {
+	db      DatabaseReader
+	batch   BatchWriter
+	pending map[string][]byte // nil value for deleted
+}
+
+// NewBatchDatabase creates a batch database reading db and writing batch.
+func NewBatchDatabase(db DatabaseReader, batch BatchWriter) *BatchDatabase {
+	return &BatchDatabase{db, batch, make(map[string][]byte)}
+}
+
+// Get implements DatabaseReader.
+func (b *BatchDatabase) Get(key []byte) ([]byte, error) {
+	if v, ok := b.pending[string(key)]; ok {
+		if v == nil {
+			return nil, errDeletedInBatch
+		}
+		return v, nil
+	}
+	return b.db.Get(key)
+}
+
+// Has implements DatabaseReader.
+func (b *BatchDatabase) Has(key []byte) (bool, error) {
+	if v, ok := b.pending[string(key)]; ok {
+		return v != nil, nil
+	}
+	return b.db.Has(key)
+}
+
+// Put implements DatabaseWriter.
+func (b *BatchDatabase) Put(key, value []byte) error {
+	// the value slice may be reused by callers
+	b.pending[string(key)] = append([]byte{}, value...)
+	return b.batch.Put(key, value)
+}
+
+// Delete deletes the key in the batch.
+func (b *BatchDatabase) Delete(key []byte) error {
+	b.pending[string(key)] = nil
+	return b.batch.Delete(key)
+}
+
+var errDeletedInBatch = errors.New("deleted in batch")
+
+func refCountKey(hash thor.Bytes32) []byte {
+	return append(append([]byte(nil), refCountPrefix...), hash[:]...)
+}
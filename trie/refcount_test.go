@@ -0,0 +1,241 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestRefCounter(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	rc := NewRefCounter(db, nil, nil)
+
+	tr, _ := New(emptyRoot, db)
+	for i := 0; i < 100; i++ {
+		tr.Update([]byte(fmt.Sprintf("key-%03d", i)), []byte(fmt.Sprintf("value-%03d", i)))
+	}
+	root1, err := tr.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Reference(root1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the second root shares most sub-tries with the first one
+	tr.Update([]byte("key-000"), []byte("changed"))
+	root2, err := tr.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Reference(root2); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := rc.Count(root1); count != 1 {
+		t.Fatalf("root1 ref count: got %v, want 1", count)
+	}
+
+	if err := rc.Dereference(root1); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := db.Has(root1[:]); has {
+		t.Fatal("root1 should be pruned")
+	}
+	tr2, err := New(root2, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 100; i++ {
+		key, want := []byte(fmt.Sprintf("key-%03d", i)), []byte(fmt.Sprintf("value-%03d", i))
+		if got, err := tr2.TryGet(key); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("key %s: got %s, %v, want %s", key, got, err, want)
+		}
+	}
+
+	if err := rc.Dereference(root2); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Len(); n != 0 {
+		t.Fatalf("%v entries left after pruning all roots", n)
+	}
+	if err := rc.Dereference(root2); err == nil {
+		t.Fatal("dereferencing a freed root should fail")
+	}
+}
+
+// testBatch buffers puts and deletes until written.
+type testBatch struct {
+	db  *ethdb.MemDatabase
+	ops []func() error
+}
+
+func (b *testBatch) Put(key, value []byte) error {
+	key, value = append([]byte(nil), key...), append([]byte(nil), value...)
+	b.ops = append(b.ops, func() error { return b.db.Put(key, value) })
+	return nil
+}
+
+func (b *testBatch) Delete(key []byte) error {
+	key = append([]byte(nil), key...)
+	b.ops = append(b.ops, func() error { return b.db.Delete(key) })
+	return nil
+}
+
+func (b *testBatch) Write() error {
+	for _, op := range b.ops {
+		if err := op(); err != nil {
+			return err
+		}
+	}
+	b.ops = nil
+	return nil
+}
+
+func TestRefCounterBatch(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	batch := &testBatch{db: db}
+	bdb := NewBatchDatabase(db, batch)
+
+	tr, _ := New(emptyRoot, db)
+	for i := 0; i < 100; i++ {
+		tr.Update([]byte(fmt.Sprintf("key-%03d", i)), []byte(fmt.Sprintf("value-%03d", i)))
+	}
+	// nodes committed are readable for referencing before the batch written
+	root, err := tr.CommitTo(bdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewRefCounter(bdb, nil, nil).Reference(root); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Len(); n != 0 {
+		t.Fatalf("%v entries written before the batch", n)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	rc := NewRefCounter(db, nil, nil)
+	if count, _ := rc.Count(root); count != 1 {
+		t.Fatalf("root ref count: got %v, want 1", count)
+	}
+
+	bdb = NewBatchDatabase(db, batch)
+	if err := NewRefCounter(bdb, nil, nil).Dereference(root); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := bdb.Has(root[:]); has {
+		t.Fatal("root should be deleted in the batch")
+	}
+	if has, _ := db.Has(root[:]); !has {
+		t.Fatal("root should be kept until the batch written")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Len(); n != 0 {
+		t.Fatalf("%v entries left after pruning the root", n)
+	}
+}
+
+func TestRefCounterSubTries(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	// sub-trie leaves are not roots, so leafRefs must be applied to top-level leaves only
+	leafRefs := func(value []byte) ([]thor.Bytes32, error) {
+		if len(value) != 32 {
+			return nil, fmt.Errorf("not a root: %x", value)
+		}
+		return []thor.Bytes32{thor.BytesToBytes32(value)}, nil
+	}
+	rc := NewRefCounter(db, leafRefs, nil)
+
+	commitSub := func(prefix string) thor.Bytes32 {
+		tr, _ := New(emptyRoot, db)
+		for i := 0; i < 20; i++ {
+			tr.Update([]byte(fmt.Sprintf("%s-key-%03d", prefix, i)), []byte(fmt.Sprintf("%s-value-%03d", prefix, i)))
+		}
+		root, err := tr.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+	sub1, sub2, sub3 := commitSub("sub1"), commitSub("sub2"), commitSub("sub3")
+
+	top, _ := New(emptyRoot, db)
+	top.Update([]byte("a"), sub1[:])
+	top.Update([]byte("b"), sub2[:])
+	root1, err := top.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Reference(root1); err != nil {
+		t.Fatal(err)
+	}
+	top.Update([]byte("b"), sub3[:])
+	root2, err := top.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Reference(root2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rc.Dereference(root1); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := db.Has(sub2[:]); has {
+		t.Fatal("sub2 should be pruned")
+	}
+	for _, sub := range []thor.Bytes32{sub1, sub3} {
+		if has, _ := db.Has(sub[:]); !has {
+			t.Fatalf("sub-trie %v should be kept", sub)
+		}
+	}
+
+	if err := rc.Dereference(root2); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.Len(); n != 0 {
+		t.Fatalf("%v entries left after pruning all roots", n)
+	}
+}
+
+func TestRefCounterKeep(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	tr, _ := New(emptyRoot, db)
+	for i := 0; i < 100; i++ {
+		tr.Update([]byte(fmt.Sprintf("key-%03d", i)), []byte(fmt.Sprintf("value-%03d", i)))
+	}
+	root, err := tr.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the key of root is also held by other data
+	rc := NewRefCounter(db, nil, func(hash thor.Bytes32) (bool, error) {
+		return hash == root, nil
+	})
+	if err := rc.Reference(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Dereference(root); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := db.Has(root[:]); !has {
+		t.Fatal("root key should be kept")
+	}
+	if count, _ := rc.Count(root); count != 0 {
+		t.Fatalf("root ref count: got %v, want 0", count)
+	}
+	if n := db.Len(); n != 1 {
+		t.Fatalf("%v entries left, want only the kept one", n)
+	}
+}
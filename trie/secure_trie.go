@@ -126,6 +126,14 @@ func (t *SecureTrie) TryDelete(key []byte) error {
 	return t.trie.TryDelete(hk)
 }
 
+// Prove constructs a merkle proof for key, see Trie.Prove.
+// The proof is keyed by the hashed key, so it should be verified with
+// VerifyProof using the blake2b-256 hash of key. A proof for a missing key
+// proves its absence.
+func (t *SecureTrie) Prove(key []byte, fromLevel uint, proofDb DatabaseWriter) error {
+	return t.trie.Prove(thor.Blake2b(key).Bytes(), fromLevel, proofDb)
+}
+
 // GetKey returns the sha3 preimage of a hashed key that was
 // previously used to store a value.
 func (t *SecureTrie) GetKey(shaKey []byte) []byte {
@@ -149,6 +157,11 @@ func (t *SecureTrie) Hash() thor.Bytes32 {
 	return t.trie.Hash()
 }
 
+// HashParallel is like Hash, but hashes top-level branches concurrently.
+func (t *SecureTrie) HashParallel() thor.Bytes32 {
+	return t.trie.HashParallel()
+}
+
 func (t *SecureTrie) Root() []byte {
 	return t.trie.Root()
 }
@@ -181,6 +194,14 @@ func (t *SecureTrie) CommitTo(db DatabaseWriter) (root thor.Bytes32, err error)
 	return t.trie.CommitTo(db)
 }
 
+// CommitNodesTo is like CommitTo, but pre-images of keys are discarded instead of written.
+func (t *SecureTrie) CommitNodesTo(db DatabaseWriter) (root thor.Bytes32, err error) {
+	if len(t.getSecKeyCache()) > 0 {
+		t.secKeyCache = make(map[string][]byte)
+	}
+	return t.trie.CommitTo(db)
+}
+
 // hashKey returns the hash of key as an ephemeral buffer.
 // The caller must not hold onto the return value because it will become
 // invalid on the next call to hashKey or secKey.
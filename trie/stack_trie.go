@@ -0,0 +1,190 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package trie
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+)
+
+const (
+	stEmpty = iota
+	stBranch
+	stExt
+	stLeaf
+	stHashed
+)
+
+// StackTrie computes the root hash of a trie whose keys are inserted in
+// strictly ascending order. Subtrees left of the insertion path can never
+// change again, so they are hashed and freed as soon as they are passed,
+// keeping only the current path in memory.
+//
+// Keys must be prefix-free, which holds for the RLP encoded indexes used by
+// DeriveRoot. The root is identical to the one computed by Trie.
+type StackTrie struct {
+	typ      uint8
+	key      []byte // hex key nibbles, without terminator
+	val      []byte // value for leaves, hash or embedded encoding for hashed nodes
+	children [16]*StackTrie
+}
+
+// NewStackTrie creates an empty stack trie.
+func NewStackTrie() *StackTrie {
+	return &StackTrie{}
+}
+
+func newStackLeaf(key, val []byte) *StackTrie {
+	return &StackTrie{typ: stLeaf, key: key, val: val}
+}
+
+// Update inserts a key value pair. Keys must be given in ascending order.
+func (st *StackTrie) Update(key, value []byte) {
+	k := keybytesToHex(key)
+	st.insert(k[:len(k)-1], value)
+}
+
+func (st *StackTrie) diffIndex(key []byte) int {
+	for i := 0; i < len(st.key); i++ {
+		if st.key[i] != key[i] {
+			return i
+		}
+	}
+	return len(st.key)
+}
+
+func (st *StackTrie) insert(key, value []byte) {
+	switch st.typ {
+	case stBranch:
+		idx := int(key[0])
+		// the nearest elder sibling is complete now
+		for i := idx - 1; i >= 0; i-- {
+			if st.children[i] != nil {
+				st.children[i].hash()
+				break
+			}
+		}
+		if st.children[idx] == nil {
+			st.children[idx] = newStackLeaf(key[1:], value)
+		} else {
+			st.children[idx].insert(key[1:], value)
+		}
+	case stExt:
+		diff := st.diffIndex(key)
+		if diff == len(st.key) {
+			st.children[0].insert(key[diff:], value)
+			return
+		}
+		// split the extension: the part after the break point becomes
+		// a complete sibling of the new leaf
+		var orig *StackTrie
+		if diff < len(st.key)-1 {
+			orig = &StackTrie{typ: stExt, key: st.key[diff+1:], children: [16]*StackTrie{st.children[0]}}
+		} else {
+			orig = st.children[0]
+		}
+		orig.hash()
+
+		var branch *StackTrie
+		if diff == 0 {
+			st.typ = stBranch
+			st.children[0] = nil
+			branch = st
+		} else {
+			branch = &StackTrie{typ: stBranch}
+			st.children[0] = branch
+		}
+		branch.children[st.key[diff]] = orig
+		branch.children[key[diff]] = newStackLeaf(key[diff+1:], value)
+		st.key = st.key[:diff]
+	case stLeaf:
+		diff := st.diffIndex(key)
+		if diff >= len(st.key) {
+			panic("stack trie: duplicated or prefixed key")
+		}
+		var branch *StackTrie
+		if diff == 0 {
+			st.typ = stBranch
+			branch = st
+		} else {
+			st.typ = stExt
+			branch = &StackTrie{typ: stBranch}
+			st.children[0] = branch
+		}
+		orig := newStackLeaf(st.key[diff+1:], st.val)
+		orig.hash()
+		branch.children[st.key[diff]] = orig
+		branch.children[key[diff]] = newStackLeaf(key[diff+1:], value)
+		st.key = st.key[:diff]
+		st.val = nil
+	case stEmpty:
+		st.typ = stLeaf
+		st.key = key
+		st.val = value
+	case stHashed:
+		panic("stack trie: insert into hashed node")
+	}
+}
+
+// hash collapses the node into its reference, which is the node hash,
+// or the encoding itself if it's shorter than 32 bytes.
+func (st *StackTrie) hash() {
+	var enc []byte
+	switch st.typ {
+	case stHashed:
+		return
+	case stEmpty:
+		enc = rlp.EmptyString
+	case stBranch:
+		var elems [17]rlp.RawValue
+		for i, child := range st.children {
+			elems[i] = child.ref()
+		}
+		elems[16] = rlp.EmptyString
+		enc, _ = rlp.EncodeToBytes(elems[:])
+	case stExt:
+		key, _ := rlp.EncodeToBytes(hexToCompact(st.key))
+		enc, _ = rlp.EncodeToBytes([]rlp.RawValue{key, st.children[0].ref()})
+	case stLeaf:
+		key, _ := rlp.EncodeToBytes(hexToCompact(concat(st.key, 16)))
+		val, _ := rlp.EncodeToBytes(st.val)
+		enc, _ = rlp.EncodeToBytes([]rlp.RawValue{key, val})
+	}
+	if len(enc) < 32 {
+		st.val = enc
+	} else {
+		st.val = thor.Blake2b(enc).Bytes()
+	}
+	st.typ = stHashed
+	st.key = nil
+	st.children = [16]*StackTrie{}
+}
+
+// ref returns how the node is referred by its parent.
+func (st *StackTrie) ref() rlp.RawValue {
+	if st == nil {
+		return rlp.EmptyString
+	}
+	st.hash()
+	if len(st.val) < 32 {
+		return st.val
+	}
+	enc, _ := rlp.EncodeToBytes(st.val)
+	return enc
+}
+
+// Hash returns the root hash. No more keys can be inserted after that.
+func (st *StackTrie) Hash() thor.Bytes32 {
+	if st.typ == stEmpty {
+		return emptyRoot
+	}
+	st.hash()
+	if len(st.val) < 32 {
+		// the root is always hashed
+		return thor.Blake2b(st.val)
+	}
+	return thor.BytesToBytes32(st.val)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type testList [][]byte
+
+func (l testList) Len() int            { return len(l) }
+func (l testList) GetRlp(i int) []byte { return l[i] }
+
+func TestDeriveRootStackTrie(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 16, 127, 128, 129, 300, 1000} {
+		for _, size := range []int{1, 5, 40} {
+			list := make(testList, n)
+			for i := range list {
+				list[i] = bytes.Repeat([]byte{byte(i) | 1}, size)
+			}
+
+			// the reference root computed by a full trie
+			keybuf := new(bytes.Buffer)
+			full := new(Trie)
+			for i := 0; i < n; i++ {
+				keybuf.Reset()
+				rlp.Encode(keybuf, uint(i))
+				full.Update(keybuf.Bytes(), list[i])
+			}
+
+			if got, want := DeriveRoot(list), full.Hash(); got != want {
+				t.Fatalf("n=%v size=%v: got %v, want %v", n, size, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkDeriveRoot(b *testing.B) {
+	list := make(testList, 500)
+	for i := range list {
+		list[i] = randBytes(100)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DeriveRoot(list)
+	}
+}
@@ -458,7 +458,15 @@ func (t *Trie) Root() []byte { return t.Hash().Bytes() }
 // Hash returns the root hash of the trie. It does not write to the
 // database and can be used even if the trie doesn't have one.
 func (t *Trie) Hash() thor.Bytes32 {
-	hash, cached, _ := t.hashRoot(nil)
+	hash, cached, _ := t.hashRoot(nil, false)
+	t.root = cached
+	return thor.BytesToBytes32(hash.(hashNode))
+}
+
+// HashParallel is like Hash, but hashes top-level branches of the trie concurrently.
+// It pays off for tries with many dirty nodes, e.g. the account trie after a block executed.
+func (t *Trie) HashParallel() thor.Bytes32 {
+	hash, cached, _ := t.hashRoot(nil, true)
 	t.root = cached
 	return thor.BytesToBytes32(hash.(hashNode))
 }
@@ -483,7 +491,7 @@ func (t *Trie) Commit() (root thor.Bytes32, err error) {
 // the changes made to db are written back to the trie's attached
 // database before using the trie.
 func (t *Trie) CommitTo(db DatabaseWriter) (root thor.Bytes32, err error) {
-	hash, cached, err := t.hashRoot(db)
+	hash, cached, err := t.hashRoot(db, false)
 	if err != nil {
 		return (thor.Bytes32{}), err
 	}
@@ -492,11 +500,12 @@ func (t *Trie) CommitTo(db DatabaseWriter) (root thor.Bytes32, err error) {
 	return thor.BytesToBytes32(hash.(hashNode)), nil
 }
 
-func (t *Trie) hashRoot(db DatabaseWriter) (node, node, error) {
+func (t *Trie) hashRoot(db DatabaseWriter, parallel bool) (node, node, error) {
 	if t.root == nil {
 		return hashNode(emptyRoot.Bytes()), nil, nil
 	}
 	h := newHasher(t.cachegen, t.cachelimit)
 	defer returnHasherToPool(h)
+	h.parallel = parallel
 	return h.hash(t.root, db, true)
 }
@@ -315,6 +315,30 @@ func TestLargeValue(t *testing.T) {
 	trie.Hash()
 }
 
+func TestHashParallel(t *testing.T) {
+	serial, parallel := newEmpty(), newEmpty()
+	for i := 0; i < 1000; i++ {
+		k, v := fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)
+		updateString(serial, k, v)
+		updateString(parallel, k, v)
+	}
+	exp := serial.Hash()
+	if hash := parallel.HashParallel(); hash != exp {
+		t.Errorf("root failure. expected %x got %x", exp, hash)
+	}
+	// hashes are cached, then committed root keeps the same
+	if root, _ := parallel.Commit(); root != exp {
+		t.Errorf("commit failure. expected %x got %x", exp, root)
+	}
+
+	updateString(serial, "key1", "changed")
+	updateString(parallel, "key1", "changed")
+	exp = serial.Hash()
+	if hash := parallel.HashParallel(); hash != exp {
+		t.Errorf("root failure after update. expected %x got %x", exp, hash)
+	}
+}
+
 type countingDB struct {
 	Database
 	gets map[string]int
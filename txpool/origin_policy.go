@@ -0,0 +1,51 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"github.com/vechain/thor/thor"
+)
+
+// OriginPolicy address based admission policy of tx origins.
+type OriginPolicy struct {
+	// Allowlist if not empty, only txs from origins listed are accepted, e.g. in private networks.
+	Allowlist []thor.Address `json:"allowlist"`
+	// Blocklist txs from origins listed are refused.
+	Blocklist []thor.Address `json:"blocklist"`
+}
+
+// compiledOriginPolicy is OriginPolicy indexed for lookup.
+type compiledOriginPolicy struct {
+	policy OriginPolicy
+	allow  map[thor.Address]bool
+	block  map[thor.Address]bool
+}
+
+func compileOriginPolicy(policy OriginPolicy) *compiledOriginPolicy {
+	c := &compiledOriginPolicy{
+		policy: OriginPolicy{
+			Allowlist: append([]thor.Address{}, policy.Allowlist...),
+			Blocklist: append([]thor.Address{}, policy.Blocklist...),
+		},
+		allow: make(map[thor.Address]bool),
+		block: make(map[thor.Address]bool),
+	}
+	for _, addr := range policy.Allowlist {
+		c.allow[addr] = true
+	}
+	for _, addr := range policy.Blocklist {
+		c.block[addr] = true
+	}
+	return c
+}
+
+// Permits returns whether txs from origin are acceptable.
+func (c *compiledOriginPolicy) Permits(origin thor.Address) bool {
+	if c.block[origin] {
+		return false
+	}
+	return len(c.allow) == 0 || c.allow[origin]
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/vechain/thor/thor"
+)
+
+// orphanSet holds txs whose dependency is neither on chain nor in the pool.
+// They are kept aside from the pool, until the dependency arrives.
+type orphanSet struct {
+	lock    sync.Mutex
+	limit   int
+	txObjs  map[thor.Bytes32]*txObject
+	waiting map[thor.Bytes32]map[thor.Bytes32]struct{} // dep id -> ids of txs depending on it
+	quota   map[thor.Address]int                       // origin -> count of txs
+}
+
+func newOrphanSet(limit int) *orphanSet {
+	return &orphanSet{
+		limit:   limit,
+		txObjs:  make(map[thor.Bytes32]*txObject),
+		waiting: make(map[thor.Bytes32]map[thor.Bytes32]struct{}),
+		quota:   make(map[thor.Address]int),
+	}
+}
+
+func (s *orphanSet) Contains(txID thor.Bytes32) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, found := s.txObjs[txID]
+	return found
+}
+
+// Add adds the tx, unless the set is full, or the origin already holds limitPerAccount orphans.
+func (s *orphanSet) Add(txObj *txObject, limitPerAccount int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, found := s.txObjs[txObj.ID()]; found {
+		return nil
+	}
+	if len(s.txObjs) >= s.limit {
+		return errors.New("orphan set is full")
+	}
+	if s.quota[txObj.Origin()] >= limitPerAccount {
+		return errors.New("account orphan quota exceeded")
+	}
+	s.quota[txObj.Origin()]++
+	dep := *txObj.DependsOn()
+	ids := s.waiting[dep]
+	if ids == nil {
+		ids = make(map[thor.Bytes32]struct{})
+		s.waiting[dep] = ids
+	}
+	ids[txObj.ID()] = struct{}{}
+	s.txObjs[txObj.ID()] = txObj
+	return nil
+}
+
+func (s *orphanSet) remove(txObj *txObject) {
+	dep := *txObj.DependsOn()
+	if ids := s.waiting[dep]; ids != nil {
+		delete(ids, txObj.ID())
+		if len(ids) == 0 {
+			delete(s.waiting, dep)
+		}
+	}
+	if s.quota[txObj.Origin()] > 1 {
+		s.quota[txObj.Origin()]--
+	} else {
+		delete(s.quota, txObj.Origin())
+	}
+	delete(s.txObjs, txObj.ID())
+}
+
+// TakeByDep removes and returns txs depending on the given tx.
+func (s *orphanSet) TakeByDep(depID thor.Bytes32) []*txObject {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var taken []*txObject
+	for id := range s.waiting[depID] {
+		txObj := s.txObjs[id]
+		s.remove(txObj)
+		taken = append(taken, txObj)
+	}
+	return taken
+}
+
+// TakeIf removes and returns txs that satisfy cond.
+func (s *orphanSet) TakeIf(cond func(*txObject) bool) []*txObject {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var taken []*txObject
+	for _, txObj := range s.txObjs {
+		if cond(txObj) {
+			s.remove(txObj)
+			taken = append(taken, txObj)
+		}
+	}
+	return taken
+}
+
+func (s *orphanSet) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.txObjs)
+}
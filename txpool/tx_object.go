@@ -24,6 +24,7 @@ type txObject struct {
 	resolved *runtime.ResolvedTransaction
 
 	timeAdded       int64
+	local           bool // submitted via local API
 	executable      bool
 	overallGasPrice *big.Int // don't touch this value, it's only be used in pool's housekeeping
 }
@@ -34,6 +34,12 @@ func (m *txObjectMap) Contains(txID thor.Bytes32) bool {
 	return found
 }
 
+func (m *txObjectMap) Get(txID thor.Bytes32) *txObject {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.txObjMap[txID]
+}
+
 func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -6,12 +6,14 @@
 package txpool
 
 import (
+	"math/big"
 	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/event"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/block"
@@ -26,6 +28,10 @@ import (
 const (
 	// max size of tx allowed
 	maxTxSize = 64 * 1024
+	// interval of the expiration pruning pass
+	pruneInterval = time.Second * 10
+	// max number of cancelled tx ids remembered
+	maxCancelledTxs = 1024
 )
 
 var (
@@ -37,6 +43,30 @@ type Options struct {
 	Limit           int
 	LimitPerAccount int
 	MaxLifetime     time.Duration
+	// MinGasPrice is the floor of overall gas price for txs to be admitted or kept. Nil means no floor.
+	MinGasPrice *big.Int
+	// MinGasPriceByOrigin overrides MinGasPrice for specific origins.
+	MinGasPriceByOrigin map[thor.Address]*big.Int
+	// OrphanLimit is the max number of txs kept aside while their dependencies are unknown,
+	// of which each origin holds at most LimitPerAccount. Zero disables it, and such txs are
+	// pooled as non-executable.
+	OrphanLimit int
+	// OriginPolicy the initial policy of tx origins, which can be replaced at runtime.
+	OriginPolicy OriginPolicy
+}
+
+// gasPriceFloor the floor of overall gas price, which can be replaced at runtime.
+type gasPriceFloor struct {
+	min      *big.Int
+	byOrigin map[thor.Address]*big.Int
+}
+
+// of returns the gas price floor applied to txs of origin.
+func (f *gasPriceFloor) of(origin thor.Address) *big.Int {
+	if price, ok := f.byOrigin[origin]; ok {
+		return price
+	}
+	return f.min
 }
 
 // TxEvent will be posted when tx is added or status changed.
@@ -45,6 +75,20 @@ type TxEvent struct {
 	Executable *bool
 }
 
+// TxDropEvent will be posted when tx is dropped from the pool by expiration pruning or cancellation.
+type TxDropEvent struct {
+	Tx     *tx.Transaction
+	Reason string
+}
+
+// PooledTx is a snapshot of a tx in the pool.
+type PooledTx struct {
+	*tx.Transaction
+	Origin     thor.Address
+	Executable bool // whether it's in the executables of last wash
+	Local      bool
+}
+
 // TxPool maintains unprocessed transactions.
 type TxPool struct {
 	options      Options
@@ -52,25 +96,35 @@ type TxPool struct {
 	stateCreator *state.Creator
 
 	executables    atomic.Value
+	originPolicy   atomic.Value // *compiledOriginPolicy
+	gasPriceFloor  atomic.Value // *gasPriceFloor
 	all            *txObjectMap
+	orphans        *orphanSet
+	cancelled      *lru.Cache // tx id -> time cancelled
 	addedAfterWash uint32
 
-	done   chan struct{}
-	txFeed event.Feed
-	scope  event.SubscriptionScope
-	goes   co.Goes
+	done     chan struct{}
+	txFeed   event.Feed
+	dropFeed event.Feed
+	scope    event.SubscriptionScope
+	goes     co.Goes
 }
 
 // New create a new TxPool instance.
 // Shutdown is required to be called at end.
 func New(chain *chain.Chain, stateCreator *state.Creator, options Options) *TxPool {
+	cancelled, _ := lru.New(maxCancelledTxs)
 	pool := &TxPool{
 		options:      options,
 		chain:        chain,
 		stateCreator: stateCreator,
 		all:          newTxObjectMap(),
+		orphans:      newOrphanSet(options.OrphanLimit),
+		cancelled:    cancelled,
 		done:         make(chan struct{}),
 	}
+	pool.originPolicy.Store(compileOriginPolicy(options.OriginPolicy))
+	pool.gasPriceFloor.Store(&gasPriceFloor{options.MinGasPrice, options.MinGasPriceByOrigin})
 	pool.goes.Go(pool.housekeeping)
 	return pool
 }
@@ -82,51 +136,64 @@ func (p *TxPool) housekeeping() {
 	ticker := time.NewTicker(time.Second * 2)
 	defer ticker.Stop()
 
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+
+	newBlockCh := make(chan *block.Block, 16)
+	sub := p.chain.SubscribeNewBlock(newBlockCh)
+	defer sub.Unsubscribe()
+
 	headBlock := p.chain.BestBlock().Header()
 
 	for {
 		select {
 		case <-p.done:
 			return
-		case <-ticker.C:
-			var headBlockChanged bool
-			if newHeadBlock := p.chain.BestBlock().Header(); newHeadBlock.ID() != headBlock.ID() {
-				headBlock = newHeadBlock
-				headBlockChanged = true
+		case <-pruneTicker.C:
+			// expiration only depends on block numbers, so it's pruned even if not synced
+			if pruned := p.pruneExpired(p.chain.BestBlock().Header()); pruned > 0 {
+				log.Debug("expired txs pruned", "count", pruned)
 			}
-			if !isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
-				// skip washing txs if not synced
-				continue
-			}
-			poolLen := p.all.Len()
-			// do wash on
-			// 1. head block changed
-			// 2. pool size exceeds limit
-			// 3. new tx added while pool size is small
-			if headBlockChanged ||
-				poolLen > p.options.Limit ||
-				(poolLen < 200 && atomic.LoadUint32(&p.addedAfterWash) > 0) {
-
-				atomic.StoreUint32(&p.addedAfterWash, 0)
-
-				startTime := mclock.Now()
-				executables, removed, err := p.wash(headBlock)
-				elapsed := mclock.Now() - startTime
-
-				ctx := []interface{}{
-					"len", poolLen,
-					"removed", removed,
-					"elapsed", common.PrettyDuration(elapsed),
-				}
-				if err != nil {
-					ctx = append(ctx, "err", err)
-				} else {
-					p.executables.Store(executables)
-				}
+		case blk := <-newBlockCh:
+			headBlock = blk.Header()
+			p.checkOrphans(headBlock)
+			p.washIfNeeded(headBlock, true)
+		case <-ticker.C:
+			p.washIfNeeded(headBlock, false)
+		}
+	}
+}
 
-				log.Debug("wash done", ctx...)
-			}
+// washIfNeeded washes the pool on the head block, when it's changed, the pool size exceeds
+// the limit, or new tx added while the pool size is small.
+func (p *TxPool) washIfNeeded(headBlock *block.Header, headBlockChanged bool) {
+	if !isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
+		// skip washing txs if not synced
+		return
+	}
+	poolLen := p.all.Len()
+	if headBlockChanged ||
+		poolLen > p.options.Limit ||
+		(poolLen < 200 && atomic.LoadUint32(&p.addedAfterWash) > 0) {
+
+		atomic.StoreUint32(&p.addedAfterWash, 0)
+
+		startTime := mclock.Now()
+		executables, removed, err := p.wash(headBlock)
+		elapsed := mclock.Now() - startTime
+
+		ctx := []interface{}{
+			"len", poolLen,
+			"removed", removed,
+			"elapsed", common.PrettyDuration(elapsed),
+		}
+		if err != nil {
+			ctx = append(ctx, "err", err)
+		} else {
+			p.executables.Store(executables)
 		}
+
+		log.Debug("wash done", ctx...)
 	}
 }
 
@@ -143,12 +210,25 @@ func (p *TxPool) SubscribeTxEvent(ch chan *TxEvent) event.Subscription {
 	return p.scope.Track(p.txFeed.Subscribe(ch))
 }
 
-func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
-	if p.all.Contains(newTx.ID()) {
+// SubscribeTxDropEvent receivers will receive txs dropped by expiration pruning or cancellation
+func (p *TxPool) SubscribeTxDropEvent(ch chan *TxDropEvent) event.Subscription {
+	return p.scope.Track(p.dropFeed.Subscribe(ch))
+}
+
+func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, local bool) error {
+	if p.all.Contains(newTx.ID()) || p.orphans.Contains(newTx.ID()) {
 		// tx already in the pool
 		return nil
 	}
 
+	if local {
+		// re-submitted by ourselves
+		p.cancelled.Remove(newTx.ID())
+	} else if p.isCancelled(newTx.ID()) {
+		// re-sent by peers
+		return txRejectedError{"tx cancelled"}
+	}
+
 	// validation
 	switch {
 	case newTx.ChainTag() != p.chain.Tag():
@@ -163,6 +243,11 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 	if err != nil {
 		return badTxError{err.Error()}
 	}
+	txObj.local = local
+
+	if !p.loadOriginPolicy().Permits(txObj.Origin()) {
+		return txRejectedError{"origin not permitted"}
+	}
 
 	headBlock := p.chain.BestBlock().Header()
 	if isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
@@ -176,6 +261,32 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 			return txRejectedError{err.Error()}
 		}
 
+		if minGasPrice := p.loadGasPriceFloor().of(txObj.Origin()); minGasPrice != nil {
+			baseGasPrice := builtin.Params.Native(state).Get(thor.KeyBaseGasPrice)
+			if err := state.Err(); err != nil {
+				return err
+			}
+			gasPrice := txObj.OverallGasPrice(baseGasPrice, headBlock.Number(), p.chain.NewSeeker(headBlock.ID()).GetID)
+			if gasPrice.Cmp(minGasPrice) < 0 {
+				return txRejectedError{"gas price too low"}
+			}
+		}
+
+		// strictly added txs are rejected rather than kept aside
+		if !executable && !rejectNonexecutable && p.options.OrphanLimit > 0 {
+			orphan, err := p.isOrphan(txObj, headBlock)
+			if err != nil {
+				return err
+			}
+			if orphan {
+				if err := p.orphans.Add(txObj, p.options.LimitPerAccount); err != nil {
+					return txRejectedError{err.Error()}
+				}
+				log.Debug("tx added as orphan", "id", newTx.ID())
+				return nil
+			}
+		}
+
 		if rejectNonexecutable && !executable {
 			return txRejectedError{"tx is not executable"}
 		}
@@ -203,18 +314,68 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 		p.txFeed.Send(&TxEvent{newTx, nil})
 	}
 	atomic.AddUint32(&p.addedAfterWash, 1)
+	p.promoteOrphans(p.orphans.TakeByDep(newTx.ID()))
 	return nil
 }
 
+// isOrphan returns whether the dependency of tx is neither on chain nor in the pool.
+func (p *TxPool) isOrphan(txObj *txObject, headBlock *block.Header) (bool, error) {
+	dep := txObj.DependsOn()
+	if dep == nil || p.all.Contains(*dep) {
+		return false, nil
+	}
+	if _, err := p.chain.GetTransactionMeta(*dep, headBlock.ID()); err != nil {
+		if p.chain.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// promoteOrphans moves orphans into the pool, once their dependencies arrived.
+func (p *TxPool) promoteOrphans(txObjs []*txObject) {
+	for _, txObj := range txObjs {
+		if err := p.add(txObj.Transaction, false, txObj.local); err != nil {
+			log.Debug("orphan dropped", "id", txObj.ID(), "err", err)
+		} else {
+			log.Debug("orphan promoted", "id", txObj.ID())
+		}
+	}
+}
+
+// checkOrphans drops orphans out of lifetime, and promotes those whose dependencies are included.
+func (p *TxPool) checkOrphans(headBlock *block.Header) {
+	if p.orphans.Len() == 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	for _, txObj := range p.orphans.TakeIf(func(txObj *txObject) bool {
+		return now > txObj.timeAdded+int64(p.options.MaxLifetime)
+	}) {
+		log.Debug("orphan dropped", "id", txObj.ID(), "err", "out of lifetime")
+	}
+	p.promoteOrphans(p.orphans.TakeIf(func(txObj *txObject) bool {
+		_, err := p.chain.GetTransactionMeta(*txObj.DependsOn(), headBlock.ID())
+		// promote on errors other than not found, to let the pool deal with it
+		return err == nil || !p.chain.IsNotFound(err)
+	}))
+}
+
 // Add add new tx into pool.
 // It's not assumed as an error if the tx to be added is already in the pool,
 func (p *TxPool) Add(newTx *tx.Transaction) error {
-	return p.add(newTx, false)
+	return p.add(newTx, false, false)
+}
+
+// AddLocal add new tx submitted locally into pool. Local txs can be cancelled by RemoveLocal.
+func (p *TxPool) AddLocal(newTx *tx.Transaction) error {
+	return p.add(newTx, false, true)
 }
 
 // StrictlyAdd add new tx into pool. A rejection error will be returned, if tx is not executable at this time.
 func (p *TxPool) StrictlyAdd(newTx *tx.Transaction) error {
-	return p.add(newTx, true)
+	return p.add(newTx, true, false)
 }
 
 // Remove removes tx from pool by its ID.
@@ -226,6 +387,133 @@ func (p *TxPool) Remove(txID thor.Bytes32) bool {
 	return false
 }
 
+// RemoveLocal cancels a locally submitted tx, so that it's neither packed nor broadcast
+// any more, and re-sending from peers is rejected for MaxLifetime. It returns whether the
+// tx was still pending. An error is returned if the pending tx was not submitted locally.
+//
+// A tx already broadcast can't be recalled from the network, and may still be packed by others.
+func (p *TxPool) RemoveLocal(txID thor.Bytes32) (bool, error) {
+	txObj := p.all.Get(txID)
+	if txObj == nil {
+		// orphans are never broadcast
+		if taken := p.orphans.TakeIf(func(o *txObject) bool { return o.ID() == txID && o.local }); len(taken) > 0 {
+			p.cancelled.Add(txID, mclock.Now())
+			log.Debug("local orphan cancelled", "id", txID)
+			p.notifyDropped(&TxDropEvent{taken[0].Transaction, "cancelled"})
+			return true, nil
+		}
+		if p.orphans.Contains(txID) {
+			return false, errors.New("not a local tx")
+		}
+		return false, nil
+	}
+	if !txObj.local {
+		return false, errors.New("not a local tx")
+	}
+	if !p.all.Remove(txID) {
+		// removed concurrently, e.g. washed out
+		return false, nil
+	}
+	// take it out of the current executables at once, rather than waiting for next wash
+	if executables := p.Executables(); len(executables) > 0 {
+		filtered := make(tx.Transactions, 0, len(executables))
+		for _, tx := range executables {
+			if tx.ID() != txID {
+				filtered = append(filtered, tx)
+			}
+		}
+		p.executables.Store(filtered)
+	}
+	p.cancelled.Add(txID, mclock.Now())
+	log.Debug("local tx cancelled", "id", txID)
+	p.notifyDropped(&TxDropEvent{txObj.Transaction, "cancelled"})
+	return true, nil
+}
+
+func (p *TxPool) isCancelled(txID thor.Bytes32) bool {
+	v, ok := p.cancelled.Get(txID)
+	if !ok {
+		return false
+	}
+	if time.Duration(mclock.Now()-v.(mclock.AbsTime)) > p.options.MaxLifetime {
+		p.cancelled.Remove(txID)
+		return false
+	}
+	return true
+}
+
+// Flush drops all pooled txs, including orphans. It returns the number of dropped txs.
+func (p *TxPool) Flush() int {
+	var dropped []*txObject
+	for _, txObj := range p.all.ToTxObjects() {
+		if p.all.Remove(txObj.ID()) {
+			dropped = append(dropped, txObj)
+		}
+	}
+	dropped = append(dropped, p.orphans.TakeIf(func(*txObject) bool { return true })...)
+	p.executables.Store(tx.Transactions(nil))
+
+	for _, txObj := range dropped {
+		p.notifyDropped(&TxDropEvent{txObj.Transaction, "flushed"})
+	}
+	log.Info("tx pool flushed", "dropped", len(dropped))
+	return len(dropped)
+}
+
+func (p *TxPool) notifyDropped(ev *TxDropEvent) {
+	p.goes.Go(func() { p.dropFeed.Send(ev) })
+}
+
+// SetOriginPolicy replaces the policy of tx origins. Pooled txs of origins no longer
+// permitted are washed out later.
+func (p *TxPool) SetOriginPolicy(policy OriginPolicy) {
+	p.originPolicy.Store(compileOriginPolicy(policy))
+	// trigger washing
+	atomic.AddUint32(&p.addedAfterWash, 1)
+	log.Info("tx origin policy updated", "allowlist", len(policy.Allowlist), "blocklist", len(policy.Blocklist))
+}
+
+// OriginPolicy returns the current policy of tx origins.
+func (p *TxPool) OriginPolicy() OriginPolicy {
+	policy := p.loadOriginPolicy().policy
+	return OriginPolicy{
+		Allowlist: append([]thor.Address{}, policy.Allowlist...),
+		Blocklist: append([]thor.Address{}, policy.Blocklist...),
+	}
+}
+
+func (p *TxPool) loadOriginPolicy() *compiledOriginPolicy {
+	return p.originPolicy.Load().(*compiledOriginPolicy)
+}
+
+// SetMinGasPrice replaces the floor of overall gas price, and overrides for specific origins.
+// Nil min means no floor. Pooled txs below the new floor are washed out later.
+func (p *TxPool) SetMinGasPrice(min *big.Int, byOrigin map[thor.Address]*big.Int) {
+	floor := &gasPriceFloor{byOrigin: make(map[thor.Address]*big.Int, len(byOrigin))}
+	if min != nil {
+		floor.min = new(big.Int).Set(min)
+	}
+	for origin, price := range byOrigin {
+		floor.byOrigin[origin] = new(big.Int).Set(price)
+	}
+	p.gasPriceFloor.Store(floor)
+	// trigger washing
+	atomic.AddUint32(&p.addedAfterWash, 1)
+	log.Info("tx gas price floor updated", "min", min, "origins", len(byOrigin))
+}
+
+func (p *TxPool) loadGasPriceFloor() *gasPriceFloor {
+	return p.gasPriceFloor.Load().(*gasPriceFloor)
+}
+
+// Get returns the pooled tx by its ID, or nil if not in the pool.
+func (p *TxPool) Get(txID thor.Bytes32) *tx.Transaction {
+	if txObj := p.all.Get(txID); txObj != nil {
+		return txObj.Transaction
+	}
+	return nil
+}
+
 // Executables returns executable txs.
 func (p *TxPool) Executables() tx.Transactions {
 	if sorted := p.executables.Load(); sorted != nil {
@@ -251,6 +539,25 @@ func (p *TxPool) Dump() tx.Transactions {
 	return p.all.ToTxs()
 }
 
+// Inspect returns snapshots of all pooled txs excluding orphans, and the count of orphans.
+func (p *TxPool) Inspect() ([]*PooledTx, int) {
+	executables := make(map[thor.Bytes32]bool)
+	for _, tx := range p.Executables() {
+		executables[tx.ID()] = true
+	}
+	txObjs := p.all.ToTxObjects()
+	pooled := make([]*PooledTx, 0, len(txObjs))
+	for _, txObj := range txObjs {
+		pooled = append(pooled, &PooledTx{
+			Transaction: txObj.Transaction,
+			Origin:      txObj.Origin(),
+			Executable:  executables[txObj.ID()],
+			Local:       txObj.local,
+		})
+	}
+	return pooled, p.orphans.Len()
+}
+
 // wash to evict txs that are over limit, out of lifetime, out of energy, settled, expired or dep broken.
 // this method should only be called in housekeeping go routine
 func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, removed int, err error) {
@@ -284,8 +591,15 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 		executableObjs    = make([]*txObject, 0, len(all))
 		nonExecutableObjs = make([]*txObject, 0, len(all))
 		now               = time.Now().UnixNano()
+		originPolicy      = p.loadOriginPolicy()
 	)
 	for _, txObj := range all {
+		// origin blocked after added
+		if !originPolicy.Permits(txObj.Origin()) {
+			toRemove = append(toRemove, txObj.ID())
+			log.Debug("tx washed out", "id", txObj.ID(), "err", "origin not permitted")
+			continue
+		}
 		// out of lifetime
 		if now > txObj.timeAdded+int64(p.options.MaxLifetime) {
 			toRemove = append(toRemove, txObj.ID())
@@ -305,6 +619,12 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 				baseGasPrice,
 				headBlock.Number(),
 				seeker.GetID)
+			// below the floor, which may be raised after the tx was admitted
+			if minGasPrice := p.loadGasPriceFloor().of(txObj.Origin()); minGasPrice != nil && txObj.overallGasPrice.Cmp(minGasPrice) < 0 {
+				toRemove = append(toRemove, txObj.ID())
+				log.Debug("tx washed out", "id", txObj.ID(), "err", "gas price too low")
+				continue
+			}
 			executableObjs = append(executableObjs, txObj)
 		} else {
 			nonExecutableObjs = append(nonExecutableObjs, txObj)
@@ -363,6 +683,42 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 	return executables, 0, nil
 }
 
+// pruneExpired drops txs that can never be packed after headBlock, because the expiration
+// window has elapsed or the block ref is too old to be traced back.
+// It's lightweight compared to wash, since no state access is required.
+func (p *TxPool) pruneExpired(headBlock *block.Header) (pruned int) {
+	var dropped []*TxDropEvent
+	for _, txObj := range p.orphans.TakeIf(func(txObj *txObject) bool {
+		return txObj.IsExpired(headBlock.Number())
+	}) {
+		log.Debug("orphan pruned", "id", txObj.ID(), "reason", "expired")
+		dropped = append(dropped, &TxDropEvent{txObj.Transaction, "expired"})
+	}
+	for _, txObj := range p.all.ToTxObjects() {
+		var reason string
+		switch {
+		case txObj.IsExpired(headBlock.Number()):
+			reason = "expired"
+		case txObj.BlockRef().Number()+thor.MaxBackTrackingBlockNumber < headBlock.Number():
+			reason = "block ref too old"
+		default:
+			continue
+		}
+		if p.all.Remove(txObj.ID()) {
+			log.Debug("tx pruned", "id", txObj.ID(), "reason", reason)
+			dropped = append(dropped, &TxDropEvent{txObj.Transaction, reason})
+		}
+	}
+	if len(dropped) > 0 {
+		p.goes.Go(func() {
+			for _, ev := range dropped {
+				p.dropFeed.Send(ev)
+			}
+		})
+	}
+	return len(dropped)
+}
+
 func isChainSynced(nowTimestamp, blockTimestamp uint64) bool {
 	timeDiff := nowTimestamp - blockTimestamp
 	if blockTimestamp > nowTimestamp {
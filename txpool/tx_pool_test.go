@@ -6,6 +6,7 @@
 package txpool
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
@@ -91,6 +92,214 @@ func TestWashTxs(t *testing.T) {
 	assert.Equal(t, Tx.Transactions{tx}, txs)
 }
 
+func TestPruneExpired(t *testing.T) {
+	pool := newPool()
+	defer pool.Close()
+
+	acc := genesis.DevAccounts()[0]
+	expiring := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 1, nil, acc)
+	alive := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc)
+	assert.Nil(t, pool.Add(expiring))
+	assert.Nil(t, pool.Add(alive))
+
+	dropCh := make(chan *TxDropEvent, 1)
+	pool.SubscribeTxDropEvent(dropCh)
+
+	b1 := new(block.Builder).
+		ParentID(pool.chain.GenesisBlock().Header().ID()).
+		Build()
+	assert.Zero(t, pool.pruneExpired(b1.Header()))
+
+	b2 := new(block.Builder).
+		ParentID(b1.Header().ID()).
+		Build()
+	assert.Equal(t, 1, pool.pruneExpired(b2.Header()))
+	assert.Equal(t, &TxDropEvent{expiring, "expired"}, <-dropCh)
+	assert.Equal(t, Tx.Transactions{alive}, pool.Dump())
+}
+
+func TestMinGasPrice(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+	acc := genesis.DevAccounts()[0]
+	pool := New(chain, state.NewCreator(kv), Options{
+		Limit:               10,
+		LimitPerAccount:     2,
+		MaxLifetime:         time.Hour,
+		MinGasPrice:         new(big.Int).Add(thor.InitialBaseGasPrice, big.NewInt(1)),
+		MinGasPriceByOrigin: map[thor.Address]*big.Int{acc.Address: thor.InitialBaseGasPrice},
+	})
+	defer pool.Close()
+
+	b1 := new(block.Builder).
+		ParentID(chain.GenesisBlock().Header().ID()).
+		Timestamp(uint64(time.Now().Unix())).
+		TotalScore(100).
+		GasLimit(10000000).
+		StateRoot(chain.GenesisBlock().Header().StateRoot()).
+		Build()
+	chain.AddBlock(b1, nil)
+
+	err := pool.Add(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[1]))
+	assert.Equal(t, "tx rejected: gas price too low", err.Error())
+	assert.True(t, IsTxRejected(err))
+
+	// floor overridden
+	assert.Nil(t, pool.Add(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc)))
+
+	// floor raised after admission
+	pool.SetMinGasPrice(pool.options.MinGasPrice, nil)
+	txs, _, err := pool.wash(chain.BestBlock().Header())
+	assert.Nil(t, err)
+	assert.Zero(t, len(txs))
+	assert.Zero(t, pool.all.Len())
+}
+
+func TestOrphans(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+	pool := New(chain, state.NewCreator(kv), Options{
+		Limit:           10,
+		LimitPerAccount: 2,
+		MaxLifetime:     time.Hour,
+		OrphanLimit:     3,
+	})
+	defer pool.Close()
+
+	b1 := new(block.Builder).
+		ParentID(chain.GenesisBlock().Header().ID()).
+		Timestamp(uint64(time.Now().Unix())).
+		TotalScore(100).
+		GasLimit(10000000).
+		StateRoot(chain.GenesisBlock().Header().StateRoot()).
+		Build()
+	chain.AddBlock(b1, nil)
+
+	acc0, acc1, acc2 := genesis.DevAccounts()[0], genesis.DevAccounts()[1], genesis.DevAccounts()[2]
+	dep := newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc1)
+	depID := dep.ID()
+	orphan := newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, &depID, acc0)
+
+	assert.Nil(t, pool.Add(orphan))
+	assert.Zero(t, pool.all.Len())
+	assert.Equal(t, 1, pool.orphans.Len())
+
+	// never kept aside if strictly added
+	err := pool.StrictlyAdd(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, &depID, acc0))
+	assert.Equal(t, "tx rejected: tx is not executable", err.Error())
+
+	assert.Nil(t, pool.Add(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, &depID, acc0)))
+	err = pool.Add(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, &depID, acc0))
+	assert.Equal(t, "tx rejected: account orphan quota exceeded", err.Error())
+
+	assert.Nil(t, pool.Add(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, &depID, acc1)))
+	err = pool.Add(newTx(chain.Tag(), nil, 21000, tx.BlockRef{}, 100, &depID, acc2))
+	assert.Equal(t, "tx rejected: orphan set is full", err.Error())
+
+	// promoted once the dependency arrives
+	assert.Nil(t, pool.Add(dep))
+	assert.Zero(t, pool.orphans.Len())
+	assert.Equal(t, 4, pool.all.Len())
+	assert.NotNil(t, pool.Get(orphan.ID()))
+	assert.Empty(t, pool.orphans.quota)
+}
+
+func TestOriginPolicy(t *testing.T) {
+	pool := newPool()
+	defer pool.Close()
+
+	acc0, acc1 := genesis.DevAccounts()[0], genesis.DevAccounts()[1]
+	pool.SetOriginPolicy(OriginPolicy{Blocklist: []thor.Address{acc0.Address}})
+	err := pool.Add(newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc0))
+	assert.Equal(t, "tx rejected: origin not permitted", err.Error())
+	assert.Nil(t, pool.Add(newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc1)))
+
+	pool.SetOriginPolicy(OriginPolicy{Allowlist: []thor.Address{acc0.Address}})
+	assert.Equal(t, OriginPolicy{Allowlist: []thor.Address{acc0.Address}, Blocklist: []thor.Address{}}, pool.OriginPolicy())
+	assert.Nil(t, pool.Add(newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc0)))
+	err = pool.Add(newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, acc1))
+	assert.True(t, IsTxRejected(err))
+
+	// txs of origins no longer permitted are washed out
+	txs, _, err := pool.wash(pool.chain.BestBlock().Header())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, 1, pool.all.Len())
+}
+
+func TestRemoveLocal(t *testing.T) {
+	pool := newPool()
+	defer pool.Close()
+
+	dropCh := make(chan *TxDropEvent, 1)
+	pool.SubscribeTxDropEvent(dropCh)
+
+	local := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[0])
+	remote := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[1])
+	assert.Nil(t, pool.AddLocal(local))
+	assert.Nil(t, pool.Add(remote))
+
+	_, err := pool.RemoveLocal(remote.ID())
+	assert.Equal(t, "not a local tx", err.Error())
+
+	pending, err := pool.RemoveLocal(local.ID())
+	assert.Nil(t, err)
+	assert.True(t, pending)
+	assert.Equal(t, &TxDropEvent{local, "cancelled"}, <-dropCh)
+	assert.Nil(t, pool.Get(local.ID()))
+
+	pending, err = pool.RemoveLocal(local.ID())
+	assert.Nil(t, err)
+	assert.False(t, pending)
+
+	// re-sent by peers
+	assert.Equal(t, "tx rejected: tx cancelled", pool.Add(local).Error())
+	assert.Nil(t, pool.Get(local.ID()))
+
+	// re-submitted locally
+	assert.Nil(t, pool.AddLocal(local))
+	assert.NotNil(t, pool.Get(local.ID()))
+}
+
+func TestFlush(t *testing.T) {
+	pool := newPool()
+	defer pool.Close()
+
+	dropCh := make(chan *TxDropEvent, 2)
+	pool.SubscribeTxDropEvent(dropCh)
+
+	assert.Nil(t, pool.AddLocal(newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[0])))
+	assert.Nil(t, pool.Add(newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[1])))
+
+	assert.Equal(t, 2, pool.Flush())
+	assert.Equal(t, 0, len(pool.Dump()))
+	assert.Equal(t, 0, len(pool.Executables()))
+	assert.Equal(t, "flushed", (<-dropCh).Reason)
+	assert.Equal(t, "flushed", (<-dropCh).Reason)
+
+	assert.Equal(t, 0, pool.Flush())
+}
+
+func TestInspect(t *testing.T) {
+	pool := newPool()
+	defer pool.Close()
+
+	local := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[0])
+	assert.Nil(t, pool.AddLocal(local))
+
+	pooled, orphans := pool.Inspect()
+	assert.Equal(t, 0, orphans)
+	assert.Equal(t, 1, len(pooled))
+	assert.Equal(t, local.ID(), pooled[0].ID())
+	assert.Equal(t, genesis.DevAccounts()[0].Address, pooled[0].Origin)
+	assert.True(t, pooled[0].Local)
+	assert.False(t, pooled[0].Executable, "not washed yet")
+
+	pool.executables.Store(tx.Transactions{local})
+	pooled, _ = pool.Inspect()
+	assert.True(t, pooled[0].Executable)
+}
+
 func TestAdd(t *testing.T) {
 	pool := newPool()
 	defer pool.Close()
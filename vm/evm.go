@@ -190,6 +190,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
 				evm.vmConfig.Tracer.CaptureEnd(ret, 0, 0, nil)
 			}
+			if ft := evm.frameTracer(); ft != nil {
+				ft.CaptureEnter(CALL, caller.Address(), addr, input, gas, value)
+				ft.CaptureExit(ret, 0, nil)
+			}
 			return nil, gas, nil
 		}
 		evm.StateDB.CreateAccount(addr)
@@ -210,6 +214,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
 		}()
 	}
+	if ft := evm.frameTracer(); ft != nil {
+		ft.CaptureEnter(CALL, caller.Address(), addr, input, gas, value)
+		defer func() { ft.CaptureExit(ret, gas-contract.Gas, err) }()
+	}
 	ret, err = run(evm, contract, input)
 
 	// When an error was returned by the EVM or when setting the creation code
@@ -255,6 +263,10 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	contract := NewContract(caller, to, value, gas)
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
+	if ft := evm.frameTracer(); ft != nil {
+		ft.CaptureEnter(CALLCODE, caller.Address(), addr, input, gas, value)
+		defer func() { ft.CaptureExit(ret, gas-contract.Gas, err) }()
+	}
 	ret, err = run(evm, contract, input)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
@@ -288,6 +300,10 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	contract := NewContract(caller, to, nil, gas).AsDelegate()
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
+	if ft := evm.frameTracer(); ft != nil {
+		ft.CaptureEnter(DELEGATECALL, caller.Address(), addr, input, gas, nil)
+		defer func() { ft.CaptureExit(ret, gas-contract.Gas, err) }()
+	}
 	ret, err = run(evm, contract, input)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
@@ -331,6 +347,10 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	// When an error was returned by the EVM or when setting the creation code
 	// above we revert to the snapshot and consume any gas remaining. Additionally
 	// when we're in Homestead this also counts for code storage gas errors.
+	if ft := evm.frameTracer(); ft != nil {
+		ft.CaptureEnter(STATICCALL, caller.Address(), addr, input, gas, nil)
+		defer func() { ft.CaptureExit(ret, gas-contract.Gas, err) }()
+	}
 	ret, err = run(evm, contract, input)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
@@ -395,6 +415,10 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	if evm.vmConfig.Debug && evm.depth == 0 {
 		evm.vmConfig.Tracer.CaptureStart(caller.Address(), contractAddr, true, code, gas, value)
 	}
+	ft := evm.frameTracer()
+	if ft != nil {
+		ft.CaptureEnter(CREATE, caller.Address(), contractAddr, code, gas, value)
+	}
 	start := time.Now()
 
 	ret, err = run(evm, contract, nil)
@@ -430,9 +454,22 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	if evm.vmConfig.Debug && evm.depth == 0 {
 		evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
 	}
+	if ft != nil {
+		ft.CaptureExit(ret, gas-contract.Gas, err)
+	}
 	return ret, contractAddr, contract.Gas, err
 }
 
+// frameTracer returns the tracer to be notified of nested call frames, or nil if
+// not in debug mode, at the top frame, or the tracer doesn't implement FrameTracer.
+func (evm *EVM) frameTracer() FrameTracer {
+	if !evm.vmConfig.Debug || evm.depth == 0 {
+		return nil
+	}
+	ft, _ := evm.vmConfig.Tracer.(FrameTracer)
+	return ft
+}
+
 // ChainConfig returns the environment's chain configuration
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 
@@ -99,6 +99,14 @@ type Tracer interface {
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error
 }
 
+// FrameTracer is optionally implemented by a Tracer, to be notified of nested call frames,
+// i.e. calls and creations made by contracts. The top frame is reported by CaptureStart
+// and CaptureEnd.
+type FrameTracer interface {
+	CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
+	CaptureExit(output []byte, gasUsed uint64, err error)
+}
+
 // StructLogger is an EVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps